@@ -0,0 +1,345 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/datanode"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// transferThrottler limits a transfer to bytesPerSec, or to
+// config.TransferBytesPerSec if it is 0
+func transferThrottler(bytesPerSec int64) *utils.Throttler {
+	if bytesPerSec == 0 {
+		bytesPerSec = config.TransferBytesPerSec
+	}
+	return utils.NewThrottler(bytesPerSec)
+}
+
+// readAnyReplica tries each datanode in turn and returns the first replica
+// of seg that passes the checksum verification
+func (c *Client) readAnyReplica(seg string, addrs []string) ([]byte, error) {
+	err := fmt.Errorf("no replica of block %v is known", seg)
+	for _, addr := range addrs {
+		if addr == "" {
+			continue
+		}
+		var data []byte
+		data, err = c.readRemoteBlk(seg, addr)
+		if err == nil {
+			return data, nil
+		}
+		logger.Warnf("error when reading %v from %v: %v\n", seg, addr, err)
+	}
+	return nil, fmt.Errorf("no intact replica available for block %v: %w", seg, err)
+}
+
+// readRemoteBlk requests block seg from the datanode at addr and
+// verifies it against the checksum sent along, the request fails after
+// config.BlkReadTimeoutInSec
+func (c *Client) readRemoteBlk(seg, addr string) ([]byte, error) {
+	logger.Debugf("request block %v from datanode %v\n", seg, addr)
+	args := datanode.RequestBlkArgs{}
+	args.BlkID = seg
+	args.Compress = config.CompressBlks
+	args.Token = c.Token
+	reply := utils.BlkData{}
+	// a slow datanode is given up on like an unreachable one
+	timeout := time.Duration(config.BlkReadTimeoutInSec) * time.Second
+	err := c.callDataNode(addr, timeout, func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+		m, err := dn.RequestBlk(ctx, args.ToPB())
+		reply.FromPB(m)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return verifyBlk(seg, addr, &reply)
+}
+
+// verifyBlk returns the data of blk read from the datanode at addr,
+// decompressed, unless it mismatches the checksum sent along
+func verifyBlk(seg, addr string, blk *utils.BlkData) ([]byte, error) {
+	if blk.Compressed {
+		data, err := utils.Gunzip(blk.Data)
+		if err != nil {
+			logger.Warnf("error when decompressing %v from %v: %v\n", seg, addr, err)
+			return nil, ErrChecksum
+		}
+		blk.Data = data
+	}
+	// if checksum mismatch, corrupted!
+	if !utils.ChecksumOf(blk.ChecksumType, blk.Data).Equal(blk.Checksum) ||
+		len(blk.Data) < blk.Length {
+		logger.Warnf("data is corrupted for %v from %v!\n", seg, addr)
+		return nil, ErrChecksum
+	}
+	logger.Debugf("data is ok for %v from %v\n", seg, addr)
+	return blk.Data[:blk.Length], nil
+}
+
+// readRemoteBlks requests the blocks segs from the datanode at addr in a
+// single round trip and verifies each of them, see readRemoteBlk. The
+// whole request is given config.BlkReadTimeoutInSec, so that a hung
+// datanode costs no more than for a single block, errs[i] is the error of
+// block i.
+func (c *Client) readRemoteBlks(segs []string, addr string) (data [][]byte, errs []error) {
+	logger.Debugf("request blocks %v from datanode %v\n", segs, addr)
+	data, errs = make([][]byte, len(segs)), make([]error, len(segs))
+	args := datanode.RequestBlksArgs{BlkIDs: segs, Compress: config.CompressBlks,
+		Token: c.Token}
+	reply := datanode.RequestBlksReply{}
+	timeout := time.Duration(config.BlkReadTimeoutInSec) * time.Second
+	err := c.callDataNode(addr, timeout, func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+		m, err := dn.RequestBlks(ctx, args.ToPB())
+		reply.FromPB(m)
+		return err
+	})
+	if err == nil && len(reply.Blks) != len(segs) {
+		err = fmt.Errorf("%v blocks received, %v requested", len(reply.Blks), len(segs))
+	}
+	for i, seg := range segs {
+		switch {
+		case err != nil:
+			errs[i] = err
+		case reply.Errors[i] != "":
+			errs[i] = errors.New(reply.Errors[i])
+		default:
+			data[i], errs[i] = verifyBlk(seg, addr, &reply.Blks[i])
+		}
+	}
+	return data, errs
+}
+
+// readBatch reads the blocks segs, whose first replicas in blkToAddrs are
+// on the same datanode, in one request to it. A block failing there is
+// read from its other replicas.
+func (c *Client) readBatch(segs []string, blkToAddrs map[string][]string) ([][]byte, error) {
+	if len(segs) == 1 {
+		data, err := c.readAnyReplica(segs[0], blkToAddrs[segs[0]])
+		return [][]byte{data}, err
+	}
+	addr := firstAddr(blkToAddrs[segs[0]])
+	data, errs := c.readRemoteBlks(segs, addr)
+	// the blocks failing are read concurrently, as they would have been
+	// without batching
+	var wg sync.WaitGroup
+	for i, seg := range segs {
+		if errs[i] == nil {
+			continue
+		}
+		logger.Warnf("error when reading %v from %v: %v\n", seg, addr, errs[i])
+		others := make([]string, 0)
+		for _, a := range blkToAddrs[seg] {
+			if a != addr {
+				others = append(others, a)
+			}
+		}
+		wg.Add(1)
+		go func(i int, seg string) {
+			defer wg.Done()
+			data[i], errs[i] = c.readAnyReplica(seg, others)
+		}(i, seg)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// firstAddr is the datanode readAnyReplica tries first among addrs, ""
+// if there is none
+func firstAddr(addrs []string) string {
+	for _, addr := range addrs {
+		if addr != "" {
+			return addr
+		}
+	}
+	return ""
+}
+
+// blkBatches splits blks into runs of consecutive blocks whose first
+// replicas in blkToAddrs are on the same datanode, each of at most
+// config.MaxBlksPerRequest blocks, see readBatch
+func blkBatches(blks []string, blkToAddrs map[string][]string) [][]string {
+	batches := make([][]string, 0)
+	prev := ""
+	for _, seg := range blks {
+		addr := firstAddr(blkToAddrs[seg])
+		if n := len(batches); n > 0 && addr != "" && addr == prev &&
+			len(batches[n-1]) < config.MaxBlksPerRequest {
+			batches[n-1] = append(batches[n-1], seg)
+			continue
+		}
+		batches = append(batches, []string{seg})
+		prev = addr
+	}
+	return batches
+}
+
+// sendBlk sends a whole block written with generation stamp genStamp to
+// the first datanode in addrs at the rate of t, which forwards it down the
+// pipeline of the others, see pipelined. The block is compressed with
+// config.CompressBlks.
+func (c *Client) sendBlk(blkID string, genStamp int64, data []byte, addrs []string,
+	t *utils.Throttler) error {
+	if len(addrs) == 0 {
+		return fmt.Errorf("sending %v: no datanode to send to", blkID)
+	}
+	args := utils.BlkData{}
+	args.BlkID = blkID
+	args.GenStamp = genStamp
+	args.ChecksumType = config.ChecksumType
+	args.Checksum = utils.ChecksumOf(args.ChecksumType, data)
+	args.Data = data
+	args.Length = len(data)
+	args.Targets = addrs[1:]
+	args.Token = c.Token
+	if config.CompressBlks {
+		var err error
+		if args.Data, err = utils.Gzip(data); err != nil {
+			return err
+		}
+		args.Compressed = true
+	}
+	logger.Debugf("sending %v to %v\n", blkID, addrs)
+	utils.Throttle(len(args.Data), c.throttle, t)
+	reply := datanode.SendBlkReply{}
+	err := c.callDataNode(addrs[0], pipelineTimeout(addrs),
+		func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+			m, err := dn.SendBlk(ctx, args.ToPB())
+			reply.FromPB(m)
+			return err
+		})
+	if err != nil {
+		return fmt.Errorf("sending %v to %v: %w", blkID, addrs[0], err)
+	}
+	return pipelined(blkID, addrs, &reply)
+}
+
+// pipelineTimeout gives each datanode of addrs RPCTimeout to store and
+// forward a block
+func pipelineTimeout(addrs []string) time.Duration {
+	return utils.RPCTimeout() * time.Duration(len(addrs))
+}
+
+// pipelined checks that every datanode of the pipeline addrs acknowledged
+// the block
+func pipelined(blkID string, addrs []string, reply *datanode.SendBlkReply) error {
+	if len(reply.Acked) != len(addrs) {
+		return fmt.Errorf("sending %v: %v of %v datanodes stored it", blkID,
+			len(reply.Acked), len(addrs))
+	}
+	return nil
+}
+
+// streamBlk sends the block read from r chunk by chunk to the first
+// datanode in addrs over a stream, the datanode forwards each chunk down
+// the pipeline of the others. The checksum of the whole block goes with
+// the last chunk so that every datanode can verify the block before
+// committing it, it is returned once they did. Chunks are sent at the rate
+// of t, each compressed on its own with config.CompressBlks, and each is
+// given pipelineTimeout to be sent.
+func (c *Client) streamBlk(blkID string, genStamp int64, r io.Reader, addrs []string,
+	t *utils.Throttler) (sum utils.Sum, err error) {
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("sending %v: no datanode to send to", blkID)
+	}
+	logger.Debugf("streaming %v to %v\n", blkID, addrs)
+	hash := utils.NewHash(config.ChecksumType)
+	if hash == nil {
+		return nil, utils.CheckChecksumType(config.ChecksumType)
+	}
+	cc, release, err := c.dataNodes.Get(addrs[0])
+	if err != nil {
+		return nil, fmt.Errorf("sending %v to %v: %w", blkID, addrs[0], connError(addrs[0], err))
+	}
+	defer func() { release(err) }()
+	ctx, bound, cancel := utils.StreamContext(pipelineTimeout(addrs))
+	defer cancel()
+	var stream gdfspb.DataNode_SendBlkChunkClient
+	err = bound(func() (err error) {
+		stream, err = gdfspb.NewDataNodeClient(cc).SendBlkChunk(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sending %v to %v: %w", blkID, addrs[0], connError(addrs[0], err))
+	}
+	buf := make([]byte, config.ChunkSize)
+	var offset int64
+	for {
+		// ReadFull only returns a short chunk at the end of the block
+		n, err := io.ReadFull(r, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("reading block %v: %w", blkID, err)
+		}
+		hash.Write(buf[:n])
+		args := utils.BlkChunk{BlkID: blkID, Offset: offset, Data: buf[:n],
+			GenStamp: genStamp, Targets: addrs[1:], ChecksumType: config.ChecksumType,
+			Token: c.Token}
+		offset += int64(n)
+		if err != nil {
+			args.Last = true
+			args.Checksum = hash.Sum(nil)
+		}
+		if config.CompressBlks {
+			if args.Data, err = utils.Gzip(buf[:n]); err != nil {
+				return nil, err
+			}
+			args.Compressed = true
+		}
+		utils.Throttle(len(args.Data), c.throttle, t)
+		/** Send copies the chunk out of buf before it returns. The
+		 * datanode ends the stream on an error, Send then returns io.EOF
+		 * and CloseAndRecv the error.
+		 * */
+		err = bound(func() error { return stream.Send(args.ToPB()) })
+		reply := datanode.SendBlkReply{}
+		if err == io.EOF || err == nil && args.Last {
+			var m *gdfspb.SendBlkReply
+			err = bound(func() (err error) {
+				m, err = stream.CloseAndRecv()
+				return err
+			})
+			reply.FromPB(m)
+			if err == nil && !args.Last {
+				err = errors.New("stream ended before the last chunk")
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sending %v to %v: %w", blkID, addrs[0], connError(addrs[0], err))
+		}
+		if args.Last {
+			if err := pipelined(blkID, addrs, &reply); err != nil {
+				return nil, err
+			}
+			logger.Debugf("streamed %v bytes of %v\n", offset, blkID)
+			return args.Checksum, nil
+		}
+	}
+}
@@ -0,0 +1,894 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client talks to a gdfs cluster: namespace commands go to the
+// namenode, block data is sent to and read from datanodes directly.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/mapreduce"
+	"github.com/WineChord/gdfs/namenode"
+	"github.com/WineChord/gdfs/utils"
+	"google.golang.org/grpc"
+)
+
+// Client is a connection to the namenode, it is not safe for concurrent use
+type Client struct {
+	addr string // namenode address
+	nn   *grpc.ClientConn
+	// dataNodes keeps the connections to datanodes across blocks
+	dataNodes *utils.Pool
+	// throttle limits the rate of all the blocks sent, see
+	// config.NodeBytesPerSec
+	throttle *utils.Throttler
+	// sentTo records datanodes which received blocks since the last
+	// notification of namenode
+	sentTo map[string]bool
+	// Timeout bounds each command, namenode gives up on datanodes in
+	// time to reply within it, see namenode.CommandArgs
+	Timeout time.Duration
+	// Token authenticates commands, config.AuthToken by default
+	Token string
+	// User owns the trash of the files removed, the login name by default
+	User string
+	// Cwd is the dfs directory relative paths are resolved against,
+	// config.CwdEnv by default, "/" if empty
+	Cwd string
+}
+
+// replyGrace is how long client waits for namenode past the timeout sent
+// along with a command
+const replyGrace = time.Second
+
+// WriteOptions are the properties of a new dfs file, zero values stand
+// for the cluster defaults
+type WriteOptions struct {
+	BlockSize   int64 // in byte
+	Replication int
+	Overwrite   bool // replace an existing dfs file
+	// BytesPerSec limits the rate the file is sent at, 0 for
+	// config.TransferBytesPerSec
+	BytesPerSec int64
+}
+
+// New connects to the namenode at addr
+func New(addr string) (*Client, error) {
+	nn, err := dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{addr: addr, nn: nn, sentTo: make(map[string]bool),
+		dataNodes: utils.NewPool(), throttle: utils.NewThrottler(config.NodeBytesPerSec)}
+	c.Timeout = utils.RPCTimeout()
+	c.Token = config.AuthToken
+	c.Cwd = os.Getenv(config.CwdEnv)
+	if u, err := user.Current(); err == nil {
+		c.User = u.Username
+	}
+	return c, nil
+}
+
+// Close closes the connections to the namenode and datanodes
+func (c *Client) Close() error {
+	c.dataNodes.Close()
+	return c.nn.Close()
+}
+
+// run calls NameNode.RunCommand
+func (c *Client) run(args *namenode.CommandArgs) (*namenode.CommandReply, error) {
+	reply := &namenode.CommandReply{}
+	args.Timeout = c.Timeout
+	/** namenode roots relative paths at "/", resolve them against Cwd
+	 * first. The caller's slice of paths is left as is.
+	 * */
+	args.DPath = c.Abs(args.DPath)
+	if args.DPaths != nil {
+		dpaths := make([]string, len(args.DPaths))
+		for i, p := range args.DPaths {
+			dpaths[i] = c.Abs(p)
+		}
+		args.DPaths = dpaths
+	}
+	if args.Output != "" {
+		args.Output = c.Abs(args.Output)
+	}
+	logged := *args
+	logged.Token = "" // keep the secret out of logs
+	logger.Debugf("called with args: %v\n", logged)
+	args.Token = c.Token
+	args.User = c.User
+	err := c.callNameNode(c.Timeout+replyGrace, func(ctx context.Context, nn gdfspb.NameNodeClient) error {
+		m, err := nn.RunCommand(ctx, args.ToPB())
+		reply.FromPB(m)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// Abs returns the canonical form of dfs path p made absolute against
+// Cwd. ".." never climbs above "/".
+func (c *Client) Abs(p string) string {
+	if path.IsAbs(p) {
+		return path.Clean(p)
+	}
+	return path.Join("/", c.Cwd, p)
+}
+
+// notify asks namenode for an immediate block report from datanodes
+// which received blocks
+func (c *Client) notify() error {
+	logger.Debugf("notify namenode\n")
+	args := namenode.NotifyArgs{Token: c.Token}
+	for addr := range c.sentTo {
+		args.Addrs = append(args.Addrs, addr)
+	}
+	c.sentTo = make(map[string]bool)
+	return c.callNameNode(utils.RPCTimeout(), func(ctx context.Context, nn gdfspb.NameNodeClient) error {
+		_, err := nn.Notify(ctx, args.ToPB())
+		return err
+	})
+}
+
+// CalMeanVar computes mean and variance of the numbers in a dfs file, one
+// per line
+func (c *Client) CalMeanVar(path string) (string, error) {
+	args := namenode.CommandArgs{CommandType: config.CalMeanVar, DPath: path}
+	reply, err := c.run(&args)
+	if err != nil {
+		return "", err
+	}
+	return reply.Result, nil
+}
+
+// WordCount counts the occurrences of each white space separated word of
+// a dfs file
+func (c *Client) WordCount(path string) (map[string]int, error) {
+	args := namenode.CommandArgs{CommandType: config.WordCount, DPath: path}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, err
+	}
+	return reply.WordCounts, nil
+}
+
+// Grep returns the lines of a dfs file matching the regular expression
+// pattern, in order, and their number. With ignoreCase, letters match
+// regardless of case. With countOnly, only the number is returned.
+func (c *Client) Grep(path, pattern string, ignoreCase, countOnly bool) ([]string, int, error) {
+	args := namenode.CommandArgs{CommandType: config.Grep, DPath: path,
+		Pattern: pattern, IgnoreCase: ignoreCase, CountOnly: countOnly}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reply.Lines, reply.NumLines, nil
+}
+
+// TopN returns the k greatest lines of a dfs file compared as integers,
+// greatest first, skipping the blank and non-numeric ones. With lexical,
+// the lines are compared as strings. Equal lines are all kept, fewer than
+// k lines are returned if the file has not enough of them.
+func (c *Client) TopN(path string, k int, lexical bool) ([]string, error) {
+	args := namenode.CommandArgs{CommandType: config.TopN, DPath: path, K: k,
+		Lexical: lexical}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Lines, nil
+}
+
+// RunJob runs the mapreduce job registered as job over a dfs file, the
+// job must be registered in namenode and datanodes, see mapreduce.Register
+func (c *Client) RunJob(job, path string) (mapreduce.Result, error) {
+	args := namenode.CommandArgs{CommandType: config.RunJob, DPath: path, Job: job}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, err
+	}
+	return reply.JobResult, nil
+}
+
+// RunDistJob runs the mapreduce job registered as job over a dfs file with
+// numReduce reduce tasks on datanodes, their outputs are the files of the
+// new dfs directory output whose names are returned
+func (c *Client) RunDistJob(job, path, output string, numReduce int) ([]string, error) {
+	if numReduce <= 0 {
+		return nil, errors.New("Number of reduce tasks should be positive")
+	}
+	args := namenode.CommandArgs{CommandType: config.RunJob, DPath: path, Job: job,
+		NumReduce: numReduce, Output: output}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Files, nil
+}
+
+// SubmitJob starts the mapreduce job registered as job over a dfs file
+// and returns its id without waiting for it, see JobStatus. With numReduce
+// set, the job runs like RunDistJob.
+func (c *Client) SubmitJob(job, path, output string, numReduce int) (string, error) {
+	args := namenode.CommandArgs{CommandType: config.RunJob, DPath: path, Job: job,
+		NumReduce: numReduce, Output: output, Timeout: c.Timeout, Token: c.Token}
+	reply := namenode.SubmitJobReply{}
+	err := c.callNameNode(utils.RPCTimeout(), func(ctx context.Context, nn gdfspb.NameNodeClient) error {
+		m, err := nn.SubmitJob(ctx, args.ToPB())
+		reply.FromPB(m)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return reply.JobID, nil
+}
+
+// JobStatus returns the progress of a job, and its result once done
+func (c *Client) JobStatus(jobID string) (*namenode.JobStatus, error) {
+	args := namenode.JobArgs{JobID: jobID, Token: c.Token}
+	reply := namenode.JobStatus{}
+	err := c.callNameNode(utils.RPCTimeout(), func(ctx context.Context, nn gdfspb.NameNodeClient) error {
+		m, err := nn.JobStatus(ctx, args.ToPB())
+		reply.FromPB(m)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// CancelJob cancels a job and returns its status
+func (c *Client) CancelJob(jobID string) (*namenode.JobStatus, error) {
+	args := namenode.JobArgs{JobID: jobID, Token: c.Token}
+	reply := namenode.JobStatus{}
+	err := c.callNameNode(utils.RPCTimeout(), func(ctx context.Context, nn gdfspb.NameNodeClient) error {
+		m, err := nn.CancelJob(ctx, args.ToPB())
+		reply.FromPB(m)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ClusterStatus returns the storage of the cluster and its live datanodes
+func (c *Client) ClusterStatus() (*namenode.ClusterStatusReply, error) {
+	args := namenode.AdminArgs{Token: c.Token}
+	reply := namenode.ClusterStatusReply{}
+	err := c.callNameNode(utils.RPCTimeout(), func(ctx context.Context, nn gdfspb.NameNodeClient) error {
+		m, err := nn.ClusterStatus(ctx, args.ToPB())
+		reply.FromPB(m)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ReportNodes returns the state and storage of every datanode
+func (c *Client) ReportNodes() ([]namenode.NodeStatus, error) {
+	args := namenode.AdminArgs{Token: c.Token}
+	reply := namenode.ReportNodesReply{}
+	err := c.callNameNode(utils.RPCTimeout(), func(ctx context.Context, nn gdfspb.NameNodeClient) error {
+		m, err := nn.ReportNodes(ctx, args.ToPB())
+		reply.FromPB(m)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Nodes, nil
+}
+
+// Cat writes the content of a dfs file to w
+func (c *Client) Cat(path string, w io.Writer) error {
+	args := namenode.CommandArgs{CommandType: config.Cat, DPath: path}
+	reply, err := c.run(&args)
+	if err != nil {
+		return err
+	}
+	// blocks are written in order
+	for _, seg := range reply.BlkList {
+		data, err := c.readAnyReplica(seg, reply.BlkToDataNodes[seg])
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PreviewSize is the number of bytes returned by Head and Tail
+const PreviewSize = 1024
+
+// Head returns the leading PreviewSize bytes of a dfs file
+func (c *Client) Head(path string) ([]byte, error) {
+	return c.preview(path, true)
+}
+
+// Tail returns the trailing PreviewSize bytes of a dfs file
+func (c *Client) Tail(path string) ([]byte, error) {
+	return c.preview(path, false)
+}
+
+// preview returns the leading (head) or trailing (!head) bytes of a dfs
+// file, only the blocks holding them are fetched from datanodes: the first
+// or the last one, and the ones next to it while they are shorter than
+// PreviewSize together
+func (c *Client) preview(path string, head bool) ([]byte, error) {
+	args := namenode.CommandArgs{CommandType: config.Cat, DPath: path}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, err
+	}
+	data := []byte{}
+	for i := range reply.BlkList {
+		if len(data) >= PreviewSize {
+			break
+		}
+		seg := reply.BlkList[len(reply.BlkList)-1-i]
+		if head {
+			seg = reply.BlkList[i]
+		}
+		blk, err := c.readAnyReplica(seg, reply.BlkToDataNodes[seg])
+		if err != nil {
+			return nil, err
+		}
+		if head {
+			data = append(data, blk...)
+		} else {
+			data = append(append([]byte{}, blk...), data...)
+		}
+	}
+	if len(data) > PreviewSize {
+		if head {
+			data = data[:PreviewSize]
+		} else {
+			data = data[len(data)-PreviewSize:]
+		}
+	}
+	return data, nil
+}
+
+// CopyFromLocal uploads a local file into the dfs directory dst with the
+// default block size and replication factor
+func (c *Client) CopyFromLocal(local, dst string) error {
+	return c.CopyFromLocalOpts(local, dst, WriteOptions{})
+}
+
+// CopyFromLocalOpts uploads a local file into the dfs directory dst. A
+// local directory is uploaded with everything below it as dst/<its name>.
+func (c *Client) CopyFromLocalOpts(local, dst string, opts WriteOptions) error {
+	fileinfo, err := os.Stat(local)
+	if err != nil {
+		return err
+	}
+	if fileinfo.IsDir() {
+		return c.copyDirFromLocal(local, dst, opts)
+	}
+	return c.copyFileFromLocal(local, fileinfo, dst, opts)
+}
+
+// copyDirFromLocal recreates the tree of the local directory under the
+// dfs directory dst, empty directories included, and uploads its files
+func (c *Client) copyDirFromLocal(local, dst string, opts WriteOptions) error {
+	local, err := filepath.Abs(local)
+	if err != nil {
+		return err
+	}
+	root := filepath.Dir(local)
+	return filepath.Walk(local, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		target := path.Join(dst, filepath.ToSlash(rel))
+		switch {
+		case info.IsDir():
+			return c.Mkdir(target, true)
+		case info.Mode().IsRegular():
+			return c.copyFileFromLocal(p, info, path.Dir(target), opts)
+		}
+		logger.Warnf("skip %v, not a regular file\n", p)
+		return nil
+	})
+}
+
+// copyFileFromLocal uploads the local file described by fileinfo into the
+// dfs directory dst. An upload which failed before is resumed, sending
+// only the blocks missing, see manifest.
+func (c *Client) copyFileFromLocal(local string, fileinfo os.FileInfo, dst string, opts WriteOptions) error {
+	file, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	t := transferThrottler(opts.BytesPerSec)
+	m, resumed, err := openManifest(local, fileinfo, path.Join(c.Abs(dst), fileinfo.Name()))
+	if err != nil {
+		return err
+	}
+	if resumed {
+		reply, err := c.resumeUpload(m)
+		if err != nil {
+			return err
+		}
+		if reply != nil {
+			logger.Infof("resume upload of %v to %v, %v of %v blocks left\n", local, m.Path,
+				len(reply.BlkToDataNodes), len(reply.BlkList))
+			return c.uploadBlks(file, m, reply, t)
+		}
+	}
+	/** namenode names the segments of the file and places them on
+	 * datanodes, only FileSize matters to it:
+	 * 	segname0: [node0, node1, node2]
+	 * 	segname1: [node1, node3, node4]
+	 * 	...
+	 * Then the client does the actual data splitting into segments of the
+	 * block size and sends each of them to its datanodes.
+	 * */
+	args := namenode.CommandArgs{}
+	args.CommandType = config.CopyFromLocal
+	args.DPath = dst
+	args.FileSize = fileinfo.Size()
+	args.FileName = fileinfo.Name()
+	args.BlockSize = opts.BlockSize
+	args.Replication = opts.Replication
+	args.Overwrite = opts.Overwrite
+	reply, err := c.run(&args)
+	if err != nil {
+		return err
+	}
+	m.BlkList, m.BlkSize = reply.BlkList, reply.BlkSize
+	m.Acked = make(map[string]ackedBlk)
+	if len(m.BlkList) > 0 {
+		if err := m.save(); err != nil {
+			logger.Warnf("cannot record the upload of %v: %v\n", local, err)
+		}
+	}
+	return c.uploadBlks(file, m, reply, t)
+}
+
+// AppendToFile appends local files, concatenated in order, to the end of
+// the dfs file dst
+func (c *Client) AppendToFile(locals []string, dst string) error {
+	// the namenode only needs to know the total size
+	totalSize := int64(0)
+	readers := make([]io.Reader, 0)
+	for _, local := range locals {
+		fileinfo, err := os.Stat(local)
+		if err != nil {
+			return err
+		}
+		totalSize += fileinfo.Size()
+		file, err := os.Open(local)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		readers = append(readers, file)
+	}
+	args := namenode.CommandArgs{}
+	args.CommandType = config.AppendToFile
+	args.DPath = dst
+	args.FileSize = totalSize
+	reply, err := c.run(&args)
+	if err != nil {
+		return err
+	}
+	return c.writeBlks(reply, io.MultiReader(readers...), transferThrottler(0))
+}
+
+// writeBlks streams the blocks allocated in reply from r at the rate of t,
+// then notifies namenode. Namenode learns the new replicas from block
+// reports, since the transfer to a datanode may fail.
+func (c *Client) writeBlks(reply *namenode.CommandReply, r io.Reader, t *utils.Throttler) error {
+	logger.Debugf("reply from server (segment name: [list of nodes]):\n")
+	for _, seg := range reply.BlkList {
+		logger.Debugf("%v: %v\n", seg, reply.BlkToDataNodes[seg])
+	}
+	for _, blkID := range reply.BlkList {
+		addrs := reply.BlkToDataNodes[blkID]
+		for _, addr := range addrs {
+			c.sentTo[addr] = true
+		}
+		// only a chunk of the block is held in memory
+		_, err := c.streamBlk(blkID, reply.GenStamp, io.LimitReader(r, reply.BlkSize), addrs, t)
+		if err != nil {
+			return err
+		}
+	}
+	return c.notify()
+}
+
+// CopyToLocal downloads the dfs file src to the local path local
+func (c *Client) CopyToLocal(src, local string) error {
+	args := namenode.CommandArgs{CommandType: config.CopyToLocal, DPath: src}
+	reply, err := c.run(&args)
+	if err != nil {
+		return err
+	}
+	/** Blocks are downloaded config.ParallelBlkReads at a time, consecutive
+	 * blocks on the same datanode in a single request, see blkBatches. For
+	 * each block:
+	 * 	1. request it from one of its datanodes
+	 * 	2. compare the checksum of the received data with the one sent
+	 * 	   along, on mismatch, failure or timeout request another datanode
+	 * 	3. write the intact block at its offset in a temp file, blocks of
+	 * 	   an appended file may be partial anywhere, so block i starts
+	 * 	   after the lengths of the blocks before it
+	 * Once all blocks are in, the digest of the file is checked, then the
+	 * temp file is renamed to local. A failed download leaves local
+	 * untouched.
+	 * */
+	var sums []utils.Sum
+	if reply.Digest != "" {
+		if err := utils.CheckChecksumType(reply.DigestType); err != nil {
+			return err
+		}
+		sums = make([]utils.Sum, len(reply.BlkList))
+	}
+	tmp := local + utils.TmpSuffix
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	err = c.fetchBlks(file, reply, sums)
+	if err == nil {
+		err = checkDigest(src, reply, sums)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, local)
+}
+
+// fetchBlks downloads the blocks in reply concurrently and writes each one
+// at its offset in file, the first error stops the download. Unless sums
+// is nil, the reply.DigestType checksum of block i is stored in sums[i].
+func (c *Client) fetchBlks(file *os.File, reply *namenode.CommandReply, sums []utils.Sum) error {
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	stop := make(chan struct{})
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			close(stop)
+		})
+	}
+	sem := make(chan struct{}, config.ParallelBlkReads)
+	if len(reply.BlkLengths) != len(reply.BlkList) {
+		return fmt.Errorf("%v block lengths for %v blocks", len(reply.BlkLengths),
+			len(reply.BlkList))
+	}
+	offsets := make([]int64, len(reply.BlkList))
+	for i := 1; i < len(offsets); i++ {
+		offsets[i] = offsets[i-1] + reply.BlkLengths[i-1]
+	}
+	start := 0
+loop:
+	for _, batch := range blkBatches(reply.BlkList, reply.BlkToDataNodes) {
+		select {
+		case sem <- struct{}{}:
+		case <-stop:
+			break loop
+		}
+		wg.Add(1)
+		go func(start int, batch []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			blks, err := c.readBatch(batch, reply.BlkToDataNodes)
+			if err != nil {
+				fail(err)
+				return
+			}
+			for j, data := range blks {
+				i := start + j
+				if int64(len(data)) != reply.BlkLengths[i] {
+					fail(fmt.Errorf("block %v has %v bytes, expected %v", batch[j],
+						len(data), reply.BlkLengths[i]))
+					return
+				}
+				if sums != nil {
+					sums[i] = utils.ChecksumOf(reply.DigestType, data)
+				}
+				_, err = file.WriteAt(data, offsets[i])
+				if err != nil {
+					fail(err)
+					return
+				}
+			}
+		}(start, batch)
+		start += len(batch)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// Cp deep copies the dfs file src to dst, which may be a directory
+func (c *Client) Cp(src, dst string, overwrite bool) error {
+	args := namenode.CommandArgs{}
+	args.CommandType = config.Cp
+	args.DPaths = []string{src, dst}
+	args.Overwrite = overwrite
+	reply, err := c.run(&args)
+	if err != nil {
+		return fmt.Errorf("cp: %v: %w", src, err)
+	}
+	logger.Infof("%v\n", reply.Result)
+	// source and new segments are in the same order
+	for i, seg := range reply.SrcBlkList {
+		data, err := c.readAnyReplica(seg, reply.BlkToDataNodes[seg])
+		if err != nil {
+			return err
+		}
+		blkID := reply.BlkList[i]
+		addrs := reply.BlkToDataNodes[blkID]
+		for _, addr := range addrs {
+			c.sentTo[addr] = true
+		}
+		err = c.sendBlk(blkID, reply.GenStamp, data, addrs, transferThrottler(0))
+		if err != nil {
+			return err
+		}
+	}
+	return c.notify()
+}
+
+// Mv moves srcs to dst, with several sources dst should be a directory
+func (c *Client) Mv(srcs []string, dst string) error {
+	args := namenode.CommandArgs{CommandType: config.Mv}
+	args.DPaths = append(append([]string{}, srcs...), dst)
+	reply, err := c.run(&args)
+	if err != nil {
+		return err
+	}
+	return checkErrors("mv", args.DPaths[:len(srcs)], reply.Errors)
+}
+
+// Ls lists the names in a dfs directory, sorted
+func (c *Client) Ls(path string) ([]string, error) {
+	args := namenode.CommandArgs{CommandType: config.Ls, DPath: path}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Files, nil
+}
+
+// List returns the metadata of the entries of a dfs directory, with
+// recursive of every entry below it, directories before their contents
+func (c *Client) List(path string, recursive bool) ([]namenode.FileStat, error) {
+	args := namenode.CommandArgs{CommandType: config.Ls, DPath: path,
+		Recursive: recursive, Long: true}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Stats, nil
+}
+
+// Du returns the space used by each entry of a dfs directory followed by
+// the total of the directory, or just the usage of a file
+func (c *Client) Du(path string) ([]namenode.DiskUsage, error) {
+	args := namenode.CommandArgs{CommandType: config.Du, DPath: path}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Usage, nil
+}
+
+// Fsck checks the health of the blocks of the dfs files under path
+func (c *Client) Fsck(path string) (*namenode.FsckReport, error) {
+	args := namenode.CommandArgs{CommandType: config.Fsck, DPath: path}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Fsck, nil
+}
+
+// SetQuota limits the number of files and directories under dfs directory
+// path, itself included, 0 removes the limit
+func (c *Client) SetQuota(path string, quota int64) error {
+	_, err := c.run(&namenode.CommandArgs{CommandType: config.SetQuota, DPath: path,
+		Quota: quota})
+	return err
+}
+
+// SetSpaceQuota limits the bytes of the files under dfs directory path
+// times their replication factor, 0 removes the limit
+func (c *Client) SetSpaceQuota(path string, bytes int64) error {
+	_, err := c.run(&namenode.CommandArgs{CommandType: config.SetSpaceQuota, DPath: path,
+		Quota: bytes})
+	return err
+}
+
+// Count returns the number of directories, files and bytes under a dfs
+// path with its quotas
+func (c *Client) Count(path string) (*namenode.DirCount, error) {
+	reply, err := c.run(&namenode.CommandArgs{CommandType: config.Count, DPath: path})
+	if err != nil {
+		return nil, err
+	}
+	return &reply.Counts[0], nil
+}
+
+// Mkdir creates a dfs directory, with parents its missing ancestors too
+func (c *Client) Mkdir(path string, parents bool) error {
+	args := namenode.CommandArgs{CommandType: config.Mkdir, DPath: path}
+	if parents {
+		args.CommandType = config.MkdirP
+	}
+	_, err := c.run(&args)
+	return err
+}
+
+// Rm removes dfs files, a failure on one path doesn't stop the others.
+// Paths may be glob patterns, see Remove.
+func (c *Client) Rm(paths ...string) error {
+	_, _, err := c.Remove(false, paths...)
+	return err
+}
+
+// Remove removes dfs files like Rm. The patterns among paths are expanded
+// by namenode, the paths removed are returned. With the trash enabled on
+// namenode, files are moved into it unless skipTrash is set, the paths
+// they were moved to are returned keyed by their old path.
+func (c *Client) Remove(skipTrash bool, paths ...string) ([]string, map[string]string, error) {
+	args := namenode.CommandArgs{CommandType: config.Rm, DPaths: paths,
+		SkipTrash: skipTrash}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+	removed := make([]string, 0, len(reply.Paths))
+	for _, p := range reply.Paths {
+		if _, ok := reply.Errors[p]; !ok {
+			removed = append(removed, p)
+		}
+	}
+	return removed, reply.Trashed, checkErrors("rm", reply.Paths, reply.Errors)
+}
+
+// Expunge deletes everything in the trash of the user right away
+func (c *Client) Expunge() (string, error) {
+	reply, err := c.run(&namenode.CommandArgs{CommandType: config.Expunge})
+	if err != nil {
+		return "", err
+	}
+	return reply.Result, nil
+}
+
+// Rmdir removes dfs directories
+func (c *Client) Rmdir(paths ...string) error {
+	args := namenode.CommandArgs{CommandType: config.Rmdir, DPaths: paths}
+	_, err := c.run(&args)
+	return err
+}
+
+// Touch creates empty dfs files
+func (c *Client) Touch(paths ...string) error {
+	args := namenode.CommandArgs{CommandType: config.Touch, DPaths: paths}
+	reply, err := c.run(&args)
+	if err != nil {
+		return err
+	}
+	return checkErrors("touch", args.DPaths, reply.Errors)
+}
+
+// Stat returns metadata of the existing paths, in argument order
+func (c *Client) Stat(paths ...string) ([]namenode.FileStat, error) {
+	args := namenode.CommandArgs{CommandType: config.Stat, DPaths: paths}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Stats, checkErrors("stat", reply.Paths, reply.Errors)
+}
+
+// SetRep changes the replication factor of a dfs file
+func (c *Client) SetRep(path string, rep int) error {
+	args := namenode.CommandArgs{CommandType: config.SetRep, DPath: path}
+	args.Replication = rep
+	reply, err := c.run(&args)
+	if err != nil {
+		return fmt.Errorf("setrep: %v: %w", path, err)
+	}
+	logger.Infof("%v\n", reply.Result)
+	return nil
+}
+
+// Truncate cuts dfs file path to its first length bytes
+func (c *Client) Truncate(path string, length int64) error {
+	args := namenode.CommandArgs{CommandType: config.Truncate, DPath: path}
+	args.FileSize = length
+	reply, err := c.run(&args)
+	if err != nil {
+		return fmt.Errorf("truncate: %v: %w", path, err)
+	}
+	logger.Infof("%v\n", reply.Result)
+	return nil
+}
+
+// Checksum returns the MD5 of the block checksums of dfs files keyed by path,
+// MD5-of-CRC32 for files written with crc32 checksums
+func (c *Client) Checksum(paths ...string) (map[string]string, error) {
+	args := namenode.CommandArgs{CommandType: config.Checksum, DPaths: paths}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Checksums, checkErrors("checksum", reply.Paths, reply.Errors)
+}
+
+// Balance asks namenode to plan block moves across datanodes
+func (c *Client) Balance() (string, error) {
+	reply, err := c.run(&namenode.CommandArgs{CommandType: config.Balance})
+	if err != nil {
+		return "", err
+	}
+	return reply.Result, nil
+}
+
+// Decommission starts retiring the datanode at addr, see -dfsadmin -report
+// for its progress
+func (c *Client) Decommission(addr string) (string, error) {
+	reply, err := c.run(&namenode.CommandArgs{CommandType: config.Decommission, Addr: addr})
+	if err != nil {
+		return "", err
+	}
+	return reply.Result, nil
+}
+
+// Format erases the whole dfs
+func (c *Client) Format() error {
+	_, err := c.run(&namenode.CommandArgs{CommandType: config.Format})
+	return err
+}
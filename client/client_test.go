@@ -0,0 +1,1480 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/datanode"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/mapreduce"
+	"github.com/WineChord/gdfs/namenode"
+	"github.com/WineChord/gdfs/utils"
+	"google.golang.org/grpc"
+)
+
+func TestMain(m *testing.M) {
+	// client, namenode and datanodes log every call
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// serve serves the gRPC services registered by register on an ephemeral
+// port of localhost and returns the address
+func serve(t *testing.T, register func(s *grpc.Server)) string {
+	t.Helper()
+	serv := utils.NewServer()
+	register(serv)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go serv.Serve(l)
+	t.Cleanup(serv.Stop)
+	return l.Addr().String()
+}
+
+// testCluster is a namenode and datanodes running in process, each
+// datanode keeps its blocks in its own directory
+type testCluster struct {
+	t     *testing.T
+	n     *namenode.NameNode
+	nodes []*testDataNode
+	c     *Client
+}
+
+// testDataNode is a datanode which can be made to misbehave when serving
+// blocks to client
+type testDataNode struct {
+	*datanode.DataNode
+	mu      sync.Mutex
+	delay   time.Duration // before serving a block or its statistics
+	corrupt bool          // flip a byte of the blocks served and received
+	// downstream holds the number of targets of each chunk received
+	downstream []int
+	// failAfter fails the chunks received once that many were, 0 never
+	failAfter int
+	// swapped maps blocks to the block served in their place
+	swapped map[string]string
+	// batches holds the number of blocks of each RequestBlks served
+	batches []int
+}
+
+func (d *testDataNode) misbehave(delay time.Duration, corrupt bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.delay, d.corrupt = delay, corrupt
+}
+
+// MapBlk answers like a datanode, slowly if asked to
+func (d *testDataNode) MapBlk(args *mapreduce.MapArgs, reply *mapreduce.MapReply) error {
+	d.mu.Lock()
+	delay := d.delay
+	d.mu.Unlock()
+	time.Sleep(delay)
+	return d.DataNode.MapBlk(args, reply)
+}
+
+// SendBlkChunk receives a chunk like a datanode, corrupted if asked to
+func (d *testDataNode) SendBlkChunk(args *utils.BlkChunk, reply *datanode.SendBlkReply) error {
+	d.mu.Lock()
+	d.downstream = append(d.downstream, len(args.Targets))
+	corrupt := d.corrupt
+	fail := d.failAfter > 0 && len(d.downstream) > d.failAfter
+	d.mu.Unlock()
+	if fail {
+		return errors.New("Interrupted")
+	}
+	if corrupt && len(args.Data) > 0 {
+		args.Data[0]++
+	}
+	return d.DataNode.SendBlkChunk(args, reply)
+}
+
+// RequestBlk serves a block like a datanode, slowly or corrupted if asked to
+func (d *testDataNode) RequestBlk(args *datanode.RequestBlkArgs, reply *utils.BlkData) error {
+	d.mu.Lock()
+	delay, corrupt := d.delay, d.corrupt
+	if blk, ok := d.swapped[args.BlkID]; ok {
+		args.BlkID = blk
+	}
+	d.mu.Unlock()
+	time.Sleep(delay)
+	err := d.DataNode.RequestBlk(args, reply)
+	if err == nil && corrupt && len(reply.Data) > 0 {
+		reply.Data[0]++
+	}
+	return err
+}
+
+// RequestBlks serves blocks like a datanode, each as RequestBlk does
+func (d *testDataNode) RequestBlks(args *datanode.RequestBlksArgs, reply *datanode.RequestBlksReply) error {
+	d.mu.Lock()
+	d.batches = append(d.batches, len(args.BlkIDs))
+	d.mu.Unlock()
+	reply.Blks = make([]utils.BlkData, len(args.BlkIDs))
+	reply.Errors = make([]string, len(args.BlkIDs))
+	for i, blk := range args.BlkIDs {
+		err := d.RequestBlk(&datanode.RequestBlkArgs{BlkID: blk, Token: args.Token},
+			&reply.Blks[i])
+		if err != nil {
+			reply.Errors[i] = err.Error()
+		}
+	}
+	return nil
+}
+
+func startCluster(t *testing.T, numNodes int) *testCluster {
+	t.Helper()
+	chdir(t, t.TempDir())
+	tc := &testCluster{t: t, n: namenode.NewNameNode()}
+	c, err := New(serve(t, func(s *grpc.Server) { namenode.RegisterServer(s, tc.n) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	tc.c = c
+	dirs := config.DataDirs
+	defer func() { config.DataDirs = dirs }()
+	for i := 0; i < numNodes; i++ {
+		// each datanode has a volume of its own
+		config.DataDirs = []string{fmt.Sprintf("dn%v", i)}
+		d := datanode.NewDataNode()
+		td := &testDataNode{DataNode: d}
+		d.Addr = serve(t, func(s *grpc.Server) { datanode.RegisterServer(s, td) })
+		args := namenode.RegisterArgs{HostName: d.Addr, Addr: d.Addr}
+		if err := tc.n.Register(&args, &namenode.RegisterReply{}); err != nil {
+			t.Fatal(err)
+		}
+		tc.nodes = append(tc.nodes, td)
+	}
+	return tc
+}
+
+// report sends a block report of every datanode, as they would do when
+// namenode asks them to after a write
+func (tc *testCluster) report() {
+	tc.t.Helper()
+	for _, d := range tc.nodes {
+		args := namenode.ReportBlockArgs{HostName: d.Addr, Addr: d.Addr,
+			IDToMetaData: d.IDToMetaData, Token: config.AuthToken}
+		if err := tc.n.ReportBlock(&args, &namenode.ReportBlockReply{}); err != nil {
+			tc.t.Fatal(err)
+		}
+	}
+}
+
+// writeLocal creates a local file of size random bytes
+func writeLocal(t *testing.T, name string, size int) []byte {
+	t.Helper()
+	data := make([]byte, size)
+	rand.New(rand.NewSource(int64(size))).Read(data)
+	if err := ioutil.WriteFile(name, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestRoundTrip(t *testing.T) {
+	tc := startCluster(t, 3)
+	c := tc.c
+	data := writeLocal(t, "f.bin", 2500)
+	if err := c.Mkdir("/a/b", true); err != nil {
+		t.Fatal(err)
+	}
+	err := c.CopyFromLocalOpts("f.bin", "/a/b", WriteOptions{BlockSize: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	files, err := c.Ls("/a/b")
+	if err != nil || !reflect.DeepEqual(files, []string{"f.bin"}) {
+		t.Fatalf("ls /a/b = %q, %v, want [f.bin]", files, err)
+	}
+	if err := c.CopyToLocal("/a/b/f.bin", "back.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data) {
+		t.Errorf("copyToLocal got %v bytes differing from the %v uploaded",
+			len(got), len(data))
+	}
+	r, err := c.Open("/a/b/f.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if got, err := ioutil.ReadAll(r); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("reading the opened file got %v bytes, %v", len(got), err)
+	}
+	// blocks are shorter than PreviewSize, head and tail read two of them
+	head, err := c.Head("/a/b/f.bin")
+	if err != nil || !bytes.Equal(head, data[:PreviewSize]) {
+		t.Errorf("head got %v bytes, %v", len(head), err)
+	}
+	tail, err := c.Tail("/a/b/f.bin")
+	if err != nil || !bytes.Equal(tail, data[len(data)-PreviewSize:]) {
+		t.Errorf("tail got %v bytes, %v", len(tail), err)
+	}
+	stats, err := c.Stat("/a/b/f.bin")
+	if err != nil || len(stats) != 1 || stats[0].Size != 2500 || stats[0].NumBlks != 3 {
+		t.Errorf("stat = %+v, %v, want 2500 bytes in 3 blocks", stats, err)
+	}
+}
+
+// TestPreviewShortBlocks previews files whose first or last block is
+// shorter than PreviewSize
+func TestPreviewShortBlocks(t *testing.T) {
+	tc := startCluster(t, 1)
+	c := tc.c
+	for _, tt := range []struct {
+		size, blkSize int
+	}{
+		{2004, 1000}, // the last block holds 4 bytes
+		{1500, 300},  // head and tail span several blocks
+		{700, 300},   // the file is shorter than PreviewSize
+	} {
+		name := fmt.Sprintf("f%v.bin", tt.size)
+		data := writeLocal(t, name, tt.size)
+		opts := WriteOptions{BlockSize: int64(tt.blkSize), Replication: 1}
+		if err := c.CopyFromLocalOpts(name, "/", opts); err != nil {
+			t.Fatal(err)
+		}
+		tc.report()
+		n := PreviewSize
+		if n > len(data) {
+			n = len(data)
+		}
+		if head, err := c.Head("/" + name); err != nil || !bytes.Equal(head, data[:n]) {
+			t.Errorf("head of %v got %v bytes, %v, want %v", name, len(head), err, n)
+		}
+		if tail, err := c.Tail("/" + name); err != nil || !bytes.Equal(tail, data[len(data)-n:]) {
+			t.Errorf("tail of %v got %v bytes, %v, want %v", name, len(tail), err, n)
+		}
+	}
+}
+
+func TestCopyToLocalAppended(t *testing.T) {
+	tc := startCluster(t, 3)
+	c := tc.c
+	data := writeLocal(t, "f.bin", 2500)
+	if err := c.CopyFromLocalOpts("f.bin", "/", WriteOptions{BlockSize: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	// each append starts a new block, leaving partial blocks in the middle
+	var more []byte
+	for _, size := range []int{6, 1200} {
+		name := fmt.Sprintf("more%v.bin", size)
+		more = append(more, writeLocal(t, name, size)...)
+		if err := c.AppendToFile([]string{name}, "/f.bin"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tc.report()
+	want := append(data, more...)
+	if err := c.CopyToLocal("/f.bin", "back.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, want) {
+		t.Errorf("copyToLocal got %v bytes differing from the %v written",
+			len(got), len(want))
+	}
+}
+
+func TestShortReadsRoundTrip(t *testing.T) {
+	tc := startCluster(t, 2)
+	c := tc.c
+	// neither the block nor the file size is a multiple of the chunk size
+	data := writeLocal(t, "f.bin", 2*config.ChunkSize+777)
+	args := namenode.CommandArgs{CommandType: config.CopyFromLocal, DPath: "/",
+		FileName: "f.bin", FileSize: int64(len(data)),
+		BlockSize: int64(config.ChunkSize + 100), Replication: 2}
+	reply, err := c.run(&args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a reader returning half of what is asked for, as a file may
+	r := iotest.HalfReader(bytes.NewReader(data))
+	if err := c.writeBlks(reply, r, transferThrottler(0)); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	if err := c.CopyToLocal("/f.bin", "back.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data) {
+		t.Errorf("copyToLocal got %v bytes differing from the %v uploaded",
+			len(got), len(data))
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tc := startCluster(t, 1)
+	c := tc.c
+	if _, err := c.Ls("/missing"); !IsNotFound(err) {
+		t.Errorf("ls of a missing dir = %v, want not found", err)
+	}
+	if err := c.CopyFromLocal("missing.txt", "/"); !IsNotFound(err) {
+		t.Errorf("copyFromLocal of a missing local file = %v, want not found", err)
+	}
+	if err := c.Touch("/a"); err != nil {
+		t.Fatal(err)
+	}
+	err := c.Rm("/a", "/missing")
+	var perrs PathErrors
+	if !errors.As(err, &perrs) || perrs.Failed("/a") || !perrs.Failed("/missing") {
+		t.Errorf("rm of an existing and a missing file = %v", err)
+	}
+	if files, _ := c.Ls("/"); len(files) != 0 {
+		t.Errorf("ls / = %q after rm, want none", files)
+	}
+	var ce *ConnError
+	if _, err := New("127.0.0.1:1"); !errors.As(err, &ce) {
+		t.Errorf("connecting to a closed port = %v, want a ConnError", err)
+	}
+}
+
+func TestCopyFromLocalOverwrite(t *testing.T) {
+	tc := startCluster(t, 2)
+	c := tc.c
+	writeLocal(t, "f.bin", 2500)
+	opts := WriteOptions{BlockSize: 1000, Replication: 2}
+	if err := c.CopyFromLocalOpts("f.bin", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	old := make(map[string]bool)
+	for blk := range tc.nodes[0].IDToMetaData {
+		old[blk] = true
+	}
+	if len(old) != 3 {
+		t.Fatalf("datanode holds %v blocks, want 3", len(old))
+	}
+	err := c.CopyFromLocalOpts("f.bin", "/", opts)
+	if err == nil || !strings.Contains(err.Error(), "File exists") {
+		t.Errorf("copyFromLocal onto an existing file = %v, want File exists", err)
+	}
+	data := writeLocal(t, "f.bin", 1500)
+	opts.Overwrite = true
+	if err := c.CopyFromLocalOpts("f.bin", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	if err := c.CopyToLocal("/f.bin", "back.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data) {
+		t.Errorf("copyToLocal got %v bytes differing from the %v overwritten",
+			len(got), len(data))
+	}
+	// the blocks of the replaced file are deleted from the datanodes
+	for _, d := range tc.nodes {
+		if len(d.IDToMetaData) != 2 {
+			t.Errorf("datanode %v holds %v blocks, want 2", d.Addr, len(d.IDToMetaData))
+		}
+		for blk := range d.IDToMetaData {
+			if old[blk] {
+				t.Errorf("block %v of the replaced file is left on %v", blk, d.Addr)
+			}
+		}
+	}
+}
+
+func TestCopyDirFromLocal(t *testing.T) {
+	tc := startCluster(t, 2)
+	c := tc.c
+	for _, dir := range []string{"tree/sub/deeper", "tree/empty"} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string][]byte{
+		"tree/a.txt":            writeLocal(t, "tree/a.txt", 1500),
+		"tree/sub/b.txt":        writeLocal(t, "tree/sub/b.txt", 10),
+		"tree/sub/deeper/c.txt": writeLocal(t, "tree/sub/deeper/c.txt", 0),
+		"tree/sub/deeper/d.txt": writeLocal(t, "tree/sub/deeper/d.txt", 2000),
+	}
+	if err := c.Mkdir("/up", false); err != nil {
+		t.Fatal(err)
+	}
+	err := c.CopyFromLocalOpts("tree", "/up", WriteOptions{BlockSize: 1000,
+		Replication: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	stats, err := c.List("/up", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, s := range stats {
+		got = append(got, fmt.Sprintf("%v %v", s.Path, s.IsDir))
+	}
+	want := []string{"/up/tree true", "/up/tree/a.txt false", "/up/tree/empty true",
+		"/up/tree/sub true", "/up/tree/sub/b.txt false", "/up/tree/sub/deeper true",
+		"/up/tree/sub/deeper/c.txt false", "/up/tree/sub/deeper/d.txt false"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ls -R /up = %q, want %q", got, want)
+	}
+	for name, data := range files {
+		if err := c.CopyToLocal(path.Join("/up", name), "back.bin"); err != nil {
+			t.Fatal(err)
+		}
+		if back, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(back, data) {
+			t.Errorf("%v got %v bytes differing from the %v uploaded", name,
+				len(back), len(data))
+		}
+		os.Remove("back.bin")
+	}
+}
+
+func TestCopyToLocalOneReplicaPerBlock(t *testing.T) {
+	tc := startCluster(t, 3)
+	c := tc.c
+	data := writeLocal(t, "f.bin", 3500)
+	opts := WriteOptions{BlockSize: 1000, Replication: 3}
+	if err := c.CopyFromLocalOpts("f.bin", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	if err := c.CopyToLocal("/f.bin", "back.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data) {
+		t.Fatalf("copyToLocal of 3 replicas wrote %v bytes, want the %v uploaded",
+			len(got), len(data))
+	}
+	// a block lost on every datanode fails the copy rather than leaving a
+	// hole in the local file
+	args := namenode.CommandArgs{CommandType: config.CopyToLocal, DPath: "/f.bin"}
+	reply, err := c.run(&args)
+	if err != nil || len(reply.BlkList) != 4 {
+		t.Fatalf("copyToLocal lists %v blocks, %v, want 4", len(reply.BlkList), err)
+	}
+	for _, d := range tc.nodes {
+		d.DeleteBlk(&utils.DeleteBlkArgs{BlkID: reply.BlkList[1]}, &utils.DeleteBlkReply{})
+	}
+	if err := c.CopyToLocal("/f.bin", "lost.bin"); err == nil {
+		t.Errorf("copyToLocal of a file with a lost block succeeded")
+	}
+	if _, err := os.Stat("lost.bin"); !os.IsNotExist(err) {
+		t.Errorf("a failed copyToLocal created the local file: %v", err)
+	}
+}
+
+func TestCopyToLocalFailover(t *testing.T) {
+	tc := startCluster(t, 3)
+	c := tc.c
+	data := writeLocal(t, "f.bin", 5500)
+	opts := WriteOptions{BlockSize: 1000, Replication: 3}
+	if err := c.CopyFromLocalOpts("f.bin", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	timeout := config.BlkReadTimeoutInSec
+	config.BlkReadTimeoutInSec = 1
+	defer func() { config.BlkReadTimeoutInSec = timeout }()
+	// every block has a replica on each datanode, only the last one is good
+	tc.nodes[0].misbehave(2*time.Second, false)
+	tc.nodes[1].misbehave(0, true)
+	if err := c.CopyToLocal("/f.bin", "back.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data) {
+		t.Errorf("copyToLocal got %v bytes differing from the %v uploaded",
+			len(got), len(data))
+	}
+
+	// without any intact replica, the local file is left untouched
+	tc.nodes[2].misbehave(0, true)
+	err := c.CopyToLocal("/f.bin", "back.bin")
+	if !errors.Is(err, ErrChecksum) && !errors.As(err, new(*ConnError)) {
+		t.Errorf("copyToLocal without intact replicas = %v", err)
+	}
+	if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data) {
+		t.Errorf("a failed copyToLocal changed the local file")
+	}
+	if _, err := os.Stat("back.bin" + utils.TmpSuffix); !os.IsNotExist(err) {
+		t.Errorf("a failed copyToLocal left its temp file: %v", err)
+	}
+}
+
+func TestCalMeanVarUnresponsiveDataNode(t *testing.T) {
+	tc := startCluster(t, 3)
+	c := tc.c
+	if err := ioutil.WriteFile("nums.txt", []byte("1\n2\n3\n4\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts := WriteOptions{BlockSize: 4, Replication: 3}
+	if err := c.CopyFromLocalOpts("nums.txt", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	timeout := config.BlkReadTimeoutInSec
+	config.BlkReadTimeoutInSec = 1
+	defer func() { config.BlkReadTimeoutInSec = timeout }()
+	tc.nodes[0].misbehave(time.Hour, false)
+	res, err := c.CalMeanVar("/nums.txt")
+	if err != nil || res != "mean: 2.5, variance: 1.25\n" {
+		t.Errorf("calMeanVar with a hung datanode = %q, %v", res, err)
+	}
+
+	// with every datanode hung, the command fails within its timeout
+	for _, d := range tc.nodes {
+		d.misbehave(time.Hour, false)
+	}
+	c.Timeout = 2 * time.Second
+	start := time.Now()
+	if _, err := c.CalMeanVar("/nums.txt"); err == nil {
+		t.Errorf("calMeanVar without any answering datanode succeeded")
+	}
+	if took := time.Since(start); took > c.Timeout+replyGrace {
+		t.Errorf("calMeanVar took %v, want at most %v", took, c.Timeout+replyGrace)
+	}
+}
+
+func TestWordCount(t *testing.T) {
+	tc := startCluster(t, 2)
+	c := tc.c
+	corpus := "the quick brown fox jumps over the lazy dog\n" +
+		"the dog barks\tand the fox runs away\n\n" +
+		"  supercalifragilisticexpialidocious  fox\n"
+	if err := ioutil.WriteFile("corpus.txt", []byte(corpus), 0600); err != nil {
+		t.Fatal(err)
+	}
+	want := make(map[string]int)
+	for _, word := range strings.Fields(corpus) {
+		want[word]++
+	}
+	// blocks of 7 bytes split most words, the long one spans five blocks
+	opts := WriteOptions{BlockSize: 7, Replication: 1}
+	if err := c.CopyFromLocalOpts("corpus.txt", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	got, err := c.WordCount("/corpus.txt")
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("wordCount = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestCalMeanVarLinesAcrossBlocks(t *testing.T) {
+	tc := startCluster(t, 2)
+	c := tc.c
+	// with blocks of 5 bytes, most numbers straddle a block boundary and
+	// 1234567 spans two boundaries
+	nums := "10\n200\n3\n1234567\n45\n6"
+	if err := ioutil.WriteFile("nums.txt", []byte(nums), 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts := WriteOptions{BlockSize: 5, Replication: 1}
+	if err := c.CopyFromLocalOpts("nums.txt", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	var sum, sq float64
+	xs := []float64{10, 200, 3, 1234567, 45, 6}
+	for _, x := range xs {
+		sum += x
+		sq += x * x
+	}
+	mean := sum / float64(len(xs))
+	want := fmt.Sprintf("mean: %v, variance: %v\n", mean, sq/float64(len(xs))-mean*mean)
+	if got, err := c.CalMeanVar("/nums.txt"); err != nil || got != want {
+		t.Errorf("calMeanVar = %q, %v, want %q", got, err, want)
+	}
+}
+
+// longestRecord is a custom mapreduce job finding the length of the
+// longest ';' separated record of a file
+type longestRecord struct{}
+
+func (longestRecord) IsSep(r rune) bool {
+	return r == ';'
+}
+
+func (longestRecord) Map(data []byte) (mapreduce.Result, error) {
+	res := mapreduce.Result{"max": 0}
+	for _, rec := range strings.Split(string(data), ";") {
+		if float64(len(rec)) > res["max"] {
+			res["max"] = float64(len(rec))
+		}
+	}
+	return res, nil
+}
+
+func (longestRecord) Reduce(partials []mapreduce.Result) (mapreduce.Result, error) {
+	res := mapreduce.Result{"max": 0}
+	for _, p := range partials {
+		if p["max"] > res["max"] {
+			res["max"] = p["max"]
+		}
+	}
+	return res, nil
+}
+
+func TestRunCustomJob(t *testing.T) {
+	mapreduce.Register("longest", longestRecord{})
+	tc := startCluster(t, 2)
+	c := tc.c
+	// the longest record spans three blocks of 4 bytes
+	data := "ab;c;0123456789;xyz;;de"
+	if err := ioutil.WriteFile("recs.txt", []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts := WriteOptions{BlockSize: 4, Replication: 1}
+	if err := c.CopyFromLocalOpts("recs.txt", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	res, err := c.RunJob("longest", "/recs.txt")
+	if err != nil || res["max"] != 10 {
+		t.Errorf("longest record = %v, %v, want 10", res, err)
+	}
+	if got := mapreduce.Format("longest", res); got != "max\t10\n" {
+		t.Errorf("Format(longest) = %q", got)
+	}
+	if _, err := c.RunJob("nosuchjob", "/recs.txt"); err == nil {
+		t.Errorf("running an unregistered job succeeded")
+	}
+}
+
+func TestRunDistJob(t *testing.T) {
+	tc := startCluster(t, 3)
+	c := tc.c
+	var corpus strings.Builder
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&corpus, "w%v the w%v\n", i%37, i%5)
+	}
+	if err := ioutil.WriteFile("corpus.txt", []byte(corpus.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts := WriteOptions{BlockSize: 64, Replication: 2}
+	if err := c.CopyFromLocalOpts("corpus.txt", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	want, err := c.WordCount("/corpus.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := c.RunDistJob(mapreduce.WordCountJob, "/corpus.txt", "/out", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	// every word is counted once, by the reducer of its partition
+	got := make(map[string]int)
+	for p, file := range files {
+		var out bytes.Buffer
+		if err := c.Cat(path.Join("/out", file), &out); err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			var word string
+			var cnt int
+			if _, err := fmt.Sscanf(line, "%s\t%d", &word, &cnt); err != nil {
+				t.Fatalf("bad line %q in %v: %v", line, file, err)
+			}
+			if _, ok := got[word]; ok || mapreduce.Partition(word, 4) != p {
+				t.Errorf("%v found in %v, want only in part %v", word, file,
+					mapreduce.Partition(word, 4))
+			}
+			got[word] = cnt
+		}
+	}
+	if len(files) != 4 || !reflect.DeepEqual(got, want) {
+		t.Errorf("distributed wordcount in %v = %v, want %v", files, got, want)
+	}
+	if _, err := c.RunDistJob(mapreduce.WordCountJob, "/corpus.txt", "/out", 2); err == nil {
+		t.Errorf("distributed job into an existing output succeeded")
+	}
+}
+
+func TestJobStatusAndCancel(t *testing.T) {
+	tc := startCluster(t, 2)
+	c := tc.c
+	if err := ioutil.WriteFile("nums.txt", []byte("1\n2\n3\n4\n5\n6\n7\n8\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts := WriteOptions{BlockSize: 4, Replication: 1}
+	if err := c.CopyFromLocalOpts("nums.txt", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	// a finished job reports its result
+	jobID, err := c.SubmitJob(mapreduce.MeanVarJob, "/nums.txt", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := waitJob(t, c, jobID, func(s *namenode.JobStatus) bool {
+		return s.State != namenode.JobRunning
+	})
+	if status.State != namenode.JobDone || status.Result != "mean: 4.5, variance: 5.25\n" {
+		t.Errorf("finished job status = %+v", status)
+	}
+
+	// the map tasks on the hung datanode keep running while the others
+	// are done, until the job is canceled. Blocks may be placed on a
+	// single datanode, so the hung one is the holder of the first block.
+	r, err := c.Open("/nums.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hung := r.addrs[r.blkList[0]][0]
+	for _, d := range tc.nodes {
+		if d.Addr == hung {
+			d.misbehave(time.Hour, false)
+		}
+	}
+	jobID, err = c.SubmitJob(mapreduce.MeanVarJob, "/nums.txt", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status = waitJob(t, c, jobID, func(s *namenode.JobStatus) bool {
+		states := make(map[string]int)
+		for _, state := range s.Tasks {
+			states[state]++
+		}
+		return states[namenode.TaskRunning] > 0 &&
+			states[namenode.TaskRunning]+states[namenode.TaskDone] == 4
+	})
+	if status.State != namenode.JobRunning {
+		t.Fatalf("job with a hung datanode is %v", status.State)
+	}
+	start := time.Now()
+	if _, err := c.CancelJob(jobID); err != nil {
+		t.Fatal(err)
+	}
+	status = waitJob(t, c, jobID, func(s *namenode.JobStatus) bool {
+		return s.State != namenode.JobRunning
+	})
+	if status.State != namenode.JobCanceled {
+		t.Errorf("canceled job is %v", status.State)
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("cancellation took %v", took)
+	}
+	for blk, state := range status.Tasks {
+		if state == namenode.TaskRunning || state == namenode.TaskPending {
+			t.Errorf("map task of %v is %v after cancellation", blk, state)
+		}
+	}
+	if _, err := c.JobStatus("nosuchjob"); err == nil {
+		t.Errorf("status of an unknown job succeeded")
+	}
+}
+
+// waitJob polls the status of a job until done returns true
+func waitJob(t *testing.T, c *Client, jobID string,
+	done func(*namenode.JobStatus) bool) *namenode.JobStatus {
+	t.Helper()
+	for i := 0; i < 500; i++ {
+		status, err := c.JobStatus(jobID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if done(status) {
+			return status
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %v still not in the awaited state", jobID)
+	return nil
+}
+
+func TestClientToken(t *testing.T) {
+	tc := startCluster(t, 1)
+	c := tc.c
+	defer func(token string) { config.AuthToken = token }(config.AuthToken)
+	config.AuthToken = "s3cret"
+	if _, err := c.Ls("/"); err == nil || IsNotFound(err) {
+		t.Errorf("ls without a token = %v, want it rejected", err)
+	}
+	if _, err := c.SubmitJob(mapreduce.WordCountJob, "/f", "", 0); err == nil {
+		t.Errorf("submitJob without a token succeeded")
+	}
+	c.Token = "s3cret"
+	if _, err := c.Ls("/"); err != nil {
+		t.Errorf("ls with the token: %v", err)
+	}
+	// datanodes check the token of blocks written and read as well
+	data := writeLocal(t, "f.bin", 2500)
+	err := c.CopyFromLocalOpts("f.bin", "/", WriteOptions{BlockSize: 1000, Replication: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	if err := c.CopyToLocal("/f.bin", "back.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data) {
+		t.Errorf("copyToLocal with the token got %v bytes differing from the %v uploaded",
+			len(got), len(data))
+	}
+	addr := tc.nodes[0].Addr
+	if len(tc.nodes[0].IDToMetaData) == 0 {
+		t.Fatalf("%v holds no block of /f.bin", addr)
+	}
+	for blk := range tc.nodes[0].IDToMetaData {
+		args := datanode.RequestBlkArgs{BlkID: blk}
+		err := c.callDataNode(addr, utils.RPCTimeout(), func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+			_, err := dn.RequestBlk(ctx, args.ToPB())
+			return err
+		})
+		if err == nil || err.Error() != namenode.ErrUnauthorized.Error() {
+			t.Errorf("reading %v without a token = %v, want %v", blk, err,
+				namenode.ErrUnauthorized)
+		}
+	}
+}
+
+func TestClientIPv6(t *testing.T) {
+	l, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("no IPv6 loopback: %v", err)
+	}
+	defer l.Close()
+	chdir(t, t.TempDir())
+	serv := utils.NewServer()
+	namenode.RegisterServer(serv, namenode.NewNameNode())
+	defer serv.Stop()
+	go serv.Serve(l)
+	_, port, err := utils.SplitHostPort(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(utils.JoinHostPort("::1", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.Mkdir("/v6", false); err != nil {
+		t.Fatal(err)
+	}
+	if files, err := c.Ls("/"); err != nil || !reflect.DeepEqual(files, []string{"v6"}) {
+		t.Errorf("ls / over IPv6 = %q, %v, want [v6]", files, err)
+	}
+}
+
+func TestAbs(t *testing.T) {
+	for _, tc := range []struct {
+		cwd, p, want string
+	}{
+		{"", "a", "/a"},
+		{"", ".", "/"},
+		{"", "..", "/"},
+		{"/a/b", "", "/a/b"},
+		{"/a/b", ".", "/a/b"},
+		{"/a/b", "c/./d", "/a/b/c/d"},
+		{"/a/b", "..", "/a"},
+		{"/a/b", "../c", "/a/c"},
+		{"/a/b", "../../../..", "/"},
+		{"/a/b", "/x/../y/", "/y"},
+		{"a/b", "*.txt", "/a/b/*.txt"},
+	} {
+		c := &Client{Cwd: tc.cwd}
+		if got := c.Abs(tc.p); got != tc.want {
+			t.Errorf("Abs(%q) in %q = %q, want %q", tc.p, tc.cwd, got, tc.want)
+		}
+	}
+}
+
+func TestRelativePaths(t *testing.T) {
+	defer os.Unsetenv(config.CwdEnv)
+	os.Setenv(config.CwdEnv, "/a/b")
+	tc := startCluster(t, 1)
+	c := tc.c
+	if c.Cwd != "/a/b" {
+		t.Fatalf("Cwd = %q, want $%v", c.Cwd, config.CwdEnv)
+	}
+	if err := c.Mkdir(".", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Touch("f", "../g"); err != nil {
+		t.Fatal(err)
+	}
+	if files, err := c.Ls(".."); err != nil || !reflect.DeepEqual(files, []string{"b", "g"}) {
+		t.Errorf("ls .. = %q, %v, want [b g]", files, err)
+	}
+	c.Cwd = "/a"
+	if err := c.Mv([]string{"b/f", "g"}, "./b/../.."); err != nil {
+		t.Fatal(err)
+	}
+	if files, err := c.Ls("../../.."); err != nil || !reflect.DeepEqual(files, []string{"a", "f", "g"}) {
+		t.Errorf("ls ../../.. = %q, %v, want [a f g]", files, err)
+	}
+	err := c.Touch("missing/h")
+	var perrs PathErrors
+	if !errors.As(err, &perrs) || len(perrs) != 1 || perrs[0].Path != "/a/missing/h" {
+		t.Errorf("touch of a file in a missing dir = %v, want an error for /a/missing/h", err)
+	}
+}
+
+func TestCopyFromLocalBandwidth(t *testing.T) {
+	tc := startCluster(t, 1)
+	data := writeLocal(t, "f.bin", 200<<10)
+	start := time.Now()
+	err := tc.c.CopyFromLocalOpts("f.bin", "/", WriteOptions{Replication: 1,
+		BytesPerSec: 400 << 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 200 kB at 400 kB/s
+	if d := time.Since(start); d < 400*time.Millisecond || d > 2*time.Second {
+		t.Errorf("copying 200 kB at 400 kB/s took %v, want about 500ms", d)
+	}
+	tc.report()
+	if err := tc.c.CopyToLocal("/f.bin", "back.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data) {
+		t.Errorf("got %v bytes differing from the %v uploaded", len(got), len(data))
+	}
+}
+
+func TestWritePipeline(t *testing.T) {
+	tc := startCluster(t, 3)
+	data := writeLocal(t, "f.bin", 3*config.ChunkSize/2)
+	if err := tc.c.CopyFromLocal("f.bin", "/"); err != nil {
+		t.Fatal(err)
+	}
+	// the client sends each chunk once, the first datanode forwards it to
+	// the two others, and the second one to the third
+	var downstream []int
+	for _, d := range tc.nodes {
+		if len(d.IDToMetaData) != 1 {
+			t.Errorf("%v holds %v blocks, want 1", d.Addr, len(d.IDToMetaData))
+		}
+		downstream = append(downstream, d.downstream...)
+	}
+	sort.Ints(downstream)
+	if want := []int{0, 0, 1, 1, 2, 2}; !reflect.DeepEqual(downstream, want) {
+		t.Errorf("chunks received with %v targets, want %v", downstream, want)
+	}
+	tc.report()
+	for _, d := range tc.nodes {
+		d.misbehave(0, true)
+		if err := tc.c.CopyToLocal("/f.bin", "back.bin"); err != nil {
+			t.Fatal(err)
+		}
+		d.misbehave(0, false)
+	}
+	if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data) {
+		t.Errorf("got %v bytes differing from the %v uploaded", len(got), len(data))
+	}
+
+	// a block corrupted on its way to a datanode is rejected there, which
+	// fails the write
+	for i, d := range tc.nodes {
+		d.misbehave(0, true)
+		held := len(d.IDToMetaData)
+		dst := fmt.Sprintf("/g%v", i)
+		if err := tc.c.Mkdir(dst, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := tc.c.CopyFromLocal("f.bin", dst); err == nil {
+			t.Errorf("writing %v with datanode %v corrupting it succeeded", dst, i)
+		}
+		d.misbehave(0, false)
+		if len(d.IDToMetaData) != held {
+			t.Errorf("datanode %v stored the block it corrupted", i)
+		}
+	}
+}
+
+func TestResumeUpload(t *testing.T) {
+	tc := startCluster(t, 2)
+	state := config.UploadStatePath
+	config.UploadStatePath = t.TempDir()
+	defer func() { config.UploadStatePath = state }()
+	data := writeLocal(t, "f.bin", 4*config.ChunkSize+10)
+	opts := WriteOptions{BlockSize: int64(config.ChunkSize), Replication: 2}
+	// a full block takes two chunks, the last one empty, the upload breaks
+	// down on the third of the five blocks
+	tc.nodes[1].failAfter = 4
+	if err := tc.c.CopyFromLocalOpts("f.bin", "/", opts); err == nil {
+		t.Fatal("interrupted upload succeeded")
+	}
+	manifests, _ := filepath.Glob(filepath.Join(config.UploadStatePath, "*.json"))
+	if len(manifests) != 1 {
+		t.Fatalf("upload left manifests %v, want 1", manifests)
+	}
+	// the datanodes haven't reported the blocks sent yet, the client
+	// checks them on the datanodes
+	tc.nodes[1].failAfter = 0
+	var before []int
+	for _, d := range tc.nodes {
+		before = append(before, len(d.downstream))
+	}
+	if err := tc.c.CopyFromLocalOpts("f.bin", "/", opts); err != nil {
+		t.Fatalf("resuming the upload: %v", err)
+	}
+	for i, d := range tc.nodes {
+		// the last 3 blocks are sent
+		if got := len(d.downstream) - before[i]; got != 5 {
+			t.Errorf("datanode %v received %v chunks, want 5", i, got)
+		}
+	}
+	manifests, _ = filepath.Glob(filepath.Join(config.UploadStatePath, "*.json"))
+	if len(manifests) != 0 {
+		t.Errorf("complete upload left manifests %v", manifests)
+	}
+	tc.report()
+	if err := tc.c.CopyToLocal("/f.bin", "back.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data) {
+		t.Errorf("got %v bytes differing from the %v uploaded", len(got), len(data))
+	}
+
+	// once the local file changed, the upload starts over
+	tc.nodes[1].failAfter = len(tc.nodes[1].downstream) + 2
+	if err := tc.c.CopyFromLocalOpts("f.bin", "/", opts); err == nil {
+		t.Fatal("interrupted upload succeeded")
+	}
+	tc.nodes[1].failAfter = 0
+	data = writeLocal(t, "f.bin", 2*config.ChunkSize)
+	opts.Overwrite = true
+	if err := tc.c.CopyFromLocalOpts("f.bin", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	if err := tc.c.CopyToLocal("/f.bin", "back.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data) {
+		t.Errorf("got %v bytes differing from the %v uploaded", len(got), len(data))
+	}
+}
+
+func TestChecksumType(t *testing.T) {
+	defer func(typ string) { config.ChecksumType = typ }(config.ChecksumType)
+	tc := startCluster(t, 1)
+	data := writeLocal(t, "f.bin", 3*config.ChunkSize/2)
+	for _, typ := range []string{utils.CRC32C, utils.SHA256} {
+		config.ChecksumType = typ
+		dst := "/" + typ
+		if err := tc.c.Mkdir(dst, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := tc.c.CopyFromLocalOpts("f.bin", dst, WriteOptions{Replication: 1}); err != nil {
+			t.Fatalf("%v: %v", typ, err)
+		}
+		tc.report()
+		if err := tc.c.CopyToLocal(dst+"/f.bin", typ); err != nil {
+			t.Fatalf("%v: %v", typ, err)
+		}
+		if got, _ := ioutil.ReadFile(typ); !bytes.Equal(got, data) {
+			t.Errorf("%v: got %v bytes differing from the %v uploaded", typ, len(got), len(data))
+		}
+	}
+	types := make(map[string]bool)
+	for _, meta := range tc.nodes[0].IDToMetaData {
+		types[meta.ChecksumType] = true
+	}
+	if len(types) != 2 || !types[utils.CRC32C] || !types[utils.SHA256] {
+		t.Errorf("blocks are checksummed with %v", types)
+	}
+	config.ChecksumType = "md5"
+	if err := tc.c.CopyFromLocalOpts("f.bin", "/", WriteOptions{Replication: 1}); err == nil {
+		t.Errorf("uploading with checksum type md5 succeeded")
+	}
+}
+
+func TestFileDigest(t *testing.T) {
+	tc := startCluster(t, 1)
+	data := writeLocal(t, "f.bin", 2*config.ChunkSize)
+	opts := WriteOptions{Replication: 1, BlockSize: int64(config.ChunkSize)}
+	if err := tc.c.CopyFromLocalOpts("f.bin", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	w, err := tc.c.CreateOpts("/w.bin", opts)
+	if err == nil {
+		_, err = w.Write(data)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	for _, p := range []string{"/f.bin", "/w.bin"} {
+		if err := tc.c.CopyToLocal(p, "back.bin"); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data) {
+			t.Errorf("%v: got %v bytes differing from the %v written", p, len(got), len(data))
+		}
+	}
+
+	// each block served passes its own checksum, the file doesn't
+	args := namenode.CommandArgs{CommandType: config.CopyToLocal, DPath: "/f.bin"}
+	reply, err := tc.c.run(&args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := tc.nodes[0]
+	d.mu.Lock()
+	d.swapped = map[string]string{reply.BlkList[0]: reply.BlkList[1],
+		reply.BlkList[1]: reply.BlkList[0]}
+	d.mu.Unlock()
+	os.Remove("back.bin")
+	err = tc.c.CopyToLocal("/f.bin", "back.bin")
+	if !errors.Is(err, ErrIntegrity) {
+		t.Errorf("copying a file with swapped blocks: %v, want %v", err, ErrIntegrity)
+	}
+	if _, err := os.Stat("back.bin"); !os.IsNotExist(err) {
+		t.Errorf("the file failing its digest is written: %v", err)
+	}
+}
+
+func TestRmdirDeletesBlocks(t *testing.T) {
+	tc := startCluster(t, 3)
+	writeLocal(t, "f.bin", 3*config.ChunkSize/2)
+	for _, dir := range []string{"/d", "/d/e", "/kept"} {
+		if err := tc.c.Mkdir(dir, true); err != nil {
+			t.Fatal(err)
+		}
+		if err := tc.c.CopyFromLocal("f.bin", dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tc.report()
+	kept, err := tc.c.run(&namenode.CommandArgs{CommandType: config.CopyToLocal,
+		DPath: "/kept/f.bin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tc.c.Rmdir("/d"); err != nil {
+		t.Fatal(err)
+	}
+	for i, d := range tc.nodes {
+		var blks []string
+		for blk := range d.IDToMetaData {
+			blks = append(blks, blk)
+		}
+		sort.Strings(blks)
+		if !reflect.DeepEqual(blks, kept.BlkList) {
+			t.Errorf("datanode %v holds %v after rmdir, want %v", i, blks, kept.BlkList)
+		}
+		files, err := ioutil.ReadDir(filepath.Join(fmt.Sprintf("dn%v", i), "actdata"))
+		if err != nil || len(files) != len(kept.BlkList) {
+			t.Errorf("datanode %v keeps %v block files after rmdir, %v", i, len(files), err)
+		}
+	}
+	if err := tc.c.Rmdir("/kept/f.bin"); err == nil {
+		t.Errorf("rmdir of a file succeeded")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tc := startCluster(t, 3)
+	c := tc.c
+	data := writeLocal(t, "f.bin", 2500)
+	if err := c.Mkdir("/d", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CopyFromLocalOpts("f.bin", "/d", WriteOptions{BlockSize: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	blks, err := c.run(&namenode.CommandArgs{CommandType: config.CopyToLocal,
+		DPath: "/d/f.bin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// on a block boundary the last block is dropped, off one the block
+	// kept last is cut on every datanode
+	for _, step := range []struct {
+		length  int64
+		numBlks int
+	}{{2000, 2}, {1500, 2}, {999, 1}, {0, 0}} {
+		if err := c.Truncate("/d/f.bin", step.length); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.CopyToLocal("/d/f.bin", "back.bin"); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data[:step.length]) {
+			t.Errorf("truncated to %v, got %v bytes", step.length, len(got))
+		}
+		stats, err := c.Stat("/d/f.bin")
+		if err != nil || stats[0].Size != step.length || stats[0].NumBlks != step.numBlks {
+			t.Errorf("stat after truncating to %v = %+v, %v", step.length, stats, err)
+		}
+		if step.length != 1500 {
+			continue
+		}
+		for i, d := range tc.nodes {
+			meta := d.IDToMetaData[blks.BlkList[1]]
+			sum := utils.ChecksumOf(meta.ChecksumType, data[1000:1500])
+			if meta.Length != 500 || !meta.Checksum.Equal(sum) {
+				t.Errorf("datanode %v holds %+v after truncating to 1500", i, meta)
+			}
+		}
+	}
+	for i, d := range tc.nodes {
+		for _, blk := range blks.BlkList {
+			if meta, ok := d.IDToMetaData[blk]; ok {
+				t.Errorf("datanode %v keeps block %v of %v bytes", i, blk, meta.Length)
+			}
+		}
+	}
+	if err := c.Truncate("/d/f.bin", 1); err == nil {
+		t.Errorf("truncating past the end of the file succeeded")
+	}
+}
+
+func TestBatchedReads(t *testing.T) {
+	tc := startCluster(t, 2)
+	c := tc.c
+	data := writeLocal(t, "f.bin", 2050)
+	opts := WriteOptions{BlockSize: 100, Replication: 2}
+	if err := c.CopyFromLocalOpts("f.bin", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	// every block is on both datanodes, the first one listed is asked for
+	// runs of at most config.MaxBlksPerRequest blocks, a corrupt block is
+	// read from the other datanode
+	tc.nodes[0].misbehave(0, true)
+	if err := c.CopyToLocal("/f.bin", "back.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ioutil.ReadFile("back.bin"); !bytes.Equal(got, data) {
+		t.Errorf("copyToLocal got %v bytes differing from the %v uploaded",
+			len(got), len(data))
+	}
+	batched := 0
+	for _, d := range tc.nodes {
+		for _, n := range d.batches {
+			if n < 2 || n > config.MaxBlksPerRequest {
+				t.Errorf("a request asked for %v blocks", n)
+			}
+			batched += n
+		}
+	}
+	if batched == 0 {
+		t.Errorf("no block was read in a batch")
+	}
+}
+
+func TestBlkBatches(t *testing.T) {
+	defer func(max int) { config.MaxBlksPerRequest = max }(config.MaxBlksPerRequest)
+	config.MaxBlksPerRequest = 2
+	blkToAddrs := map[string][]string{"a": {"x", "y"}, "b": {"", "x"}, "c": {"x"},
+		"d": {"y"}, "e": {}, "f": {}}
+	got := blkBatches([]string{"a", "b", "c", "d", "e", "f"}, blkToAddrs)
+	want := [][]string{{"a", "b"}, {"c"}, {"d"}, {"e"}, {"f"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %v, want %v", got, want)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	tc := startCluster(t, 2)
+	c := tc.c
+	defer func(wire, rest bool) {
+		config.CompressBlks, config.CompressAtRest = wire, rest
+	}(config.CompressBlks, config.CompressAtRest)
+	data := bytes.Repeat([]byte("0.5\n1.5\n2.5\n"), 2000)
+	if err := ioutil.WriteFile("nums.txt", data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts := WriteOptions{BlockSize: 10000, Replication: 2}
+	// blocks go compressed on the wire, stored raw or compressed
+	for _, rest := range []bool{false, true} {
+		config.CompressBlks, config.CompressAtRest = true, rest
+		dir := fmt.Sprintf("/rest-%v", rest)
+		if err := c.Mkdir(dir, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.CopyFromLocalOpts("nums.txt", dir, opts); err != nil {
+			t.Fatal(err)
+		}
+		dst := dir + "/nums.txt"
+		tc.report()
+		if err := c.Cp(dst, dst+".cp", false); err != nil {
+			t.Fatal(err)
+		}
+		tc.report()
+		for _, p := range []string{dst, dst + ".cp"} {
+			if err := c.CopyToLocal(p, "back.txt"); err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := ioutil.ReadFile("back.txt"); !bytes.Equal(got, data) {
+				t.Errorf("%v reads back %v bytes differing from the %v written",
+					p, len(got), len(data))
+			}
+			reply, err := c.run(&namenode.CommandArgs{CommandType: config.CopyToLocal,
+				DPath: p})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, blk := range reply.BlkList {
+				stat, err := os.Stat(filepath.Join("dn0", "actdata", blk))
+				small := err == nil && stat.Size() < tc.nodes[0].IDToMetaData[blk].Length/2
+				if err != nil || small != rest {
+					t.Errorf("%v of %v stored compressed %v, want %v, %v", blk, p,
+						small, rest, err)
+				}
+			}
+		}
+		// reading uncompressed blocks of stored compressed ones works too
+		config.CompressBlks = false
+		if got, err := c.Head(dst); err != nil || !bytes.Equal(got, data[:1024]) {
+			t.Errorf("head of %v = %v bytes, %v", dst, len(got), err)
+		}
+	}
+}
+
+func TestGrep(t *testing.T) {
+	tc := startCluster(t, 2)
+	c := tc.c
+	var text strings.Builder
+	var want, wantFold []string
+	empty := 0
+	for i := 0; i < 200; i++ {
+		line := fmt.Sprintf("line %v", i)
+		switch i % 7 {
+		case 0:
+			line += " ERROR disk full"
+		case 3:
+			line += " error timeout"
+		case 5:
+			line = "" // empty lines count as lines
+			empty++
+		}
+		if strings.Contains(line, "ERROR") {
+			want = append(want, line)
+		}
+		if strings.Contains(strings.ToLower(line), "error") {
+			wantFold = append(wantFold, line)
+		}
+		text.WriteString(line + "\n")
+	}
+	last := "trailing ERROR without newline" + strings.Repeat(".", 300)
+	text.WriteString(last)
+	want, wantFold = append(want, last), append(wantFold, last)
+	if err := ioutil.WriteFile("log.txt", []byte(text.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// blocks of 100 bytes cut most matching lines, and some blocks hold no
+	// newline at all
+	opts := WriteOptions{BlockSize: 100, Replication: 1}
+	if err := c.CopyFromLocalOpts("log.txt", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	lines, num, err := c.Grep("/log.txt", "ERROR", false, false)
+	if err != nil || num != len(want) || !reflect.DeepEqual(lines, want) {
+		t.Errorf("grep ERROR = %q, %v, %v, want %q", lines, num, err, want)
+	}
+	lines, num, err = c.Grep("/log.txt", "error", true, true)
+	if err != nil || num != len(wantFold) || lines != nil {
+		t.Errorf("grep -i -c error = %v lines, %v, %v, want %v", len(lines), num, err,
+			len(wantFold))
+	}
+	if _, num, err := c.Grep("/log.txt", "^$", false, true); err != nil || num != empty {
+		t.Errorf("grep -c of empty lines = %v, %v, want %v", num, err, empty)
+	}
+	if _, _, err := c.Grep("/log.txt", "(", false, false); err == nil {
+		t.Errorf("grep with an invalid pattern succeeded")
+	}
+}
+
+func TestTopN(t *testing.T) {
+	tc := startCluster(t, 2)
+	c := tc.c
+	r := rand.New(rand.NewSource(1))
+	var text strings.Builder
+	var nums []int
+	for i := 0; i < 500; i++ {
+		num := r.Intn(200) - 100 // plenty of ties
+		nums = append(nums, num)
+		fmt.Fprintf(&text, "%v\n", num)
+		if i%50 == 0 {
+			text.WriteString("\nnot a number\n") // skipped
+		}
+	}
+	nums = append(nums, 1000) // the greatest ends the file without newline
+	fmt.Fprintf(&text, "%v", 1000)
+	sort.Sort(sort.Reverse(sort.IntSlice(nums)))
+	if err := ioutil.WriteFile("nums.txt", []byte(text.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// blocks of 64 bytes cut many numbers
+	opts := WriteOptions{BlockSize: 64, Replication: 1}
+	if err := c.CopyFromLocalOpts("nums.txt", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	for _, k := range []int{1, 10, 137, 501, 1000} {
+		want := nums
+		if k < len(want) {
+			want = want[:k]
+		}
+		var wantStr []string
+		for _, num := range want {
+			wantStr = append(wantStr, fmt.Sprint(num))
+		}
+		top, err := c.TopN("/nums.txt", k, false)
+		if err != nil || !reflect.DeepEqual(top, wantStr) {
+			t.Errorf("top %v = %v, %v, want %v", k, top, err, wantStr)
+		}
+	}
+	top, err := c.TopN("/nums.txt", 1, true)
+	if err != nil || !reflect.DeepEqual(top, []string{"not a number"}) {
+		t.Errorf("lexical top 1 = %q, %v", top, err)
+	}
+	if _, err := c.TopN("/nums.txt", 0, false); err == nil {
+		t.Errorf("top 0 succeeded")
+	}
+}
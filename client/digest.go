@@ -0,0 +1,69 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/namenode"
+	"github.com/WineChord/gdfs/utils"
+)
+
+/** The digest of a dfs file covers its data end to end: the client
+ * writing the file computes it over the checksums of the blocks it sent
+ * and namenode records it, see utils.FileDigest. A client downloading the
+ * file recomputes it over the blocks it received, which catches a block
+ * missing, swapped or served for another even though each block passes
+ * its own checksum.
+ * */
+
+// setDigest records the digest of the dfs file p made of the blocks of
+// blkList, sums are their config.ChecksumType checksums in order
+func (c *Client) setDigest(p string, blkList []string, sums []utils.Sum) error {
+	args := namenode.CommandArgs{CommandType: config.SetDigest, DPath: p}
+	args.BlkList = blkList
+	args.Digest = utils.FileDigest(sums)
+	args.ChecksumType = config.ChecksumType
+	_, err := c.run(&args)
+	return err
+}
+
+// sumOf computes the checksum of type typ of the data read from r
+func sumOf(typ string, r io.Reader) (utils.Sum, error) {
+	hash := utils.NewHash(typ)
+	if hash == nil {
+		return nil, utils.CheckChecksumType(typ)
+	}
+	if _, err := io.Copy(hash, r); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+// checkDigest compares the digest of the blocks of dfs file src, whose
+// checksums are sums, with the one recorded in reply. A file without
+// digest passes.
+func checkDigest(src string, reply *namenode.CommandReply, sums []utils.Sum) error {
+	if reply.Digest == "" {
+		return nil
+	}
+	if digest := utils.FileDigest(sums); digest != reply.Digest {
+		return fmt.Errorf("%v: digest %v, recorded %v: %w", src, digest, reply.Digest,
+			ErrIntegrity)
+	}
+	return nil
+}
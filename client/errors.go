@@ -0,0 +1,143 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/namenode"
+	"github.com/WineChord/gdfs/utils"
+	"google.golang.org/grpc"
+)
+
+// ErrChecksum is wrapped by errors of blocks failing verification
+var ErrChecksum = errors.New("checksum mismatch")
+
+// ErrIntegrity is wrapped by errors of files whose blocks pass their
+// checksums but not the digest of the whole file, see checkDigest
+var ErrIntegrity = errors.New("file integrity check failed")
+
+// ConnError is a failure to reach a namenode or datanode. It is transient
+// as opposed to errors returned by the server.
+type ConnError struct {
+	Addr string
+	Err  error
+}
+
+func (e *ConnError) Error() string {
+	return fmt.Sprintf("cannot reach %v: %v", e.Addr, e.Err)
+}
+
+func (e *ConnError) Unwrap() error {
+	return e.Err
+}
+
+// PathError is the failure of a multi-path command on one of its paths
+type PathError struct {
+	Op   string
+	Path string
+	Msg  string
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("%v: %v: %v", e.Op, e.Path, e.Msg)
+}
+
+// PathErrors are the failures of a multi-path command in argument order,
+// the paths absent from it succeeded
+type PathErrors []*PathError
+
+func (e PathErrors) Error() string {
+	lines := make([]string, 0, len(e))
+	for _, pe := range e {
+		lines = append(lines, pe.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Failed tells whether the command failed on path
+func (e PathErrors) Failed(path string) bool {
+	for _, pe := range e {
+		if pe.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// checkErrors turns the per path errors of a multi-path command into an
+// error, or nil if every path succeeded
+func checkErrors(op string, paths []string, errs map[string]string) error {
+	var res PathErrors
+	for _, path := range paths {
+		if msg, ok := errs[path]; ok {
+			res = append(res, &PathError{op, path, msg})
+		}
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
+
+// IsNotFound tells whether err is about a missing local or dfs path
+func IsNotFound(err error) bool {
+	return err != nil && (errors.Is(err, os.ErrNotExist) ||
+		strings.Contains(err.Error(), namenode.ErrNotFound.Error()))
+}
+
+// dial connects to the gRPC server at addr
+func dial(addr string) (*grpc.ClientConn, error) {
+	cc, err := utils.Dial(addr)
+	if err != nil {
+		return nil, &ConnError{addr, err}
+	}
+	return cc, nil
+}
+
+// connError reports the error of a call to the server at addr as
+// ConnError if the server was unreachable or didn't reply in time
+func connError(addr string, err error) error {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) || errors.Is(err, utils.ErrUnavailable) ||
+		errors.Is(err, utils.ErrTimeout) {
+		return &ConnError{addr, err}
+	}
+	return err
+}
+
+// callDataNode calls f with the datanode at addr over the pooled
+// connection, giving up after timeout. A datanode unreachable or not
+// replying in time is reported as ConnError.
+func (c *Client) callDataNode(addr string, timeout time.Duration,
+	f func(ctx context.Context, dn gdfspb.DataNodeClient) error) error {
+	return connError(addr, c.dataNodes.CallDataNode(addr, timeout, nil, f))
+}
+
+// callNameNode calls f with the namenode giving up after timeout, a
+// namenode unreachable or not replying in time is reported as ConnError
+func (c *Client) callNameNode(timeout time.Duration,
+	f func(ctx context.Context, nn gdfspb.NameNodeClient) error) error {
+	ctx, cancel := utils.Context(timeout, nil)
+	defer cancel()
+	return connError(c.addr, f(ctx, gdfspb.NewNameNodeClient(c.nn)))
+}
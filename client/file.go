@@ -0,0 +1,229 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/namenode"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// Reader reads a dfs file. Blocks are fetched from datanodes as the
+// caller reads them, one at a time, failing over to other replicas when a
+// datanode is unreachable or returns a corrupted block.
+type Reader struct {
+	c       *Client
+	blkList []string
+	addrs   map[string][]string // block to datanodes holding it
+	offsets []int64             // offset of each block in the file
+	size    int64
+	off     int64
+	cur     int    // index of the block in buf, -1 for none
+	buf     []byte // data of block cur
+}
+
+// Open opens a dfs file for reading
+func (c *Client) Open(path string) (*Reader, error) {
+	args := namenode.CommandArgs{CommandType: config.Cat, DPath: path}
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, err
+	}
+	if len(reply.BlkLengths) != len(reply.BlkList) {
+		return nil, fmt.Errorf("%v block lengths for %v blocks", len(reply.BlkLengths),
+			len(reply.BlkList))
+	}
+	// blocks of an appended file may be partial anywhere, a block starts
+	// after the lengths of the blocks before it
+	offsets := make([]int64, len(reply.BlkList))
+	for i := 1; i < len(offsets); i++ {
+		offsets[i] = offsets[i-1] + reply.BlkLengths[i-1]
+	}
+	r := &Reader{c: c, blkList: reply.BlkList, addrs: reply.BlkToDataNodes,
+		offsets: offsets, size: reply.FileSize, cur: -1}
+	return r, nil
+}
+
+// Size returns the size of the file in byte
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+// Read reads from the block holding the current offset, the last one
+// starting at or before it
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.off >= r.size {
+		return 0, io.EOF
+	}
+	idx := sort.Search(len(r.offsets), func(i int) bool {
+		return r.offsets[i] > r.off
+	}) - 1
+	if idx != r.cur {
+		seg := r.blkList[idx]
+		data, err := r.c.readAnyReplica(seg, r.addrs[seg])
+		if err != nil {
+			return 0, err
+		}
+		r.cur, r.buf = idx, data
+	}
+	inBlk := r.off - r.offsets[idx]
+	if inBlk >= int64(len(r.buf)) {
+		return 0, io.ErrUnexpectedEOF // the block is shorter than reported
+	}
+	n := copy(p, r.buf[inBlk:])
+	r.off += int64(n)
+	return n, nil
+}
+
+// Seek sets the offset of the next Read, only the block holding the new
+// offset is fetched on the next Read
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.off
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.New("Seek: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("Seek: negative position")
+	}
+	r.off = offset
+	return offset, nil
+}
+
+// Close releases the buffered block
+func (r *Reader) Close() error {
+	r.cur, r.buf = -1, nil
+	return nil
+}
+
+// Writer writes a new dfs file. Data is buffered until a block is full,
+// then the block is appended to the file and sent to its datanodes. The
+// file exists from Create on and grows block by block, Close sends the
+// last partial block and asks datanodes to report the new blocks.
+type Writer struct {
+	c       *Client
+	path    string
+	blkSize int64
+	buf     []byte
+	closed  bool
+	// blocks written so far and their checksums, for the digest of the file
+	blks []string
+	sums []utils.Sum
+	// throttle limits the rate of the blocks flushed
+	throttle *utils.Throttler
+}
+
+// Create creates an empty dfs file for writing with the default block
+// size and replication factor
+func (c *Client) Create(path string) (*Writer, error) {
+	return c.CreateOpts(path, WriteOptions{})
+}
+
+// CreateOpts creates an empty dfs file for writing
+func (c *Client) CreateOpts(p string, opts WriteOptions) (*Writer, error) {
+	args := namenode.CommandArgs{}
+	args.CommandType = config.CopyFromLocal
+	args.DPath, args.FileName = path.Split(p)
+	args.BlockSize = opts.BlockSize
+	args.Replication = opts.Replication
+	reply, err := c.run(&args)
+	if err != nil {
+		return nil, err
+	}
+	w := &Writer{c: c, path: c.Abs(p), blkSize: reply.BlkSize,
+		throttle: transferThrottler(opts.BytesPerSec)}
+	w.buf = make([]byte, 0, w.blkSize)
+	return w, nil
+}
+
+// Write buffers p, sending each block filled up
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("Write on closed file")
+	}
+	written := 0
+	for len(p) > 0 {
+		n := int(w.blkSize) - len(w.buf)
+		if n > len(p) {
+			n = len(p)
+		}
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		written += n
+		if int64(len(w.buf)) == w.blkSize {
+			if err := w.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// flush appends the buffered data to the file as a new block
+func (w *Writer) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	args := namenode.CommandArgs{}
+	args.CommandType = config.AppendToFile
+	args.DPath = w.path
+	args.FileSize = int64(len(w.buf))
+	reply, err := w.c.run(&args)
+	if err != nil {
+		return err
+	}
+	for _, blkID := range reply.BlkList {
+		addrs := reply.BlkToDataNodes[blkID]
+		for _, addr := range addrs {
+			w.c.sentTo[addr] = true
+		}
+		sum, err := w.c.streamBlk(blkID, reply.GenStamp, bytes.NewReader(w.buf), addrs,
+			w.throttle)
+		if err != nil {
+			return err
+		}
+		w.blks, w.sums = append(w.blks, blkID), append(w.sums, sum)
+	}
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// Close sends the last block, notifies namenode and records the digest of
+// the file
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	err := w.flush()
+	if err != nil {
+		return err
+	}
+	if err := w.c.notify(); err != nil {
+		return err
+	}
+	return w.c.setDigest(w.path, w.blks, w.sums)
+}
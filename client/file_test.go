@@ -0,0 +1,139 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+
+	"github.com/WineChord/gdfs/utils"
+)
+
+func TestCreateAndOpen(t *testing.T) {
+	tc := startCluster(t, 3)
+	c := tc.c
+	data := make([]byte, 2500)
+	rand.New(rand.NewSource(1)).Read(data)
+	w, err := c.CreateOpts("/f.bin", WriteOptions{BlockSize: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a small buffer makes writes straddle block boundaries
+	buf := make([]byte, 300)
+	if _, err := io.CopyBuffer(w, bytes.NewReader(data), buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tc.report()
+	stats, err := c.Stat("/f.bin")
+	if err != nil || stats[0].Size != 2500 || stats[0].NumBlks != 3 {
+		t.Fatalf("stat = %+v, %v, want 2500 bytes in 3 blocks", stats, err)
+	}
+
+	r, err := c.Open("/f.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	got := &bytes.Buffer{}
+	if _, err := io.Copy(got, r); err != nil || !bytes.Equal(got.Bytes(), data) {
+		t.Fatalf("io.Copy from the opened file got %v bytes, %v", got.Len(), err)
+	}
+	for _, tt := range []struct {
+		offset int64
+		whence int
+		want   int64
+	}{
+		{1500, io.SeekStart, 1500},
+		{-100, io.SeekCurrent, 2400}, // the previous read stopped at the end
+		{-10, io.SeekEnd, 2490},
+		{0, io.SeekStart, 0},
+	} {
+		pos, err := r.Seek(tt.offset, tt.whence)
+		if err != nil || pos != tt.want {
+			t.Fatalf("Seek(%v, %v) = %v, %v, want %v", tt.offset, tt.whence,
+				pos, err, tt.want)
+		}
+		rest, err := ioutil.ReadAll(r)
+		if err != nil || !bytes.Equal(rest, data[pos:]) {
+			t.Errorf("read %v bytes after seeking to %v, %v", len(rest), pos, err)
+		}
+	}
+	if _, err := r.Seek(-1, io.SeekStart); err == nil {
+		t.Errorf("seeking before the start of the file succeeded")
+	}
+
+	// a replica lost on the first datanode is read from another one
+	seg := r.blkList[1]
+	for _, d := range tc.nodes {
+		if d.Addr == r.addrs[seg][0] {
+			d.DeleteBlk(&utils.DeleteBlkArgs{BlkID: seg}, &utils.DeleteBlkReply{})
+		}
+	}
+	r.Close()
+	if _, err := r.Seek(1200, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	p := make([]byte, 100)
+	if _, err := io.ReadFull(r, p); err != nil || !bytes.Equal(p, data[1200:1300]) {
+		t.Errorf("reading a block with a lost replica = %v", err)
+	}
+}
+
+func TestOpenAppended(t *testing.T) {
+	tc := startCluster(t, 3)
+	c := tc.c
+	data := writeLocal(t, "f.bin", 2500)
+	if err := c.CopyFromLocalOpts("f.bin", "/", WriteOptions{BlockSize: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	// each append starts a new block, leaving partial blocks in the middle
+	for _, size := range []int{6, 1200} {
+		name := fmt.Sprintf("more%v.bin", size)
+		data = append(data, writeLocal(t, name, size)...)
+		if err := c.AppendToFile([]string{name}, "/f.bin"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tc.report()
+	r, err := c.Open("/f.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if r.Size() != int64(len(data)) {
+		t.Fatalf("opened file has %v bytes, want %v", r.Size(), len(data))
+	}
+	got := &bytes.Buffer{}
+	if _, err := io.Copy(got, r); err != nil || !bytes.Equal(got.Bytes(), data) {
+		t.Fatalf("io.Copy from the opened file got %v bytes, %v", got.Len(), err)
+	}
+	// in the partial blocks and in the blocks after them
+	for _, pos := range []int64{2400, 2500, 2503, 2506, 3000, 3505, 3705} {
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		rest, err := ioutil.ReadAll(r)
+		if err != nil || !bytes.Equal(rest, data[pos:]) {
+			t.Errorf("read %v bytes after seeking to %v, %v", len(rest), pos, err)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/datanode"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/namenode"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// manifest records the progress of the upload of a local file to a dfs
+// file, so that a failed copyFromLocal resumes where it stopped. It is
+// kept in JSON under config.UploadStatePath until the upload completes.
+type manifest struct {
+	Path    string   // dfs file uploaded to
+	Size    int64    // size of the local file
+	ModTime int64    // modification time of the local file in ns
+	BlkList []string // blocks of the dfs file
+	BlkSize int64
+	// Acked maps the blocks sent so far to their write
+	Acked map[string]ackedBlk
+	file  string // where the manifest is kept
+}
+
+// ackedBlk is a block acknowledged by every datanode of its pipeline
+type ackedBlk struct {
+	GenStamp int64
+	Addrs    []string
+}
+
+// openManifest returns the manifest of the upload of the local file
+// described by info to the dfs file p. The manifest of an earlier upload
+// is returned with resumed set, unless the local file changed since.
+func openManifest(local string, info os.FileInfo, p string) (m *manifest, resumed bool,
+	err error) {
+	abs, err := filepath.Abs(local)
+	if err != nil {
+		return nil, false, err
+	}
+	sum := sha1.Sum([]byte(abs))
+	m = &manifest{Path: p, Size: info.Size(), ModTime: info.ModTime().UnixNano(),
+		Acked: make(map[string]ackedBlk),
+		file:  filepath.Join(config.UploadStatePath, hex.EncodeToString(sum[:])+".json")}
+	data, err := ioutil.ReadFile(m.file)
+	if err != nil {
+		return m, false, nil
+	}
+	old := manifest{}
+	if err := json.Unmarshal(data, &old); err != nil {
+		logger.Warnf("ignore malformed upload manifest %v: %v\n", m.file, err)
+		return m, false, nil
+	}
+	if old.Path != m.Path || old.Size != m.Size || old.ModTime != m.ModTime ||
+		old.Acked == nil {
+		return m, false, nil
+	}
+	old.file = m.file
+	return &old, true, nil
+}
+
+// save writes m to its file, replacing the previous version at once
+func (m *manifest) save() error {
+	if err := os.MkdirAll(config.UploadStatePath, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	tmp := m.file + utils.TmpSuffix
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.file)
+}
+
+// remove drops the file of m once the upload is complete
+func (m *manifest) remove() error {
+	err := os.Remove(m.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// blkLen is the length of block i of the upload
+func (m *manifest) blkLen(i int) int64 {
+	if rest := m.Size - int64(i)*m.BlkSize; rest < m.BlkSize {
+		return rest
+	}
+	return m.BlkSize
+}
+
+// resumeUpload asks namenode which blocks of the upload of m are still to
+// send. Datanodes report the blocks they receive late, so a block namenode
+// doesn't know replicated yet is skipped as well if every datanode which
+// acknowledged it still holds it. It returns nil if the dfs file is no
+// longer the one m uploads to.
+func (c *Client) resumeUpload(m *manifest) (*namenode.CommandReply, error) {
+	args := namenode.CommandArgs{CommandType: config.UploadStatus, DPath: m.Path}
+	reply, err := c.run(&args)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !reflect.DeepEqual(reply.BlkList, m.BlkList) || reply.BlkSize != m.BlkSize {
+		return nil, nil
+	}
+	for i, blk := range reply.BlkList {
+		ack, ok := m.Acked[blk]
+		if _, todo := reply.BlkToDataNodes[blk]; !todo || !ok {
+			continue
+		}
+		if c.holdBlk(blk, m.blkLen(i), ack) {
+			delete(reply.BlkToDataNodes, blk)
+			for _, addr := range ack.Addrs {
+				c.sentTo[addr] = true
+			}
+		}
+	}
+	return reply, nil
+}
+
+// holdBlk tells whether every datanode which acknowledged blk still holds
+// it as it was written
+func (c *Client) holdBlk(blk string, length int64, ack ackedBlk) bool {
+	for _, addr := range ack.Addrs {
+		args := datanode.StatBlkArgs{BlkID: blk, Token: c.Token}
+		meta := utils.MetaData{}
+		err := c.callDataNode(addr, utils.RPCTimeout(), func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+			m, err := dn.StatBlk(ctx, args.ToPB())
+			meta.FromPB(m)
+			return err
+		})
+		if err != nil || meta.GenStamp != ack.GenStamp || meta.Length != length {
+			logger.Debugf("%v is not in place on %v: %+v, %v\n", blk, addr, meta, err)
+			return false
+		}
+	}
+	return true
+}
+
+// uploadBlks sends the blocks of reply.BlkToDataNodes read at their offset
+// in file, recording each one in m once acknowledged, then notifies
+// namenode and records the digest of the file
+func (c *Client) uploadBlks(file io.ReaderAt, m *manifest, reply *namenode.CommandReply,
+	t *utils.Throttler) error {
+	sums := make([]utils.Sum, len(reply.BlkList))
+	for i, blk := range reply.BlkList {
+		r := io.NewSectionReader(file, int64(i)*reply.BlkSize, reply.BlkSize)
+		addrs, ok := reply.BlkToDataNodes[blk]
+		if !ok {
+			// sent before, the digest still needs its checksum
+			sum, err := sumOf(config.ChecksumType, r)
+			if err != nil {
+				return err
+			}
+			sums[i] = sum
+			continue
+		}
+		for _, addr := range addrs {
+			c.sentTo[addr] = true
+		}
+		sum, err := c.streamBlk(blk, reply.GenStamp, r, addrs, t)
+		if err != nil {
+			return err
+		}
+		sums[i] = sum
+		m.Acked[blk] = ackedBlk{GenStamp: reply.GenStamp, Addrs: addrs}
+		if err := m.save(); err != nil {
+			logger.Warnf("cannot record the upload of %v: %v\n", blk, err)
+		}
+	}
+	if err := c.notify(); err != nil {
+		return err
+	}
+	if err := c.setDigest(m.Path, reply.BlkList, sums); err != nil {
+		return err
+	}
+	return m.remove()
+}
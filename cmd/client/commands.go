@@ -0,0 +1,250 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// command is a command of the client
+type command struct {
+	synopsis    string // arguments of the command, e.g. "[-f] <src> ... <dst>"
+	description string // what the command does, lines are indented by -help
+	run         func() error
+	// local commands run without connecting to the namenode
+	local bool
+}
+
+// commands maps each command line switch to its command
+var commands = map[string]*command{
+	"-appendToFile": {"<localsrc> ... <dst>",
+		"Append local files, concatenated in order, to the end of dfs file dst.",
+		runAppendToFile, false},
+	"-calMeanVar": {"<src>",
+		"Print the mean and variance of the numbers of dfs file src, one per line.",
+		runCalMeanVar, false},
+	"-cancelJob": {"<jobID>",
+		"Cancel a job started by -submitJob and print its status.",
+		runCancelJob, false},
+	"-cat": {"<src>",
+		"Write dfs file src to stdout.",
+		runCat, false},
+	"-checksum": {"<src> ...",
+		"Print the MD5 of the block checksums of dfs files, which is the same for\n" +
+			"files of the same content, block size and checksum type.",
+		runChecksum, false},
+	"-copyFromLocal": {"[-f] [-blockSize <size>] [-rep <rep>] <localsrc> <dst>",
+		"Copy a local file or directory tree into dfs directory dst.\n" +
+			"-f replaces an existing file, -blockSize sets the block size of the\n" +
+			"new files, e.g. 64m, and -rep their replication factor.",
+		runCopyFromLocal, false},
+	"-copyToLocal": {"<src> <localdst>",
+		"Copy dfs file src to localdst, a local file or directory.",
+		runCopyToLocal, false},
+	"-count": {"[-q] <path>",
+		"Print the number of directories, files and bytes under path.\n" +
+			"-q prints the name and space quotas and what remains of them first.",
+		runCount, false},
+	"-cp": {"[-f] <src> ... <dst>",
+		"Copy dfs files to dst, a directory when there are several sources.\n" +
+			"-f replaces existing files.",
+		runCp, false},
+	"-df": {"",
+		"Print the capacity, used and remaining space of the cluster and of\n" +
+			"each live datanode.",
+		runDf, false},
+	"-dfsadmin": {"-balance|-report|-decommission <addr>",
+		"Administer datanodes: -balance moves blocks off the fullest datanodes,\n" +
+			"-report prints the state and storage of every datanode and\n" +
+			"-decommission copies the blocks of the datanode at addr elsewhere,\n" +
+			"then retires it.",
+		runDfsAdmin, false},
+	"-du": {"[-replicated] <path>",
+		"Print the bytes used by each entry of directory path and their total.\n" +
+			"-replicated also prints the bytes counting every replica.",
+		runDu, false},
+	"-expunge": {"",
+		"Delete everything in your trash.",
+		runExpunge, false},
+	"-format": {"",
+		"Erase the whole dfs.",
+		runFormat, false},
+	"-fsck": {"<path>",
+		"List the missing, under or over replicated and corrupt blocks of the\n" +
+			"files under path, it fails if any block is missing or corrupt.",
+		runFsck, false},
+	"-grep": {"[-i] [-c] <pattern> <src>",
+		"Print the lines of dfs file src matching the regular expression pattern,\n" +
+			"matched on the datanodes holding its blocks. -i ignores case, -c prints\n" +
+			"the number of matching lines instead.",
+		runGrep, false},
+	"-head": {"<file>",
+		"Write the first kilobyte of dfs file file to stdout.",
+		runHead, false},
+	"-help": {"[cmd ...]",
+		"Print the synopsis and description of each cmd, of every command\n" +
+			"without argument.",
+		nil, true},
+	"-job": {"<name> <src> [<dst> <numReduce>]",
+		"Run mapreduce job name (meanvar, wordcount) over dfs file src and\n" +
+			"print its result. With numReduce reduce tasks on datanodes, write the\n" +
+			"outputs in dfs directory dst and print their paths.",
+		runJob, false},
+	"-jobStatus": {"<jobID>",
+		"Print the state of a job started by -submitJob and of its tasks, then\n" +
+			"its result once done.",
+		runJobStatus, false},
+	"-ls": {"[-R] [-l] <path>",
+		"List the entries of directory path, or the paths matching a pattern.\n" +
+			"-R lists the whole subtree, -l the type, replication factor, size and\n" +
+			"modification time of each entry.",
+		runLs, false},
+	"-mkdir": {"[-p] <path>",
+		"Create dfs directory path, with -p its missing parents too.",
+		runMkdir, false},
+	"-mv": {"<src> ... <dst>",
+		"Move dfs files to dst, a directory when there are several sources.",
+		runMv, false},
+	"-pwd": {"",
+		"Print the working directory relative dfs paths are resolved against.",
+		runPwd, false},
+	"-rm": {"[-skipTrash] <src> ...",
+		"Remove dfs files, moving them to the trash if it is enabled.\n" +
+			"-skipTrash deletes them right away.",
+		runRm, false},
+	"-rmdir": {"<dir> ...",
+		"Remove dfs directories with everything below them, deleting the blocks\n" +
+			"of their files from datanodes.",
+		runRmdir, false},
+	"-setQuota": {"<count> <path>",
+		"Limit the number of files and directories under directory path, 0\n" +
+			"removes the limit.",
+		runSetQuota, false},
+	"-setSpaceQuota": {"<bytes> <path>",
+		"Limit the bytes of the files under directory path times their\n" +
+			"replication factor, 0 removes the limit.",
+		runSetQuota, false},
+	"-setrep": {"<rep> <path>",
+		"Change the replication factor of dfs file path.",
+		runSetRep, false},
+	"-shell": {"",
+		"Run the commands read from stdin, one per line, until EOF. The leading\n" +
+			"dash of commands is optional and cd changes the working directory.",
+		nil, false},
+	"-stat": {"<path> ...",
+		"Print the type, size, number of blocks, block size, replication factor,\n" +
+			"modification and access time of each path.",
+		runStat, false},
+	"-submitJob": {"<name> <src> [<dst> <numReduce>]",
+		"Start a job like -job without waiting for it and print its id.",
+		runSubmitJob, false},
+	"-tail": {"<file>",
+		"Write the last kilobyte of dfs file file to stdout.",
+		runTail, false},
+	"-topN": {"[-l] <k> <src>",
+		"Print the k greatest lines of dfs file src as integers, greatest first,\n" +
+			"ranked on the datanodes holding its blocks. Blank and non-numeric lines\n" +
+			"are skipped, -l compares the lines as strings instead.",
+		runTopN, false},
+	"-touch": {"<path> ...",
+		"Create empty dfs files.",
+		runTouch, false},
+	"-truncate": {"<length> <path>",
+		"Cut dfs file path to its first length bytes.",
+		runTruncate, false},
+	"-usage": {"[cmd ...]",
+		"Print the synopsis of each cmd, of every command without argument.",
+		nil, true},
+	"-wordCount": {"<src>",
+		"Print the number of occurrences of each word of dfs file src.",
+		runWordCount, false},
+}
+
+// commands dispatching to commands join them once they're set
+func init() {
+	commands["-help"].run = runHelp
+	commands["-usage"].run = runUsage
+	commands["-shell"].run = runShell
+	commands["format"] = commands["-format"]
+}
+
+// lookup returns the command named name, with or without its leading dash
+func lookup(name string) (*command, error) {
+	cmd, ok := commands["-"+strings.TrimPrefix(name, "-")]
+	if !ok {
+		return nil, usagef("%q is not a valid command", name)
+	}
+	return cmd, nil
+}
+
+// usageLine is the synopsis of the command named name
+func usageLine(name string, cmd *command) string {
+	if cmd.synopsis == "" {
+		return name
+	}
+	return name + " " + cmd.synopsis
+}
+
+// printHelp prints the synopsis of every command
+func printHelp() {
+	fmt.Printf("Usage: [-conf <file>] [-namenode <host:port>] [-json] [-log <file>] <cmd> ...\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		if strings.HasPrefix(name, "-") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("\t%v\n", usageLine(name, commands[name]))
+	}
+}
+
+func runHelp() error {
+	return printCommands(true)
+}
+
+func runUsage() error {
+	return printCommands(false)
+}
+
+// printCommands prints the synopsis of the commands named in os.Args,
+// with long their description too. Without names, it prints the synopsis
+// of every command.
+func printCommands(long bool) error {
+	if len(os.Args) == 2 {
+		printHelp()
+		return nil
+	}
+	for _, name := range os.Args[2:] {
+		cmd, err := lookup(name)
+		if err != nil {
+			return err
+		}
+		name = "-" + strings.TrimPrefix(name, "-")
+		if !long {
+			fmt.Printf("Usage: %v\n", usageLine(name, cmd))
+			continue
+		}
+		fmt.Printf("%v\n", usageLine(name, cmd))
+		for _, line := range strings.Split(cmd.description, "\n") {
+			fmt.Printf("\t%v\n", line)
+		}
+	}
+	return nil
+}
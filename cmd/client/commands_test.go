@@ -0,0 +1,52 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHelp(t *testing.T) {
+	for name, cmd := range commands {
+		if cmd.run == nil || cmd.description == "" {
+			t.Errorf("%v has no handler or no description", name)
+		}
+	}
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-help", "cp"}, "-cp [-f] <src> ... <dst>\n" +
+			"\tCopy dfs files to dst, a directory when there are several sources.\n" +
+			"\t-f replaces existing files.\n"},
+		{[]string{"-help", "-pwd"}, "-pwd\n" +
+			"\tPrint the working directory relative dfs paths are resolved against.\n"},
+		{[]string{"-usage", "cp"}, "Usage: -cp [-f] <src> ... <dst>\n"},
+		{[]string{"-usage", "ls", "-df"}, "Usage: -ls [-R] [-l] <path>\nUsage: -df\n"},
+	} {
+		if got, err := runCmd(t, tc.args...); err != nil || got != tc.want {
+			t.Errorf("%v printed %q, %v, want %q", tc.args, got, err, tc.want)
+		}
+	}
+	if _, err := runCmd(t, "-usage", "nosuchcmd"); exitCode(err) != exitUsage {
+		t.Errorf("usage of an unknown command = %v, want a usage error", err)
+	}
+	all, err := runCmd(t, "-help")
+	if err != nil || !strings.Contains(all, "\t-cp [-f] <src> ... <dst>\n") ||
+		!strings.Contains(all, "\t-usage [cmd ...]\n") || strings.Contains(all, "\tformat\n") {
+		t.Errorf("help printed %q, %v, want every command once", all, err)
+	}
+}
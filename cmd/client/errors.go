@@ -0,0 +1,63 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/WineChord/gdfs/client"
+)
+
+// exit status of the client for each class of failure
+const (
+	exitOK       = 0
+	exitError    = 1 // any other failure
+	exitUsage    = 2 // malformed command line
+	exitNotFound = 3 // a local or dfs path doesn't exist
+	exitConn     = 4 // namenode or datanode unreachable
+	exitChecksum = 5 // no intact replica of a block, or a file failing its digest
+)
+
+// usageError is a malformed command line
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+func usagef(format string, a ...interface{}) error {
+	return usageError(fmt.Sprintf(format, a...))
+}
+
+// exitCode maps err to the exit status of the client
+func exitCode(err error) int {
+	var ue usageError
+	var ce *client.ConnError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &ue):
+		return exitUsage
+	case errors.As(err, &ce):
+		return exitConn
+	case errors.Is(err, client.ErrChecksum), errors.Is(err, client.ErrIntegrity):
+		return exitChecksum
+	case client.IsNotFound(err):
+		return exitNotFound
+	default:
+		return exitError
+	}
+}
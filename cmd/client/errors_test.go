@@ -0,0 +1,118 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/WineChord/gdfs/client"
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/namenode"
+	"github.com/WineChord/gdfs/utils"
+)
+
+func TestExitCode(t *testing.T) {
+	_, statErr := os.Stat("no-such-local-file")
+	_, dialErr := client.New("127.0.0.1:1")
+	for _, tc := range []struct {
+		err  error
+		want int
+	}{
+		{nil, exitOK},
+		{errors.New("Invalid replication factor"), exitError},
+		{usagef("ls expects 1 argument, got %v", 2), exitUsage},
+		{statErr, exitNotFound},
+		{utils.ServerError(namenode.ErrNotFound.Error()), exitNotFound},
+		{client.PathErrors{{Op: "rm", Path: "/b", Msg: namenode.ErrNotFound.Error()}},
+			exitNotFound},
+		{dialErr, exitConn},
+		{fmt.Errorf("no intact replica available for block b: %w", client.ErrChecksum),
+			exitChecksum},
+	} {
+		if got := exitCode(tc.err); got != tc.want {
+			t.Errorf("exitCode(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+// TestRunExitStatus runs command lines failing in each way and checks the
+// exit status of the client
+func TestRunExitStatus(t *testing.T) {
+	cluster := startCluster(t, 1)
+	defer func(args []string) { os.Args = args }(os.Args)
+	defer func(addr string) { config.NameNodeAddress = addr }(config.NameNodeAddress)
+	data := bytes.Repeat([]byte("intact "), 100)
+	for _, name := range []string{"f", "g"} {
+		if err := ioutil.WriteFile(name, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.CopyFromLocalOpts("f", "/", client.WriteOptions{Replication: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cluster.WaitHealthy("/f"); err != nil {
+		t.Fatal(err)
+	}
+	// the only replica of /g goes bad
+	store := cluster.DataNodes[0].Volumes[0].Store
+	intact, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CopyFromLocalOpts("g", "/", client.WriteOptions{Replication: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cluster.WaitHealthy("/g"); err != nil {
+		t.Fatal(err)
+	}
+	blks, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for blk, meta := range blks {
+		if _, ok := intact[blk]; !ok {
+			if err := store.Put(blk, meta, bytes.Repeat([]byte("broken "), 100)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want int
+	}{
+		{[]string{"-cat", "/f"}, exitOK},
+		{[]string{"-copyFromLocal", "f", "/"}, exitError},
+		{nil, exitUsage},
+		{[]string{"-nosuchcmd"}, exitUsage},
+		{[]string{"-ls", "/", "/f"}, exitUsage},
+		{[]string{"-namenode"}, exitUsage},
+		{[]string{"-cat", "/missing"}, exitNotFound},
+		{[]string{"-copyFromLocal", "no-such-local-file", "/"}, exitNotFound},
+		{[]string{"-cat", "/g"}, exitChecksum},
+		{[]string{"-namenode", "127.0.0.1:1", "-ls", "/"}, exitConn},
+	} {
+		var got int
+		capture(t, func() { got = run(tc.args) })
+		if got != tc.want {
+			t.Errorf("%q exits with %v, want %v", tc.args, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/WineChord/gdfs/client"
+)
+
+// keys returns the sorted keys of a JSON object
+func keys(obj map[string]interface{}) []string {
+	res := make([]string, 0, len(obj))
+	for k := range obj {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func TestJSONOutput(t *testing.T) {
+	cluster := startCluster(t, 1)
+	defer func() { jsonOut = false }()
+	if _, err := parseGlobals([]string{"-json"}); err != nil || !jsonOut {
+		t.Fatalf("-json gives %v, JSON output %v", err, jsonOut)
+	}
+	if err := c.Mkdir("/d/e", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile("f", []byte("some data\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CopyFromLocalOpts("f", "/d", client.WriteOptions{Replication: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cluster.WaitHealthy("/d"); err != nil {
+		t.Fatal(err)
+	}
+
+	statKeys := []string{"AccessTime", "BlkSize", "IsDir", "ModTime", "NumBlks", "Path",
+		"Replication", "Size"}
+	nodeKeys := []string{"Addr", "Capacity", "DeadSince", "FracInUse", "HostName",
+		"LastHeartBeat", "Remaining", "State", "StorageID", "Used"}
+	for _, tc := range []struct {
+		args []string
+		// check validates the output decoded into a generic value
+		check func(out interface{}) bool
+	}{
+		{[]string{"-ls", "/d"}, func(out interface{}) bool {
+			stats := out.([]interface{})
+			e, f := stats[0].(map[string]interface{}), stats[1].(map[string]interface{})
+			return len(stats) == 2 && reflect.DeepEqual(keys(e), statKeys) &&
+				e["Path"] == "/d/e" && e["IsDir"] == true &&
+				f["Path"] == "/d/f" && f["Size"] == 10.0 && f["Replication"] == 1.0
+		}},
+		{[]string{"-ls", "/d/e"}, func(out interface{}) bool {
+			return len(out.([]interface{})) == 0
+		}},
+		{[]string{"-stat", "/d/f", "/d"}, func(out interface{}) bool {
+			stats := out.([]interface{})
+			f, d := stats[0].(map[string]interface{}), stats[1].(map[string]interface{})
+			return len(stats) == 2 && reflect.DeepEqual(keys(f), statKeys) &&
+				f["Path"] == "/d/f" && f["NumBlks"] == 1.0 && d["IsDir"] == true
+		}},
+		{[]string{"-checksum", "/d/f"}, func(out interface{}) bool {
+			sums := out.(map[string]interface{})
+			return len(sums) == 1 && sums["/d/f"] != ""
+		}},
+		{[]string{"-df"}, func(out interface{}) bool {
+			status := out.(map[string]interface{})
+			nodes := status["Nodes"].([]interface{})
+			return reflect.DeepEqual(keys(status),
+				[]string{"Capacity", "Nodes", "Remaining", "Used"}) &&
+				len(nodes) == 1 &&
+				reflect.DeepEqual(keys(nodes[0].(map[string]interface{})), nodeKeys)
+		}},
+		{[]string{"-dfsadmin", "-report"}, func(out interface{}) bool {
+			nodes := out.([]interface{})
+			node := nodes[0].(map[string]interface{})
+			return len(nodes) == 1 && reflect.DeepEqual(keys(node), nodeKeys) &&
+				node["Addr"] == cluster.DataNodes[0].Addr && node["State"] == "live"
+		}},
+	} {
+		stdout, err := runCmd(t, tc.args...)
+		if err != nil {
+			t.Errorf("%v: %v", tc.args, err)
+			continue
+		}
+		var out interface{}
+		if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+			t.Errorf("%v wrote invalid JSON %q: %v", tc.args, stdout, err)
+			continue
+		}
+		ok := func() (ok bool) {
+			defer func() {
+				if recover() != nil {
+					ok = false
+				}
+			}()
+			return tc.check(out)
+		}()
+		if !ok {
+			t.Errorf("%v wrote unexpected JSON %v", tc.args, stdout)
+		}
+	}
+}
@@ -16,392 +16,938 @@ package main
 
 import (
 	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"hash/crc32"
 	"log"
-	"net/rpc"
 	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/WineChord/gdfs/client"
 	"github.com/WineChord/gdfs/config"
-	"github.com/WineChord/gdfs/datanode"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/mapreduce"
 	"github.com/WineChord/gdfs/namenode"
 	"github.com/WineChord/gdfs/utils"
 )
 
-var c *rpc.Client
-
-func printHelp() {
-	fmt.Printf("Usage:\n")
-	fmt.Printf("\t-appendToFile <localsrc> ... <dst>\n")
-	fmt.Printf("\t-calMeanVar <dst>\n")
-	fmt.Printf("\t-cat <src>\n")
-	fmt.Printf("\t-checksum <src> ...\n")
-	fmt.Printf("\t-copyFromLocal <localsrc> <dst>\n")
-	fmt.Printf("\t-copyToLocal <src> <localdst>\n")
-	fmt.Printf("\t-cp <src> ... <dst>\n")
-	fmt.Printf("\t-head <file>\n")
-	fmt.Printf("\t-help [cmd ...]\n")
-	fmt.Printf("\t-ls <path>\n")
-	fmt.Printf("\t-mkdir [-p] <path>\n")
-	fmt.Printf("\t-moveFromLocal <localsrc> ... <dst>\n")
-	fmt.Printf("\t-moveToLocal <src> <localdst>\n")
-	fmt.Printf("\t-mv <src> ... <dst>\n")
-	fmt.Printf("\t-rm <src> ...\n")
-	fmt.Printf("\t-rmdir <dir> ...\n")
-	fmt.Printf("\t-stat <path> ...\n")
-	fmt.Printf("\t-tail <file>\n")
-	fmt.Printf("\t-touch <path> ...\n")
-	fmt.Printf("\t-usage [cmd ...]\n")
-}
-
-func runCalMeanVar() {
+var c *client.Client
+
+// jsonOut makes -ls, -stat, -df, -dfsadmin -report and -checksum print
+// JSON, see printJSON
+var jsonOut bool
+
+// printJSON writes v to stdout as indented JSON, the fields of structs
+// named as in Go
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func runCalMeanVar() error {
 	start := utils.GetCurrentTimeInMs()
-	log.Printf("runCalMean\n")
+	logger.Debugf("enter runCalMeanVar\n")
 	if len(os.Args) != 3 {
-		log.Fatalf("calMean expects 1 argument <dst>, got %v\n",
-			len(os.Args)-2)
+		return usagef("calMean expects 1 argument <dst>, got %v", len(os.Args)-2)
 	}
-	dfsPath := os.Args[2]
-	args := namenode.CommandArgs{}
-	args.CommandType = config.CalMeanVar
-	args.DPath = dfsPath
-	reply := namenode.CommandReply{}
-	log.Printf("called with args: %v\n", args)
-	err := c.Call("NameNode.RunCommand", &args, &reply)
+	result, err := c.CalMeanVar(os.Args[2])
 	if err != nil {
-		log.Fatal("Calling: ", err)
+		return err
 	}
-	log.Printf("result returned from server: %v\n", reply.Result)
-	log.Printf("time elapsed: %v ms\n", utils.GetCurrentTimeInMs() - start)
+	fmt.Printf("%v\n", result)
+	logger.Debugf("time elapsed: %v ms\n", utils.GetCurrentTimeInMs()-start)
+	return nil
 }
 
-func runCat() {
-	log.Printf("enter runCat\n")
+func runWordCount() error {
+	if len(os.Args) != 3 {
+		return usagef("wordCount expects 1 argument <src>, got %v", len(os.Args)-2)
+	}
+	counts, err := c.WordCount(os.Args[2])
+	if err != nil {
+		return err
+	}
+	words := make([]string, 0, len(counts))
+	for word := range counts {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	for _, word := range words {
+		fmt.Printf("%v\t%v\n", word, counts[word])
+	}
+	return nil
 }
 
-func runCopyFromLocal() {
-	log.Printf("enter runCopyFromLocal\n")
-	if len(os.Args) != 4 {
-		log.Fatalf("copyFromLocal expects 2 arguments <localsrc> <dst>, got %v\n",
+func runGrep() error {
+	args := os.Args[2:]
+	ignoreCase, countOnly := false, false
+	for len(args) > 0 && (args[0] == "-i" || args[0] == "-c") {
+		ignoreCase = ignoreCase || args[0] == "-i"
+		countOnly = countOnly || args[0] == "-c"
+		args = args[1:]
+	}
+	if len(args) != 2 {
+		return usagef("grep expects 2 arguments [-i] [-c] <pattern> <src>, got %v",
 			len(os.Args)-2)
 	}
-	// name.txt, /
-	localPath, dfsPath := os.Args[2], os.Args[3]
-	fileinfo, err := os.Stat(localPath)
+	lines, num, err := c.Grep(args[1], args[0], ignoreCase, countOnly)
 	if err != nil {
-		log.Fatal("error when get file information", err)
-	}
-	fileSize := fileinfo.Size() // size in byte
-	args := namenode.CommandArgs{}
-	args.CommandType = config.CopyFromLocal
-	args.DPath = dfsPath // '/'
-	args.FileSize = fileSize
-	args.FileName = fileinfo.Name()
-	reply := namenode.CommandReply{}
-	log.Printf("called with args: %v\n", args)
-	err = c.Call("NameNode.RunCommand", &args, &reply)
-	if err != nil {
-		log.Fatal("Calling: ", err)
-	}
-	log.Printf("reply from server (segment name: [list of nodes]):\n")
-	for _, seg := range reply.BlkList {
-		log.Printf("%v: %v\n", seg, reply.BlkToDataNodes[seg])
-	}
-	/** Here we've got:
-	 * list of segment names: [segname0, segnamt1, ...]
-	 * seg to list of nodes:
-	 * 		segname0: [node0, node1, node2]
-	 * 		segname1: [node1, node3, node4]
-	 *  ...
-	 * Then the client will do the actual data splitting,
-	 * It will split the file data into segments of fixed length (e.g. 4KB).
-	 * For each segment, it will calculate its checksum, then send the
-	 * information below to the datanodes in list:
-	 * 		1. BlkID (string) format: filename-index-timestamp-random
-	 * 		2. BlockData ([]byte)
-	 * 		3. checksum (uint32)
-	 * */
-	// For each segment:
-	file, err := os.Open(localPath)
-	if err != nil {
-		log.Printf("error when opening local file of path %v: %v\n",
-			localPath, err)
+		return err
+	}
+	if countOnly {
+		fmt.Printf("%v\n", num)
+		return nil
 	}
-	for _, blkID := range reply.BlkList {
-		data := make([]byte, config.BlkSize)
-		n, err := file.Read(data)
+	for _, line := range lines {
+		fmt.Printf("%v\n", line)
+	}
+	return nil
+}
+
+func runJob() error {
+	if len(os.Args) == 6 {
+		numReduce, err := strconv.Atoi(os.Args[5])
 		if err != nil {
-			log.Printf("reading block %v in file %v: %v\n", blkID, localPath, err)
-		}
-		checksum := crc32.ChecksumIEEE(data)
-		// send [blkId, data, checksum] to each datanode
-		for _, addr := range reply.BlkToDataNodes[blkID] {
-			args1 := utils.BlkData{}
-			args1.BlkID = blkID
-			args1.Checksum = checksum
-			args1.Data = data
-			args1.Length = n
-			reply1 := datanode.SendBlkReply{}
-			c, err := rpc.DialHTTP("tcp", addr)
-			log.Printf("sending %v to %v\n", blkID, addr)
-			if err != nil {
-				log.Fatal("dialing: ", err)
-			}
-			err = c.Call("DataNode.SendBlk", &args1, &reply1)
-			if err != nil {
-				log.Fatal("Calling: ", err)
-			}
+			return usagef("job: invalid number of reduce tasks %q", os.Args[5])
 		}
+		files, err := c.RunDistJob(os.Args[2], os.Args[3], os.Args[4], numReduce)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			fmt.Printf("%v\n", path.Join(os.Args[4], file))
+		}
+		return nil
 	}
-	// when namenode did the segment naming, it only records file -> segName map
-	// but didn't update segName -> [nodes] map, this is because it is possible
-	// that the data tranfer happened between client and datanode is broken.
-	// Therefore, it is more appropriate to notify namenode after successful
-	// transmission of data. notify here in namenode is a simple urgent request
-	// for block report to each datanodes.
-	notifyNameNode()
+	if len(os.Args) != 4 {
+		return usagef("job expects 2 or 4 arguments <name> <src> [<dst> <numReduce>], got %v",
+			len(os.Args)-2)
+	}
+	res, err := c.RunJob(os.Args[2], os.Args[3])
+	if err != nil {
+		return err
+	}
+	fmt.Print(mapreduce.Format(os.Args[2], res))
+	return nil
 }
 
-func notifyNameNode() {
-	log.Printf("notify namenode\n")
-	args := namenode.NotifyArgs{}
-	reply := namenode.NotifyReply{}
-	c, err := rpc.DialHTTP("tcp", config.NameNodeAddress)
+func runSubmitJob() error {
+	if len(os.Args) != 4 && len(os.Args) != 6 {
+		return usagef("submitJob expects 2 or 4 arguments <name> <src> [<dst> <numReduce>], got %v",
+			len(os.Args)-2)
+	}
+	output, numReduce := "", 0
+	if len(os.Args) == 6 {
+		var err error
+		output = os.Args[4]
+		numReduce, err = strconv.Atoi(os.Args[5])
+		if err != nil || numReduce <= 0 {
+			return usagef("submitJob: invalid number of reduce tasks %q", os.Args[5])
+		}
+	}
+	jobID, err := c.SubmitJob(os.Args[2], os.Args[3], output, numReduce)
 	if err != nil {
-		log.Fatal("dialing: ", err)
+		return err
+	}
+	fmt.Printf("%v\n", jobID)
+	return nil
+}
+
+func runJobStatus() error {
+	if len(os.Args) != 3 {
+		return usagef("jobStatus expects 1 argument <jobID>, got %v", len(os.Args)-2)
 	}
-	err = c.Call("NameNode.Notify", &args, &reply)
+	status, err := c.JobStatus(os.Args[2])
 	if err != nil {
-		log.Fatal("Calling: ", err)
+		return err
 	}
+	printJobStatus(status)
+	return nil
 }
 
-func runCopyToLocal() {
-	log.Printf("enter runCopyToLocal\n")
-	if len(os.Args) != 4 {
-		log.Fatalf("copyToLocal expects 2 arguments <dst> <localsrc>, got %v\n",
-			len(os.Args)-2)
+func runCancelJob() error {
+	if len(os.Args) != 3 {
+		return usagef("cancelJob expects 1 argument <jobID>, got %v", len(os.Args)-2)
 	}
-	/** copyToLocal will first send request to namenode with dfsPath
-	 * namenode stores
-	 * 	1. dfsPath -> [segmentFiles] mapping
-	 *  2. segmentFiles -> [datanodes] mapping
-	 * we retrieve [segmentFiles] of this file and [datanotes] for
-	 * each segment.
-	 * we request each segment on the list of datanodee and append
-	 * each segment to local disk.
-	 * */
-	dfsPath, localFilePath := os.Args[2], os.Args[3]
-	args := namenode.CommandArgs{}
-	args.CommandType = config.CopyToLocal
-	args.DPath = dfsPath // '/'
-	reply := namenode.CommandReply{}
-	log.Printf("called with args: %v\n", args)
-	err := c.Call("NameNode.RunCommand", &args, &reply)
+	status, err := c.CancelJob(os.Args[2])
 	if err != nil {
-		log.Fatal("Calling: ", err)
-	}
-	log.Printf("retrieve dfs file segments and datanodes:\n")
-	for _, seg := range reply.BlkList {
-		log.Printf("%v: %v\n", seg, reply.BlkToDataNodes[seg])
-	}
-	/** Now we've got two things from reply:
-	 * 1. blk list for a dfs file
-	 * 2. datanodes list for each block
-	 * now we need to perform the following operations:
-	 * For each block:
-	 * 	1. select a datanode from list
-	 *  2. request data segment from that datanode
-	 *  3. will receive: data, timestamp, checksum
-	 * 	   then calculate the checksum of the data to compare
-	 *     with the received checksum
-	 *  4. if checksum do not match, or the datanode takes too
-	 *     long time to respond, request another datanode
-	 *  5. when we've got intact segment, append it to local file
-	 * */
-	file, err := os.Create(localFilePath)
+		return err
+	}
+	printJobStatus(status)
+	return nil
+}
+
+// printJobStatus prints the state of a job and of its tasks, then its
+// result or error once finished
+func printJobStatus(status *namenode.JobStatus) {
+	fmt.Printf("%v\t%v %v\t%v\n", status.JobID, status.Job, status.Path, status.State)
+	blks := make([]string, 0, len(status.Tasks))
+	for blk := range status.Tasks {
+		blks = append(blks, blk)
+	}
+	sort.Strings(blks)
+	for _, blk := range blks {
+		fmt.Printf("map\t%v\t%v\n", blk, status.Tasks[blk])
+	}
+	for p, state := range status.ReduceTasks {
+		fmt.Printf("reduce\t%v\t%v\n", p, state)
+	}
+	switch status.State {
+	case namenode.JobDone:
+		if len(status.Files) > 0 {
+			fmt.Printf("output: %v\n", strings.Join(status.Files, " "))
+		} else {
+			fmt.Print(status.Result)
+		}
+	case namenode.JobFailed:
+		fmt.Printf("error: %v\n", status.Err)
+	}
+}
+
+func runCat() error {
+	logger.Debugf("enter runCat\n")
+	if len(os.Args) != 3 {
+		return usagef("cat expects 1 argument <src>, got %v", len(os.Args)-2)
+	}
+	return c.Cat(os.Args[2], os.Stdout)
+}
+
+func runCp() error {
+	logger.Debugf("enter runCp\n")
+	argv := os.Args[2:]
+	force := false
+	if len(argv) > 0 && argv[0] == "-f" {
+		force = true
+		argv = argv[1:]
+	}
+	if len(argv) < 2 {
+		return usagef("cp expects at least 2 arguments <src> ... <dst>, got %v",
+			len(argv))
+	}
+	srcs, dst := argv[:len(argv)-1], argv[len(argv)-1]
+	for _, src := range srcs {
+		err := c.Cp(src, dst, force)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runMv() error {
+	logger.Debugf("enter runMv\n")
+	if len(os.Args) < 4 {
+		return usagef("mv expects at least 2 arguments <src> ... <dst>, got %v",
+			len(os.Args)-2)
+	}
+	return c.Mv(os.Args[2:len(os.Args)-1], os.Args[len(os.Args)-1])
+}
+
+// runPwd prints the working directory relative dfs paths are resolved
+// against, see config.CwdEnv
+func runPwd() error {
+	if len(os.Args) != 2 {
+		return usagef("pwd expects no argument, got %v", len(os.Args)-2)
+	}
+	fmt.Printf("%v\n", c.Abs(""))
+	return nil
+}
+
+func runHead() error {
+	logger.Debugf("enter runHead\n")
+	return runPreview("head", c.Head)
+}
+
+func runTail() error {
+	logger.Debugf("enter runTail\n")
+	return runPreview("tail", c.Tail)
+}
+
+// runPreview prints the leading or trailing bytes of a dfs file
+func runPreview(name string, preview func(path string) ([]byte, error)) error {
+	if len(os.Args) != 3 {
+		return usagef("%v expects 1 argument <file>, got %v", name, len(os.Args)-2)
+	}
+	data, err := preview(os.Args[2])
 	if err != nil {
-		log.Printf("error when creating local file: %v\n", err)
-	}
-	log.Printf("start request segments\n")
-	for _, seg := range reply.BlkList {
-		log.Printf("reply.BlkToDataNodes[seg]: %v\n", reply.BlkToDataNodes[seg])
-		log.Printf("len: %v\n", len(reply.BlkToDataNodes[seg]))
-		for _, addr := range reply.BlkToDataNodes[seg] {
-			if addr == "" {
-				continue
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func runCopyFromLocal() error {
+	logger.Debugf("enter runCopyFromLocal\n")
+	argv := os.Args[2:]
+	opts := client.WriteOptions{}
+	for len(argv) > 0 && strings.HasPrefix(argv[0], "-") {
+		switch {
+		case argv[0] == "-f":
+			opts.Overwrite = true
+			argv = argv[1:]
+		case argv[0] == "-blockSize" && len(argv) > 1:
+			size, err := parseSize(argv[1])
+			if err != nil {
+				return usagef("invalid block size %q: %v", argv[1], err)
+			}
+			opts.BlockSize = size
+			argv = argv[2:]
+		case argv[0] == "-rep" && len(argv) > 1:
+			r, err := strconv.Atoi(argv[1])
+			if err != nil || r <= 0 {
+				return usagef("invalid replication factor %q", argv[1])
 			}
-			log.Printf("addr: %v\n", addr)
-			data, length, ok := readRemoteBlk(seg, addr)
-			if ok { // ok means the data is intact
-				writeLocalFile(file, data, length)
+			opts.Replication = r
+			argv = argv[2:]
+		case argv[0] == "-bw" && len(argv) > 1:
+			rate, err := parseSize(argv[1])
+			if err != nil || rate <= 0 {
+				return usagef("invalid bandwidth %q", argv[1])
 			}
+			opts.BytesPerSec = rate
+			argv = argv[2:]
+		default:
+			return usagef("copyFromLocal: unknown option %v", argv[0])
 		}
 	}
-	file.Sync()
-	file.Close()
-	log.Printf("write to local file done\n")
+	if len(argv) != 2 {
+		return usagef("copyFromLocal expects 2 arguments <localsrc> <dst>, got %v",
+			len(argv))
+	}
+	return c.CopyFromLocalOpts(argv[0], argv[1], opts)
 }
 
-func readRemoteBlk(seg, addr string) ([]byte, int, bool) {
-	/** we need to request block from addr (a datanode)
-	 * the argument is segment name
-	 * the reply is BlkData
-	 * */
-	log.Printf("request block %v from datanode %v\n", seg, addr)
-	args := datanode.RequestBlkArgs{}
-	args.BlkID = seg
-	reply := utils.BlkData{}
-	c, err := rpc.DialHTTP("tcp", addr)
-	log.Printf("request %v from %v\n", seg, addr)
+// parseSize parses a size in byte with an optional k, m or g suffix,
+// e.g. 64m
+func parseSize(s string) (int64, error) {
+	if s == "" {
+		return 0, errors.New("empty size")
+	}
+	unit := int64(1)
+	switch strings.ToLower(s[len(s)-1:]) {
+	case "k":
+		unit = 1 << 10
+	case "m":
+		unit = 1 << 20
+	case "g":
+		unit = 1 << 30
+	}
+	if unit != 1 {
+		s = s[:len(s)-1]
+	}
+	size, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
-		log.Fatal("dialing: ", err)
+		return 0, err
+	}
+	if size <= 0 {
+		return 0, errors.New("size should be positive")
+	}
+	return size * unit, nil
+}
+
+func runAppendToFile() error {
+	logger.Debugf("enter runAppendToFile\n")
+	if len(os.Args) < 4 {
+		return usagef("appendToFile expects at least 2 arguments <localsrc> ... <dst>, got %v",
+			len(os.Args)-2)
 	}
-	err = c.Call("DataNode.RequestBlk", &args, &reply)
+	return c.AppendToFile(os.Args[2:len(os.Args)-1], os.Args[len(os.Args)-1])
+}
+
+func runCopyToLocal() error {
+	logger.Debugf("enter runCopyToLocal\n")
+	if len(os.Args) != 4 {
+		return usagef("copyToLocal expects 2 arguments <dst> <localsrc>, got %v",
+			len(os.Args)-2)
+	}
+	return c.CopyToLocal(os.Args[2], os.Args[3])
+}
+
+func runLs() error {
+	logger.Debugf("enter runLs\n")
+	recursive, long := false, false
+	args := os.Args[2:]
+	for len(args) > 1 && strings.HasPrefix(args[0], "-") {
+		switch args[0] {
+		case "-R":
+			recursive = true
+		case "-l":
+			long = true
+		default:
+			return usagef("unknown ls flag %q", args[0])
+		}
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		return usagef("ls expects 1 argument, got %v", len(args))
+	}
+	if jsonOut {
+		// a FileStat per entry, whatever the flags
+		stats, err := c.List(args[0], recursive)
+		if err != nil {
+			return err
+		}
+		if stats == nil {
+			stats = []namenode.FileStat{}
+		}
+		return printJSON(stats)
+	}
+	if !recursive && !long {
+		files, err := c.Ls(args[0])
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			fmt.Printf("%v\t", file)
+		}
+		fmt.Printf("\n")
+		return nil
+	}
+	stats, err := c.List(args[0], recursive)
 	if err != nil {
-		log.Fatal("Calling: ", err)
+		return err
 	}
-	checksum := crc32.ChecksumIEEE(reply.Data)
-	// if checksum mismatch, corrupted!
-	if checksum != reply.Checksum {
-		log.Printf("data is corrupted for %v from %v!\n", seg, addr)
-		return []byte{}, 0, false
+	if !long {
+		for _, stat := range stats {
+			fmt.Printf("%v\n", stat.Path)
+		}
+		return nil
+	}
+	// one line per entry: type replication size mtime name, in columns.
+	// -R and patterns show full paths, plain -l the names as ls does.
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, stat := range stats {
+		kind, rep := "-", strconv.Itoa(stat.Replication)
+		if stat.IsDir {
+			kind, rep = "d", "-"
+		}
+		name := stat.Path
+		if !recursive && !strings.ContainsAny(args[0], "*?[") {
+			name = path.Base(name)
+		}
+		mtime := time.Unix(0, stat.ModTime*int64(time.Millisecond))
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t %v\n", kind, rep, stat.Size,
+			mtime.Format("2006-01-02 15:04"), name)
 	}
-	log.Printf("data is ok for %v from %v\n", seg, addr)
-	return reply.Data, reply.Length, true
+	return w.Flush()
 }
 
-func writeLocalFile(file *os.File, data []byte, length int) {
-	// write bytes to local file
-	_, err := file.Write(data[:length])
+func runDu() error {
+	logger.Debugf("enter runDu\n")
+	replicated := false
+	args := os.Args[2:]
+	if len(args) == 2 && args[0] == "-replicated" {
+		replicated = true
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		return usagef("du expects 1 argument <path>, got %v", len(args))
+	}
+	usage, err := c.Du(args[0])
 	if err != nil {
-		log.Printf("error writing to local file: %v\n", err)
+		return err
+	}
+	// one line per child and a last one for the total: size [replicated] path
+	for _, du := range usage {
+		if replicated {
+			fmt.Printf("%v\t%v\t%v\n", du.Size, du.Replicated, du.Path)
+		} else {
+			fmt.Printf("%v\t%v\n", du.Size, du.Path)
+		}
 	}
+	return nil
 }
 
-func runLs() {
-	log.Printf("enter runLs\n")
+func runFsck() error {
+	logger.Debugf("enter runFsck\n")
 	if len(os.Args) != 3 {
-		log.Fatalf("ls expects 1 argument, got %v\n", len(os.Args)-2)
-	}
-	path := os.Args[2]
-	args := namenode.CommandArgs{}
-	args.CommandType = config.Ls
-	args.DPath = path
-	reply := namenode.CommandReply{}
-	err := c.Call("NameNode.RunCommand", &args, &reply)
+		return usagef("fsck expects 1 argument <path>, got %v", len(os.Args)-2)
+	}
+	report, err := c.Fsck(os.Args[2])
 	if err != nil {
-		log.Fatal("Calling: ", err)
+		return err
+	}
+	var missing, under, over, corrupt int
+	for _, file := range report.Unhealthy {
+		fmt.Printf("%v: %v blocks\n", file.Path, file.NumBlks)
+		for _, problem := range []struct {
+			name string
+			blks []string
+		}{
+			{"MISSING", file.Missing},
+			{"UNDER REPLICATED", file.UnderReplicated},
+			{"OVER REPLICATED", file.OverReplicated},
+			{"CORRUPT", file.Corrupt},
+		} {
+			for _, blk := range problem.blks {
+				fmt.Printf("\t%v %v\n", problem.name, blk)
+			}
+		}
+		missing += len(file.Missing)
+		under += len(file.UnderReplicated)
+		over += len(file.OverReplicated)
+		corrupt += len(file.Corrupt)
+	}
+	status := "HEALTHY"
+	if missing+corrupt > 0 {
+		status = "CORRUPT"
+	}
+	fmt.Printf("Status: %v\n", status)
+	fmt.Printf(" Total files:\t%v\n", report.Files)
+	fmt.Printf(" Total blocks:\t%v\n", report.Blks)
+	fmt.Printf(" Missing blocks:\t%v\n", missing)
+	fmt.Printf(" Under-replicated blocks:\t%v\n", under)
+	fmt.Printf(" Over-replicated blocks:\t%v\n", over)
+	fmt.Printf(" Corrupt blocks:\t%v\n", corrupt)
+	if status != "HEALTHY" {
+		return fmt.Errorf("The filesystem under %v is CORRUPT", os.Args[2])
+	}
+	return nil
+}
+
+func runSetQuota() error {
+	logger.Debugf("enter runSetQuota\n")
+	name := strings.TrimPrefix(os.Args[1], "-")
+	if len(os.Args) != 4 {
+		return usagef("%v expects 2 arguments <quota> <path>, got %v", name, len(os.Args)-2)
 	}
-	if reply.Files != nil {
-		for _, file := range reply.Files {
-			fmt.Printf("%v\t", file)
+	quota, err := strconv.ParseInt(os.Args[2], 10, 64)
+	if err != nil || quota < 0 {
+		return usagef("invalid quota %q", os.Args[2])
+	}
+	if name == "setSpaceQuota" {
+		return c.SetSpaceQuota(os.Args[3], quota)
+	}
+	return c.SetQuota(os.Args[3], quota)
+}
+
+func runCount() error {
+	logger.Debugf("enter runCount\n")
+	args := os.Args[2:]
+	quotas := len(args) == 2 && args[0] == "-q"
+	if quotas {
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		return usagef("count expects 1 argument [-q] <path>, got %v", len(os.Args)-2)
+	}
+	count, err := c.Count(args[0])
+	if err != nil {
+		return err
+	}
+	// like hdfs dfs -count: [QUOTA REM_QUOTA SPACE_QUOTA REM_SPACE_QUOTA]
+	// DIR_COUNT FILE_COUNT CONTENT_SIZE PATHNAME
+	if quotas {
+		quota, rem := "none", "inf"
+		if count.NsQuota > 0 {
+			quota = strconv.FormatInt(count.NsQuota, 10)
+			rem = strconv.FormatInt(count.NsQuota-count.Dirs-count.Files, 10)
+		}
+		spaceQuota, spaceRem := "none", "inf"
+		if count.SpaceQuota > 0 {
+			spaceQuota = strconv.FormatInt(count.SpaceQuota, 10)
+			spaceRem = strconv.FormatInt(count.SpaceQuota-count.SpaceConsumed, 10)
 		}
+		fmt.Printf("%12v %15v %15v %15v ", quota, rem, spaceQuota, spaceRem)
 	}
-	fmt.Printf("\n")
+	fmt.Printf("%12v %12v %18v %v\n", count.Dirs, count.Files, count.Bytes, count.Path)
+	return nil
 }
 
-func runMkdir() {
-	log.Printf("enter runMkdir\n")
+func runMkdir() error {
+	logger.Debugf("enter runMkdir\n")
 	if len(os.Args) < 3 {
-		log.Fatalf("Insufficient number of argument\n")
+		return usagef("Insufficient number of argument")
 	}
 	if os.Args[2] == "-p" && len(os.Args) == 4 {
 		// cool. mkdir -p somepath
+		return c.Mkdir(os.Args[3], true)
 	} else if os.Args[2] != "-p" && len(os.Args) == 3 {
 		// super cool. mkdir somepath
-	} else { // bad :(
-		log.Fatalf("Invalid argument\n")
-	}
-	args := namenode.CommandArgs{}
-	if os.Args[2] == "-p" {
-		args.CommandType = config.MkdirP
-		args.DPath = os.Args[3]
-	} else {
-		args.CommandType = config.Mkdir
-		args.DPath = os.Args[2]
-	}
-	reply := namenode.CommandReply{}
-	err := c.Call("NameNode.RunCommand", &args, &reply)
+		return c.Mkdir(os.Args[2], false)
+	}
+	return usagef("Invalid argument") // bad :(
+}
+
+func runRm() error {
+	logger.Debugf("enter runRm\n")
+	if len(os.Args) < 3 {
+		return usagef("Insufficient number of argument")
+	}
+	paths := os.Args[2:]
+	skipTrash := paths[0] == "-skipTrash"
+	if skipTrash {
+		paths = paths[1:]
+		if len(paths) == 0 {
+			return usagef("Insufficient number of argument")
+		}
+	}
+	removed, trashed, err := c.Remove(skipTrash, paths...)
+	var perrs client.PathErrors
+	if err != nil && !errors.As(err, &perrs) {
+		return err
+	}
+	for _, path := range removed {
+		if target, ok := trashed[path]; ok {
+			fmt.Printf("Moved %v to trash at %v\n", path, target)
+		} else {
+			fmt.Printf("Deleted %v\n", path)
+		}
+	}
+	return err
+}
+
+func runExpunge() error {
+	logger.Debugf("enter runExpunge\n")
+	if len(os.Args) != 2 {
+		return usagef("expunge expects no argument, got %v", len(os.Args)-2)
+	}
+	result, err := c.Expunge()
 	if err != nil {
-		log.Fatal("Calling: ", err)
+		return err
+	}
+	fmt.Printf("%v\n", result)
+	return nil
+}
+
+func runRmdir() error {
+	logger.Debugf("enter runRmdir\n")
+	if len(os.Args) < 3 {
+		return usagef("Insufficient number of argument")
+	}
+	return c.Rmdir(os.Args[2:]...)
+}
+
+func runSetRep() error {
+	logger.Debugf("enter runSetRep\n")
+	if len(os.Args) != 4 {
+		return usagef("setrep expects 2 arguments <rep> <path>, got %v",
+			len(os.Args)-2)
+	}
+	rep, err := strconv.Atoi(os.Args[2])
+	if err != nil || rep <= 0 {
+		return usagef("invalid replication factor %q", os.Args[2])
+	}
+	return c.SetRep(os.Args[3], rep)
+}
+
+func runTruncate() error {
+	logger.Debugf("enter runTruncate\n")
+	if len(os.Args) != 4 {
+		return usagef("truncate expects 2 arguments <length> <path>, got %v",
+			len(os.Args)-2)
+	}
+	length, err := strconv.ParseInt(os.Args[2], 10, 64)
+	if err != nil || length < 0 {
+		return usagef("invalid length %q", os.Args[2])
+	}
+	return c.Truncate(os.Args[3], length)
+}
+
+func runTopN() error {
+	logger.Debugf("enter runTopN\n")
+	args := os.Args[2:]
+	lexical := len(args) > 0 && args[0] == "-l"
+	if lexical {
+		args = args[1:]
+	}
+	if len(args) != 2 {
+		return usagef("topN expects 2 arguments [-l] <k> <src>, got %v",
+			len(os.Args)-2)
+	}
+	k, err := strconv.Atoi(args[0])
+	if err != nil || k <= 0 {
+		return usagef("invalid count %q", args[0])
+	}
+	top, err := c.TopN(args[1], k, lexical)
+	if err != nil {
+		return err
+	}
+	for _, line := range top {
+		fmt.Printf("%v\n", line)
+	}
+	return nil
+}
+
+func runStat() error {
+	logger.Debugf("enter runStat\n")
+	if len(os.Args) < 3 {
+		return usagef("Insufficient number of argument")
+	}
+	stats, err := c.Stat(os.Args[2:]...)
+	if jsonOut {
+		if stats == nil {
+			stats = []namenode.FileStat{}
+		}
+		if jerr := printJSON(stats); err == nil {
+			err = jerr
+		}
+		return err
+	}
+	// one line per path: type size blocks blocksize replication mtime
+	// atime path, atime is - for directories
+	for _, stat := range stats {
+		kind := "file"
+		if stat.IsDir {
+			kind = "directory"
+		}
+		mtime := time.Unix(0, stat.ModTime*int64(time.Millisecond))
+		atime := "-"
+		if stat.AccessTime > 0 {
+			atime = time.Unix(0, stat.AccessTime*int64(time.Millisecond)).Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", kind, stat.Size, stat.NumBlks,
+			stat.BlkSize, stat.Replication, mtime.Format("2006-01-02 15:04:05"), atime,
+			stat.Path)
+	}
+	return err
+}
+
+func runChecksum() error {
+	logger.Debugf("enter runChecksum\n")
+	if len(os.Args) < 3 {
+		return usagef("Insufficient number of argument")
+	}
+	checksums, err := c.Checksum(os.Args[2:]...)
+	if jsonOut {
+		// keyed by path, sorted by encoding/json
+		if checksums == nil {
+			checksums = map[string]string{}
+		}
+		if jerr := printJSON(checksums); err == nil {
+			err = jerr
+		}
+		return err
+	}
+	// patterns expand to several paths, print them in order
+	paths := make([]string, 0, len(checksums))
+	for path := range checksums {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Printf("%v\tMD5-of-CRC32\t%v\n", path, checksums[path])
 	}
+	return err
 }
 
-func runRm() {
-	log.Printf("enter runRm\n")
+func runTouch() error {
+	logger.Debugf("enter runTouch\n")
+	if len(os.Args) < 3 {
+		return usagef("Insufficient number of argument")
+	}
+	return c.Touch(os.Args[2:]...)
 }
 
-func runRmdir() {
-	log.Printf("enter runRmdir\n")
+func runDfsAdmin() error {
+	logger.Debugf("enter runDfsAdmin\n")
 	if len(os.Args) < 3 {
-		log.Fatalf("Insufficient number of argument\n")
+		return usagef("dfsadmin expects an option, got none")
 	}
-	args := namenode.CommandArgs{}
-	reply := namenode.CommandReply{}
-	args.CommandType = config.Rmdir
-	args.DPaths = os.Args[2:]
-	err := c.Call("NameNode.RunCommand", &args, &reply)
+	if os.Args[2] == "-decommission" {
+		if len(os.Args) != 4 {
+			return usagef("dfsadmin -decommission expects 1 argument <addr>, got %v",
+				len(os.Args)-3)
+		}
+		result, err := c.Decommission(os.Args[3])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%v\n", result)
+		return nil
+	}
+	if len(os.Args) != 3 {
+		return usagef("dfsadmin expects 1 argument, got %v", len(os.Args)-2)
+	}
+	switch os.Args[2] {
+	case "-balance":
+		result, err := c.Balance()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%v\n", result)
+		return nil
+	case "-report":
+		return runReport()
+	default:
+		return usagef("dfsadmin: unknown option %v", os.Args[2])
+	}
+}
+
+func runDf() error {
+	logger.Debugf("enter runDf\n")
+	if len(os.Args) != 2 {
+		return usagef("df expects no argument, got %v", len(os.Args)-2)
+	}
+	status, err := c.ClusterStatus()
 	if err != nil {
-		log.Fatal("Calling: ", err)
+		return err
+	}
+	if jsonOut {
+		if status.Nodes == nil {
+			status.Nodes = []namenode.NodeStatus{}
+		}
+		return printJSON(status)
+	}
+	printUsage := func(capacity, used, remaining uint64) {
+		fmt.Printf("Configured Capacity: %v (%v)\n", capacity, formatBytes(capacity))
+		fmt.Printf("DFS Used: %v (%v)\n", used, formatBytes(used))
+		fmt.Printf("DFS Remaining: %v (%v)\n", remaining, formatBytes(remaining))
+		pct := 0.0
+		if capacity > 0 {
+			pct = 100 * float64(used) / float64(capacity)
+		}
+		fmt.Printf("DFS Used%%: %.2f%%\n", pct)
+	}
+	printUsage(status.Capacity, status.Used, status.Remaining)
+	fmt.Printf("\nLive datanodes (%v):\n", len(status.Nodes))
+	for _, node := range status.Nodes {
+		fmt.Printf("\nName: %v (%v)\n", node.Addr, node.HostName)
+		printUsage(node.Capacity, node.Used, node.Remaining)
+		last := time.Unix(0, node.LastHeartBeat*int64(time.Millisecond))
+		fmt.Printf("Last contact: %v\n", last.Format("2006-01-02 15:04:05"))
 	}
+	return nil
 }
 
-func runTouch() {
-	log.Printf("enter runTouch\n")
+// formatBytes writes a size in the largest binary unit it reaches
+func formatBytes(b uint64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+	size, i := float64(b), 0
+	for size >= 1024 && i < len(units)-1 {
+		size /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%v B", b)
+	}
+	return fmt.Sprintf("%.2f %v", size, units[i])
 }
 
-func runFormat() {
-	log.Printf("enter runFormat\n")
+// runReport prints one row per datanode: address, storage id, state,
+// capacity, used space, utilization and last heartbeat. Dead datanodes,
+// decommissioned ones included, show when they were declared dead instead.
+func runReport() error {
+	nodes, err := c.ReportNodes()
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		if nodes == nil {
+			nodes = []namenode.NodeStatus{}
+		}
+		return printJSON(nodes)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ADDRESS\tSTORAGE ID\tSTATE\tCAPACITY\tUSED\tUSED%%\tLAST CONTACT\n")
+	for _, node := range nodes {
+		if node.DeadSince != 0 {
+			since := time.Unix(0, node.DeadSince*int64(time.Millisecond))
+			fmt.Fprintf(w, "%v\t-\t%v\t-\t-\t-\tgone since %v\n", node.Addr, node.State,
+				since.Format("2006-01-02 15:04:05"))
+			continue
+		}
+		last := time.Unix(0, node.LastHeartBeat*int64(time.Millisecond))
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%.2f%%\t%v\n", node.Addr, node.StorageID,
+			node.State, formatBytes(node.Capacity), formatBytes(node.Used),
+			100*node.FracInUse, last.Format("2006-01-02 15:04:05"))
+	}
+	return w.Flush()
+}
+
+func runFormat() error {
+	logger.Debugf("enter runFormat\n")
 	if len(os.Args) != 2 {
-		log.Fatalf("format expects no argument, got %v\n", len(os.Args)-2)
+		return usagef("format expects no argument, got %v", len(os.Args)-2)
 	}
-	args := namenode.CommandArgs{}
-	args.CommandType = config.Format
-	reply := namenode.CommandReply{}
-	err := c.Call("NameNode.RunCommand", &args, &reply)
+	err := c.Format()
 	if err != nil {
-		log.Fatal("Calling: ", err)
+		return err
 	}
-	log.Printf("Format succeed!\n")
+	logger.Infof("Format succeed!\n")
+	return nil
 }
 
-func main() {
-	gob.Register(utils.BlkData{})
-	if len(os.Args) == 1 {
-		printHelp()
+// parseGlobals applies the options preceding the command, a later option
+// overriding an earlier one, and returns the arguments left
+func parseGlobals(args []string) ([]string, error) {
+	for len(args) > 0 {
+		switch args[0] {
+		case "-json":
+			jsonOut = true
+			args = args[1:]
+			continue
+		case "-conf", "-namenode", "-log":
+		default:
+			return args, nil
+		}
+		if len(args) == 1 {
+			return nil, usagef("%v expects a value", args[0])
+		}
+		switch args[0] {
+		case "-conf":
+			if err := config.Load(args[1]); err != nil {
+				return nil, err
+			}
+		case "-namenode":
+			if _, _, err := utils.SplitHostPort(args[1]); err != nil {
+				return nil, usagef("invalid namenode address %q: %v", args[1], err)
+			}
+			config.NameNodeAddress = args[1]
+		case "-log":
+			// stderr by default, stdout only holds the output of commands
+			f, err := os.OpenFile(args[1], os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+			if err != nil {
+				return nil, err
+			}
+			log.SetOutput(f)
+		}
+		args = args[2:]
 	}
-	var err error
-	c, err = rpc.DialHTTP("tcp", config.NameNodeAddress)
+	return args, nil
+}
+
+// run runs the command line args, the arguments following the program
+// name, and returns the exit status of the client, see exitCode
+func run(args []string) int {
+	args, err := parseGlobals(args)
 	if err != nil {
-		log.Fatal("dialing: ", err)
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return exitCode(err)
 	}
-	defer c.Close()
-	switch os.Args[1] {
-	case "-calMeanVar":
-		runCalMeanVar()
-	case "-cat":
-		runCat()
-	case "-copyFromLocal":
-		runCopyFromLocal()
-	case "-copyToLocal":
-		runCopyToLocal()
-	case "-help", "help", "-h":
+	os.Args = append(os.Args[:1], args...)
+	if len(os.Args) == 1 {
 		printHelp()
-	case "-ls":
-		runLs()
-	case "-mkdir":
-		runMkdir()
-	case "-rm":
-		runRm()
-	case "-rmdir":
-		runRmdir()
-	case "-touch":
-		runTouch()
-	case "format", "-format":
-		runFormat()
-	default:
-		fmt.Printf("%q is not a valid command.\n", os.Args[1])
-		os.Exit(2)
+		return exitUsage
+	}
+	switch os.Args[1] {
+	case "help", "-h":
+		os.Args[1] = "-help"
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "%q is not a valid command.\n", os.Args[1])
+		return exitUsage
 	}
+	if cmd.local {
+		err = cmd.run()
+	} else if c, err = client.New(config.NameNodeAddress); err == nil {
+		err = cmd.run()
+		c.Close()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+	return exitCode(err)
+}
+
+func main() {
+	gob.Register(utils.BlkData{})
+	os.Exit(run(os.Args[1:]))
 }
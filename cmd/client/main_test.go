@@ -0,0 +1,202 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/WineChord/gdfs/client"
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/minicluster"
+)
+
+func TestMain(m *testing.M) {
+	// the nodes of test clusters log to the same output
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+// startCluster starts a cluster of numNodes datanodes, c is its client
+// until the test ends
+func startCluster(t *testing.T, numNodes int) *minicluster.Cluster {
+	cluster := minicluster.Start(t, numNodes)
+	c = cluster.Client
+	t.Cleanup(func() { c = nil })
+	return cluster
+}
+
+// capture runs f and returns what it wrote to stdout and stderr
+func capture(t *testing.T, f func()) (stdout, stderr string) {
+	outFile, err := ioutil.TempFile(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	errFile, err := ioutil.TempFile(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(out, err *os.File) { os.Stdout, os.Stderr = out, err }(os.Stdout, os.Stderr)
+	os.Stdout, os.Stderr = outFile, errFile
+	f()
+	out, _ := ioutil.ReadFile(outFile.Name())
+	errs, _ := ioutil.ReadFile(errFile.Name())
+	return string(out), string(errs)
+}
+
+// runCmd runs a command line as main does once connected and returns
+// what the command wrote to stdout
+func runCmd(t *testing.T, args ...string) (stdout string, err error) {
+	defer func(args []string) { os.Args = args }(os.Args)
+	os.Args = append([]string{"client"}, args...)
+	stdout, _ = capture(t, func() { err = commands[args[0]].run() })
+	return stdout, err
+}
+
+func TestParseGlobals(t *testing.T) {
+	defer func(addr string) { config.NameNodeAddress = addr }(config.NameNodeAddress)
+	args, err := parseGlobals([]string{"-namenode", "10.0.0.1:9000", "-ls", "/"})
+	if err != nil || !reflect.DeepEqual(args, []string{"-ls", "/"}) {
+		t.Fatalf("parseGlobals = %q, %v, want [-ls /]", args, err)
+	}
+	if config.NameNodeAddress != "10.0.0.1:9000" {
+		t.Errorf("namenode address is %v", config.NameNodeAddress)
+	}
+
+	// flags apply in order, the last one wins
+	conf := filepath.Join(t.TempDir(), "gdfs.json")
+	err = ioutil.WriteFile(conf, []byte(`{"NameNodeAddress": "10.0.0.2:9000"}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err = parseGlobals([]string{"-namenode", "10.0.0.1:9000", "-conf", conf, "-ls", "/"})
+	if err != nil || len(args) != 2 || config.NameNodeAddress != "10.0.0.2:9000" {
+		t.Errorf("parseGlobals with -conf = %q, %v, namenode at %v", args, err,
+			config.NameNodeAddress)
+	}
+
+	for _, bad := range [][]string{
+		{"-namenode"},
+		{"-namenode", "no-port", "-ls", "/"},
+		{"-conf"},
+	} {
+		if _, err := parseGlobals(bad); exitCode(err) != exitUsage {
+			t.Errorf("parseGlobals(%q) = %v, want a usage error", bad, err)
+		}
+	}
+	err = ioutil.WriteFile(conf, []byte(`{"NameNodeAddres": "typo:9000"}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseGlobals([]string{"-conf", conf, "-ls", "/"}); err == nil {
+		t.Errorf("config file with an unknown field is accepted")
+	}
+}
+
+// TestLogsOffStdout checks stdout holds the result of a command only, its
+// logs going to stderr or the file of -log
+func TestLogsOffStdout(t *testing.T) {
+	// set before the cluster starts and restored once it stopped, since
+	// its nodes read the level whenever they log
+	level := config.LogLevel
+	t.Cleanup(func() { config.LogLevel = level })
+	config.LogLevel = "debug"
+	startCluster(t, 1)
+	logFile := filepath.Join(t.TempDir(), "client.log")
+	if _, err := parseGlobals([]string{"-log", logFile}); err != nil {
+		t.Fatal(err)
+	}
+	defer log.SetOutput(ioutil.Discard)
+	if err := c.Mkdir("/d", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Touch("/d/f", "/d/g"); err != nil {
+		t.Fatal(err)
+	}
+	if stdout, err := runCmd(t, "-ls", "/d"); err != nil || stdout != "f\tg\t\n" {
+		t.Errorf("ls wrote %q, %v, want the entries only", stdout, err)
+	}
+	logs, err := ioutil.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"DEBUG enter runLs", "DEBUG called with args"} {
+		if !strings.Contains(string(logs), want) {
+			t.Errorf("log file misses %q", want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	cluster := startCluster(t, 1)
+	if err := c.Mkdir("/d/e", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile("f", []byte("some data\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{"/d", "/d/e"} {
+		if err := c.CopyFromLocalOpts("f", dir, client.WriteOptions{Replication: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cluster.WaitHealthy("/d"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetQuota("/d", 10); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-count", "/d"}, "           2            2                 20 /d\n"},
+		{[]string{"-count", "-q", "/d"}, "          10               6            none             inf " +
+			"           2            2                 20 /d\n"},
+	} {
+		if stdout, err := runCmd(t, tc.args...); err != nil || stdout != tc.want {
+			t.Errorf("%v wrote %q, %v, want %q", tc.args, stdout, err, tc.want)
+		}
+	}
+	if _, err := runCmd(t, "-count", "/d", "/d/e"); err == nil {
+		t.Errorf("count of two paths succeeded")
+	}
+}
+
+func TestCopyFromLocalRep(t *testing.T) {
+	cluster := startCluster(t, 3)
+	if err := ioutil.WriteFile("f", []byte("some data\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCmd(t, "-copyFromLocal", "-rep", "2", "f", "/"); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := c.Stat("/f")
+	if err != nil || stats[0].Replication != 2 {
+		t.Fatalf("stat = %+v, %v, want replication 2", stats, err)
+	}
+	// healthy means neither under nor over replicated
+	if err := cluster.WaitHealthy("/f"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCmd(t, "-copyFromLocal", "-rep", "4", "f", "/g"); err == nil {
+		t.Errorf("copyFromLocal -rep 4 on 3 datanodes succeeded")
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func runShell() error {
+	if len(os.Args) != 2 {
+		return usagef("shell expects no argument, got %v", len(os.Args)-2)
+	}
+	return shell(os.Stdin)
+}
+
+// shell runs the commands read from r, one per line, until EOF over the
+// connection of the client. Commands are written as on the command line,
+// the leading dash may be left out: "ls -l a" runs -ls -l a. Relative
+// dfs paths are resolved against the working directory, which cd changes.
+// A failing command is reported and the next one runs.
+func shell(r io.Reader) error {
+	prog := os.Args[0]
+	defer func() { os.Args = []string{prog} }()
+	scanner := bufio.NewScanner(r)
+	for {
+		/** the prompt goes to stderr as in sh, stdout only holds the
+		 * output of the commands
+		 * */
+		fmt.Fprintf(os.Stderr, "gdfs:%v> ", c.Abs(""))
+		if !scanner.Scan() {
+			break
+		}
+		args := strings.Fields(scanner.Text())
+		if len(args) == 0 || strings.HasPrefix(args[0], "#") {
+			continue
+		}
+		name := strings.TrimPrefix(args[0], "-")
+		if name == "exit" || name == "quit" {
+			break
+		}
+		if err := runLine(prog, name, args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\n")
+	return scanner.Err()
+}
+
+// runLine runs command name of the shell with its arguments
+func runLine(prog, name string, args []string) error {
+	switch name {
+	case "cd":
+		return runCd(args)
+	case "h":
+		name = "help"
+	case "shell":
+		return usagef("already in the shell")
+	}
+	cmd, err := lookup(name)
+	if err != nil {
+		return err
+	}
+	os.Args = append([]string{prog, "-" + name}, args...)
+	return cmd.run()
+}
+
+// runCd changes the working directory of the shell, to "/" without
+// argument
+func runCd(args []string) error {
+	if len(args) > 1 {
+		return usagef("cd expects at most 1 argument <dir>, got %v", len(args))
+	}
+	dir := "/"
+	if len(args) == 1 {
+		dir = c.Abs(args[0])
+	}
+	stats, err := c.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if len(stats) != 1 || !stats[0].IsDir {
+		return fmt.Errorf("cd: %v: Not a directory", dir)
+	}
+	c.Cwd = dir
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// runScript runs script in the shell of a client of a new cluster and
+// returns what the shell wrote to stdout and stderr
+func runScript(t *testing.T, script string) (stdout, stderr string) {
+	startCluster(t, 1)
+	return capture(t, func() {
+		if err := shell(strings.NewReader(script)); err != nil {
+			t.Errorf("shell: %v", err)
+		}
+	})
+}
+
+func TestShell(t *testing.T) {
+	stdout, stderr := runScript(t, `
+format
+mkdir -p /a/b
+# relative paths are resolved against the working directory, .. stops
+# at the root
+cd /a
+touch f
+-ls .
+cd b
+pwd
+touch g
+ls /a/b
+cd /a/f
+cd /missing
+nosuchcmd
+ls a b
+cd ../../..
+pwd
+ls a
+exit
+ls /never/run
+`)
+	if want := "b\tf\t\n/a/b\ng\t\n/\nb\tf\t\n"; stdout != want {
+		t.Errorf("shell wrote %q to stdout, want %q", stdout, want)
+	}
+	for _, want := range []string{
+		"gdfs:/> ", "gdfs:/a> ", "gdfs:/a/b> ",
+		"cd: /a/f: Not a directory",
+		`"nosuchcmd" is not a valid command`,
+		"ls expects 1 argument, got 2",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("shell wrote %q to stderr, missing %q", stderr, want)
+		}
+	}
+	if strings.Contains(stderr, "/never/run") {
+		t.Errorf("shell ran commands after exit: %q", stderr)
+	}
+}
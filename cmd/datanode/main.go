@@ -14,9 +14,38 @@
 
 package main
 
-import "github.com/WineChord/gdfs/datanode"
+import (
+	"flag"
+	"log"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/datanode"
+	"github.com/WineChord/gdfs/utils"
+)
 
 func main() {
+	conf := flag.String("conf", "", "config file overriding the configuration, see config.File")
+	port := flag.String("port", "", "port to serve clients at, overriding config.DataNodePort")
+	nn := flag.String("namenode", "", "host:port of namenode, overriding config.NameNodeAddress")
+	data := flag.String("data", "", "directory of the datanode files, see config.SetDataPath")
+	flag.Parse()
+	if *conf != "" {
+		if err := config.Load(*conf); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *port != "" {
+		config.DataNodePort = *port
+	}
+	if *nn != "" {
+		if _, _, err := utils.SplitHostPort(*nn); err != nil {
+			log.Fatalf("invalid namenode address %q: %v", *nn, err)
+		}
+		config.NameNodeAddress = *nn
+	}
+	if *data != "" {
+		config.SetDataPath(*data)
+	}
 	d := datanode.NewDataNode()
 	d.Run()
 }
@@ -15,10 +15,30 @@
 package main
 
 import (
+	"flag"
+	"log"
+
+	"github.com/WineChord/gdfs/config"
 	"github.com/WineChord/gdfs/namenode"
+	"github.com/WineChord/gdfs/utils"
 )
 
 func main() {
+	conf := flag.String("conf", "", "config file overriding the configuration, see config.File")
+	port := flag.String("port", "", "port to serve at, overriding the port of config.NameNodeAddress")
+	flag.Parse()
+	if *conf != "" {
+		if err := config.Load(*conf); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *port != "" {
+		host, _, err := utils.SplitHostPort(config.NameNodeAddress)
+		if err != nil {
+			log.Fatal(err)
+		}
+		config.NameNodeAddress = utils.JoinHostPort(host, *port)
+	}
 	n := namenode.NewNameNode()
 	n.Run()
 }
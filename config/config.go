@@ -14,7 +14,10 @@
 
 package config
 
-import "os"
+import (
+	"net"
+	"os"
+)
 
 var (
 	thumm01      = "192.168.0.101"
@@ -27,31 +30,192 @@ var (
 	NameNodePort = "21170"
 	// DataNodePort is the port for data node
 	DataNodePort = "11170"
-	// NameNodeAddress is the address for name node
-	NameNodeAddress = nameNodeHost + ":" + NameNodePort
+	// NameNodeMetricsPort is the port namenode serves Prometheus metrics
+	// on at /metrics, empty disables it
+	NameNodeMetricsPort = "21180"
+	// NameNodeWebPort is the port of the read-only web UI of namenode,
+	// served in plain HTTP without authentication, empty disables it
+	NameNodeWebPort = "21190"
+	// DataNodeMetricsPort is the port datanodes serve Prometheus metrics
+	// on at /metrics, empty disables it
+	DataNodeMetricsPort = "11180"
+	// DataNodeHost is the host or IP a datanode advertises and listens on,
+	// empty picks an address its hostname resolves to, see PreferIPv6
+	DataNodeHost = ""
+	// PreferIPv6 makes a datanode pick an IPv6 address of its hostname
+	// over IPv4 ones
+	PreferIPv6 = false
+	// NameNodeAddress is the address for name node, IPv6 hosts are
+	// bracketed by net.JoinHostPort (utils.JoinHostPort imports config)
+	NameNodeAddress = net.JoinHostPort(nameNodeHost, NameNodePort)
 	dataNodeHosts   = []string{thumm01, thumm02, thumm03, thumm04, thumm05}
 	// DFSRootPath is the local path to file system metadata
 	DFSRootPath = "meta/gdfs"
 	// NNamespaceIDPath is NameNode's namespace id path
 	NNamespaceIDPath = "meta" + string(os.PathSeparator) + "nid"
-	// DataPath for datanode to store data block replicas
+	// NStatePath is NameNode's persistent cluster state (storage ids and replicas)
+	NStatePath = "meta" + string(os.PathSeparator) + "state"
+	// NEditLogPath is NameNode's edit log of namespace mutations
+	NEditLogPath = "meta" + string(os.PathSeparator) + "edits"
+	// NFSImagePath is NameNode's checkpoint of the namespace
+	NFSImagePath = "meta" + string(os.PathSeparator) + "fsimage"
+	// DataPath for datanode to store its namespace id and storage id
 	DataPath = "data"
 	// NamespaceIDPath specifies the path of namespace id
 	NamespaceIDPath = DataPath + string(os.PathSeparator) + "nid"
 	// StorageIDPath specifies the path of storage id
 	StorageIDPath = DataPath + string(os.PathSeparator) + "sid"
-	// IDToMetaDataPath is the path for metadata on datanode
-	IDToMetaDataPath = DataPath + string(os.PathSeparator) + "id2meta"
-	// ActualDataPath is the path for actual data on datanode
-	ActualDataPath = DataPath + string(os.PathSeparator) + "actdata"
+	// DataDirs are the volumes datanode stores block replicas in,
+	// typically one per disk. Each volume keeps metadata in id2meta,
+	// actual data in actdata and quarantined blocks in corrupt.
+	DataDirs = []string{DataPath}
+	// VolumeChoice is how datanode picks the volume of a new block,
+	// "round-robin" or "most-free" (most space available)
+	VolumeChoice = "round-robin"
+	// VolumeMaxErrors is the number of I/O errors in a row on a volume
+	// after which datanode stops using it and reports its blocks lost
+	VolumeMaxErrors = 3
+	// EncryptBlks encrypts the block files of datanodes with AES-GCM, the
+	// key is 64 hex digits (AES-256) taken from the environment variable
+	// BlkKeyEnv if it is set, from BlkKeyFile otherwise. Existing plaintext
+	// blocks can't be read once it is switched on, format datanodes first.
+	EncryptBlks = false
+	// CompressBlks has client gzip the blocks it sends and ask datanodes
+	// to gzip the blocks they serve. Checksums are always of uncompressed
+	// data, so blocks are verified the same either way.
+	CompressBlks = false
+	// CompressAtRest has datanodes keep new blocks gzip compressed in
+	// their stores, under the encryption of EncryptBlks if it is on
+	CompressAtRest = false
+	// ChecksumType is the algorithm of the checksums of new blocks,
+	// "crc32", "crc32c" or "sha256". Blocks keep the type they were written
+	// with and are verified with it.
+	ChecksumType = "crc32"
+	// BlkKeyEnv is the environment variable holding the block key
+	BlkKeyEnv = "GDFS_BLK_KEY"
+	// BlkKeyFile is the file holding the block key
+	BlkKeyFile = DataPath + string(os.PathSeparator) + "blkkey"
 	// ReplicationFactor specifies number of replicas for each block
 	ReplicationFactor = 3
+	// MinDataNodes is the minimum number of live datanodes required
+	// before namenode allocates any block
+	MinDataNodes = 1
+	// MaxFracInUse is the fullness above which a datanode is not chosen for
+	// new blocks, unless there are not enough emptier datanodes
+	MaxFracInUse = 0.95
+	// BlockPlacement is the policy namenode chooses the datanodes of
+	// replicas with, "capacity" (distinct hosts, emptier and less busy
+	// datanodes first), "first-n" (the first datanodes by address) or one
+	// added with namenode.RegisterPlacementPolicy
+	BlockPlacement = "capacity"
+	// BalanceThreshold is how far a datanode's utilization may be from the
+	// cluster average before the balancer moves blocks off or onto it
+	BalanceThreshold = 0.1
+	// BalanceMaxTrans is the number of transfers in progress above which a
+	// datanode isn't given balancer moves
+	BalanceMaxTrans = 4
 	// BlkSize in byte
 	BlkSize = 4096 * 1024 // 4KB -> 4MB
+	// ChunkSize in byte, blocks are streamed to datanodes in chunks
+	ChunkSize = 64 * 1024
+	// RPCTimeoutInSec is how long an RPC may take before the caller gives up
+	RPCTimeoutInSec = 30
+	// TransferBytesPerSec limits the rate of each block transfer, a file
+	// written by client or a block replicated by a datanode, 0 for none
+	TransferBytesPerSec int64 = 0
+	// NodeBytesPerSec limits the rate of all the block transfers of a
+	// client or datanode together, 0 for none
+	NodeBytesPerSec int64 = 0
+	// PartIdleInSec is how long a block being streamed to a datanode may
+	// go without a chunk before the datanode drops it
+	PartIdleInSec = 600
+	// ConnIdleInSec is how long a pooled connection to a datanode may stay
+	// unused before it is closed, see utils.Pool
+	ConnIdleInSec = 60
+	// UploadStatePath is the local directory where client records the
+	// progress of uploads, so that a failed copyFromLocal resumes
+	UploadStatePath = os.TempDir() + string(os.PathSeparator) + "gdfs-uploads"
+	// BlkReadTimeoutInSec is how long client waits for a datanode to send a
+	// block, or a batch of them, before trying another replica
+	BlkReadTimeoutInSec = 10
+	// BlkCacheBytes is the memory datanodes keep recently read blocks in,
+	// 0 disables the cache
+	BlkCacheBytes = 64 * 1024 * 1024
+	// ParallelBlkReads is the number of blocks client downloads at once
+	ParallelBlkReads = 4
+	// MaxBlksPerRequest is the most blocks client fetches from a datanode
+	// in a single request, it bounds the memory of the batch
+	MaxBlksPerRequest = 8
+	// TLS encrypts every RPC: namenode and datanodes serve with the
+	// certificate TLSCertFile and its key TLSKeyFile, every caller
+	// verifies servers against the CA certificates of TLSCAFile. With TLS
+	// off, RPCs go in plaintext, which is meant for local testing only.
+	TLS = false
+	// TLSCertFile is the PEM certificate of namenode and datanodes
+	TLSCertFile = "tls" + string(os.PathSeparator) + "node.crt"
+	// TLSKeyFile is the PEM private key of TLSCertFile
+	TLSKeyFile = "tls" + string(os.PathSeparator) + "node.key"
+	// TLSCAFile holds the PEM certificates of the CAs trusted by callers
+	TLSCAFile = "tls" + string(os.PathSeparator) + "ca.crt"
+	// AuthToken is the secret shared by namenode, datanodes and clients,
+	// namenode and datanodes reject every call carrying another token.
+	// Empty disables authentication.
+	AuthToken = ""
+	// CwdEnv is the environment variable holding the dfs working directory
+	// of clients, relative dfs paths are resolved against it, "/" if unset
+	CwdEnv = "GDFS_CWD"
 	// HeartBeatInSec is the frequency of datanode notifies namenode
 	HeartBeatInSec = 3
 	// BlkReportInSec is the frequency of datanode reporting to namenode
 	BlkReportInSec = 600
+	// DeadNodeInSec is how long a datanode can stay silent before namenode
+	// considers it dead
+	DeadNodeInSec = 10 * HeartBeatInSec
+	// RepPendingInSec is how long namenode waits for a scheduled replication
+	// to show up in block reports before scheduling it again
+	RepPendingInSec = 5 * HeartBeatInSec
+	// BlkScanInSec is the frequency of datanode verifying its blocks on disk
+	BlkScanInSec = 3600
+	// DeleteCorruptBlk tells datanodes to remove blocks failing verification
+	DeleteCorruptBlk = true
+	// CheckpointInSec is the frequency of namenode checkpointing its namespace
+	CheckpointInSec = 3600
+	// StateFlushInSec is how often namenode dumps its cluster state to
+	// NStatePath if it changed, see namenode.saveState
+	StateFlushInSec = 1
+	// RetainedJobs is how many finished mapreduce jobs namenode keeps the
+	// status of
+	RetainedJobs = 100
+	// TrashInSec is how long files removed by rm stay in the trash of their
+	// user before namenode deletes them, 0 disables the trash
+	TrashInSec = 0
+	// TrashCheckInSec is the frequency of namenode purging expired trash
+	TrashCheckInSec = 60
+	// TrashDir is the dfs directory holding the trash of each user
+	TrashDir = "/.Trash"
+	// OrphanCheckInSec is the frequency of namenode looking for blocks no
+	// file references to remove them from datanodes
+	OrphanCheckInSec = 600
+	// OrphanGraceInSec is how long after it was allocated a block no file
+	// references may stay on datanodes, so that the blocks of files being
+	// created are not removed
+	OrphanGraceInSec = 3600
+	// AccessTimeInSec is the precision of file access times: a read
+	// records its time only if the access time of the file is older, so
+	// that reads don't all write the edit log. 0 disables access times.
+	AccessTimeInSec = 0
+	// Version is the software version of gdfs, major.minor.patch
+	Version = "1.0.0"
+	// VersionCheck is how namenode treats the handshake of a datanode
+	// running another version: "strict" rejects another major or minor
+	// version, "major" rejects another major version, "off" rejects
+	// none. Mismatches let in are logged as warnings.
+	VersionCheck = "major"
+	// LogLevel is the lowest level of the messages logged by namenode and
+	// datanodes: debug, info, warn or error, see package logger
+	LogLevel = "info"
+	// LogJSON logs one JSON object per message, for log aggregation
+	LogJSON = false
 )
 
 const (
@@ -77,4 +241,46 @@ const (
 	Rmdir
 	// Format for init the dfs
 	Format
+	// AppendToFile appends local files to the end of a dfs file
+	AppendToFile
+	// Stat shows metadata of a list of paths
+	Stat
+	// Cp copies a file inside dfs
+	Cp
+	// Mv moves or renames files and directories inside dfs
+	Mv
+	// Checksum computes whole-file checksums
+	Checksum
+	// SetRep changes the replication factor of a file
+	SetRep
+	// Balance redistributes blocks across datanodes
+	Balance
+	// WordCount counts the words of a file
+	WordCount
+	// RunJob runs a registered mapreduce job over a file
+	RunJob
+	// Du sums the space used by a directory subtree
+	Du
+	// Decommission retires a datanode once its blocks are copied elsewhere
+	Decommission
+	// Fsck checks the health of the blocks of files
+	Fsck
+	// Expunge empties the trash of a user
+	Expunge
+	// SetQuota limits the number of names under a directory
+	SetQuota
+	// SetSpaceQuota limits the bytes of replicas under a directory
+	SetSpaceQuota
+	// Count counts the directories, files and bytes under a path
+	Count
+	// UploadStatus lists the blocks of a file still to upload
+	UploadStatus
+	// SetDigest records the digest of a file once uploaded
+	SetDigest
+	// Truncate cuts a file to a given length
+	Truncate
+	// Grep finds the lines of a file matching a regular expression
+	Grep
+	// TopN finds the greatest records of a file
+	TopN
 )
@@ -0,0 +1,89 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// File is the content of a config file, a JSON object whose fields
+// override the variables of the same name, e.g.
+//
+//	{"DataNodePort": "11171", "DataPath": "data1"}
+//
+// lets a second datanode run on a host. Fields left out keep their value,
+// an empty port disables the server as it does in the variables.
+type File struct {
+	NameNodeAddress     *string
+	NameNodeMetricsPort *string
+	NameNodeWebPort     *string
+	DataNodeHost        *string
+	DataNodePort        *string
+	DataNodeMetricsPort *string
+	DataPath            *string // see SetDataPath
+	DataDirs            []string
+	AuthToken           *string
+}
+
+// Load applies the config file at path
+func Load(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var f File
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return fmt.Errorf("Malformed config file %v: %v", path, err)
+	}
+	for _, v := range []struct {
+		dst *string
+		src *string
+	}{
+		{&NameNodeAddress, f.NameNodeAddress},
+		{&NameNodeMetricsPort, f.NameNodeMetricsPort},
+		{&NameNodeWebPort, f.NameNodeWebPort},
+		{&DataNodeHost, f.DataNodeHost},
+		{&DataNodePort, f.DataNodePort},
+		{&DataNodeMetricsPort, f.DataNodeMetricsPort},
+		{&AuthToken, f.AuthToken},
+	} {
+		if v.src != nil {
+			*v.dst = *v.src
+		}
+	}
+	if f.DataPath != nil {
+		SetDataPath(*f.DataPath)
+	}
+	if f.DataDirs != nil {
+		DataDirs = f.DataDirs
+	}
+	return nil
+}
+
+// SetDataPath moves the files of a datanode to dir: the namespace and
+// storage ids, the block key and its single volume
+func SetDataPath(dir string) {
+	DataPath = dir
+	NamespaceIDPath = filepath.Join(dir, "nid")
+	StorageIDPath = filepath.Join(dir, "sid")
+	BlkKeyFile = filepath.Join(dir, "blkkey")
+	DataDirs = []string{dir}
+}
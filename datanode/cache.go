@@ -0,0 +1,108 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"container/list"
+	"sync"
+)
+
+// blkCache keeps the data of recently read blocks in memory, up to budget
+// bytes, evicting the least recently used blocks first
+type blkCache struct {
+	mu     sync.Mutex
+	budget int64
+	size   int64
+	ll     *list.List               // front is the most recently used
+	items  map[string]*list.Element // block id to element of ll
+	// gen is bumped by every invalidation, a block read from disk before
+	// an invalidation may be stale and is not cached, see put
+	gen uint64
+}
+
+type cacheEntry struct {
+	blkID string
+	data  []byte
+}
+
+func newBlkCache(budget int64) *blkCache {
+	return &blkCache{budget: budget, ll: list.New(), items: make(map[string]*list.Element)}
+}
+
+// get returns a copy of the cached data of a block, callers may modify it
+func (c *blkCache) get(blkID string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[blkID]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	data := e.Value.(*cacheEntry).data
+	return append([]byte(nil), data...), true
+}
+
+// generation returns the current generation, to be passed to put with
+// the data read from disk afterwards
+func (c *blkCache) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+// put caches a copy of the data of a block read at generation gen, unless
+// a block was invalidated since or the data exceeds the budget
+func (c *blkCache) put(blkID string, data []byte, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen || int64(len(data)) > c.budget {
+		return
+	}
+	if _, ok := c.items[blkID]; ok {
+		return
+	}
+	entry := &cacheEntry{blkID: blkID, data: append([]byte(nil), data...)}
+	c.items[blkID] = c.ll.PushFront(entry)
+	c.size += int64(len(data))
+	for c.size > c.budget {
+		c.remove(c.ll.Back())
+	}
+}
+
+// invalidate drops a block, e.g. when it is overwritten or deleted
+func (c *blkCache) invalidate(blkID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	if e, ok := c.items[blkID]; ok {
+		c.remove(e)
+	}
+}
+
+// clear drops every block
+func (c *blkCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+	c.size = 0
+}
+
+func (c *blkCache) remove(e *list.Element) {
+	entry := c.ll.Remove(e).(*cacheEntry)
+	delete(c.items, entry.blkID)
+	c.size -= int64(len(entry.data))
+}
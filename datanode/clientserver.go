@@ -15,179 +15,922 @@
 package datanode
 
 import (
-	"bufio"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"hash"
+	"io"
 	"log"
 	"net"
-	"net/http"
-	"net/rpc"
-	"os"
-	"path/filepath"
-	"strconv"
-	"strings"
+	"sync/atomic"
 
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/mapreduce"
+	"github.com/WineChord/gdfs/metrics"
+	"github.com/WineChord/gdfs/namenode"
 	"github.com/WineChord/gdfs/utils"
 )
 
-// CalMeanVarMap calculates mean and variance for this segment
+// MapBlk runs the map task of a registered mapreduce job over a block
+func (d *DataNode) MapBlk(args *mapreduce.MapArgs, reply *mapreduce.MapReply) error {
+	if err := namenode.CheckToken("MapBlk", args.Token); err != nil {
+		return err
+	}
+	logger.Debugf("enter MapBlk %v for %v\n", args.Job, args.BlkID)
+	job, err := mapreduce.Lookup(args.Job)
+	if err != nil {
+		return err
+	}
+	res, err := d.mapBlk(job, args.BlkID)
+	if err != nil {
+		return err
+	}
+	if args.NumReduce > 0 {
+		d.keepMap(args.JobID, args.BlkID, mapreduce.Split(res.Result, args.NumReduce))
+		res.Result = nil
+	}
+	*reply = *res
+	return nil
+}
+
+// mapBlk maps the records lying entirely in block blkID, the records cut
+// by the block boundaries are sent back as the edges of the block
+func (d *DataNode) mapBlk(job mapreduce.Job, blkID string) (*mapreduce.MapReply, error) {
+	if !d.hasBlk(blkID) {
+		return nil, errors.New("No such block")
+	}
+	return mapreduce.MapBlk(job, d.readData(blkID))
+}
+
+// CalMeanVarMap calculates mean and variance for this segment, over the
+// lines lying entirely in it, see mapreduce.MeanVar. It is kept for
+// namenodes predating MapBlk.
 func (d *DataNode) CalMeanVarMap(args *utils.CalMVArgs, reply *utils.CalMVReply) error {
-	blkID := args.BlkID
-	log.Printf("enter CalMeanVarMap\n")
-	file, err := os.Open(filepath.Join(d.ActPath, blkID))
-	defer file.Close()
+	if err := namenode.CheckToken("CalMeanVarMap", args.Token); err != nil {
+		return err
+	}
+	logger.Debugf("enter CalMeanVarMap\n")
+	res, err := d.mapBlk(mapreduce.MeanVar{}, args.BlkID)
 	if err != nil {
-		log.Printf("error when opening actual data file: %v\n", err)
+		return err
 	}
-	s := bufio.NewScanner(file)
-	cnt, tot, sq := int64(0), float64(0), float64(0)
-	for s.Scan() {
-		n, err := strconv.Atoi(s.Text())
-		if err == nil {
-			cnt++
-			tot += float64(n)
-			sq += float64(n) * float64(n)
-			log.Printf("got NamespaceID from disk: %v\n", d.NamespaceID)
-		}
+	reply.Edges = res.Edges
+	reply.Cnt = int64(res.Result["cnt"])
+	if reply.Cnt > 0 { // a block may have no whole line at all
+		reply.Mean = res.Result["sum"] / float64(reply.Cnt)
+		reply.MeanSQ = res.Result["sumsq"] / float64(reply.Cnt)
 	}
-	reply.Cnt = cnt
-	reply.Mean = tot / float64(cnt)
-	reply.MeanSQ = sq / float64(cnt)
-	log.Printf("%v cnt: %v, mean: %v, meansq: %v\n", blkID, reply.Cnt, reply.Mean,
-		reply.MeanSQ)
+	logger.Debugf("%v cnt: %v, mean: %v, meansq: %v\n", args.BlkID, reply.Cnt,
+		reply.Mean, reply.MeanSQ)
 	return nil
 }
 
-// RequestBlkArgs is used by client to request a block
+// WordCountMap counts the words of a block, see mapreduce.WordCount. It
+// is kept for namenodes predating MapBlk.
+func (d *DataNode) WordCountMap(args *utils.WordCountArgs, reply *utils.WordCountReply) error {
+	if err := namenode.CheckToken("WordCountMap", args.Token); err != nil {
+		return err
+	}
+	logger.Debugf("enter WordCountMap for %v\n", args.BlkID)
+	if !d.hasBlk(args.BlkID) {
+		return errors.New("No such block")
+	}
+	*reply = countWords(d.readData(args.BlkID))
+	logger.Debugf("%v has %v distinct inner words\n", args.BlkID, len(reply.Counts))
+	return nil
+}
+
+// GrepMap matches the lines lying entirely in a block against the pattern
+// of args, the lines cut by the block boundaries are sent back as the
+// edges of the block for namenode to match, see NameNode.runGrep
+func (d *DataNode) GrepMap(args *utils.GrepArgs, reply *utils.GrepReply) error {
+	if err := namenode.CheckToken("GrepMap", args.Token); err != nil {
+		return err
+	}
+	logger.Debugf("enter GrepMap for %v\n", args.BlkID)
+	re, err := utils.GrepRegexp(args.Pattern, args.IgnoreCase)
+	if err != nil {
+		return err
+	}
+	if !d.hasBlk(args.BlkID) {
+		return errors.New("No such block")
+	}
+	edges, inner := utils.SplitEdges(d.readData(args.BlkID), utils.IsNewline)
+	lines := utils.Grep(re, inner)
+	reply.Count, reply.Edges = len(lines), edges
+	if !args.CountOnly {
+		reply.Lines = lines
+	}
+	logger.Debugf("%v has %v matching inner lines\n", args.BlkID, reply.Count)
+	return nil
+}
+
+// TopNMap returns the args.K greatest records among the lines lying
+// entirely in a block, the lines cut by the block boundaries are sent back
+// as the edges of the block for namenode to rank, see NameNode.runTopN
+func (d *DataNode) TopNMap(args *utils.TopNArgs, reply *utils.TopNReply) error {
+	if err := namenode.CheckToken("TopNMap", args.Token); err != nil {
+		return err
+	}
+	logger.Debugf("enter TopNMap for %v\n", args.BlkID)
+	if !d.hasBlk(args.BlkID) {
+		return errors.New("No such block")
+	}
+	edges, inner := utils.SplitEdges(d.readData(args.BlkID), utils.IsNewline)
+	reply.Top, reply.Edges = utils.TopN(utils.Lines(inner), args.K, args.Lexical), edges
+	logger.Debugf("%v sends its top %v records\n", args.BlkID, len(reply.Top))
+	return nil
+}
+
+// countWords counts the words of data but its edges
+func countWords(data []byte) utils.WordCountReply {
+	res, _ := mapreduce.MapBlk(mapreduce.WordCount{}, data) // never fails
+	reply := utils.WordCountReply{Counts: make(map[string]int), Edges: res.Edges}
+	for word, cnt := range res.Result {
+		reply.Counts[word] = int(cnt)
+	}
+	return reply
+}
+
+// hasBlk tells whether d holds block blkID
+func (d *DataNode) hasBlk(blkID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.IDToMetaData[blkID]
+	return ok
+}
+
+// RequestBlkArgs is used by client to request a block. Offset and Length
+// select a byte range of the block, a zero Length means up to the end of
+// the block, so the zero value requests the whole block.
 type RequestBlkArgs struct {
-	BlkID string
+	BlkID  string
+	Offset int64
+	Length int64
+	// Compress asks for the data gzip compressed, see config.CompressBlks
+	Compress bool
+	Token    string // see config.AuthToken
 }
 
 // RequestBlk will read two files on disk to construct meta data and actual
 // perspectively
+// For a whole block, the stored checksum is sent along so that client
+// detects corruption on disk as well as in transit. For a byte range, the
+// stored checksum covers bytes not sent, the checksum is computed over the
+// returned bytes instead, corruption on disk is then left to the scanner.
 func (d *DataNode) RequestBlk(args *RequestBlkArgs, reply *utils.BlkData) error {
+	if err := namenode.CheckToken("RequestBlk", args.Token); err != nil {
+		return err
+	}
 	blkID := args.BlkID
-	log.Printf("process block request for %v\n", blkID)
-	_, checksum, length := d.readMeta(blkID)
-	data := d.readData(blkID)
+	logger.Debugf("process block request for %v\n", blkID)
+	defer d.beginTransfer()()
+	if !d.hasBlk(blkID) {
+		// without this an unknown block reads as a valid empty one
+		return errors.New("No such block")
+	}
+	meta := d.readMeta(blkID)
+	length := int(meta.Length)
 	reply.BlkID = blkID
-	reply.Checksum = checksum
-	reply.Length = length
+	reply.ChecksumType = meta.ChecksumType
+	if args.Offset == 0 && args.Length == 0 {
+		reply.Checksum = meta.Checksum
+		reply.Length = length
+		reply.Data = d.readData(blkID)
+		atomic.AddInt64(&d.bytesRead, int64(len(reply.Data)))
+		return compressReply(args.Compress, reply)
+	}
+	if args.Offset < 0 || args.Length < 0 || args.Offset > int64(length) {
+		return fmt.Errorf("Invalid range [%v, +%v) of block %v of %v bytes",
+			args.Offset, args.Length, blkID, length)
+	}
+	n := int64(length) - args.Offset
+	if args.Length > 0 && args.Length < n {
+		n = args.Length
+	}
+	data, err := d.readRange(blkID, args.Offset, n)
+	if err != nil {
+		return err
+	}
+	logger.Debugf("read %v bytes at %v of %v\n", len(data), args.Offset, blkID)
+	reply.Checksum = utils.ChecksumOf(meta.ChecksumType, data)
+	reply.Length = len(data)
 	reply.Data = data
+	atomic.AddInt64(&d.bytesRead, int64(len(data)))
+	return compressReply(args.Compress, reply)
+}
+
+// compressReply gzips the data of reply if compress is set, its checksum
+// and length stay those of the uncompressed data
+func compressReply(compress bool, reply *utils.BlkData) error {
+	if !compress {
+		return nil
+	}
+	data, err := utils.Gzip(reply.Data)
+	if err != nil {
+		return err
+	}
+	reply.Data, reply.Compressed = data, true
+	return nil
+}
+
+// RequestBlksArgs names the whole blocks of RequestBlks
+type RequestBlksArgs struct {
+	BlkIDs   []string
+	Compress bool   // see RequestBlkArgs
+	Token    string // see config.AuthToken
+}
+
+// RequestBlksReply holds the blocks of RequestBlks in order, Errors[i] is
+// empty unless block i could not be read
+type RequestBlksReply struct {
+	Blks   []utils.BlkData
+	Errors []string
+}
+
+// RequestBlks serves several whole blocks in one round trip, each like
+// RequestBlk. At most config.MaxBlksPerRequest blocks are served at once.
+func (d *DataNode) RequestBlks(args *RequestBlksArgs, reply *RequestBlksReply) error {
+	if err := namenode.CheckToken("RequestBlks", args.Token); err != nil {
+		return err
+	}
+	logger.Debugf("process request for %v blocks\n", len(args.BlkIDs))
+	if len(args.BlkIDs) > config.MaxBlksPerRequest {
+		return fmt.Errorf("Too many blocks requested: %v, at most %v",
+			len(args.BlkIDs), config.MaxBlksPerRequest)
+	}
+	reply.Blks = make([]utils.BlkData, len(args.BlkIDs))
+	reply.Errors = make([]string, len(args.BlkIDs))
+	for i, blkID := range args.BlkIDs {
+		err := d.RequestBlk(&RequestBlkArgs{BlkID: blkID, Compress: args.Compress,
+			Token: args.Token}, &reply.Blks[i])
+		if err != nil {
+			reply.Blks[i] = utils.BlkData{BlkID: blkID}
+			reply.Errors[i] = err.Error()
+		}
+	}
 	return nil
 }
 
 func (d *DataNode) readData(blkID string) []byte {
-	log.Printf("read actual data from file for %v\n", blkID)
-	file, err := os.Open(filepath.Join(d.ActPath, blkID))
-	if err != nil {
-		log.Printf("error when opening actual data file: %v\n", err)
+	if data, ok := d.cache.get(blkID); ok {
+		return data
 	}
-	data, err := ioutil.ReadAll(file)
+	logger.Debugf("read actual data from file for %v\n", blkID)
+	gen := d.cache.generation()
+	data, err := d.loadData(blkID)
 	if err != nil {
-		log.Printf("error reading actual data file: %v\n", err)
+		logger.Errorf("error reading actual data file: %v\n", err)
+		return data
 	}
+	d.cache.put(blkID, data, gen)
 	return data
 }
 
-func (d *DataNode) readMeta(blkID string) (timestamp string, checksum uint32, length int) {
-	meta := d.IDToMetaData[blkID]
-	timestamp = fmt.Sprintf("%v", meta.Timestamp)
-	checksum = meta.Checksum
-	length = int(meta.Length)
-	return
+// loadData reads the actual data of a block from its store, decrypted and
+// decompressed
+func (d *DataNode) loadData(blkID string) ([]byte, error) {
+	v := d.volumeOf(blkID)
+	if v == nil {
+		return nil, errors.New("No such block")
+	}
+	atomic.AddInt64(&d.diskReads, 1)
+	data, err := v.Store.Get(blkID)
+	d.checkIO(v, err)
+	if err != nil {
+		return nil, err
+	}
+	data, err = d.openBlk(blkID, data)
+	if err != nil || !d.readMeta(blkID).Compressed {
+		return data, err
+	}
+	return utils.Gunzip(data)
+}
+
+// readRange reads n bytes at offset of the actual data of a block
+func (d *DataNode) readRange(blkID string, offset, n int64) ([]byte, error) {
+	if data, ok := d.cache.get(blkID); ok {
+		return sliceRange(data, offset, n)
+	}
+	v := d.volumeOf(blkID)
+	if v == nil {
+		return nil, errors.New("No such block")
+	}
+	r, ok := v.Store.(rangeReader)
+	// an encrypted or compressed block is read as a whole
+	if !ok || config.EncryptBlks || d.readMeta(blkID).Compressed {
+		data, err := d.loadData(blkID)
+		if err != nil {
+			return nil, err
+		}
+		return sliceRange(data, offset, n)
+	}
+	data, err := r.ReadRange(blkID, offset, n)
+	d.checkIO(v, err)
+	if err != nil {
+		logger.Errorf("error reading actual data of %v: %v\n", blkID, err)
+		return nil, err
+	}
+	return data, nil
+}
+
+// sliceRange returns n bytes at offset of data
+func sliceRange(data []byte, offset, n int64) ([]byte, error) {
+	if offset+n > int64(len(data)) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return data[offset : offset+n], nil
+}
+
+// readMeta returns the metadata of a block
+func (d *DataNode) readMeta(blkID string) utils.MetaData {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.IDToMetaData[blkID]
 }
 
 // SendBlkReply contains status, the argument is BlkData
 type SendBlkReply struct {
 	Status bool
+	// Acked are the datanodes of the pipeline which stored the block,
+	// the receiver first, see forward
+	Acked []string
 }
 
 // SendBlk is called by client
-// Upon receiving the block data [BlkID, Data, Checksum], datanode will
-// store the meta data in metadata path (data/id2meta)
-// the actual data will be stored in actual data path (data/actdata)
-// for each block, these two files have the same file name: BlkID
-// which is of format: filename-index-timestamp-random
+// Upon receiving the block data [BlkID, Data, Checksum], decompressed if it
+// is compressed, datanode will
+// store the meta data and the actual data in the store of a volume, see
+// DiskStore for the files on disk. BlkID is of format:
+// filename-index-timestamp-random
 // datanode will also update its in memory map: IDToMetaData
+// The checksum is recomputed over the received data first, with the
+// algorithm of args.ChecksumType, a corrupted block is rejected without
+// touching the disk, so the sender can retry.
+// A stored block is forwarded to args.Targets, see forward.
 func (d *DataNode) SendBlk(args *utils.BlkData, reply *SendBlkReply) error {
+	if err := namenode.CheckToken("SendBlk", args.Token); err != nil {
+		return err
+	}
 	blkID, checksum, data, length := args.BlkID, args.Checksum, args.Data, args.Length
-	timestamp := getTimestamp(blkID)
-	log.Printf("receive block from client: %v, len: %v\n", blkID, length)
-	d.saveMeta(blkID, timestamp, checksum, length)
-	d.saveData(blkID, data)
+	logger.Debugf("receive block from client: %v, len: %v\n", blkID, length)
+	defer d.beginTransfer()()
+	reply.Status = false
+	if err := utils.CheckChecksumType(args.ChecksumType); err != nil {
+		return err
+	}
+	if args.Compressed {
+		var err error
+		if data, err = utils.Gunzip(data); err != nil {
+			logger.Warnf("error when decompressing received block %v: %v\n", blkID, err)
+			return errors.New("Checksum mismatch")
+		}
+	}
+	if length != len(data) || !utils.ChecksumOf(args.ChecksumType, data).Equal(checksum) {
+		logger.Warnf("checksum mismatch of received block %v\n", blkID)
+		return errors.New("Checksum mismatch")
+	}
+	v, err := d.chooseVolume(blkID)
+	if err != nil {
+		return err
+	}
+	meta := utils.MetaData{Timestamp: getTimestamp(blkID), GenStamp: args.GenStamp,
+		Checksum: checksum, ChecksumType: args.ChecksumType, Length: int64(length),
+		Compressed: config.CompressAtRest}
+	err = d.saveBlk(v, blkID, meta, data)
+	if err != nil {
+		return err
+	}
+	atomic.AddInt64(&d.bytesWritten, int64(length))
+	logger.Debugf("successfully saved blkData: %v\n", blkID)
+	reply.Acked = []string{d.Addr}
+	if len(args.Targets) > 0 {
+		next := *args
+		next.Targets = args.Targets[1:]
+		err = d.forward(args.Targets[0], &next, length, reply)
+		if err != nil {
+			return err
+		}
+	}
 	reply.Status = true
-	log.Printf("successfully saved blkData: %v\n", blkID)
 	return nil
 }
 
-func (d *DataNode) saveData(blkID string, data []byte) {
-	log.Printf("start save actual data to file: %v\n", blkID)
-	file, err := os.Create(filepath.Join(d.ActPath, blkID))
+// forward sends a block which d received to addr, the next datanode of
+// the write pipeline, which forwards it further. Receivers verify the
+// checksum of the block on their own and the reply of addr acknowledges it
+// up the pipeline: the datanodes which stored it are added to reply.Acked.
+func (d *DataNode) forward(addr string, args *utils.BlkData, n int,
+	reply *SendBlkReply) error {
+	utils.Throttle(n, d.throttle)
+	down := SendBlkReply{}
+	err := d.dataNodes.CallDataNode(addr, utils.RPCTimeout(), nil,
+		func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+			m, err := dn.SendBlk(ctx, args.ToPB())
+			down.FromPB(m)
+			return err
+		})
 	if err != nil {
-		log.Printf("error when creating actual data file: %v\n", err)
+		logger.Warnf("error when forwarding to %v: %v\n", addr, err)
+		return fmt.Errorf("Forwarding to %v: %w", addr, err)
 	}
-	_, err = file.Write(data)
+	reply.Acked = append(reply.Acked, down.Acked...)
+	return nil
+}
+
+// forwardChunk is forward for the chunks of the part p of a block, which
+// go to addr over a stream opened with the first one, see sendChunk. The
+// reply to the last chunk acknowledges the block.
+func (d *DataNode) forwardChunk(p *part, addr string, next *utils.BlkChunk, n int,
+	reply *SendBlkReply) error {
+	utils.Throttle(n, d.throttle)
+	down, err := d.sendChunk(p, addr, next)
 	if err != nil {
-		log.Printf("error when writing actual data file: %v\n", err)
+		logger.Warnf("error when forwarding to %v: %v\n", addr, err)
+		return fmt.Errorf("Forwarding to %v: %w", addr, err)
+	}
+	reply.Acked = append(reply.Acked, down.Acked...)
+	return nil
+}
+
+// sendChunk sends a chunk of p to addr and returns the reply to it, which
+// is empty but for the last chunk. The stream ends with the last chunk or
+// an error.
+func (d *DataNode) sendChunk(p *part, addr string, next *utils.BlkChunk) (down SendBlkReply, err error) {
+	d.mu.Lock()
+	s := p.fwd
+	d.mu.Unlock()
+	if s == nil {
+		if s, err = d.openChunks(addr); err != nil {
+			return down, err
+		}
+		d.mu.Lock()
+		p.fwd = s
+		d.mu.Unlock()
+	}
+	defer func() {
+		if err != nil || next.Last {
+			s.end(err)
+		}
+	}()
+	err = s.bound(func() error { return s.chunks.Send(next.ToPB()) })
+	if err != io.EOF && (err != nil || !next.Last) {
+		return down, err
+	}
+	/** on io.EOF the receiver ended the stream, which it only does on an
+	 * error before the last chunk, CloseAndRecv returns the error
+	 * */
+	var m *gdfspb.SendBlkReply
+	err = s.bound(func() (err error) {
+		m, err = s.chunks.CloseAndRecv()
+		return err
+	})
+	down.FromPB(m)
+	if err == nil && !next.Last {
+		err = errors.New("Stream ended before the last chunk")
 	}
-	file.Sync()
-	file.Close()
-	log.Printf("saved actual data to file %v\n", blkID)
+	return down, err
 }
 
-func (d *DataNode) saveMeta(blkID, timestamp string, checksum uint32, length int) {
-	log.Printf("start save meta data to file: %v\n", blkID)
-	meta := utils.MetaData{}
-	var err error
-	meta.Timestamp, err = strconv.ParseInt(timestamp, 10, 64)
+// chunkStream is a stream of the chunks of a block to the next datanode
+// of the write pipeline, see sendChunk
+type chunkStream struct {
+	chunks  gdfspb.DataNode_SendBlkChunkClient
+	bound   func(call func() error) error // see utils.StreamContext
+	cancel  context.CancelFunc
+	release func(error)
+}
+
+// openChunks opens a stream of chunks to addr, each call on it gives up
+// after RPCTimeout
+func (d *DataNode) openChunks(addr string) (*chunkStream, error) {
+	cc, release, err := d.dataNodes.Get(addr)
+	if err != nil {
+		return nil, err
+	}
+	ctx, bound, cancel := utils.StreamContext(utils.RPCTimeout())
+	s := &chunkStream{bound: bound, cancel: cancel, release: release}
+	err = bound(func() (err error) {
+		s.chunks, err = gdfspb.NewDataNodeClient(cc).SendBlkChunk(ctx)
+		return err
+	})
 	if err != nil {
-		log.Printf("error when converting timestamp: %v\n", err)
+		s.end(err)
+		return nil, err
+	}
+	return s, nil
+}
+
+// end ends s, which failed with err if any, it may be called more than once
+func (s *chunkStream) end(err error) {
+	s.cancel()
+	s.release(err)
+}
+
+// part is a block being received by SendBlkChunk
+type part struct {
+	v    *Volume
+	size int64     // bytes received so far
+	hash hash.Hash // checksum of the bytes received, see BlkChunk.ChecksumType
+	// inPlace parts are appended to the store of v, see partStore, the
+	// others are kept in data until the last chunk
+	inPlace bool
+	data    []byte
+	touched int64 // time of the last chunk in ms, see expireParts
+	// fwd streams the chunks to the next datanode of the pipeline, see
+	// sendChunk, it is guarded by d.mu
+	fwd *chunkStream
+}
+
+// SendBlkChunk is the streaming counterpart of SendBlk, the block is
+// received chunk by chunk. Chunks go to a partial block of the store if
+// it keeps them, see partStore, they are kept in memory otherwise and
+// with config.EncryptBlks or config.CompressAtRest. Compressed chunks are
+// decompressed on receipt. With the last chunk, the checksum of the whole
+// block is verified and the block is committed like SendBlk does. Each
+// chunk is forwarded to args.Targets once written, so that every datanode
+// of the pipeline verifies the block when the last chunk reaches it.
+func (d *DataNode) SendBlkChunk(args *utils.BlkChunk, reply *SendBlkReply) error {
+	if err := namenode.CheckToken("SendBlkChunk", args.Token); err != nil {
+		return err
+	}
+	blkID := args.BlkID
+	// a streamed block counts as a transfer while a chunk is being received
+	defer d.beginTransfer()()
+	p, err := d.partOf(blkID, args.Offset, args.ChecksumType)
+	if err != nil {
+		return err
+	}
+	data := args.Data
+	if args.Compressed {
+		if data, err = utils.Gunzip(data); err != nil {
+			logger.Warnf("error when decompressing chunk of %v: %v\n", blkID, err)
+			d.dropPart(blkID, p)
+			return errors.New("Checksum mismatch")
+		}
+	}
+	if p.inPlace {
+		err = p.v.Store.(partStore).AppendPart(blkID, args.Offset, data)
+		d.checkIO(p.v, err)
+		if err != nil {
+			logger.Errorf("error when writing partial block %v: %v\n", blkID, err)
+			d.dropPart(blkID, p)
+			return err
+		}
+	} else {
+		p.data = append(p.data, data...)
+	}
+	p.size += int64(len(data))
+	p.hash.Write(data)
+	atomic.AddInt64(&d.bytesWritten, int64(len(data)))
+	/** the last chunk is forwarded even if the block turns out corrupted
+	 * here, downstream datanodes then drop their part as well
+	 * */
+	reply.Acked = []string{d.Addr}
+	var fwdErr error
+	if len(args.Targets) > 0 {
+		next := *args
+		next.Targets = args.Targets[1:]
+		fwdErr = d.forwardChunk(p, args.Targets[0], &next, len(args.Data), reply)
+	}
+	if !args.Last {
+		// the client gives up on the block once a chunk fails
+		if fwdErr != nil {
+			d.dropPart(blkID, p)
+		}
+		reply.Status = fwdErr == nil
+		return fwdErr
 	}
-	meta.Checksum = checksum
-	meta.Length = int64(length)
 	d.mu.Lock()
-	d.IDToMetaData[blkID] = meta
+	delete(d.parts, blkID)
+	d.mu.Unlock()
+	checksum := utils.Sum(p.hash.Sum(nil))
+	if !checksum.Equal(args.Checksum) {
+		logger.Warnf("checksum mismatch of streamed block %v\n", blkID)
+		if p.inPlace {
+			p.v.Store.(partStore).RemovePart(blkID)
+		}
+		return errors.New("Checksum mismatch")
+	}
+	meta := utils.MetaData{Timestamp: getTimestamp(blkID), GenStamp: args.GenStamp,
+		Checksum: checksum, ChecksumType: args.ChecksumType, Length: p.size,
+		Compressed: !p.inPlace && config.CompressAtRest}
+	if p.inPlace {
+		err = d.commitPart(p.v, blkID, meta)
+	} else {
+		err = d.saveBlk(p.v, blkID, meta, p.data)
+	}
+	if err != nil {
+		logger.Errorf("error when committing streamed block: %v\n", err)
+		return err
+	}
+	if fwdErr != nil {
+		return fwdErr
+	}
+	reply.Status = true
+	logger.Debugf("successfully received streamed block: %v, len: %v\n", blkID, p.size)
+	return nil
+}
+
+// partOf returns the part of a block receiving the chunk at offset, the
+// first chunk starts a new part checksummed with typ on the volume picked
+// by chooseVolume. Chunks of a block come one at a time.
+func (d *DataNode) partOf(blkID string, offset int64, typ string) (*part, error) {
+	if offset == 0 {
+		if err := utils.CheckChecksumType(typ); err != nil {
+			return nil, err
+		}
+		v, err := d.chooseVolume(blkID)
+		if err != nil {
+			return nil, err
+		}
+		_, ok := v.Store.(partStore)
+		p := &part{v: v, hash: utils.NewHash(typ),
+			inPlace: ok && !config.EncryptBlks && !config.CompressAtRest,
+			touched: utils.GetCurrentTimeInMs()}
+		d.mu.Lock()
+		var oldFwd *chunkStream
+		if old := d.parts[blkID]; old != nil {
+			// the block is sent again, its old stream is of no use
+			oldFwd = old.fwd
+		}
+		d.parts[blkID] = p
+		d.mu.Unlock()
+		if oldFwd != nil {
+			oldFwd.end(nil)
+		}
+		return p, nil
+	}
+	d.mu.Lock()
+	p, ok := d.parts[blkID]
+	if ok {
+		p.touched = utils.GetCurrentTimeInMs()
+	}
+	d.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("No partial block %v", blkID)
+	}
+	if p.size != offset {
+		d.dropPart(blkID, p)
+		return nil, fmt.Errorf("Unexpected offset %v of block %v, have %v bytes",
+			offset, blkID, p.size)
+	}
+	return p, nil
+}
+
+// dropPart forgets the part p of a block along with what the store kept
+// of it, unless a new part of the block replaced it meanwhile
+func (d *DataNode) dropPart(blkID string, p *part) {
+	d.mu.Lock()
+	if d.parts[blkID] != p {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.parts, blkID)
+	fwd := p.fwd
+	d.mu.Unlock()
+	if fwd != nil {
+		fwd.end(nil)
+	}
+	if p.inPlace {
+		p.v.Store.(partStore).RemovePart(blkID)
+	}
+}
+
+// expireParts drops the parts which received no chunk for
+// config.PartIdleInSec, left by clients which died mid-upload
+func (d *DataNode) expireParts() {
+	deadline := utils.GetCurrentTimeInMs() - int64(config.PartIdleInSec)*1000
+	expired := make(map[string]*part)
+	d.mu.Lock()
+	for blkID, p := range d.parts {
+		if p.touched < deadline {
+			expired[blkID] = p
+		}
+	}
 	d.mu.Unlock()
-	file, err := os.Create(filepath.Join(d.MetaPath, blkID))
+	for blkID, p := range expired {
+		logger.Warnf("drop partial block %v idle for %vs\n", blkID, config.PartIdleInSec)
+		d.dropPart(blkID, p)
+	}
+}
+
+// commitPart turns a complete partial block of v into the block
+func (d *DataNode) commitPart(v *Volume, blkID string, meta utils.MetaData) error {
+	err := v.Store.(partStore).CommitPart(blkID, meta)
+	d.cache.invalidate(blkID)
+	d.checkIO(v, err)
+	if err != nil {
+		return err
+	}
+	d.record(v, blkID, meta)
+	return nil
+}
+
+// blkChecksum computes the checksum of type typ and the length of the
+// actual data of a block, over plaintext
+func (d *DataNode) blkChecksum(blkID, typ string) (utils.Sum, int64, error) {
+	if !config.EncryptBlks && !d.readMeta(blkID).Compressed {
+		v := d.volumeOf(blkID)
+		if v == nil {
+			return nil, 0, errors.New("No such block")
+		}
+		checksum, length, err := v.Store.Checksum(blkID, typ)
+		d.checkIO(v, err)
+		return checksum, length, err
+	}
+	data, err := d.loadData(blkID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := utils.CheckChecksumType(typ); err != nil {
+		return nil, 0, err
+	}
+	return utils.ChecksumOf(typ, data), int64(len(data)), nil
+}
+
+// saveBlk stores a block on v, compressed if meta says so and encrypted if
+// blocks are, then records it in IDToMetaData
+func (d *DataNode) saveBlk(v *Volume, blkID string, meta utils.MetaData, data []byte) error {
+	logger.Debugf("start save block: %v\n", blkID)
+	if meta.Compressed {
+		var err error
+		if data, err = utils.Gzip(data); err != nil {
+			return err
+		}
+	}
+	data, err := d.sealBlk(blkID, data)
+	if err != nil {
+		logger.Errorf("error when encrypting block %v: %v\n", blkID, err)
+		return err
+	}
+	err = v.Store.Put(blkID, meta, data)
+	d.cache.invalidate(blkID)
+	d.checkIO(v, err)
 	if err != nil {
-		log.Printf("error when creating metadata file: %v\n", err)
+		logger.Errorf("error when saving block %v: %v\n", blkID, err)
+		return err
+	}
+	d.record(v, blkID, meta)
+	logger.Debugf("saved block %v\n", blkID)
+	return nil
+}
+
+// record adds a block stored on v to IDToMetaData
+func (d *DataNode) record(v *Volume, blkID string, meta utils.MetaData) {
+	d.mu.Lock()
+	d.IDToMetaData[blkID] = meta
+	d.blkVols[blkID] = v
+	d.mu.Unlock()
+}
+
+// StatBlkArgs names the block of StatBlk
+type StatBlkArgs struct {
+	BlkID string
+	Token string // see config.AuthToken
+}
+
+// StatBlk is called by client to learn whether a block it sent is still
+// in place without reading it back, reply is the metadata of the block
+func (d *DataNode) StatBlk(args *StatBlkArgs, reply *utils.MetaData) error {
+	if err := namenode.CheckToken("StatBlk", args.Token); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	meta, ok := d.IDToMetaData[args.BlkID]
+	d.mu.Unlock()
+	if !ok {
+		return errors.New("No such block")
+	}
+	*reply = meta
+	return nil
+}
+
+// TruncateBlk is called by namenode when a file is truncated within its
+// last retained block. The actual data is cut and the metadata gets the
+// new length, checksum and generation stamp, it is returned in reply.
+func (d *DataNode) TruncateBlk(args *utils.TruncateBlkArgs, reply *utils.MetaData) error {
+	if err := namenode.CheckToken("TruncateBlk", args.Token); err != nil {
+		return err
+	}
+	logger.Debugf("truncate block %v to %v bytes\n", args.BlkID, args.Length)
+	d.mu.Lock()
+	meta, ok := d.IDToMetaData[args.BlkID]
+	d.mu.Unlock()
+	v := d.volumeOf(args.BlkID)
+	if !ok || v == nil {
+		return errors.New("No such block")
+	}
+	if args.Length < 0 || args.Length > meta.Length {
+		return errors.New("Invalid length")
+	}
+	meta.Length, meta.GenStamp = args.Length, args.GenStamp
+	t, ok := v.Store.(truncater)
+	// an encrypted or compressed block is written as a whole
+	if !ok || config.EncryptBlks || meta.Compressed {
+		data, err := d.loadData(args.BlkID)
+		if err != nil {
+			return err
+		}
+		data = data[:args.Length]
+		meta.Checksum = utils.ChecksumOf(meta.ChecksumType, data)
+		if err := d.saveBlk(v, args.BlkID, meta, data); err != nil {
+			return err
+		}
+		*reply = meta
+		return nil
 	}
-	bytes, err := json.Marshal(meta)
+	data, err := d.readRange(args.BlkID, 0, args.Length)
 	if err != nil {
-		log.Printf("error when marshaling meta data to json: %v\n", err)
+		return err
 	}
-	_, err = file.Write(bytes)
+	meta.Checksum = utils.ChecksumOf(meta.ChecksumType, data)
+	err = t.Truncate(args.BlkID, meta)
+	d.cache.invalidate(args.BlkID)
+	d.checkIO(v, err)
 	if err != nil {
-		log.Printf("error when writing metadata to file: %v\n", err)
+		logger.Errorf("error when truncating block %v: %v\n", args.BlkID, err)
+		return err
+	}
+	d.record(v, args.BlkID, meta)
+	*reply = meta
+	return nil
+}
+
+// DeleteBlk is called by namenode when the file owning the block is removed
+// both the metadata and the actual data will be removed, and the
+// block is dropped from the in memory IDToMetaData map
+func (d *DataNode) DeleteBlk(args *utils.DeleteBlkArgs, reply *utils.DeleteBlkReply) error {
+	if err := namenode.CheckToken("DeleteBlk", args.Token); err != nil {
+		return err
+	}
+	reply.Status = d.deleteBlk(args.BlkID)
+	return nil
+}
+
+// deleteBlk removes a block from its store and IDToMetaData, it returns
+// false if it exists but cannot be removed. Every healthy
+// volume is cleaned, a write failing halfway may leave data on a volume
+// not recorded in blkVols.
+func (d *DataNode) deleteBlk(blkID string) bool {
+	logger.Debugf("delete block %v\n", blkID)
+	d.mu.Lock()
+	delete(d.IDToMetaData, blkID)
+	delete(d.blkVols, blkID)
+	vols := make([]*Volume, 0, len(d.Volumes))
+	for _, v := range d.Volumes {
+		if !v.Failed {
+			vols = append(vols, v)
+		}
+	}
+	d.mu.Unlock()
+	ok := true
+	for _, v := range vols {
+		if err := v.Store.Delete(blkID); err != nil {
+			logger.Errorf("error when removing %v from %v: %v\n", blkID, v.Dir, err)
+			ok = false
+		}
 	}
-	file.Sync()
-	file.Close()
-	log.Printf("saved meta data to file %v\n", blkID)
+	d.cache.invalidate(blkID)
+	return ok
 }
 
-func getTimestamp(blkID string) string {
+func getTimestamp(blkID string) int64 {
 	// blkID of format:
 	//    filename-index-timestamp-random
-	return strings.Split(blkID, "-")[2]
+	id := utils.BlockID{}
+	err := id.Parse(blkID)
+	if err != nil {
+		logger.Errorf("error when parsing block id: %v\n", err)
+	}
+	return id.Timestamp
 }
 
 func (d *DataNode) serveClients() {
-	serv := rpc.NewServer()
-	serv.Register(d)
-	oldMux := http.DefaultServeMux
-	mux := http.NewServeMux()
-	http.DefaultServeMux = mux
-	serv.HandleHTTP(rpc.DefaultRPCPath, rpc.DefaultDebugPath)
-	http.DefaultServeMux = oldMux
-	l, e := net.Listen("tcp", d.Addr) // ip:11170 (datanode port)
-	log.Printf("DataNode listening to %v\n", d.Addr)
+	l, e := utils.Listen(d.Addr) // ip:11170 (datanode port)
 	if e != nil {
 		log.Fatal("listen err: ", e)
 	}
-	go http.Serve(l, mux)
-	for {
-		// wait
+	if d.Port == "0" { // advertise the port picked
+		_, d.Port, _ = net.SplitHostPort(l.Addr().String())
+		d.Addr = utils.JoinHostPort(d.IP, d.Port)
+	}
+	logger.Infof("DataNode listening to %v\n", d.Addr)
+	d.server = utils.NewServer(metrics.ServerOptions(d.rpcLatency)...)
+	RegisterServer(d.server, d)
+	go d.server.Serve(l)
+	d.serveMetrics()
+}
+
+// stopServing stops the client server, closing its connections
+func (d *DataNode) stopServing() {
+	if d.server != nil {
+		logger.Infof("DataNode stops listening to %v\n", d.Addr)
+		d.server.Stop()
+	}
+	if d.metricsListener != nil {
+		d.metricsListener.Close()
 	}
 }
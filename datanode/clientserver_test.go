@@ -0,0 +1,511 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/mapreduce"
+	"github.com/WineChord/gdfs/namenode"
+	"github.com/WineChord/gdfs/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestConcurrentSendBlk(t *testing.T) {
+	d := newTestDataNode(t)
+	const senders, blks = 8, 20
+	ids := make([][]string, senders)
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		for j := 0; j < blks; j++ {
+			ids[i] = append(ids[i], testBlkID(fmt.Sprintf("file%v", i), j))
+		}
+		wg.Add(1)
+		go func(ids []string) {
+			defer wg.Done()
+			for _, id := range ids {
+				data := []byte(id)
+				args := utils.BlkData{BlkID: id, Data: data,
+					Checksum: utils.ChecksumOf(utils.CRC32, data), Length: len(data)}
+				if err := d.SendBlk(&args, &SendBlkReply{}); err != nil {
+					t.Errorf("sending %v: %v", id, err)
+				}
+			}
+		}(ids[i])
+	}
+	wg.Wait()
+	d.mu.Lock()
+	if len(d.IDToMetaData) != senders*blks {
+		t.Errorf("%v blocks recorded, want %v", len(d.IDToMetaData), senders*blks)
+	}
+	for _, list := range ids {
+		for _, id := range list {
+			if meta := d.IDToMetaData[id]; meta.Length != int64(len(id)) {
+				t.Errorf("%v recorded with length %v, want %v", id, meta.Length, len(id))
+			}
+		}
+	}
+	d.mu.Unlock()
+	for _, list := range ids {
+		for _, id := range list {
+			if got := readTestBlk(t, d, id); string(got) != id {
+				t.Errorf("%v holds %q", id, got)
+			}
+		}
+	}
+}
+
+// sendChunks streams data to d as block blkID in chunks of
+// config.ChunkSize, the last of which carries sum, a crc32
+func sendChunks(d *DataNode, blkID string, data []byte, sum utils.Sum) error {
+	return sendChunksOf(d, blkID, data, utils.CRC32, sum)
+}
+
+// sendChunksOf is sendChunks with a checksum of type typ
+func sendChunksOf(d *DataNode, blkID string, data []byte, typ string, sum utils.Sum) error {
+	for off := 0; ; off += config.ChunkSize {
+		end := off + config.ChunkSize
+		last := end >= len(data)
+		if last {
+			end = len(data)
+		}
+		args := utils.BlkChunk{BlkID: blkID, Offset: int64(off),
+			Data: data[off:end], Last: last, ChecksumType: typ}
+		if last {
+			args.Checksum = sum
+		}
+		if err := d.SendBlkChunk(&args, &SendBlkReply{}); err != nil || last {
+			return err
+		}
+	}
+}
+
+// flipped returns a copy of sum with its first byte changed
+func flipped(sum utils.Sum) utils.Sum {
+	res := append(utils.Sum(nil), sum...)
+	res[0] ^= 0xff
+	return res
+}
+
+func TestSendBlkChunkLargeBlock(t *testing.T) {
+	d := newTestDataNode(t)
+	data := make([]byte, config.BlkSize+config.ChunkSize/2)
+	rand.New(rand.NewSource(1)).Read(data)
+	sum := utils.ChecksumOf(utils.CRC32, data)
+	blk := testBlkID("large.bin", 0)
+	if err := sendChunks(d, blk, data, sum); err != nil {
+		t.Fatalf("streaming %v: %v", blk, err)
+	}
+	if got := readTestBlk(t, d, blk); !bytes.Equal(got, data) {
+		t.Errorf("read back %v bytes differing from the %v streamed", len(got), len(data))
+	}
+	// the checksum of the last chunk covers the whole block
+	bad := testBlkID("large.bin", 1)
+	if err := sendChunks(d, bad, data, flipped(sum)); err == nil {
+		t.Errorf("streaming %v with a wrong checksum succeeded", bad)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.IDToMetaData[bad]; ok {
+		t.Errorf("%v is kept after a checksum mismatch", bad)
+	}
+	if meta := d.IDToMetaData[blk]; meta.Length != int64(len(data)) {
+		t.Errorf("%v recorded with length %v, want %v", blk, meta.Length, len(data))
+	}
+}
+
+func TestSendBlkChunkDropsParts(t *testing.T) {
+	d := newTestDataNode(t)
+	chunk := []byte("first chunk")
+	held := func(blk string) bool {
+		d.mu.Lock()
+		_, inMemory := d.parts[blk]
+		d.mu.Unlock()
+		_, err := os.Stat(filepath.Join(disk(d, 0).ActPath, blk+partSuffix))
+		return inMemory || !os.IsNotExist(err)
+	}
+	// a chunk failing to be forwarded ends the upload
+	failed := testBlkID("failed.bin", 0)
+	args := utils.BlkChunk{BlkID: failed, Data: chunk, ChecksumType: utils.CRC32,
+		Targets: []string{"127.0.0.1:1"}}
+	if err := d.SendBlkChunk(&args, &SendBlkReply{}); err == nil {
+		t.Errorf("forwarding %v to a datanode down succeeded", failed)
+	}
+	if held(failed) {
+		t.Errorf("%v is kept after a failed chunk", failed)
+	}
+	// so does a client going away mid-upload
+	idle := testBlkID("idle.bin", 0)
+	args = utils.BlkChunk{BlkID: idle, Data: chunk, ChecksumType: utils.CRC32}
+	if err := d.SendBlkChunk(&args, &SendBlkReply{}); err != nil {
+		t.Fatal(err)
+	}
+	d.expireParts()
+	if !held(idle) {
+		t.Fatalf("%v is dropped before being idle", idle)
+	}
+	defer func(sec int) { config.PartIdleInSec = sec }(config.PartIdleInSec)
+	config.PartIdleInSec = -1
+	d.expireParts()
+	if held(idle) {
+		t.Errorf("%v is kept once idle", idle)
+	}
+	args = utils.BlkChunk{BlkID: idle, Offset: int64(len(chunk)), Data: chunk,
+		ChecksumType: utils.CRC32}
+	if err := d.SendBlkChunk(&args, &SendBlkReply{}); err == nil {
+		t.Errorf("a chunk of an expired part is accepted")
+	}
+}
+
+func TestSendBlkRejectsBadChecksum(t *testing.T) {
+	d := newTestDataNode(t)
+	data := []byte("0123456789")
+	sum := utils.ChecksumOf(utils.CRC32, data)
+	corrupt := append([]byte(nil), data...)
+	corrupt[3] ^= 0xff
+	for name, args := range map[string]utils.BlkData{
+		"wrong checksum": {Data: data, Checksum: flipped(sum), Length: len(data)},
+		"corrupted data": {Data: corrupt, Checksum: sum, Length: len(data)},
+		"wrong length":   {Data: data, Checksum: sum, Length: len(data) + 1},
+	} {
+		args.BlkID = testBlkID("bad.txt", 0)
+		reply := SendBlkReply{Status: true}
+		if err := d.SendBlk(&args, &reply); err == nil || reply.Status {
+			t.Errorf("%v: got status %v and error %v, want a rejection", name,
+				reply.Status, err)
+		}
+		d.mu.Lock()
+		_, ok := d.IDToMetaData[args.BlkID]
+		d.mu.Unlock()
+		if ok {
+			t.Errorf("%v: block is recorded", name)
+		}
+		for _, dir := range []string{disk(d, 0).ActPath, disk(d, 0).MetaPath} {
+			_, err := os.Stat(filepath.Join(dir, args.BlkID))
+			if !os.IsNotExist(err) {
+				t.Errorf("%v: %v of the block is written: %v", name, dir, err)
+			}
+		}
+	}
+}
+
+func TestRequestBlks(t *testing.T) {
+	d := newTestDataNode(t)
+	var blks []string
+	for i := 0; i < 3; i++ {
+		blk := testBlkID("batch.txt", i)
+		putBlk(t, d, blk, []byte(fmt.Sprintf("block %v", i)))
+		blks = append(blks, blk)
+	}
+	missing := testBlkID("batch.txt", 3)
+	args := RequestBlksArgs{BlkIDs: append(blks, missing)}
+	reply := RequestBlksReply{}
+	if err := d.RequestBlks(&args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Blks) != 4 || len(reply.Errors) != 4 {
+		t.Fatalf("got %v blocks and %v errors for 4 blocks", len(reply.Blks),
+			len(reply.Errors))
+	}
+	for i, blk := range blks {
+		got := reply.Blks[i]
+		if reply.Errors[i] != "" || got.BlkID != blk ||
+			string(got.Data) != fmt.Sprintf("block %v", i) ||
+			!got.Checksum.Equal(utils.ChecksumOf(got.ChecksumType, got.Data)) {
+			t.Errorf("block %v read as %+v, %q", i, got, reply.Errors[i])
+		}
+	}
+	if reply.Errors[3] == "" {
+		t.Errorf("missing block %v served", missing)
+	}
+	defer func(max int) { config.MaxBlksPerRequest = max }(config.MaxBlksPerRequest)
+	config.MaxBlksPerRequest = 3
+	if err := d.RequestBlks(&args, &RequestBlksReply{}); err == nil {
+		t.Errorf("a request for more than config.MaxBlksPerRequest blocks is served")
+	}
+}
+
+func TestTruncateBlk(t *testing.T) {
+	d := newTestDataNode(t)
+	data := []byte("0123456789")
+	blk := testBlkID("cut.txt", 0)
+	putBlk(t, d, blk, data)
+	readTestBlk(t, d, blk) // cached
+	args := utils.TruncateBlkArgs{BlkID: blk, Length: 11, GenStamp: 7}
+	if err := d.TruncateBlk(&args, &utils.MetaData{}); err == nil {
+		t.Errorf("truncating a block past its end succeeded")
+	}
+	args.Length = 4
+	meta := utils.MetaData{}
+	if err := d.TruncateBlk(&args, &meta); err != nil {
+		t.Fatal(err)
+	}
+	if meta.Length != 4 || meta.GenStamp != 7 ||
+		!meta.Checksum.Equal(utils.ChecksumOf(utils.CRC32, data[:4])) {
+		t.Errorf("truncated block has metadata %+v", meta)
+	}
+	if got := readTestBlk(t, d, blk); string(got) != "0123" {
+		t.Errorf("truncated block reads %q, want \"0123\"", got)
+	}
+	onDisk, err := disk(d, 0).readMeta(blk)
+	if err != nil || !reflect.DeepEqual(onDisk, meta) {
+		t.Errorf("stored metadata %+v, %v, want %+v", onDisk, err, meta)
+	}
+	if corrupt := d.scanOnce(); len(corrupt) != 0 {
+		t.Errorf("scanner found the truncated block corrupt")
+	}
+	args.BlkID = testBlkID("cut.txt", 1)
+	if err := d.TruncateBlk(&args, &utils.MetaData{}); err == nil {
+		t.Errorf("truncating a missing block succeeded")
+	}
+}
+
+func TestAuthToken(t *testing.T) {
+	nodes := startPipeline(t, 2)
+	d := nodes[0]
+	defer func(token string) { config.AuthToken = token }(config.AuthToken)
+	config.AuthToken = "s3cret"
+	data := []byte("authenticated")
+	sum := utils.ChecksumOf(utils.CRC32, data)
+	blk, other := testBlkID("auth.txt", 0), testBlkID("auth.txt", 1)
+	args := utils.BlkData{BlkID: blk, Data: data, Checksum: sum, Length: len(data),
+		Targets: []string{nodes[1].Addr}, Token: "s3cret"}
+	if err := d.SendBlk(&args, &SendBlkReply{}); err != nil {
+		t.Fatalf("sending %v with the token: %v", blk, err)
+	}
+	// the token goes down the pipeline along with the block
+	if got := nodes[1].readData(blk); !bytes.Equal(got, data) {
+		t.Errorf("%v forwarded with the token reads %q", blk, got)
+	}
+	for _, token := range []string{"", "wrong"} {
+		for method, call := range map[string]func() error{
+			"SendBlk": func() error {
+				return d.SendBlk(&utils.BlkData{BlkID: other, Data: data, Checksum: sum,
+					Length: len(data), Token: token}, &SendBlkReply{})
+			},
+			"SendBlkChunk": func() error {
+				return d.SendBlkChunk(&utils.BlkChunk{BlkID: other, Data: data, Last: true,
+					Checksum: sum, Token: token}, &SendBlkReply{})
+			},
+			"RequestBlk": func() error {
+				return d.RequestBlk(&RequestBlkArgs{BlkID: blk, Token: token}, &utils.BlkData{})
+			},
+			"RequestBlks": func() error {
+				return d.RequestBlks(&RequestBlksArgs{BlkIDs: []string{blk}, Token: token},
+					&RequestBlksReply{})
+			},
+			"StatBlk": func() error {
+				return d.StatBlk(&StatBlkArgs{BlkID: blk, Token: token}, &utils.MetaData{})
+			},
+			"TruncateBlk": func() error {
+				return d.TruncateBlk(&utils.TruncateBlkArgs{BlkID: blk, Length: 1, GenStamp: 2,
+					Token: token}, &utils.MetaData{})
+			},
+			"DeleteBlk": func() error {
+				return d.DeleteBlk(&utils.DeleteBlkArgs{BlkID: blk, Token: token},
+					&utils.DeleteBlkReply{})
+			},
+			"MapBlk": func() error {
+				return d.MapBlk(&mapreduce.MapArgs{Job: mapreduce.WordCountJob, BlkID: blk,
+					Token: token}, &mapreduce.MapReply{})
+			},
+		} {
+			if err := call(); err != namenode.ErrUnauthorized {
+				t.Errorf("%v with token %q = %v, want %v", method, token, err,
+					namenode.ErrUnauthorized)
+			}
+		}
+	}
+	if d.hasBlk(other) {
+		t.Errorf("%v is stored without a valid token", other)
+	}
+	reply := utils.BlkData{}
+	err := d.RequestBlk(&RequestBlkArgs{BlkID: blk, Token: "s3cret"}, &reply)
+	if err != nil || !bytes.Equal(reply.Data, data) {
+		t.Errorf("%v reads %q, %v with the token after rejected calls", blk, reply.Data, err)
+	}
+}
+
+// startPipeline serves n datanodes, each with a volume of its own, on free
+// loopback ports
+func startPipeline(t *testing.T, n int) []*DataNode {
+	t.Helper()
+	chdir(t, t.TempDir())
+	dirs := config.DataDirs
+	defer func() { config.DataDirs = dirs }()
+	var nodes []*DataNode
+	for i := 0; i < n; i++ {
+		config.DataDirs = []string{fmt.Sprintf("dn%v", i)}
+		d := NewDataNode()
+		serv := utils.NewServer()
+		RegisterServer(serv, d)
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		go serv.Serve(l)
+		d.Addr = l.Addr().String()
+		t.Cleanup(func() {
+			d.Stop()
+			serv.Stop()
+		})
+		nodes = append(nodes, d)
+	}
+	return nodes
+}
+
+func TestSendBlkPipeline(t *testing.T) {
+	nodes := startPipeline(t, 3)
+	targets := []string{nodes[1].Addr, nodes[2].Addr}
+	data := []byte("pipelined block")
+	blk := testBlkID("p.txt", 0)
+	args := utils.BlkData{BlkID: blk, Data: data, Checksum: utils.ChecksumOf(utils.CRC32, data),
+		Length: len(data), Targets: targets}
+	reply := SendBlkReply{}
+	if err := nodes[0].SendBlk(&args, &reply); err != nil || !reply.Status {
+		t.Fatalf("sending %v: status %v, %v", blk, reply.Status, err)
+	}
+	want := []string{nodes[0].Addr, nodes[1].Addr, nodes[2].Addr}
+	if !reflect.DeepEqual(reply.Acked, want) {
+		t.Errorf("acked by %v, want %v", reply.Acked, want)
+	}
+	for i, d := range nodes {
+		if got := readTestBlk(t, d, blk); !bytes.Equal(got, data) {
+			t.Errorf("datanode %v holds %q", i, got)
+		}
+	}
+
+	// a corrupted block is rejected by the first datanode, and not
+	// forwarded
+	bad := testBlkID("p.txt", 1)
+	args = utils.BlkData{BlkID: bad, Data: data,
+		Checksum: flipped(utils.ChecksumOf(utils.CRC32, data)), Length: len(data),
+		Targets: targets}
+	if err := nodes[0].SendBlk(&args, &SendBlkReply{}); err == nil {
+		t.Errorf("sending %v with a wrong checksum succeeded", bad)
+	}
+	// a datanode down fails the pipeline, the datanodes before it keep
+	// the block
+	down := testBlkID("p.txt", 2)
+	args = utils.BlkData{BlkID: down, Data: data, Checksum: utils.ChecksumOf(utils.CRC32, data),
+		Length: len(data), Targets: []string{nodes[1].Addr, "127.0.0.1:1"}}
+	if err := nodes[0].SendBlk(&args, &SendBlkReply{}); err == nil {
+		t.Errorf("sending %v through a datanode down succeeded", down)
+	}
+	for i, d := range nodes {
+		d.mu.Lock()
+		_, badHeld := d.IDToMetaData[bad]
+		_, downHeld := d.IDToMetaData[down]
+		d.mu.Unlock()
+		if badHeld {
+			t.Errorf("datanode %v holds the corrupted block", i)
+		}
+		if downHeld != (i < 2) {
+			t.Errorf("datanode %v holds the block sent through a datanode down: %v",
+				i, downHeld)
+		}
+	}
+}
+
+// TestSendBlkChunkStream streams a block through a pipeline of datanodes,
+// as a client generated from gdfs.proto in any language would
+func TestSendBlkChunkStream(t *testing.T) {
+	nodes := startPipeline(t, 3)
+	conn, err := grpc.NewClient(nodes[0].Addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	dn := gdfspb.NewDataNodeClient(conn)
+	data := make([]byte, 2*config.ChunkSize+100)
+	rand.New(rand.NewSource(1)).Read(data)
+	targets := []string{nodes[1].Addr, nodes[2].Addr}
+	send := func(blk string, sum utils.Sum) (*gdfspb.SendBlkReply, error) {
+		stream, err := dn.SendBlkChunk(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for off := 0; off < len(data); off += config.ChunkSize {
+			end := off + config.ChunkSize
+			if end > len(data) {
+				end = len(data)
+			}
+			chunk := &gdfspb.BlkChunk{BlkId: blk, Offset: int64(off), Data: data[off:end],
+				Last: end == len(data), ChecksumType: utils.CRC32,
+				Targets: targets}
+			if chunk.Last {
+				chunk.Checksum = sum
+			}
+			if err := stream.Send(chunk); err != nil {
+				break // the error comes with the reply
+			}
+		}
+		return stream.CloseAndRecv()
+	}
+	blk := testBlkID("s.bin", 0)
+	reply, err := send(blk, utils.ChecksumOf(utils.CRC32, data))
+	want := []string{nodes[0].Addr, nodes[1].Addr, nodes[2].Addr}
+	if err != nil || !reply.Status || !reflect.DeepEqual(reply.Acked, want) {
+		t.Fatalf("streaming %v = %v, %v, want acked by %v", blk, reply, err, want)
+	}
+	for i, d := range nodes {
+		if got := readTestBlk(t, d, blk); !bytes.Equal(got, data) {
+			t.Errorf("datanode %v holds %v bytes differing from the %v streamed",
+				i, len(got), len(data))
+		}
+	}
+	bad := testBlkID("s.bin", 1)
+	if _, err := send(bad, flipped(utils.ChecksumOf(utils.CRC32, data))); err == nil {
+		t.Errorf("streaming %v with a wrong checksum succeeded", bad)
+	}
+	// a datanode down ends the streams of the pipeline, which drop their
+	// part of the block
+	targets = []string{nodes[1].Addr, "127.0.0.1:1"}
+	down := testBlkID("s.bin", 2)
+	if _, err := send(down, utils.ChecksumOf(utils.CRC32, data)); err == nil {
+		t.Errorf("streaming %v through a datanode down succeeded", down)
+	}
+	for i, d := range nodes {
+		d.mu.Lock()
+		_, held := d.parts[down]
+		d.mu.Unlock()
+		if held {
+			t.Errorf("datanode %v keeps a part of %v", i, down)
+		}
+	}
+}
+
+func TestGetTimestampDashedName(t *testing.T) {
+	for _, name := range []string{"my-data.txt", "a-b-c-d-e", "-"} {
+		id := utils.BlockID{FileName: name, Index: 3, Timestamp: 1600000000000,
+			Random: "8674665223082153551"}
+		if got := getTimestamp(id.String()); got != id.Timestamp {
+			t.Errorf("getTimestamp(%q) = %v, want %v", id.String(), got, id.Timestamp)
+		}
+	}
+}
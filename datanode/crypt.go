@@ -0,0 +1,110 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+/** With config.EncryptBlks, the actual data file of a block holds
+ * 	nonce | AES-GCM(data)
+ * with the block id as additional data, so that a block file copied over
+ * another one fails to decrypt. Checksums in metadata are computed over
+ * the plaintext, a block is decrypted before being verified or sent.
+ * Blocks being streamed are kept in memory until they are complete, see
+ * SendBlkChunk.
+ * */
+
+// blkCipher returns the cipher of block files, nil if they are not
+// encrypted
+func (d *DataNode) blkCipher() (cipher.AEAD, error) {
+	if !config.EncryptBlks {
+		return nil, nil
+	}
+	d.aeadOnce.Do(func() {
+		var key []byte
+		key, d.aeadErr = loadBlkKey()
+		if d.aeadErr != nil {
+			return
+		}
+		var block cipher.Block
+		block, d.aeadErr = aes.NewCipher(key)
+		if d.aeadErr != nil {
+			return
+		}
+		d.aead, d.aeadErr = cipher.NewGCM(block)
+	})
+	return d.aead, d.aeadErr
+}
+
+// loadBlkKey reads the hex encoded key of block files from the environment
+// variable config.BlkKeyEnv, or from config.BlkKeyFile if it isn't set
+func loadBlkKey() ([]byte, error) {
+	text, ok := os.LookupEnv(config.BlkKeyEnv)
+	if !ok {
+		data, err := ioutil.ReadFile(config.BlkKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("No block key in $%v or %v: %v", config.BlkKeyEnv,
+				config.BlkKeyFile, err)
+		}
+		text = string(data)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(text))
+	if err != nil {
+		return nil, fmt.Errorf("Malformed block key: %v", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("Block key is %v bytes, want 32", len(key))
+	}
+	return key, nil
+}
+
+// sealBlk encrypts data of block blkID for the disk, if blocks are
+// encrypted
+func (d *DataNode) sealBlk(blkID string, data []byte) ([]byte, error) {
+	aead, err := d.blkCipher()
+	if aead == nil || err != nil {
+		return data, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, []byte(blkID)), nil
+}
+
+// openBlk decrypts the content of the data file of block blkID, if
+// blocks are encrypted
+func (d *DataNode) openBlk(blkID string, sealed []byte) ([]byte, error) {
+	aead, err := d.blkCipher()
+	if aead == nil || err != nil {
+		return sealed, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("Encrypted block too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(blkID))
+}
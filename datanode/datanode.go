@@ -16,21 +16,24 @@ package datanode
 
 import (
 	"bufio"
+	"context"
+	"crypto/cipher"
 	"encoding/gob"
-	"encoding/json"
-	"io/ioutil"
 	"log"
 	"net"
-	"net/rpc"
 	"os"
 	"strconv"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/logger"
 	"github.com/WineChord/gdfs/namenode"
 	"github.com/WineChord/gdfs/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
 )
 
 // DataNode contains block names and
@@ -38,14 +41,20 @@ import (
 // IDToMetaData will be persistent on disk
 // IDList is restored as IDToMetaData.keys()
 type DataNode struct {
+	// DataPath holds NamespaceID and StorageID
 	DataPath string
-	MetaPath string
-	ActPath  string
+	// files of NamespaceID and StorageID, config.NamespaceIDPath and
+	// config.StorageIDPath when datanode is created
+	nidPath string
+	sidPath string
+	// Volumes are the data directories holding blocks, see
+	// config.DataDirs
+	Volumes []*Volume
 	// Assigned after each format.
 	// When DataNode first starts, it will perform a
 	// handshake with NameNode. During this process
-	// NamespaceID will be verified. (also software version
-	// as described in paper, but I omit it)
+	// NamespaceID will be verified, and so will the software
+	// version, see config.VersionCheck
 	NamespaceID int
 	// Persistent to disk, generated when DataNode first
 	// registers with NameNode
@@ -54,6 +63,12 @@ type DataNode struct {
 	IP        string
 	Port      string
 	Addr      string
+	// server of client requests, stopped on shutdown
+	server *grpc.Server
+	// done is closed by Stop, ending Run and the periodic tasks
+	done     chan struct{}
+	stopOnce sync.Once
+	tasks    sync.WaitGroup
 	/* Each block has tow files on DataNode:
 	 * 1. metadata file
 	 * 2. actual data file
@@ -69,7 +84,7 @@ type DataNode struct {
 	 *    the DataNode restart with different IP, it will
 	 *    still be able to work.
 	 * 3. send a block report to NameNode. Report each block's
-	 *    blockID, generation stamp(here I use timestamp instead)
+	 *    blockID, generation stamp
 	 *	  and block length. block report then is send periodically
 	 *    to NameNode. (every hour as described in paper)
 	 * 4. start sending heartbeats to NameNode. (every 3 seconds in paper)
@@ -81,114 +96,174 @@ type DataNode struct {
 	 */
 	// IDList       []string
 	IDToMetaData map[string]utils.MetaData
-	mu           sync.Mutex
+	// blkVols maps the blocks of IDToMetaData to their volume
+	blkVols map[string]*Volume
+	// nextVol is the turn of round-robin volume choice
+	nextVol int
+	// parts are the blocks being streamed, see SendBlkChunk
+	parts map[string]*part
+	// mu protects IDToMetaData, blkVols, nextVol, parts and the state of
+	// Volumes, every read and write of them should hold it since client
+	// RPCs are served concurrently
+	mu sync.Mutex
+	// numDataTrans is the number of block transfers in progress, see
+	// beginTransfer
+	numDataTrans int32
+	// jobs holds the intermediate data of distributed mapreduce jobs,
+	// guarded by jobMu
+	jobs  jobData
+	jobMu sync.Mutex
+	// cipher of block files with config.EncryptBlks, see blkCipher
+	aead     cipher.AEAD
+	aeadErr  error
+	aeadOnce sync.Once
+	// cache of recently read blocks, see readData
+	cache *blkCache
+	// diskReads counts the block files read, see loadData
+	diskReads int64
+	// bytes of block data sent by RequestBlk and received by SendBlk and
+	// SendBlkChunk
+	bytesRead    int64
+	bytesWritten int64
+	// throttle limits the rate of the blocks replicated or forwarded to
+	// other datanodes, see config.NodeBytesPerSec
+	throttle *utils.Throttler
+	// dataNodes keeps the connections to the next datanodes of write
+	// pipelines, see forward
+	dataNodes *utils.Pool
+	// metrics served at config.DataNodeMetricsPort, see registerMetrics
+	registry        *prometheus.Registry
+	rpcLatency      *prometheus.HistogramVec
+	metricsListener net.Listener
+}
+
+// beginTransfer counts a block transfer in progress until the returned
+// function is called, the count goes to namenode with each heartbeat
+func (d *DataNode) beginTransfer() (end func()) {
+	atomic.AddInt32(&d.numDataTrans, 1)
+	return func() { atomic.AddInt32(&d.numDataTrans, -1) }
+}
+
+// NumDataTrans returns the number of block transfers in progress
+func (d *DataNode) NumDataTrans() int {
+	return int(atomic.LoadInt32(&d.numDataTrans))
 }
 
 // NewDataNode retrieve NamespaceID and StorageID on disk
 // (if exist)
 func NewDataNode() *DataNode {
-	d := &DataNode{}
+	d := &DataNode{cache: newBlkCache(int64(config.BlkCacheBytes)),
+		parts: make(map[string]*part), done: make(chan struct{}),
+		throttle: utils.NewThrottler(config.NodeBytesPerSec), dataNodes: utils.NewPool()}
+	d.registerMetrics()
 	d.init()
 	return d
 }
 
 func (d *DataNode) init() {
-	log.Printf("start initializing datanode...\n")
+	logger.Infof("start initializing datanode...\n")
 	gob.Register(utils.MetaData{})
 	d.DataPath = config.DataPath
+	d.nidPath, d.sidPath = config.NamespaceIDPath, config.StorageIDPath
+	for _, dir := range config.DataDirs {
+		d.Volumes = append(d.Volumes, NewVolume(dir))
+	}
 	ex, err := utils.Exists(d.DataPath)
 	if err != nil {
-		log.Printf("error with data node path: %v\n", err)
+		logger.Errorf("error with data node path: %v\n", err)
 	}
 	d.NamespaceID = -1
 	d.StorageID = ""
 	if !ex {
-		log.Printf("create datapath for datanode: %v\n", d.DataPath)
+		logger.Debugf("create datapath for datanode: %v\n", d.DataPath)
 		os.MkdirAll(d.DataPath, 0700)
 	} else {
 		// try read NamespaceID and StorageID from disk
 		d.tryReadNamespaceID()
 		d.tryReadStorageID()
 	}
+	d.mu.Lock()
 	d.constructInfo() // construct IDToMetaData map using local disk files
+	d.mu.Unlock()
 	d.getAddress()
-	log.Printf("datanode %v is successfully initialized\n", d.HostName)
-	log.Printf("addr: %v, datapath: %v, nid: %v, sid: %v", d.Addr, d.DataPath,
+	logger.Infof("datanode %v is successfully initialized\n", d.HostName)
+	logger.Infof("addr: %v, datapath: %v, nid: %v, sid: %v", d.Addr, d.DataPath,
 		d.NamespaceID, d.StorageID)
 }
 
+// constructInfo rebuilds IDToMetaData from the store of each volume, a
+// volume which can't be loaded is failed. The caller should
+// hold d.mu.
 func (d *DataNode) constructInfo() {
 	d.IDToMetaData = make(map[string]utils.MetaData)
-	d.MetaPath = config.IDToMetaDataPath
-	d.ActPath = config.ActualDataPath
-	ex, err := utils.Exists(d.MetaPath)
-	if err != nil {
-		log.Printf("error with metadata path: %v\n", err)
-	}
-	if !ex {
-		log.Printf("create metadata path %v\n", d.MetaPath)
-		os.MkdirAll(d.MetaPath, 0700)
-	} else {
-		// dir exists, try to read IDToMetaData map
-		files, err := ioutil.ReadDir(d.MetaPath)
-		if err != nil {
-			log.Printf("error when reading dir %v: %v", d.MetaPath, err)
+	d.blkVols = make(map[string]*Volume)
+	for _, v := range d.Volumes {
+		if v.Failed {
+			continue
 		}
-		for _, file := range files {
-			d.readJSON(file)
+		if err := d.load(v); err != nil {
+			d.failVolume(v, err)
 		}
 	}
-	ex, err = utils.Exists(d.ActPath)
-	if err != nil {
-		log.Printf("error with actual data path: %v\n", err)
-	}
-	if !ex {
-		log.Printf("create actual data path %v\n", d.ActPath)
-		os.MkdirAll(d.ActPath, 0700)
-	} else {
-		// actual data path exists, should check whether it
-		// matches with metadata information TODO
-	}
 }
 
-func (d *DataNode) readJSON(file os.FileInfo) {
-	// the struct MetaData is store in json format in file
-	filename := d.MetaPath + string(os.PathSeparator) + file.Name()
-	jsonFile, err := os.Open(filename)
-	if err != nil {
-		log.Printf("error when opening %v: %v\n", filename, err)
-	}
-	defer jsonFile.Close()
-	byteValue, err := ioutil.ReadAll(jsonFile)
-	if err != nil {
-		log.Printf("error when reading %v: %v\n", filename, err)
-	}
-	var metadata utils.MetaData
-	json.Unmarshal(byteValue, &metadata)
-	d.IDToMetaData[file.Name()] = metadata // store metadata
-	log.Printf("load metadata from %v: , checksum: %v, timestamp: %v, len: %v\n",
-		file.Name(), metadata.Checksum, metadata.Timestamp, metadata.Length)
-}
+// lookupHost resolves host names, tests replace it
+var lookupHost = net.LookupHost
 
+// getAddress sets the address datanode advertises, config.DataNodeHost if
+// set, otherwise the best address of the hostname, see chooseAddress
 func (d *DataNode) getAddress() {
 	name, err := os.Hostname() // should be thumm0[1-5] :)
 	if err != nil {
-		log.Printf("error when getting hostname: %v\n", err)
+		logger.Errorf("error when getting hostname: %v\n", err)
 	}
 	d.HostName = name
-	addrs, err := net.LookupHost(name)
-	if err != nil {
-		log.Printf("error when looking up %v: %v\n", name, err)
+	d.IP = config.DataNodeHost
+	if d.IP == "" {
+		addrs, err := lookupHost(name)
+		if err != nil {
+			logger.Errorf("error when looking up %v: %v\n", name, err)
+		}
+		d.IP = chooseAddress(addrs, config.PreferIPv6)
+		if d.IP == "" {
+			d.IP = name // let the resolver of the peers try
+		}
 	}
-	d.IP = addrs[0] // I will take the first one :)
 	d.Port = config.DataNodePort
-	d.Addr = d.IP + ":" + d.Port
-	log.Printf("datanode information: %v %v:%v\n", name, d.IP, d.Port)
+	d.Addr = utils.JoinHostPort(d.IP, d.Port)
+	logger.Infof("datanode information: %v %v\n", name, d.Addr)
+}
+
+// chooseAddress picks the address other nodes most likely reach datanode
+// at among addrs: the first routable one of the preferred IP version, then
+// of the other version, then any address but loopback ones, which are
+// taken last. It returns "" if addrs is empty.
+func chooseAddress(addrs []string, preferIPv6 bool) string {
+	best, bestRank := "", 0
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		rank := 1 // not an IP, e.g. a name from /etc/hosts
+		switch {
+		case ip == nil:
+		case ip.IsLoopback():
+			rank = 0
+		case ip.IsGlobalUnicast() && (ip.To4() == nil) == preferIPv6:
+			rank = 4
+		case ip.IsGlobalUnicast():
+			rank = 3
+		default:
+			rank = 2 // link-local and the like
+		}
+		if best == "" || rank > bestRank {
+			best, bestRank = addr, rank
+		}
+	}
+	return best
 }
 
 func (d *DataNode) tryReadNamespaceID() {
-	log.Printf("try to read NamespaceID on disk from %v\n", config.NamespaceIDPath)
-	f, err := os.Open(config.NamespaceIDPath)
+	logger.Debugf("try to read NamespaceID on disk from %v\n", d.nidPath)
+	f, err := os.Open(d.nidPath)
 	defer f.Close()
 	if err == nil {
 		s := bufio.NewScanner(f)
@@ -196,7 +271,7 @@ func (d *DataNode) tryReadNamespaceID() {
 			n, err := strconv.Atoi(s.Text())
 			if err == nil {
 				d.NamespaceID = n
-				log.Printf("got NamespaceID from disk: %v\n", d.NamespaceID)
+				logger.Debugf("got NamespaceID from disk: %v\n", d.NamespaceID)
 			}
 		}
 	}
@@ -204,21 +279,21 @@ func (d *DataNode) tryReadNamespaceID() {
 }
 
 func (d *DataNode) tryReadStorageID() {
-	log.Printf("try to read StorageID on disk from %v\n", config.StorageIDPath)
-	f, err := os.Open(config.StorageIDPath)
+	logger.Debugf("try to read StorageID on disk from %v\n", d.sidPath)
+	f, err := os.Open(d.sidPath)
 	defer f.Close()
 	if err == nil {
 		s := bufio.NewScanner(f)
 		if s.Scan() {
 			d.StorageID = s.Text()
-			log.Printf("got StorageID from disk: %v\n", d.StorageID)
+			logger.Debugf("got StorageID from disk: %v\n", d.StorageID)
 		}
 	}
 }
 
 func (d *DataNode) dumpNID() {
-	log.Printf("dump NamespaceID to disk\n")
-	f, err := os.Create(config.NamespaceIDPath)
+	logger.Debugf("dump NamespaceID to disk\n")
+	f, err := os.Create(d.nidPath)
 	defer f.Close()
 	if err != nil {
 		log.Fatalf("err when creating nid file for datanode: %v\n", err)
@@ -226,12 +301,12 @@ func (d *DataNode) dumpNID() {
 	w := bufio.NewWriter(f)
 	w.WriteString(strconv.Itoa(d.NamespaceID))
 	w.Flush()
-	log.Printf("dump NamespaceID done\n")
+	logger.Debugf("dump NamespaceID done\n")
 }
 
 func (d *DataNode) dumpSID() {
-	log.Printf("dump StorageID to disk\n")
-	f, err := os.Create(config.StorageIDPath)
+	logger.Debugf("dump StorageID to disk\n")
+	f, err := os.Create(d.sidPath)
 	defer f.Close()
 	if err != nil {
 		log.Fatalf("err when creating sid file for datanode: %v\n", err)
@@ -239,25 +314,29 @@ func (d *DataNode) dumpSID() {
 	w := bufio.NewWriter(f)
 	w.WriteString(d.StorageID)
 	w.Flush()
-	log.Printf("dump StorageID done\n")
+	logger.Debugf("dump StorageID done\n")
 }
 
 func (d *DataNode) handshakeWithNameNode() {
-	log.Printf("%v starts to handshake with namenode with nid: %v, addr: %v\n",
+	logger.Debugf("%v starts to handshake with namenode with nid: %v, addr: %v\n",
 		d.HostName, d.NamespaceID, d.Addr)
 	args := namenode.HandshakeArgs{NamespaceID: d.NamespaceID, Addr: d.Addr,
-		HostName: d.HostName}
+		HostName: d.HostName, Token: config.AuthToken, Version: config.Version}
 	reply := namenode.HandshakeReply{}
-	c, err := rpc.DialHTTP("tcp", config.NameNodeAddress)
-	if err != nil {
-		log.Fatal("dialing: ", err)
-	}
-	err = c.Call("NameNode.Handshake", &args, &reply)
+	err := callNameNode(func(ctx context.Context, nn gdfspb.NameNodeClient) error {
+		m, err := nn.Handshake(ctx, args.ToPB())
+		reply.FromPB(m)
+		return err
+	})
 	if err != nil {
 		log.Fatal("Calling: ", err)
 	}
+	if reply.Version != config.Version {
+		logger.Warnf("datanode runs version %v, namenode runs %v\n", config.Version,
+			reply.Version)
+	}
 	d.NamespaceID = reply.NamespaceID // update nid
-	log.Printf("%v got NamespaceID from namenode: %v", d.HostName, d.NamespaceID)
+	logger.Debugf("%v got NamespaceID from namenode: %v", d.HostName, d.NamespaceID)
 	if args.NamespaceID != reply.NamespaceID {
 		d.dumpNID() // persistent to disk
 	}
@@ -275,93 +354,176 @@ func (d *DataNode) registerWithNameNode() {
 	// id to namenode. Otherwise we report our storage
 	// id with an empty string to request name to assign
 	// one.
-	log.Printf("%v starts to register with namenode with sid: %v, addr: %v\n",
+	logger.Debugf("%v starts to register with namenode with sid: %v, addr: %v\n",
 		d.HostName, d.StorageID, d.Addr)
 	args := namenode.RegisterArgs{}
 	args.HostName = d.HostName
 	args.Addr = d.Addr
 	args.StorageID = d.StorageID
+	args.Token = config.AuthToken
 	reply := namenode.RegisterReply{}
-	c, err := rpc.DialHTTP("tcp", config.NameNodeAddress)
-	if err != nil {
-		log.Fatal("dialing: ", err)
-	}
-	err = c.Call("NameNode.Register", &args, &reply)
+	err := callNameNode(func(ctx context.Context, nn gdfspb.NameNodeClient) error {
+		m, err := nn.Register(ctx, args.ToPB())
+		reply.FromPB(m)
+		return err
+	})
 	if err != nil {
 		log.Fatal("Calling: ", err)
 	}
 	d.StorageID = reply.StorageID // update nid
-	log.Printf("%v got StorageID from namenode: %v", d.HostName, d.StorageID)
+	logger.Debugf("%v got StorageID from namenode: %v", d.HostName, d.StorageID)
 	if args.StorageID == "" {
 		d.dumpSID() // persistent to disk
 	}
 }
 
-func (d *DataNode) sendHeartBeat() {
-	log.Printf("sends heartbeat to namenode\n")
-	var stat syscall.Statfs_t
-	wd, err := os.Getwd()
-	if err != nil {
-		log.Printf("error when getting root path name: %v\n", err)
-	}
-	err = syscall.Statfs(wd, &stat)
-	if err != nil {
-		log.Printf("error when getting fs stat: %v\n", err)
-	}
-	// total size in bytes = total block number * block size
-	TotalSize := stat.Blocks * uint64(stat.Bsize) // uint64
-	// fraction in use = available blocks / total blocks
-	FracInUse := float64(stat.Blocks-stat.Bavail) / float64(stat.Blocks) // float64
+// sendHeartBeat sends a heartbeat to namenode and acts on the reply,
+// it returns false if namenode asks the datanode to shutdown
+func (d *DataNode) sendHeartBeat() bool {
+	logger.Debugf("sends heartbeat to namenode\n")
+	d.expireParts()
+	TotalSize, FracInUse := d.capacity()
 	// number of data transfer in progress
-	NumDataTrans := 0 // int
+	NumDataTrans := d.NumDataTrans() // int
 	args := namenode.HeartBeatArgs{}
 	args.HostName = d.HostName
 	args.Addr = d.Addr
 	args.TotalCapacity = TotalSize
 	args.FracInUse = FracInUse
 	args.NumDataTrans = NumDataTrans
+	args.Token = config.AuthToken
 	reply := namenode.HeartBeatReply{}
-	c, err := rpc.DialHTTP("tcp", config.NameNodeAddress)
-	if err != nil {
-		log.Fatal("dialing: ", err)
-	}
-	err = c.Call("NameNode.HeartBeat", &args, &reply)
+	err := callNameNode(func(ctx context.Context, nn gdfspb.NameNodeClient) error {
+		m, err := nn.HeartBeat(ctx, args.ToPB())
+		reply.FromPB(m)
+		return err
+	})
 	if err != nil {
 		log.Fatal("Calling: ", err)
 	}
-	log.Printf("heartbeat reply from namenode:\n"+
+	logger.Debugf("heartbeat reply from namenode:\n"+
 		"\tlen(RepBlk): %v, len(RmBlk): %v, ReRegister: %v, ShutDown: %v"+
 		"ReqBlkRep: %v, Format: %v\n", len(reply.RepBlkToNodes), len(reply.RmBlk),
 		reply.ReRegister, reply.Shutdown, reply.ReqBlkReport, reply.Format)
+	if reply.Shutdown {
+		logger.Infof("namenode requests shutdown\n")
+		return false
+	}
+	if reply.ReRegister {
+		// e.g. namenode restarted or evicted us, join the cluster again
+		logger.Infof("namenode requests re-register\n")
+		d.handshakeWithNameNode()
+		d.registerWithNameNode()
+		d.reportBlock()
+	}
 	if reply.Format {
 		d.format(reply.FormatID)
 	}
+	for blkID, target := range reply.RepBlkToNodes {
+		go d.replicateBlk(blkID, target)
+	}
+	for _, blkID := range reply.RmBlk {
+		d.deleteBlk(blkID)
+	}
 	if reply.ReqBlkReport {
 		d.reportBlock()
 	}
+	return true
+}
+
+// replicateBlk sends a local block to another datanode as instructed by
+// namenode, then notifies namenode so that the new replica gets reported
+func (d *DataNode) replicateBlk(blkID, target string) {
+	logger.Infof("replicate %v to %v\n", blkID, target)
+	defer d.beginTransfer()()
+	args := utils.BlkData{}
+	args.BlkID = blkID
+	meta := d.readMeta(blkID)
+	args.Checksum, args.ChecksumType = meta.Checksum, meta.ChecksumType
+	args.Length = int(meta.Length)
+	// the copy is as recent as the local replica
+	args.GenStamp = meta.GenStamp
+	args.Token = config.AuthToken
+	args.Data = d.readData(blkID)
+	if config.CompressBlks {
+		data, err := utils.Gzip(args.Data)
+		if err != nil {
+			logger.Errorf("error when compressing %v: %v\n", blkID, err)
+			return
+		}
+		args.Data, args.Compressed = data, true
+	}
+	utils.Throttle(len(args.Data), utils.NewThrottler(config.TransferBytesPerSec),
+		d.throttle)
+	err := d.dataNodes.CallDataNode(target, utils.RPCTimeout(), nil,
+		func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+			_, err := dn.SendBlk(ctx, args.ToPB())
+			return err
+		})
+	if err != nil {
+		logger.Errorf("error when replicating %v to %v: %v\n", blkID, target, err)
+		return
+	}
+	d.notifyNameNode(target)
+}
+
+// notifyNameNode asks namenode to request a block report from addr
+func (d *DataNode) notifyNameNode(addr string) {
+	args := namenode.NotifyArgs{Token: config.AuthToken}
+	args.Addrs = []string{addr}
+	err := callNameNode(func(ctx context.Context, nn gdfspb.NameNodeClient) error {
+		_, err := nn.Notify(ctx, args.ToPB())
+		return err
+	})
+	if err != nil {
+		logger.Errorf("error when notifying namenode: %v\n", err)
+	}
 }
 
 func (d *DataNode) format(formatID int) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
-	log.Printf("start format datanode\n")
+	logger.Infof("start format datanode\n")
 	d.NamespaceID = formatID
 	d.dumpNID()
-	err := os.RemoveAll(d.ActPath)
-	if err != nil {
-		log.Printf("error when removing actual data path\n")
-	}
-	err = os.RemoveAll(d.MetaPath)
-	if err != nil {
-		log.Printf("error when removing meta data path\n")
+	for _, v := range d.Volumes {
+		if v.Failed {
+			continue
+		}
+		blks, err := v.Store.List()
+		if err != nil {
+			logger.Errorf("error when listing blocks of %v: %v\n", v.Dir, err)
+			continue
+		}
+		for blkID := range blks {
+			if err := v.Store.Delete(blkID); err != nil {
+				logger.Errorf("error when removing %v from %v: %v\n", blkID, v.Dir, err)
+			}
+		}
 	}
 	// inside constrcutInfo, the in memory data structure will
 	// be cleared
 	d.constructInfo()
+	d.cache.clear()
 	// d.reportBlock()
 }
 
 func (d *DataNode) reportBlock() {
+	err := d.sendBlockReport()
+	if err != nil && err.Error() == namenode.ErrNotRegistered.Error() {
+		// evicted since the last heartbeat, join the cluster again
+		logger.Infof("namenode requests re-register\n")
+		d.handshakeWithNameNode()
+		d.registerWithNameNode()
+		err = d.sendBlockReport()
+	}
+	if err != nil {
+		log.Fatal("block report: ", err)
+	}
+}
+
+// sendBlockReport sends the blocks of the healthy volumes to namenode
+func (d *DataNode) sendBlockReport() error {
 	// datanode does the first block report after registration
 	// with namenode, then it will do block report hourly (in paper)
 	// Here we set the report time to be every 1 minuate.
@@ -371,40 +533,87 @@ func (d *DataNode) reportBlock() {
 	//    1. Block id (string)
 	//    2. Timestamp (string)
 	//    3. Block length (int64)
-	log.Printf("report blocks to namenode, length: %v\n", len(d.IDToMetaData))
+	// blocks of a failed volume are left out, namenode then
+	// re-replicates them from other datanodes
+	d.checkVolumes()
 	args := namenode.ReportBlockArgs{}
 	args.HostName = d.HostName
 	args.Addr = d.Addr
-	args.IDToMetaData = d.IDToMetaData
-	reply := namenode.ReportBlockReply{}
-	c, err := rpc.DialHTTP("tcp", config.NameNodeAddress)
-	if err != nil {
-		log.Fatal("dialing: ", err)
+	args.Token = config.AuthToken
+	// take a snapshot of the map, since it may be modified by
+	// client requests while being encoded for the RPC
+	args.IDToMetaData = make(map[string]utils.MetaData)
+	d.mu.Lock()
+	for id, meta := range d.IDToMetaData {
+		args.IDToMetaData[id] = meta
 	}
-	err = c.Call("NameNode.ReportBlock", &args, &reply)
+	d.mu.Unlock()
+	logger.Debugf("report blocks to namenode, length: %v\n", len(args.IDToMetaData))
+	reply := namenode.ReportBlockReply{}
+	err := callNameNode(func(ctx context.Context, nn gdfspb.NameNodeClient) error {
+		m, err := nn.ReportBlock(ctx, args.ToPB())
+		reply.FromPB(m)
+		return err
+	})
 	if err != nil {
-		log.Fatal("Calling: ", err)
+		return err
 	}
-	log.Printf("report blocks status: %v\n", reply.Status)
+	logger.Debugf("report blocks status: %v\n", reply.Status)
+	return nil
 }
 
 // Run first perform handshake with NameNode,
-// then register with NameNode to get storage id
+// then register with NameNode to get storage id.
+// It serves clients from the start, so that a port 0 of
+// config.DataNodePort is replaced by the free port picked before
+// datanode introduces itself. It returns once namenode asks for
+// shutdown or Stop is called.
 func (d *DataNode) Run() {
-	log.Printf("datanode starts running...\n")
+	logger.Infof("datanode starts running...\n")
+	d.serveClients()
 	// perform handshake with NameNode
 	d.handshakeWithNameNode()
 	d.registerWithNameNode()
 	d.reportBlock()
-	go d.reportPeriodically()
-	go d.serveClients()
-	for {
-		d.sendHeartBeat()
-		time.Sleep(time.Second * time.Duration(config.HeartBeatInSec))
+	for _, task := range []func(){d.reportPeriodically, d.scanBlocks} {
+		d.tasks.Add(1)
+		go func(task func()) {
+			defer d.tasks.Done()
+			task()
+		}(task)
+	}
+	for d.sendHeartBeat() {
+		if !d.sleep(config.HeartBeatInSec) {
+			break
+		}
+	}
+	d.Stop()
+	d.tasks.Wait()
+	d.stopServing()
+	logger.Infof("datanode %v shutdown\n", d.HostName)
+}
+
+// Stop makes Run return after the heartbeat in progress, if any
+func (d *DataNode) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.done)
+		d.dataNodes.Close()
+	})
+}
+
+// sleep waits for sec seconds, it returns false if datanode is stopped
+// meanwhile
+func (d *DataNode) sleep(sec int) bool {
+	select {
+	case <-d.done:
+		return false
+	case <-time.After(time.Second * time.Duration(sec)):
+		return true
 	}
 }
 
 func (d *DataNode) reportPeriodically() {
-	time.Sleep(time.Second * time.Duration(config.BlkReportInSec))
-	d.reportBlock()
+	if d.sleep(config.BlkReportInSec) {
+		d.reportBlock()
+	}
 }
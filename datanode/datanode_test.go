@@ -0,0 +1,710 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/namenode"
+	"github.com/WineChord/gdfs/utils"
+)
+
+func TestMain(m *testing.M) {
+	// datanode logs every block it handles
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+// chdir changes the working directory to dir until the test ends
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// newTestDataNode starts a datanode, not connected to any namenode,
+// keeping its blocks in a temporary directory
+func newTestDataNode(t *testing.T) *DataNode {
+	t.Helper()
+	chdir(t, t.TempDir())
+	return NewDataNode()
+}
+
+// disk returns the store of volume i of d, on disk
+func disk(d *DataNode, i int) *DiskStore {
+	return d.Volumes[i].Store.(*DiskStore)
+}
+
+// testBlkID returns the id of block index of file name
+func testBlkID(name string, index int) string {
+	return fmt.Sprintf("%v-%08d-%v-%v", name, index, utils.GetCurrentTimeInMs(),
+		rand.Int())
+}
+
+// putBlk sends data to d as block blkID
+func putBlk(t *testing.T, d *DataNode, blkID string, data []byte) {
+	t.Helper()
+	args := utils.BlkData{BlkID: blkID, Data: data,
+		Checksum: utils.ChecksumOf(utils.CRC32, data), Length: len(data)}
+	if err := d.SendBlk(&args, &SendBlkReply{}); err != nil {
+		t.Fatalf("sending %v: %v", blkID, err)
+	}
+}
+
+// readTestBlk reads the whole block blkID from d
+func readTestBlk(t *testing.T, d *DataNode, blkID string) []byte {
+	t.Helper()
+	reply := utils.BlkData{}
+	args := RequestBlkArgs{BlkID: blkID}
+	if err := d.RequestBlk(&args, &reply); err != nil {
+		t.Fatalf("reading %v: %v", blkID, err)
+	}
+	return reply.Data
+}
+
+// startNameNode serves a namenode keeping its metadata in the working
+// directory on a free loopback port, config.NameNodeAddress points to it
+// until the test ends
+func startNameNode(t *testing.T) *namenode.NameNode {
+	t.Helper()
+	n := namenode.NewNameNode()
+	serv := utils.NewServer()
+	namenode.RegisterServer(serv, n)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go serv.Serve(l)
+	addr := config.NameNodeAddress
+	config.NameNodeAddress = l.Addr().String()
+	t.Cleanup(func() {
+		serv.Stop()
+		config.NameNodeAddress = addr
+	})
+	return n
+}
+
+// join makes d join the namenode at config.NameNodeAddress the way Run
+// does, up to the first block report
+func join(d *DataNode) {
+	d.handshakeWithNameNode()
+	d.registerWithNameNode()
+	d.reportBlock()
+}
+
+func TestHeartBeatRemovesBlocks(t *testing.T) {
+	d := newTestDataNode(t)
+	n := startNameNode(t)
+	keep, drop := testBlkID("rm.txt", 0), testBlkID("rm.txt", 1)
+	putBlk(t, d, keep, []byte("kept"))
+	putBlk(t, d, drop, []byte("removed"))
+	join(d)
+	// replicas past the replication factor are removed through the next
+	// heartbeat, see scheduleRemoval
+	var nodes []string
+	for i := 0; i < config.ReplicationFactor; i++ {
+		nodes = append(nodes, fmt.Sprintf("other-%v", i))
+	}
+	n.BlkToDatanodes[drop] = append(nodes, d.StorageID)
+	d.sendHeartBeat()
+	for _, dir := range []string{disk(d, 0).ActPath, disk(d, 0).MetaPath} {
+		_, err := os.Stat(filepath.Join(dir, drop))
+		if !os.IsNotExist(err) {
+			t.Errorf("%v of the removed block is still there: %v", dir, err)
+		}
+		_, err = os.Stat(filepath.Join(dir, keep))
+		if err != nil {
+			t.Errorf("%v of the kept block: %v", dir, err)
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.IDToMetaData[drop]; ok {
+		t.Errorf("removed block %v is still recorded", drop)
+	}
+}
+
+func TestHeartBeatReRegister(t *testing.T) {
+	d := newTestDataNode(t)
+	n := startNameNode(t)
+	blk := testBlkID("reregister.txt", 0)
+	putBlk(t, d, blk, []byte("data"))
+	join(d)
+	sid := d.StorageID
+	// as if namenode evicted the datanode and forgot its blocks
+	delete(n.Addr2SID, d.Addr)
+	delete(n.BlkToDatanodes, blk)
+	if !d.sendHeartBeat() {
+		t.Fatal("datanode shut down")
+	}
+	if got := n.Addr2SID[d.Addr]; got != sid {
+		t.Errorf("storage id %q after registering again, want %q", got, sid)
+	}
+	if got := n.BlkToDatanodes[blk]; len(got) != 1 || got[0] != sid {
+		t.Errorf("replicas of %v are %v after registering again, want [%v]",
+			blk, got, sid)
+	}
+}
+
+// shutdownNameNode is a namenode telling every datanode to shutdown
+type shutdownNameNode struct {
+	gdfspb.UnimplementedNameNodeServer
+}
+
+func (shutdownNameNode) HeartBeat(ctx context.Context,
+	args *gdfspb.HeartBeatArgs) (*gdfspb.HeartBeatReply, error) {
+	return &gdfspb.HeartBeatReply{Shutdown: true}, nil
+}
+
+func TestHeartBeatShutdown(t *testing.T) {
+	d := newTestDataNode(t)
+	startNameNode(t)
+	join(d)
+	if !d.sendHeartBeat() {
+		t.Fatal("datanode shut down without being asked to")
+	}
+	serv := utils.NewServer()
+	gdfspb.RegisterNameNodeServer(serv, shutdownNameNode{})
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer serv.Stop()
+	go serv.Serve(l)
+	config.NameNodeAddress = l.Addr().String()
+	if d.sendHeartBeat() {
+		t.Error("datanode keeps running after namenode asked for shutdown")
+	}
+}
+
+func TestHeartBeatRequestsReport(t *testing.T) {
+	d := newTestDataNode(t)
+	n := startNameNode(t)
+	join(d)
+	blk := testBlkID("report.txt", 0)
+	putBlk(t, d, blk, []byte("data"))
+	if len(n.BlkToDatanodes[blk]) != 0 {
+		t.Fatalf("namenode knows %v before it was reported", blk)
+	}
+	// what a client does after writing a block
+	args := namenode.NotifyArgs{Addrs: []string{d.Addr}}
+	if err := n.Notify(&args, &namenode.NotifyReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if !d.sendHeartBeat() {
+		t.Fatal("datanode shut down")
+	}
+	if got := n.BlkToDatanodes[blk]; len(got) != 1 || got[0] != d.StorageID {
+		t.Errorf("replicas of %v are %v after the heartbeat, want [%v]", blk,
+			got, d.StorageID)
+	}
+}
+
+func TestCrashBeforeRename(t *testing.T) {
+	d := newTestDataNode(t)
+	data := []byte("the quick brown fox jumps over the lazy dog")
+	good, lost := testBlkID("crash.txt", 0), testBlkID("crash.txt", 1)
+	putBlk(t, d, good, data)
+	// what a crash leaves when writing each file before its rename: a new
+	// block, a new version of an existing one and a streamed one
+	leftovers := []string{
+		filepath.Join(disk(d, 0).ActPath, lost+utils.TmpSuffix),
+		filepath.Join(disk(d, 0).MetaPath, lost+utils.TmpSuffix),
+		filepath.Join(disk(d, 0).ActPath, good+utils.TmpSuffix),
+		filepath.Join(disk(d, 0).MetaPath, good+utils.TmpSuffix),
+		filepath.Join(disk(d, 0).ActPath, lost+partSuffix),
+	}
+	for _, file := range leftovers {
+		if err := ioutil.WriteFile(file, []byte(`{"Len`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d = NewDataNode()
+	if len(d.IDToMetaData) != 1 || d.IDToMetaData[good].Length != int64(len(data)) {
+		t.Errorf("blocks after restart are %v, want only %v", d.IDToMetaData, good)
+	}
+	if got := readTestBlk(t, d, good); !bytes.Equal(got, data) {
+		t.Errorf("%v reads %q after restart, want %q", good, got, data)
+	}
+	for _, file := range leftovers {
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Errorf("%v is left after restart: %v", file, err)
+		}
+	}
+}
+
+func TestMalformedMetadataQuarantined(t *testing.T) {
+	d := newTestDataNode(t)
+	good := testBlkID("meta.txt", 0)
+	putBlk(t, d, good, []byte("data"))
+	bad := map[string]string{
+		testBlkID("meta.txt", 1): `{"Length":4,"Check`,
+		testBlkID("meta.txt", 2): `garbage`,
+		testBlkID("meta.txt", 3): `{"Length":-1}`,
+	}
+	for blk, meta := range bad {
+		putBlk(t, d, blk, []byte("data"))
+		path := filepath.Join(disk(d, 0).MetaPath, blk)
+		if err := ioutil.WriteFile(path, []byte(meta), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	restarted := NewDataNode()
+	if len(restarted.IDToMetaData) != 1 {
+		t.Errorf("blocks after restart are %v, want only %v",
+			restarted.IDToMetaData, good)
+	}
+	if got := readTestBlk(t, restarted, good); string(got) != "data" {
+		t.Errorf("%v holds %q after restart", good, got)
+	}
+	for blk := range bad {
+		corrupt := filepath.Join(disk(d, 0).CorruptPath, blk)
+		for _, file := range []string{corrupt, corrupt + ".meta"} {
+			if _, err := os.Stat(file); err != nil {
+				t.Errorf("%v is not quarantined: %v", file, err)
+			}
+		}
+		for _, dir := range []string{disk(d, 0).ActPath, disk(d, 0).MetaPath} {
+			_, err := os.Stat(filepath.Join(dir, blk))
+			if !os.IsNotExist(err) {
+				t.Errorf("%v of %v is left: %v", dir, blk, err)
+			}
+		}
+	}
+}
+
+func TestRequestBlkRange(t *testing.T) {
+	d := newTestDataNode(t)
+	blk := testBlkID("range.txt", 0)
+	data := []byte("the quick brown fox jumps over the lazy dog")
+	putBlk(t, d, blk, data)
+	full := readTestBlk(t, d, blk)
+	size := int64(len(data))
+	for _, tt := range []struct {
+		offset, length int64
+		want           []byte
+	}{
+		{0, 0, full},
+		{4, 5, full[4:9]},
+		{0, 3, full[:3]},
+		{40, 0, full[40:]},           // up to the end
+		{35, 100, full[35:]},         // clamped to the end
+		{size, 0, []byte{}},          // empty range at the end
+		{size - 1, 1, full[size-1:]}, // last byte
+	} {
+		reply := utils.BlkData{}
+		args := RequestBlkArgs{BlkID: blk, Offset: tt.offset, Length: tt.length}
+		if err := d.RequestBlk(&args, &reply); err != nil {
+			t.Errorf("reading [%v, +%v): %v", tt.offset, tt.length, err)
+			continue
+		}
+		if !bytes.Equal(reply.Data, tt.want) || reply.Length != len(tt.want) ||
+			!reply.Checksum.Equal(utils.ChecksumOf(utils.CRC32, tt.want)) {
+			t.Errorf("reading [%v, +%v) = %q (%v bytes, checksum %x), want %q",
+				tt.offset, tt.length, reply.Data, reply.Length, reply.Checksum, tt.want)
+		}
+	}
+	for _, args := range []RequestBlkArgs{
+		{BlkID: blk, Offset: size + 1},
+		{BlkID: blk, Offset: -1, Length: 2},
+		{BlkID: blk, Offset: 1, Length: -2},
+		{BlkID: testBlkID("range.txt", 1)},
+	} {
+		if err := d.RequestBlk(&args, &utils.BlkData{}); err == nil {
+			t.Errorf("reading %+v succeeded", args)
+		}
+	}
+}
+
+func TestNumDataTransReported(t *testing.T) {
+	d := newTestDataNode(t)
+	n := startNameNode(t)
+	blk := testBlkID("busy.txt", 0)
+	putBlk(t, d, blk, []byte("busy"))
+	join(d)
+	// readers of a fifo block until a writer opens it, so requests of the
+	// block stay in progress until the test lets them go
+	path := filepath.Join(disk(d, 0).ActPath, blk)
+	os.Remove(path)
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		t.Skipf("no fifo: %v", err)
+	}
+	const transfers = 3
+	done := make(chan struct{})
+	for i := 0; i < transfers; i++ {
+		go func() {
+			d.RequestBlk(&RequestBlkArgs{BlkID: blk}, &utils.BlkData{})
+			done <- struct{}{}
+		}()
+	}
+	waitFor := func(want int) {
+		t.Helper()
+		for start := time.Now(); d.NumDataTrans() != want; {
+			if time.Since(start) > 5*time.Second {
+				t.Fatalf("%v transfers in progress, want %v", d.NumDataTrans(), want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitFor(transfers)
+	d.sendHeartBeat()
+	if got := n.NodeStats[d.Addr].NumDataTrans; got != transfers {
+		t.Errorf("heartbeat reported %v transfers, want %v", got, transfers)
+	}
+	// closing a writer ends the reads in progress, keep doing so in case
+	// a request hasn't opened the fifo yet
+	for i := 0; i < transfers; {
+		w, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+		if err == nil {
+			w.Close()
+		}
+		select {
+		case <-done:
+			i++
+		case <-time.After(time.Millisecond):
+		}
+	}
+	waitFor(0)
+	d.sendHeartBeat()
+	if got := n.NodeStats[d.Addr].NumDataTrans; got != 0 {
+		t.Errorf("heartbeat reported %v transfers after they ended, want 0", got)
+	}
+}
+
+func TestCountWords(t *testing.T) {
+	for _, tt := range []struct {
+		data       string
+		head, tail string
+		whole      bool
+		counts     map[string]int
+	}{
+		{"", "", "", true, map[string]int{}},
+		{"straddling", "straddling", "", true, map[string]int{}},
+		{"ing a b a bl", "ing", "bl", false, map[string]int{"a": 2, "b": 1}},
+		{" a b\n", "", "", false, map[string]int{"a": 1, "b": 1}},
+		{"x\ty", "x", "y", false, map[string]int{}},
+		{"héllo wörld é", "héllo", "é", false, map[string]int{"wörld": 1}},
+	} {
+		got := countWords([]byte(tt.data))
+		if got.Head != tt.head || got.Tail != tt.tail || got.Whole != tt.whole ||
+			!reflect.DeepEqual(got.Counts, tt.counts) {
+			t.Errorf("countWords(%q) = %+v, want head %q, tail %q, whole %v, %v",
+				tt.data, got, tt.head, tt.tail, tt.whole, tt.counts)
+		}
+	}
+}
+
+func TestCalMeanVarMap(t *testing.T) {
+	d := newTestDataNode(t)
+	for _, tt := range []struct {
+		data       string
+		cnt        int64
+		mean, sq   float64
+		head, tail string
+	}{
+		// the first and the last line are edges, see utils.Edges
+		{"x\n1\n2\n3\n6\ny", 4, 3, 12.5, "x", "y"},
+		{"\n-2\n\n 4 \nabc\n2.5\n", 2, 1, 10, "", ""},
+		{"", 0, 0, 0, "", ""},
+		{"\nnot a number\n", 0, 0, 0, "", ""},
+	} {
+		blk := testBlkID("nums.txt", 0)
+		putBlk(t, d, blk, []byte(tt.data))
+		reply := utils.CalMVReply{}
+		if err := d.CalMeanVarMap(&utils.CalMVArgs{BlkID: blk}, &reply); err != nil {
+			t.Errorf("calMeanVar of %q: %v", tt.data, err)
+			continue
+		}
+		if reply.Cnt != tt.cnt || reply.Mean != tt.mean || reply.MeanSQ != tt.sq ||
+			reply.Head != tt.head || reply.Tail != tt.tail {
+			t.Errorf("calMeanVar of %q = %+v, want cnt %v, mean %v, meansq %v, "+
+				"edges %q %q", tt.data, reply, tt.cnt, tt.mean, tt.sq, tt.head, tt.tail)
+		}
+	}
+	err := d.CalMeanVarMap(&utils.CalMVArgs{BlkID: testBlkID("nums.txt", 1)},
+		&utils.CalMVReply{})
+	if err == nil {
+		t.Errorf("calMeanVar of a missing block succeeded")
+	}
+}
+
+func TestEncryptedBlks(t *testing.T) {
+	d := newTestDataNode(t)
+	defer func(on bool, file string) {
+		config.EncryptBlks, config.BlkKeyFile = on, file
+	}(config.EncryptBlks, config.BlkKeyFile)
+	config.EncryptBlks = true
+	config.BlkKeyFile = "blkkey"
+	key := strings.Repeat("0123456789abcdef", 4)
+	if err := ioutil.WriteFile("blkkey", []byte(key+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("attack at dawn, attack at dawn, attack at dawn")
+	sent, streamed := testBlkID("secret.txt", 0), testBlkID("secret.txt", 1)
+	putBlk(t, d, sent, data)
+	for i, off := 0, 0; off < len(data); i, off = i+1, off+10 {
+		end := off + 10
+		if end > len(data) {
+			end = len(data)
+		}
+		args := utils.BlkChunk{BlkID: streamed, Offset: int64(off), Data: data[off:end],
+			Last: end == len(data), Checksum: utils.ChecksumOf(utils.CRC32, data)}
+		if err := d.SendBlkChunk(&args, &SendBlkReply{}); err != nil {
+			t.Fatalf("streaming chunk %v: %v", i, err)
+		}
+	}
+	for _, blk := range []string{sent, streamed} {
+		onDisk, err := ioutil.ReadFile(filepath.Join(disk(d, 0).ActPath, blk))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(onDisk, []byte("attack")) || len(onDisk) <= len(data) {
+			t.Errorf("block file of %v is not encrypted: %q", blk, onDisk)
+		}
+		if got := readTestBlk(t, d, blk); !bytes.Equal(got, data) {
+			t.Errorf("reading %v = %q, want %q", blk, got, data)
+		}
+		reply := utils.BlkData{}
+		args := RequestBlkArgs{BlkID: blk, Offset: 7, Length: 2}
+		if err := d.RequestBlk(&args, &reply); err != nil || string(reply.Data) != "at" {
+			t.Errorf("reading [7, +2) of %v = %q, %v", blk, reply.Data, err)
+		}
+	}
+	if corrupt := d.scanOnce(); len(corrupt) != 0 {
+		t.Errorf("scanner found intact encrypted blocks %v corrupt", corrupt)
+	}
+	// a block is truncated by sealing its remainder again
+	args := utils.TruncateBlkArgs{BlkID: streamed, Length: 6}
+	if err := d.TruncateBlk(&args, &utils.MetaData{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestBlk(t, d, streamed); string(got) != "attack" {
+		t.Errorf("truncated encrypted block reads %q", got)
+	}
+	if corrupt := d.scanOnce(); len(corrupt) != 0 {
+		t.Errorf("scanner found the truncated encrypted block corrupt")
+	}
+	// the environment variable takes precedence over the key file, a
+	// datanode with another key can't read the blocks
+	defer os.Unsetenv(config.BlkKeyEnv)
+	os.Setenv(config.BlkKeyEnv, strings.Repeat("f", 64))
+	other := NewDataNode()
+	if _, err := other.loadData(sent); err == nil {
+		t.Errorf("decrypting with another key succeeded")
+	}
+	os.Setenv(config.BlkKeyEnv, "not hex")
+	if _, err := NewDataNode().sealBlk(sent, data); err == nil {
+		t.Errorf("encrypting with a malformed key succeeded")
+	}
+
+	// a block file copied over another one doesn't decrypt
+	onDisk, err := ioutil.ReadFile(filepath.Join(disk(d, 0).ActPath, sent))
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(disk(d, 0).ActPath, streamed), onDisk, 0600)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corrupt := d.scanOnce(); len(corrupt) != 1 || corrupt[0] != streamed {
+		t.Errorf("scanner found %v corrupt, want [%v]", corrupt, streamed)
+	}
+}
+
+func TestCompressedBlks(t *testing.T) {
+	d := newTestDataNode(t)
+	defer func(on bool) { config.CompressAtRest = on }(config.CompressAtRest)
+	config.CompressAtRest = true
+	data := bytes.Repeat([]byte("3.14159\n2.71828\n"), config.ChunkSize/8)
+	zipped, err := utils.Gzip(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// checksums are of the uncompressed data
+	sum := utils.ChecksumOf(utils.CRC32, data)
+	sent, streamed := testBlkID("nums.txt", 0), testBlkID("nums.txt", 1)
+	args := utils.BlkData{BlkID: sent, Data: zipped, Compressed: true,
+		Checksum: sum, Length: len(data)}
+	if err := d.SendBlk(&args, &SendBlkReply{}); err != nil {
+		t.Fatal(err)
+	}
+	for off := 0; off < len(data); off += config.ChunkSize {
+		end := off + config.ChunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk, err := utils.Gzip(data[off:end])
+		if err != nil {
+			t.Fatal(err)
+		}
+		args := utils.BlkChunk{BlkID: streamed, Offset: int64(off), Data: chunk,
+			Compressed: true, Last: end == len(data), Checksum: sum}
+		if err := d.SendBlkChunk(&args, &SendBlkReply{}); err != nil {
+			t.Fatalf("streaming chunk at %v: %v", off, err)
+		}
+	}
+	for _, blk := range []string{sent, streamed} {
+		onDisk, err := ioutil.ReadFile(filepath.Join(disk(d, 0).ActPath, blk))
+		if err != nil || len(onDisk) >= len(data)/10 {
+			t.Errorf("block file of %v holds %v bytes for %v, %v", blk, len(onDisk),
+				len(data), err)
+		}
+		if meta := d.readMeta(blk); !meta.Compressed || meta.Length != int64(len(data)) {
+			t.Errorf("%v recorded as %+v", blk, meta)
+		}
+		d.cache.clear()
+		if got := readTestBlk(t, d, blk); !bytes.Equal(got, data) {
+			t.Errorf("%v reads %v bytes differing from the %v sent", blk, len(got),
+				len(data))
+		}
+		reply := utils.BlkData{}
+		args := RequestBlkArgs{BlkID: blk, Offset: 8, Length: 7, Compress: true}
+		if err := d.RequestBlk(&args, &reply); err != nil || !reply.Compressed {
+			t.Fatalf("reading [8, +7) of %v compressed: %v", blk, err)
+		}
+		got, err := utils.Gunzip(reply.Data)
+		if err != nil || string(got) != "2.71828" ||
+			!reply.Checksum.Equal(utils.ChecksumOf(reply.ChecksumType, got)) {
+			t.Errorf("reading [8, +7) of %v = %q, %v", blk, got, err)
+		}
+	}
+	if corrupt := d.scanOnce(); len(corrupt) != 0 {
+		t.Errorf("scanner found intact compressed blocks %v corrupt", corrupt)
+	}
+	args.BlkID, args.Data = testBlkID("nums.txt", 2), []byte("not gzip")
+	if err := d.SendBlk(&args, &SendBlkReply{}); err == nil {
+		t.Errorf("a block failing to decompress is stored")
+	}
+}
+
+func TestBlkCache(t *testing.T) {
+	d := newTestDataNode(t)
+	reads := func() int64 { return atomic.LoadInt64(&d.diskReads) }
+	blk := testBlkID("hot.txt", 0)
+	putBlk(t, d, blk, []byte("hot block"))
+	readTestBlk(t, d, blk)
+	before := reads()
+	got := readTestBlk(t, d, blk)
+	if reads() != before || string(got) != "hot block" {
+		t.Errorf("second read = %q with %v disk reads, want it cached", got, reads()-before)
+	}
+	// callers may modify what they are served
+	got[0] = 'n'
+	reply := utils.BlkData{}
+	if err := d.RequestBlk(&RequestBlkArgs{BlkID: blk, Offset: 4, Length: 5}, &reply); err != nil ||
+		string(reply.Data) != "block" || reads() != before {
+		t.Errorf("range read of a cached block = %q, %v", reply.Data, err)
+	}
+	if got := readTestBlk(t, d, blk); string(got) != "hot block" {
+		t.Errorf("cached block changed to %q", got)
+	}
+
+	// overwritten and deleted blocks are not served from the cache
+	putBlk(t, d, blk, []byte("new data"))
+	if got := readTestBlk(t, d, blk); string(got) != "new data" || reads() != before+1 {
+		t.Errorf("read after overwrite = %q", got)
+	}
+	d.deleteBlk(blk)
+	putBlk(t, d, blk, []byte("again"))
+	if got := readTestBlk(t, d, blk); string(got) != "again" {
+		t.Errorf("read after delete = %q", got)
+	}
+
+	// the least recently used blocks go first beyond the budget
+	d.cache = newBlkCache(100)
+	var blks []string
+	for i := 0; i < 3; i++ {
+		blks = append(blks, testBlkID("lru.txt", i))
+		putBlk(t, d, blks[i], bytes.Repeat([]byte{byte('a' + i)}, 40))
+		readTestBlk(t, d, blks[i])
+	}
+	before = reads()
+	readTestBlk(t, d, blks[2])
+	readTestBlk(t, d, blks[1])
+	if reads() != before {
+		t.Errorf("recently read blocks were evicted")
+	}
+	readTestBlk(t, d, blks[0])
+	if reads() != before+1 {
+		t.Errorf("block read before two others of a budget of 100 bytes was still cached")
+	}
+}
+
+func TestChooseAddress(t *testing.T) {
+	for _, tc := range []struct {
+		addrs      []string
+		preferIPv6 bool
+		want       string
+	}{
+		{[]string{"127.0.0.1", "192.168.0.101"}, false, "192.168.0.101"},
+		{[]string{"::1", "127.0.1.1", "fe80::1", "10.0.0.2", "2001:db8::2"}, false, "10.0.0.2"},
+		{[]string{"::1", "127.0.1.1", "fe80::1", "10.0.0.2", "2001:db8::2"}, true, "2001:db8::2"},
+		{[]string{"127.0.0.1", "2001:db8::2"}, false, "2001:db8::2"},
+		{[]string{"127.0.0.1", "fe80::1"}, false, "fe80::1"},
+		{[]string{"127.0.0.1", "::1"}, false, "127.0.0.1"},
+		{nil, false, ""},
+	} {
+		if got := chooseAddress(tc.addrs, tc.preferIPv6); got != tc.want {
+			t.Errorf("chooseAddress(%q, %v) = %q, want %q", tc.addrs, tc.preferIPv6,
+				got, tc.want)
+		}
+	}
+}
+
+func TestGetAddress(t *testing.T) {
+	defer func(lookup func(string) ([]string, error)) { lookupHost = lookup }(lookupHost)
+	lookupHost = func(string) ([]string, error) {
+		return []string{"127.0.0.1", "::1", "192.168.0.102", "2001:db8::2"}, nil
+	}
+	d := &DataNode{}
+	d.getAddress()
+	if want := "192.168.0.102:" + config.DataNodePort; d.Addr != want {
+		t.Errorf("datanode advertises %v, want %v", d.Addr, want)
+	}
+	defer func(prefer bool) { config.PreferIPv6 = prefer }(config.PreferIPv6)
+	config.PreferIPv6 = true
+	d.getAddress()
+	if want := "[2001:db8::2]:" + config.DataNodePort; d.Addr != want {
+		t.Errorf("datanode preferring IPv6 advertises %v, want %v", d.Addr, want)
+	}
+	// the configured host wins over the resolver
+	defer func(host string) { config.DataNodeHost = host }(config.DataNodeHost)
+	config.DataNodeHost = "10.1.2.3"
+	d.getAddress()
+	if want := "10.1.2.3:" + config.DataNodePort; d.Addr != want {
+		t.Errorf("datanode with DataNodeHost set advertises %v, want %v", d.Addr, want)
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// partSuffix marks a block being streamed by SendBlkChunk
+const partSuffix = ".part"
+
+// DiskStore keeps the blocks of a data directory in files named by block
+// id: the metadata of a block as json in MetaPath, its actual data in
+// ActPath. Files are written atomically, see utils.WriteFileAtomic.
+type DiskStore struct {
+	MetaPath    string // Dir/id2meta
+	ActPath     string // Dir/actdata
+	CorruptPath string // quarantined blocks, Dir/corrupt
+}
+
+// NewDiskStore returns the store of data directory dir
+func NewDiskStore(dir string) *DiskStore {
+	return &DiskStore{MetaPath: filepath.Join(dir, "id2meta"),
+		ActPath: filepath.Join(dir, "actdata"), CorruptPath: filepath.Join(dir, "corrupt")}
+}
+
+// checkBlkID rejects block ids which don't round trip through
+// utils.BlockID, so that no id names a file outside the store
+func checkBlkID(blkID string) error {
+	var id utils.BlockID
+	if err := id.Parse(blkID); err != nil {
+		return err
+	}
+	if id.String() != blkID || strings.ContainsRune(blkID, filepath.Separator) {
+		return fmt.Errorf("Invalid block id %q", blkID)
+	}
+	return nil
+}
+
+// Put writes the data first, so a block with metadata always has its data
+func (s *DiskStore) Put(blkID string, meta utils.MetaData, data []byte) error {
+	if err := checkBlkID(blkID); err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	err = utils.WriteFileAtomic(filepath.Join(s.ActPath, blkID), data, 0600)
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(filepath.Join(s.MetaPath, blkID), bytes, 0600)
+}
+
+func (s *DiskStore) Get(blkID string) ([]byte, error) {
+	if err := checkBlkID(blkID); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(filepath.Join(s.ActPath, blkID))
+}
+
+// Delete removes the metadata first, a block without metadata is gone
+func (s *DiskStore) Delete(blkID string) error {
+	if err := checkBlkID(blkID); err != nil {
+		return err
+	}
+	var res error
+	for _, dir := range []string{s.MetaPath, s.ActPath} {
+		err := os.Remove(filepath.Join(dir, blkID))
+		if err != nil && !os.IsNotExist(err) && res == nil {
+			res = err
+		}
+	}
+	return res
+}
+
+// List creates the directories of the store if needed and drops what
+// interrupted writes and deletes leave: temp files, partial blocks and
+// data without metadata. Blocks with malformed metadata are quarantined.
+func (s *DiskStore) List() (map[string]utils.MetaData, error) {
+	for _, dir := range []string{s.MetaPath, s.ActPath} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, err
+		}
+	}
+	utils.RemoveTmpFiles(s.MetaPath, utils.TmpSuffix)
+	utils.RemoveTmpFiles(s.ActPath, utils.TmpSuffix, partSuffix)
+	files, err := ioutil.ReadDir(s.MetaPath)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]utils.MetaData)
+	for _, file := range files {
+		meta, err := s.readMeta(file.Name())
+		if err != nil {
+			logger.Errorf("error when reading metadata of %v: %v\n", file.Name(), err)
+			continue
+		}
+		res[file.Name()] = meta
+	}
+	files, err = ioutil.ReadDir(s.ActPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if _, ok := res[file.Name()]; !ok {
+			logger.Warnf("remove data of %v without metadata\n", file.Name())
+			os.Remove(filepath.Join(s.ActPath, file.Name()))
+		}
+	}
+	return res, nil
+}
+
+// readMeta reads the metadata of a block, quarantining the block if the
+// metadata is malformed
+func (s *DiskStore) readMeta(blkID string) (utils.MetaData, error) {
+	if err := checkBlkID(blkID); err != nil {
+		return utils.MetaData{}, err
+	}
+	var meta utils.MetaData
+	bytes, err := ioutil.ReadFile(filepath.Join(s.MetaPath, blkID))
+	if err != nil {
+		return meta, err
+	}
+	err = json.Unmarshal(bytes, &meta)
+	if err == nil && meta.Length < 0 {
+		err = errors.New("negative block length")
+	}
+	if err != nil {
+		// a bogus entry would mis-describe the block, keep it out
+		logger.Warnf("malformed metadata of %v: %v\n", blkID, err)
+		s.quarantine(blkID)
+	}
+	return meta, err
+}
+
+// quarantine moves the metadata and actual data of a block into
+// CorruptPath, so it is neither loaded nor served anymore
+func (s *DiskStore) quarantine(blkID string) {
+	err := os.MkdirAll(s.CorruptPath, 0700)
+	if err != nil {
+		logger.Errorf("error when creating quarantine dir: %v\n", err)
+		return
+	}
+	dst := filepath.Join(s.CorruptPath, blkID)
+	err = os.Rename(filepath.Join(s.MetaPath, blkID), dst+".meta")
+	if err != nil {
+		logger.Errorf("error when quarantining metadata of %v: %v\n", blkID, err)
+	}
+	err = os.Rename(filepath.Join(s.ActPath, blkID), dst)
+	if err != nil && !os.IsNotExist(err) {
+		logger.Errorf("error when quarantining data of %v: %v\n", blkID, err)
+	}
+	logger.Warnf("block %v quarantined in %v\n", blkID, s.CorruptPath)
+}
+
+func (s *DiskStore) Checksum(blkID, typ string) (utils.Sum, int64, error) {
+	if err := checkBlkID(blkID); err != nil {
+		return nil, 0, err
+	}
+	hash := utils.NewHash(typ)
+	if hash == nil {
+		return nil, 0, utils.CheckChecksumType(typ)
+	}
+	file, err := os.Open(filepath.Join(s.ActPath, blkID))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+	length, err := io.Copy(hash, file)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hash.Sum(nil), length, nil
+}
+
+// ReadRange reads n bytes at offset of the actual data of a block
+func (s *DiskStore) ReadRange(blkID string, offset, n int64) ([]byte, error) {
+	if err := checkBlkID(blkID); err != nil {
+		return nil, err
+	}
+	file, err := os.Open(filepath.Join(s.ActPath, blkID))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data := make([]byte, n)
+	_, err = file.Seek(offset, io.SeekStart)
+	if err == nil {
+		_, err = io.ReadFull(file, data)
+	}
+	return data, err
+}
+
+// AppendPart writes to the partial file of a block, BlkID.part
+func (s *DiskStore) AppendPart(blkID string, offset int64, data []byte) error {
+	if err := checkBlkID(blkID); err != nil {
+		return err
+	}
+	flag := os.O_WRONLY | os.O_APPEND
+	if offset == 0 {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	file, err := os.OpenFile(filepath.Join(s.ActPath, blkID+partSuffix), flag, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// CommitPart syncs the partial file and renames it into place, then
+// writes the metadata
+func (s *DiskStore) CommitPart(blkID string, meta utils.MetaData) error {
+	if err := checkBlkID(blkID); err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	part := filepath.Join(s.ActPath, blkID+partSuffix)
+	file, err := os.OpenFile(part, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	err = file.Sync()
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(part, filepath.Join(s.ActPath, blkID))
+	}
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(filepath.Join(s.MetaPath, blkID), bytes, 0600)
+}
+
+func (s *DiskStore) RemovePart(blkID string) error {
+	if err := checkBlkID(blkID); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(s.ActPath, blkID+partSuffix))
+}
+
+// Truncate cuts the data file first, metadata of the old length left by a
+// crash in between fails verification and the replica is replaced
+func (s *DiskStore) Truncate(blkID string, meta utils.MetaData) error {
+	if err := checkBlkID(blkID); err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Join(s.ActPath, blkID), os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	err = file.Truncate(meta.Length)
+	if err == nil {
+		err = file.Sync()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(filepath.Join(s.MetaPath, blkID), bytes, 0600)
+}
+
+// Check tells whether the directories of the store can be read
+func (s *DiskStore) Check() error {
+	for _, dir := range []string{s.MetaPath, s.ActPath} {
+		if _, err := ioutil.ReadDir(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/mapreduce"
+	"github.com/WineChord/gdfs/utils"
+	"google.golang.org/grpc"
+)
+
+// Server holds the methods served as the DataNode service of gdfspb,
+// DataNode implements it. SendBlkChunk is called with each chunk of a
+// stream in order.
+type Server interface {
+	MapBlk(args *mapreduce.MapArgs, reply *mapreduce.MapReply) error
+	CalMeanVarMap(args *utils.CalMVArgs, reply *utils.CalMVReply) error
+	WordCountMap(args *utils.WordCountArgs, reply *utils.WordCountReply) error
+	GrepMap(args *utils.GrepArgs, reply *utils.GrepReply) error
+	TopNMap(args *utils.TopNArgs, reply *utils.TopNReply) error
+	RequestBlk(args *RequestBlkArgs, reply *utils.BlkData) error
+	RequestBlks(args *RequestBlksArgs, reply *RequestBlksReply) error
+	SendBlk(args *utils.BlkData, reply *SendBlkReply) error
+	SendBlkChunk(args *utils.BlkChunk, reply *SendBlkReply) error
+	StatBlk(args *StatBlkArgs, reply *utils.MetaData) error
+	TruncateBlk(args *utils.TruncateBlkArgs, reply *utils.MetaData) error
+	DeleteBlk(args *utils.DeleteBlkArgs, reply *utils.DeleteBlkReply) error
+	FetchPartition(args *mapreduce.FetchArgs, reply *mapreduce.FetchReply) error
+	Reduce(args *mapreduce.ReduceArgs, reply *mapreduce.ReduceReply) error
+	CommitReduce(args *mapreduce.CommitArgs, reply *SendBlkReply) error
+	EndJob(args *mapreduce.JobArgs, reply *SendBlkReply) error
+}
+
+// RegisterServer serves d as the DataNode service of s
+func RegisterServer(s *grpc.Server, d Server) {
+	gdfspb.RegisterDataNodeServer(s, grpcServer{d: d})
+}
+
+// grpcServer serves the methods of a Server, converting their args and
+// replies
+type grpcServer struct {
+	gdfspb.UnimplementedDataNodeServer
+	d Server
+}
+
+func (s grpcServer) MapBlk(ctx context.Context, pb *gdfspb.MapArgs) (*gdfspb.MapReply, error) {
+	var args mapreduce.MapArgs
+	var reply mapreduce.MapReply
+	args.FromPB(pb)
+	if err := s.d.MapBlk(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) CalMeanVarMap(ctx context.Context, pb *gdfspb.CalMVArgs) (*gdfspb.CalMVReply, error) {
+	var args utils.CalMVArgs
+	var reply utils.CalMVReply
+	args.FromPB(pb)
+	if err := s.d.CalMeanVarMap(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) WordCountMap(ctx context.Context, pb *gdfspb.WordCountArgs) (*gdfspb.WordCountReply, error) {
+	var args utils.WordCountArgs
+	var reply utils.WordCountReply
+	args.FromPB(pb)
+	if err := s.d.WordCountMap(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) GrepMap(ctx context.Context, pb *gdfspb.GrepArgs) (*gdfspb.GrepReply, error) {
+	var args utils.GrepArgs
+	var reply utils.GrepReply
+	args.FromPB(pb)
+	if err := s.d.GrepMap(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) TopNMap(ctx context.Context, pb *gdfspb.TopNArgs) (*gdfspb.TopNReply, error) {
+	var args utils.TopNArgs
+	var reply utils.TopNReply
+	args.FromPB(pb)
+	if err := s.d.TopNMap(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) RequestBlk(ctx context.Context, pb *gdfspb.RequestBlkArgs) (*gdfspb.BlkData, error) {
+	var args RequestBlkArgs
+	var reply utils.BlkData
+	args.FromPB(pb)
+	if err := s.d.RequestBlk(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) RequestBlks(ctx context.Context, pb *gdfspb.RequestBlksArgs) (*gdfspb.RequestBlksReply, error) {
+	var args RequestBlksArgs
+	var reply RequestBlksReply
+	args.FromPB(pb)
+	if err := s.d.RequestBlks(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) SendBlk(ctx context.Context, pb *gdfspb.BlkData) (*gdfspb.SendBlkReply, error) {
+	var args utils.BlkData
+	var reply SendBlkReply
+	args.FromPB(pb)
+	if err := s.d.SendBlk(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+// SendBlkChunk receives the chunks of a block in order until the last one
+// and replies the reply to it, a chunk failing ends the stream. A stream
+// ending early leaves the part of the block to expireParts.
+func (s grpcServer) SendBlkChunk(stream gdfspb.DataNode_SendBlkChunkServer) error {
+	for {
+		pb, err := stream.Recv()
+		if err == io.EOF {
+			return errors.New("Stream ended before the last chunk")
+		}
+		if err != nil {
+			return err
+		}
+		var args utils.BlkChunk
+		var reply SendBlkReply
+		args.FromPB(pb)
+		if err := s.d.SendBlkChunk(&args, &reply); err != nil {
+			return err
+		}
+		if args.Last {
+			return stream.SendAndClose(reply.ToPB())
+		}
+	}
+}
+
+func (s grpcServer) StatBlk(ctx context.Context, pb *gdfspb.StatBlkArgs) (*gdfspb.MetaData, error) {
+	var args StatBlkArgs
+	var reply utils.MetaData
+	args.FromPB(pb)
+	if err := s.d.StatBlk(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) TruncateBlk(ctx context.Context, pb *gdfspb.TruncateBlkArgs) (*gdfspb.MetaData, error) {
+	var args utils.TruncateBlkArgs
+	var reply utils.MetaData
+	args.FromPB(pb)
+	if err := s.d.TruncateBlk(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) DeleteBlk(ctx context.Context, pb *gdfspb.DeleteBlkArgs) (*gdfspb.DeleteBlkReply, error) {
+	var args utils.DeleteBlkArgs
+	var reply utils.DeleteBlkReply
+	args.FromPB(pb)
+	if err := s.d.DeleteBlk(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) FetchPartition(ctx context.Context, pb *gdfspb.FetchArgs) (*gdfspb.FetchReply, error) {
+	var args mapreduce.FetchArgs
+	var reply mapreduce.FetchReply
+	args.FromPB(pb)
+	if err := s.d.FetchPartition(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) Reduce(ctx context.Context, pb *gdfspb.ReduceArgs) (*gdfspb.ReduceReply, error) {
+	var args mapreduce.ReduceArgs
+	var reply mapreduce.ReduceReply
+	args.FromPB(pb)
+	if err := s.d.Reduce(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) CommitReduce(ctx context.Context, pb *gdfspb.CommitArgs) (*gdfspb.SendBlkReply, error) {
+	var args mapreduce.CommitArgs
+	var reply SendBlkReply
+	args.FromPB(pb)
+	if err := s.d.CommitReduce(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) EndJob(ctx context.Context, pb *gdfspb.EndJobArgs) (*gdfspb.SendBlkReply, error) {
+	var args mapreduce.JobArgs
+	var reply SendBlkReply
+	args.FromPB(pb)
+	if err := s.d.EndJob(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+// callNameNode calls f with the NameNode service over a new connection,
+// giving up after RPCTimeout
+func callNameNode(f func(ctx context.Context, nn gdfspb.NameNodeClient) error) error {
+	cc, err := utils.Dial(config.NameNodeAddress)
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+	ctx, cancel := utils.Context(utils.RPCTimeout(), nil)
+	defer cancel()
+	return f(ctx, gdfspb.NewNameNodeClient(cc))
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"sync/atomic"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/metrics"
+	"github.com/WineChord/gdfs/utils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// registerMetrics creates the registry of the metrics served at
+// config.DataNodeMetricsPort
+func (d *DataNode) registerMetrics() {
+	d.registry = prometheus.NewRegistry()
+	d.rpcLatency = metrics.NewRPCLatency("datanode")
+	opts := func(name, help string) prometheus.Opts {
+		return prometheus.Opts{Namespace: metrics.Namespace, Subsystem: "datanode",
+			Name: name, Help: help}
+	}
+	d.registry.MustRegister(d.rpcLatency,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts(opts("blocks", "Blocks stored.")),
+			func() float64 {
+				d.mu.Lock()
+				defer d.mu.Unlock()
+				return float64(len(d.IDToMetaData))
+			}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts(opts("active_transfers",
+			"Block transfers in progress.")),
+			func() float64 { return float64(d.NumDataTrans()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts(opts("read_bytes_total",
+			"Bytes of block data sent.")),
+			func() float64 { return float64(atomic.LoadInt64(&d.bytesRead)) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts(opts("written_bytes_total",
+			"Bytes of block data received.")),
+			func() float64 { return float64(atomic.LoadInt64(&d.bytesWritten)) }))
+}
+
+// serveMetrics serves the metrics at config.DataNodeMetricsPort on the
+// host of d.Addr
+func (d *DataNode) serveMetrics() {
+	if config.DataNodeMetricsPort == "" {
+		return
+	}
+	host, _, err := utils.SplitHostPort(d.Addr)
+	if err != nil {
+		logger.Errorf("error when serving metrics: %v\n", err)
+		return
+	}
+	addr := utils.JoinHostPort(host, config.DataNodeMetricsPort)
+	l, err := metrics.Serve(addr, d.registry)
+	if err != nil {
+		logger.Errorf("error when serving metrics at %v: %v\n", addr, err)
+		return
+	}
+	logger.Infof("DataNode serving metrics at %v/metrics\n", l.Addr())
+	d.metricsListener = l
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/WineChord/gdfs/metrics"
+	"github.com/WineChord/gdfs/utils"
+)
+
+func TestMetrics(t *testing.T) {
+	d := newTestDataNode(t)
+	blk := testBlkID("a.bin", 0)
+	putBlk(t, d, blk, []byte("0123456789"))
+	streamed := []byte("abc")
+	if err := sendChunks(d, testBlkID("b.bin", 0), streamed,
+		utils.ChecksumOf(utils.CRC32, streamed)); err != nil {
+		t.Fatal(err)
+	}
+	readTestBlk(t, d, blk)
+	args := RequestBlkArgs{BlkID: blk, Offset: 2, Length: 4}
+	if err := d.RequestBlk(&args, &utils.BlkData{}); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := metrics.Serve("127.0.0.1:0", d.registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	resp, err := http.Get("http://" + l.Addr().String() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"gdfs_datanode_blocks 2",
+		"gdfs_datanode_active_transfers 0",
+		"gdfs_datanode_written_bytes_total 13",
+		"gdfs_datanode_read_bytes_total 14",
+	} {
+		if !strings.Contains(string(body), want+"\n") {
+			t.Errorf("metrics miss %q:\n%s", want, body)
+		}
+	}
+}
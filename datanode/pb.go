@@ -0,0 +1,116 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// ToPB converts r to gdfspb.RequestBlkArgs
+func (r *RequestBlkArgs) ToPB() *gdfspb.RequestBlkArgs {
+	return &gdfspb.RequestBlkArgs{
+		BlkId:    r.BlkID,
+		Offset:   r.Offset,
+		Length:   r.Length,
+		Compress: r.Compress,
+		Token:    r.Token,
+	}
+}
+
+// FromPB sets r to pb
+func (r *RequestBlkArgs) FromPB(pb *gdfspb.RequestBlkArgs) {
+	*r = RequestBlkArgs{
+		BlkID:    pb.GetBlkId(),
+		Offset:   pb.GetOffset(),
+		Length:   pb.GetLength(),
+		Compress: pb.GetCompress(),
+		Token:    pb.GetToken(),
+	}
+}
+
+// ToPB converts r to gdfspb.RequestBlksArgs
+func (r *RequestBlksArgs) ToPB() *gdfspb.RequestBlksArgs {
+	return &gdfspb.RequestBlksArgs{
+		BlkIds:   r.BlkIDs,
+		Compress: r.Compress,
+		Token:    r.Token,
+	}
+}
+
+// FromPB sets r to pb
+func (r *RequestBlksArgs) FromPB(pb *gdfspb.RequestBlksArgs) {
+	*r = RequestBlksArgs{
+		BlkIDs:   pb.GetBlkIds(),
+		Compress: pb.GetCompress(),
+		Token:    pb.GetToken(),
+	}
+}
+
+// ToPB converts r to gdfspb.RequestBlksReply
+func (r *RequestBlksReply) ToPB() *gdfspb.RequestBlksReply {
+	pb := &gdfspb.RequestBlksReply{
+		Errors: r.Errors,
+	}
+	for i := range r.Blks {
+		pb.Blks = append(pb.Blks, r.Blks[i].ToPB())
+	}
+	return pb
+}
+
+// FromPB sets r to pb
+func (r *RequestBlksReply) FromPB(pb *gdfspb.RequestBlksReply) {
+	*r = RequestBlksReply{
+		Errors: pb.GetErrors(),
+	}
+	if vs := pb.GetBlks(); len(vs) > 0 {
+		r.Blks = make([]utils.BlkData, len(vs))
+		for i, v := range vs {
+			r.Blks[i].FromPB(v)
+		}
+	}
+}
+
+// ToPB converts s to gdfspb.SendBlkReply
+func (s *SendBlkReply) ToPB() *gdfspb.SendBlkReply {
+	return &gdfspb.SendBlkReply{
+		Status: s.Status,
+		Acked:  s.Acked,
+	}
+}
+
+// FromPB sets s to pb
+func (s *SendBlkReply) FromPB(pb *gdfspb.SendBlkReply) {
+	*s = SendBlkReply{
+		Status: pb.GetStatus(),
+		Acked:  pb.GetAcked(),
+	}
+}
+
+// ToPB converts s to gdfspb.StatBlkArgs
+func (s *StatBlkArgs) ToPB() *gdfspb.StatBlkArgs {
+	return &gdfspb.StatBlkArgs{
+		BlkId: s.BlkID,
+		Token: s.Token,
+	}
+}
+
+// FromPB sets s to pb
+func (s *StatBlkArgs) FromPB(pb *gdfspb.StatBlkArgs) {
+	*s = StatBlkArgs{
+		BlkID: pb.GetBlkId(),
+		Token: pb.GetToken(),
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"context"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/namenode"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// scanBlocks periodically verifies every block on disk, see scanOnce
+func (d *DataNode) scanBlocks() {
+	for d.sleep(config.BlkScanInSec) {
+		d.scanOnce()
+	}
+}
+
+/** scanOnce reads the actual data of every block, recomputes its checksum
+ * with the algorithm the block was written with and compares it with the
+ * checksum and length in the block's metadata.
+ * Corrupt blocks are dropped from IDToMetaData, so they are neither served
+ * nor reported anymore, and reported to namenode which re-replicates them
+ * from healthy replicas. With config.DeleteCorruptBlk the local copy is
+ * removed as well, otherwise it is kept on disk for inspection.
+ * */
+func (d *DataNode) scanOnce() []string {
+	d.mu.Lock()
+	metas := make(map[string]utils.MetaData)
+	for id, meta := range d.IDToMetaData {
+		metas[id] = meta
+	}
+	d.mu.Unlock()
+	corrupt := make([]string, 0)
+	for id, meta := range metas {
+		checksum, n, err := d.blkChecksum(id, meta.ChecksumType)
+		if err == nil && checksum.Equal(meta.Checksum) && n == meta.Length {
+			continue
+		}
+		logger.Warnf("block scanner: block %v is corrupt (err: %v)\n", id, err)
+		corrupt = append(corrupt, id)
+	}
+	logger.Infof("block scanner: %v blocks scanned, %v corrupt\n", len(metas),
+		len(corrupt))
+	if len(corrupt) == 0 {
+		return corrupt
+	}
+	for _, id := range corrupt {
+		if config.DeleteCorruptBlk {
+			d.deleteBlk(id)
+		} else {
+			d.mu.Lock()
+			delete(d.IDToMetaData, id)
+			d.mu.Unlock()
+		}
+	}
+	d.reportCorruptBlocks(corrupt)
+	return corrupt
+}
+
+func (d *DataNode) reportCorruptBlocks(blkIDs []string) {
+	args := namenode.ReportCorruptBlockArgs{}
+	args.Addr = d.Addr
+	args.BlkIDs = blkIDs
+	args.Token = config.AuthToken
+	err := callNameNode(func(ctx context.Context, nn gdfspb.NameNodeClient) error {
+		_, err := nn.ReportCorruptBlock(ctx, args.ToPB())
+		return err
+	})
+	if err != nil {
+		logger.Errorf("error when reporting corrupt blocks: %v\n", err)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/WineChord/gdfs/utils"
+)
+
+func TestScannerReportsCorruptBlock(t *testing.T) {
+	d := newTestDataNode(t)
+	n := startNameNode(t)
+	good, bad := testBlkID("scan.txt", 0), testBlkID("scan.txt", 1)
+	putBlk(t, d, good, []byte("intact block"))
+	putBlk(t, d, bad, []byte("block to corrupt"))
+	join(d)
+	file := filepath.Join(disk(d, 0).ActPath, bad)
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := ioutil.WriteFile(file, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	corrupt := d.scanOnce()
+	if len(corrupt) != 1 || corrupt[0] != bad {
+		t.Fatalf("scanner found %v corrupt, want [%v]", corrupt, bad)
+	}
+	d.mu.Lock()
+	_, kept := d.IDToMetaData[bad]
+	_, goodKept := d.IDToMetaData[good]
+	d.mu.Unlock()
+	if kept || !goodKept {
+		t.Errorf("after the scan: corrupt block kept %v, intact block kept %v",
+			kept, goodKept)
+	}
+	if sids := n.BlkToDatanodes[bad]; len(sids) != 0 {
+		t.Errorf("namenode keeps replicas %v of corrupt block %v", sids, bad)
+	}
+	if sids := n.BlkToDatanodes[good]; len(sids) != 1 || sids[0] != d.StorageID {
+		t.Errorf("replicas of intact block %v are %v, want [%v]", good, sids,
+			d.StorageID)
+	}
+}
+
+func TestChecksumTypes(t *testing.T) {
+	data := []byte("the quick brown fox jumps over the lazy dog")
+	for typ, size := range map[string]int{utils.CRC32: 4, utils.CRC32C: 4, utils.SHA256: 32} {
+		d := newTestDataNode(t)
+		sent, streamed := testBlkID(typ, 0), testBlkID(typ, 1)
+		sum := utils.ChecksumOf(typ, data)
+		args := utils.BlkData{BlkID: sent, Data: data, Checksum: sum,
+			ChecksumType: typ, Length: len(data)}
+		if err := d.SendBlk(&args, &SendBlkReply{}); err != nil {
+			t.Fatalf("%v: sending %v: %v", typ, sent, err)
+		}
+		if err := sendChunksOf(d, streamed, data, typ, sum); err != nil {
+			t.Fatalf("%v: streaming %v: %v", typ, streamed, err)
+		}
+		if err := sendChunksOf(d, testBlkID(typ, 2), data, typ, flipped(sum)); err == nil {
+			t.Errorf("%v: streaming with a wrong checksum succeeded", typ)
+		}
+		for _, blk := range []string{sent, streamed} {
+			reply := utils.BlkData{}
+			if err := d.RequestBlk(&RequestBlkArgs{BlkID: blk}, &reply); err != nil {
+				t.Fatalf("%v: reading %v: %v", typ, blk, err)
+			}
+			if !bytes.Equal(reply.Data, data) || reply.ChecksumType != typ ||
+				len(reply.Checksum) != size || !reply.Checksum.Equal(sum) {
+				t.Errorf("%v: reading %v = %q with %v checksum %v, want %v", typ, blk,
+					reply.Data, reply.ChecksumType, reply.Checksum, sum)
+			}
+		}
+		// the type of each block survives a restart
+		d = NewDataNode()
+		if corrupt := d.scanOnce(); len(corrupt) != 0 {
+			t.Errorf("%v: scanner found intact blocks %v corrupt", typ, corrupt)
+		}
+		file := filepath.Join(disk(d, 0).ActPath, streamed)
+		if err := ioutil.WriteFile(file, bytes.ToUpper(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if corrupt := d.scanOnce(); len(corrupt) != 1 || corrupt[0] != streamed {
+			t.Errorf("%v: scanner found %v corrupt, want [%v]", typ, corrupt, streamed)
+		}
+	}
+
+	d := newTestDataNode(t)
+	args := utils.BlkData{BlkID: testBlkID("md5", 0), Data: data,
+		Checksum: utils.ChecksumOf(utils.CRC32, data), ChecksumType: "md5", Length: len(data)}
+	if err := d.SendBlk(&args, &SendBlkReply{}); err == nil {
+		t.Errorf("sending a block with an unknown checksum type succeeded")
+	}
+}
+
+func TestLegacyChecksum(t *testing.T) {
+	d := newTestDataNode(t)
+	blk := testBlkID("legacy.txt", 0)
+	data := []byte("written before checksum types")
+	putBlk(t, d, blk, data)
+	// metadata of blocks written when checksums were crc32 numbers
+	meta := fmt.Sprintf(`{"Checksum":%v,"Timestamp":1,"Length":%v,"GenStamp":0}`,
+		crc32.ChecksumIEEE(data), len(data))
+	path := filepath.Join(disk(d, 0).MetaPath, blk)
+	if err := ioutil.WriteFile(path, []byte(meta), 0600); err != nil {
+		t.Fatal(err)
+	}
+	d = NewDataNode()
+	if corrupt := d.scanOnce(); len(corrupt) != 0 {
+		t.Errorf("scanner found legacy blocks %v corrupt", corrupt)
+	}
+	if got := readTestBlk(t, d, blk); !bytes.Equal(got, data) {
+		t.Errorf("reading %v = %q, want %q", blk, got, data)
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/mapreduce"
+	"github.com/WineChord/gdfs/namenode"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// jobData holds the map and reduce outputs of the distributed mapreduce
+// jobs in progress, until namenode ends them, see mapreduce.Split
+type jobData struct {
+	maps    map[string][]mapreduce.Result // partitions by job and block
+	reduces map[string][]byte             // output by job and partition
+}
+
+func mapKey(jobID, blkID string) string {
+	return jobID + "/" + blkID
+}
+
+func reduceKey(jobID string, partition int) string {
+	return fmt.Sprintf("%v/%v", jobID, partition)
+}
+
+// keepMap stores the partitions of the map output of a block
+func (d *DataNode) keepMap(jobID, blkID string, parts []mapreduce.Result) {
+	d.jobMu.Lock()
+	defer d.jobMu.Unlock()
+	if d.jobs.maps == nil {
+		d.jobs.maps = make(map[string][]mapreduce.Result)
+	}
+	d.jobs.maps[mapKey(jobID, blkID)] = parts
+}
+
+// FetchPartition sends a partition of the map output of a block to a
+// reducer
+func (d *DataNode) FetchPartition(args *mapreduce.FetchArgs, reply *mapreduce.FetchReply) error {
+	if err := namenode.CheckToken("FetchPartition", args.Token); err != nil {
+		return err
+	}
+	d.jobMu.Lock()
+	defer d.jobMu.Unlock()
+	parts, ok := d.jobs.maps[mapKey(args.JobID, args.BlkID)]
+	if !ok || args.Partition < 0 || args.Partition >= len(parts) {
+		return errors.New("No such map output")
+	}
+	reply.Result = parts[args.Partition]
+	return nil
+}
+
+// Reduce runs a reduce task, its output is kept until namenode commits it
+// to dfs with CommitReduce
+func (d *DataNode) Reduce(args *mapreduce.ReduceArgs, reply *mapreduce.ReduceReply) error {
+	if err := namenode.CheckToken("Reduce", args.Token); err != nil {
+		return err
+	}
+	logger.Debugf("enter Reduce %v partition %v of job %v\n", args.Job, args.Partition, args.JobID)
+	job, err := mapreduce.Lookup(args.Job)
+	if err != nil {
+		return err
+	}
+	partials := append([]mapreduce.Result{}, args.Extra...)
+	for _, src := range args.Sources {
+		res, err := d.fetchPartition(src, args.JobID, args.Partition)
+		if err != nil {
+			return fmt.Errorf("fetch map output of %v from %v: %v", src.BlkID, src.Addr, err)
+		}
+		partials = append(partials, res)
+	}
+	res, err := job.Reduce(partials)
+	if err != nil {
+		return err
+	}
+	out := mapreduce.Lines(res)
+	d.jobMu.Lock()
+	if d.jobs.reduces == nil {
+		d.jobs.reduces = make(map[string][]byte)
+	}
+	d.jobs.reduces[reduceKey(args.JobID, args.Partition)] = out
+	d.jobMu.Unlock()
+	reply.Size = int64(len(out))
+	return nil
+}
+
+// fetchPartition gets a partition of the map output of a block, from d
+// itself if it mapped the block
+func (d *DataNode) fetchPartition(src mapreduce.MapSource, jobID string,
+	partition int) (mapreduce.Result, error) {
+	args := mapreduce.FetchArgs{JobID: jobID, BlkID: src.BlkID, Partition: partition,
+		Token: config.AuthToken}
+	reply := mapreduce.FetchReply{}
+	if src.Addr == d.Addr {
+		err := d.FetchPartition(&args, &reply)
+		return reply.Result, err
+	}
+	defer d.beginTransfer()()
+	timeout := time.Duration(config.BlkReadTimeoutInSec) * time.Second
+	err := d.dataNodes.CallDataNode(src.Addr, timeout, nil,
+		func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+			m, err := dn.FetchPartition(ctx, args.ToPB())
+			reply.FromPB(m)
+			return err
+		})
+	return reply.Result, err
+}
+
+// CommitReduce sends the output of a reduce task as the blocks allocated
+// by namenode to their datanodes, which are then asked to report them
+func (d *DataNode) CommitReduce(args *mapreduce.CommitArgs, reply *SendBlkReply) error {
+	if err := namenode.CheckToken("CommitReduce", args.Token); err != nil {
+		return err
+	}
+	d.jobMu.Lock()
+	out, ok := d.jobs.reduces[reduceKey(args.JobID, args.Partition)]
+	d.jobMu.Unlock()
+	if !ok {
+		return errors.New("No such reduce output")
+	}
+	targets := make(map[string]bool)
+	for i, blkID := range args.BlkList {
+		start := int64(i) * args.BlkSize
+		end := start + args.BlkSize
+		if end > int64(len(out)) {
+			end = int64(len(out))
+		}
+		blk := utils.BlkData{BlkID: blkID, Data: out[start:end], GenStamp: args.GenStamp,
+			Token: config.AuthToken}
+		blk.ChecksumType = config.ChecksumType
+		blk.Checksum = utils.ChecksumOf(blk.ChecksumType, blk.Data)
+		blk.Length = len(blk.Data)
+		for _, addr := range args.BlkToDataNodes[blkID] {
+			if err := d.pushBlk(&blk, addr); err != nil {
+				return fmt.Errorf("send %v to %v: %v", blkID, addr, err)
+			}
+			targets[addr] = true
+		}
+	}
+	for addr := range targets {
+		d.notifyNameNode(addr)
+	}
+	reply.Status = true
+	return nil
+}
+
+// pushBlk stores blk on the datanode at addr, d included
+func (d *DataNode) pushBlk(blk *utils.BlkData, addr string) error {
+	reply := SendBlkReply{}
+	if addr == d.Addr {
+		return d.SendBlk(blk, &reply)
+	}
+	return d.dataNodes.CallDataNode(addr, utils.RPCTimeout(), nil,
+		func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+			_, err := dn.SendBlk(ctx, blk.ToPB())
+			return err
+		})
+}
+
+// EndJob drops the map and reduce outputs of a job
+func (d *DataNode) EndJob(args *mapreduce.JobArgs, reply *SendBlkReply) error {
+	if err := namenode.CheckToken("EndJob", args.Token); err != nil {
+		return err
+	}
+	d.jobMu.Lock()
+	defer d.jobMu.Unlock()
+	prefix := args.JobID + "/"
+	for key := range d.jobs.maps {
+		if strings.HasPrefix(key, prefix) {
+			delete(d.jobs.maps, key)
+		}
+	}
+	for key := range d.jobs.reduces {
+		if strings.HasPrefix(key, prefix) {
+			delete(d.jobs.reduces, key)
+		}
+	}
+	reply.Status = true
+	return nil
+}
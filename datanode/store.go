@@ -0,0 +1,144 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"os"
+	"sync"
+
+	"github.com/WineChord/gdfs/utils"
+)
+
+// BlockStore keeps the blocks of a volume, the actual data of each block
+// along with its metadata. Data is stored as given, encrypted or not. A
+// missing block is reported by an error satisfying os.IsNotExist.
+type BlockStore interface {
+	// Put stores a block, replacing the previous version if any
+	Put(blkID string, meta utils.MetaData, data []byte) error
+	// Get returns the actual data of a block
+	Get(blkID string) ([]byte, error)
+	// Delete removes a block, deleting a missing block is no error
+	Delete(blkID string) error
+	// List returns the metadata of every block
+	List() (map[string]utils.MetaData, error)
+	// Checksum computes the checksum of type typ and the length of the
+	// actual data of a block, see utils.NewHash
+	Checksum(blkID, typ string) (utils.Sum, int64, error)
+}
+
+/** Stores may implement the following interfaces as well, the datanode
+ * falls back to the methods of BlockStore otherwise.
+ * */
+
+// rangeReader reads a byte range of a block without reading it whole
+type rangeReader interface {
+	ReadRange(blkID string, offset, n int64) ([]byte, error)
+}
+
+// partStore receives a streamed block in place, see SendBlkChunk. A
+// partial block is not listed, and not kept across restarts.
+type partStore interface {
+	// AppendPart appends data at offset of the partial block, offset 0
+	// starts it over
+	AppendPart(blkID string, offset int64, data []byte) error
+	// CommitPart turns a complete partial block into the block
+	CommitPart(blkID string, meta utils.MetaData) error
+	// RemovePart drops a partial block
+	RemovePart(blkID string) error
+}
+
+// truncater cuts a block in place, see TruncateBlk
+type truncater interface {
+	// Truncate cuts the actual data of a block to meta.Length bytes, then
+	// replaces its metadata with meta
+	Truncate(blkID string, meta utils.MetaData) error
+}
+
+// storeChecker is implemented by stores which may become unreachable,
+// e.g. when their disk goes away, see checkVolumes
+type storeChecker interface {
+	Check() error
+}
+
+// MemStore keeps blocks in memory, it serves tests of datanodes not
+// touching the disk
+type MemStore struct {
+	mu   sync.Mutex
+	blks map[string]memBlk
+}
+
+type memBlk struct {
+	meta utils.MetaData
+	data []byte
+}
+
+// NewMemStore returns an empty in-memory store
+func NewMemStore() *MemStore {
+	return &MemStore{blks: make(map[string]memBlk)}
+}
+
+// notExist is the error of a block missing from a MemStore
+func notExist(op, blkID string) error {
+	return &os.PathError{Op: op, Path: blkID, Err: os.ErrNotExist}
+}
+
+// Put keeps a copy of data, callers may modify it afterwards
+func (s *MemStore) Put(blkID string, meta utils.MetaData, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blks[blkID] = memBlk{meta: meta, data: append([]byte(nil), data...)}
+	return nil
+}
+
+// Get returns a copy of the data of a block
+func (s *MemStore) Get(blkID string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	blk, ok := s.blks[blkID]
+	if !ok {
+		return nil, notExist("get", blkID)
+	}
+	return append([]byte(nil), blk.data...), nil
+}
+
+func (s *MemStore) Delete(blkID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.blks, blkID)
+	return nil
+}
+
+func (s *MemStore) List() (map[string]utils.MetaData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make(map[string]utils.MetaData, len(s.blks))
+	for blkID, blk := range s.blks {
+		res[blkID] = blk.meta
+	}
+	return res, nil
+}
+
+func (s *MemStore) Checksum(blkID, typ string) (utils.Sum, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	blk, ok := s.blks[blkID]
+	if !ok {
+		return nil, 0, notExist("checksum", blkID)
+	}
+	if err := utils.CheckChecksumType(typ); err != nil {
+		return nil, 0, err
+	}
+	return utils.ChecksumOf(typ, blk.data), int64(len(blk.data)), nil
+}
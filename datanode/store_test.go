@@ -0,0 +1,233 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// testStore checks s keeps to the contract of BlockStore, and of the
+// optional interfaces it implements
+func testStore(t *testing.T, s BlockStore) {
+	// datanode lists the blocks of a store before anything else
+	if blks, err := s.List(); err != nil || len(blks) != 0 {
+		t.Fatalf("new store lists %v, %v", blks, err)
+	}
+	blk, missing := testBlkID("store.txt", 0), testBlkID("store.txt", 1)
+	data := []byte("the quick brown fox")
+	meta := utils.MetaData{Checksum: utils.ChecksumOf(utils.CRC32, data), Timestamp: getTimestamp(blk),
+		Length: int64(len(data)), GenStamp: 1}
+	if err := s.Put(blk, meta, data); err != nil {
+		t.Fatal(err)
+	}
+	data[0] = 'T' // stores keep what they were given
+	if got, err := s.Get(blk); err != nil || string(got) != "the quick brown fox" {
+		t.Errorf("Get = %q, %v", got, err)
+	}
+	if blks, err := s.List(); err != nil || !reflect.DeepEqual(blks, map[string]utils.MetaData{blk: meta}) {
+		t.Errorf("List = %v, %v, want %v only", blks, err, meta)
+	}
+	if sum, n, err := s.Checksum(blk, utils.CRC32); err != nil || !sum.Equal(meta.Checksum) || n != meta.Length {
+		t.Errorf("Checksum = %x, %v, %v, want %x, %v", sum, n, err, meta.Checksum, meta.Length)
+	}
+	if r, ok := s.(rangeReader); ok {
+		if got, err := r.ReadRange(blk, 4, 5); err != nil || string(got) != "quick" {
+			t.Errorf("ReadRange(4, 5) = %q, %v", got, err)
+		}
+	}
+
+	// a new version replaces the block
+	data, meta.GenStamp = []byte("jumps over"), 2
+	meta.Checksum, meta.Length = utils.ChecksumOf(utils.CRC32, data), int64(len(data))
+	if err := s.Put(blk, meta, data); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Get(blk); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("Get after Put of a new version = %q, %v", got, err)
+	}
+	if blks, err := s.List(); err != nil || !reflect.DeepEqual(blks[blk], meta) {
+		t.Errorf("List after Put of a new version = %v, %v", blks, err)
+	}
+
+	if _, err := s.Get(missing); !os.IsNotExist(err) {
+		t.Errorf("Get of a missing block: %v", err)
+	}
+	if _, _, err := s.Checksum(missing, utils.CRC32); !os.IsNotExist(err) {
+		t.Errorf("Checksum of a missing block: %v", err)
+	}
+	if err := s.Delete(missing); err != nil {
+		t.Errorf("Delete of a missing block: %v", err)
+	}
+	if err := s.Delete(blk); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(blk); !os.IsNotExist(err) {
+		t.Errorf("Get after Delete: %v", err)
+	}
+	if blks, err := s.List(); err != nil || len(blks) != 0 {
+		t.Errorf("List after Delete = %v, %v", blks, err)
+	}
+
+	ps, ok := s.(partStore)
+	if !ok {
+		return
+	}
+	for off, chunk := range []string{"lazy ", "dog"} {
+		if err := ps.AppendPart(blk, int64(off*5), []byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if blks, err := s.List(); err != nil || len(blks) != 0 {
+		t.Errorf("List with a partial block = %v, %v", blks, err)
+	}
+	if err := ps.AppendPart(blk, 0, []byte("lazy dog")); err != nil {
+		t.Fatal(err)
+	}
+	meta.Checksum, meta.Length = utils.ChecksumOf(utils.CRC32, []byte("lazy dog")), 8
+	if err := ps.CommitPart(blk, meta); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Get(blk); err != nil || string(got) != "lazy dog" {
+		t.Errorf("Get of a committed part = %q, %v", got, err)
+	}
+	if blks, err := s.List(); err != nil || !reflect.DeepEqual(blks[blk], meta) {
+		t.Errorf("List after CommitPart = %v, %v", blks, err)
+	}
+	if err := ps.AppendPart(missing, 0, []byte("dropped")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.RemovePart(missing); err != nil {
+		t.Errorf("RemovePart: %v", err)
+	}
+	if err := ps.CommitPart(missing, meta); err == nil {
+		t.Errorf("committing a removed part succeeded")
+	}
+}
+
+func TestDiskStore(t *testing.T) {
+	testStore(t, NewDiskStore(t.TempDir()))
+}
+
+func TestDiskStoreRejectsBadBlkIDs(t *testing.T) {
+	dir := t.TempDir()
+	s := NewDiskStore(filepath.Join(dir, "vol"))
+	if _, err := s.List(); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("escaped")
+	meta := utils.MetaData{Checksum: utils.ChecksumOf(utils.CRC32, data), Length: int64(len(data))}
+	for _, blk := range []string{
+		"../../../x-00000000-1-abc", // climbs out of actdata
+		"a/b-00000000-1-abc",
+		"..-00000000-1-abc",
+		"x-00000000-1-abc/../../../y",
+		"..%2f..%2fx-1-1-abc", // index not formatted as String does
+		"x-1-1-a",
+		"x-00000000-1",
+		"x-00000000-1-abc\x00",
+	} {
+		if err := s.Put(blk, meta, data); err == nil {
+			t.Errorf("Put(%q) succeeded", blk)
+		}
+		if err := s.AppendPart(blk, 0, data); err == nil {
+			t.Errorf("AppendPart(%q) succeeded", blk)
+		}
+		if _, err := s.Get(blk); err == nil {
+			t.Errorf("Get(%q) succeeded", blk)
+		}
+		if err := s.Delete(blk); err == nil {
+			t.Errorf("Delete(%q) succeeded", blk)
+		}
+	}
+	var files []string
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if len(files) != 0 {
+		t.Errorf("rejected block ids wrote %q", files)
+	}
+	// an encoded name is a literal name and stays in the store
+	blk := testBlkID("..%2f..%2fx", 0)
+	if err := s.Put(blk, meta, data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(s.ActPath, blk)); err != nil {
+		t.Errorf("%v is not in the store: %v", blk, err)
+	}
+}
+
+func TestMemStore(t *testing.T) {
+	testStore(t, NewMemStore())
+}
+
+// TestMemDataNode runs a datanode over a store in memory
+func TestMemDataNode(t *testing.T) {
+	d := newTestDataNode(t)
+	store := NewMemStore()
+	d.mu.Lock()
+	d.Volumes = []*Volume{{Dir: "mem", Store: store}}
+	d.constructInfo()
+	d.mu.Unlock()
+	sent, streamed := testBlkID("mem.txt", 0), testBlkID("mem.txt", 1)
+	data := bytes.Repeat([]byte("in memory "), config.ChunkSize/4)
+	putBlk(t, d, sent, data)
+	if err := sendChunks(d, streamed, data, utils.ChecksumOf(utils.CRC32, data)); err != nil {
+		t.Fatalf("streaming %v: %v", streamed, err)
+	}
+	for _, blk := range []string{sent, streamed} {
+		if got := readTestBlk(t, d, blk); !bytes.Equal(got, data) {
+			t.Errorf("%v reads %v bytes, want %v", blk, len(got), len(data))
+		}
+		d.cache.clear()
+		reply := utils.BlkData{}
+		args := RequestBlkArgs{BlkID: blk, Offset: 3, Length: 6}
+		if err := d.RequestBlk(&args, &reply); err != nil || string(reply.Data) != "memory" {
+			t.Errorf("reading [3, +6) of %v = %q, %v", blk, reply.Data, err)
+		}
+	}
+	if blks, _ := store.List(); len(blks) != 2 {
+		t.Errorf("store holds %v blocks, want 2", len(blks))
+	}
+
+	// the scanner verifies blocks through the store
+	meta := d.IDToMetaData[streamed]
+	if err := store.Put(streamed, meta, []byte("corrupted")); err != nil {
+		t.Fatal(err)
+	}
+	defer func(del bool) { config.DeleteCorruptBlk = del }(config.DeleteCorruptBlk)
+	config.DeleteCorruptBlk, d.cache = true, newBlkCache(0)
+	if corrupt := d.scanOnce(); len(corrupt) != 1 || corrupt[0] != streamed {
+		t.Errorf("scanner found %v corrupt, want [%v]", corrupt, streamed)
+	}
+	if _, err := store.Get(streamed); !os.IsNotExist(err) {
+		t.Errorf("corrupt block is still stored: %v", err)
+	}
+	if !d.deleteBlk(sent) || d.hasBlk(sent) {
+		t.Errorf("%v is kept after being deleted", sent)
+	}
+	if blks, _ := store.List(); len(blks) != 0 {
+		t.Errorf("store holds %v after deleting every block", blks)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/logger"
+)
+
+// policies of config.VolumeChoice
+const (
+	// RoundRobin puts new blocks on each volume in turn
+	RoundRobin = "round-robin"
+	// MostFree puts a new block on the volume with the most space available
+	MostFree = "most-free"
+)
+
+// ErrNoVolume is returned for writes when every volume has failed
+var ErrNoVolume = errors.New("No healthy volume")
+
+// Volume is a data directory of the datanode, typically a disk of its
+// own. Each block lives on one volume, kept by the store of the volume.
+type Volume struct {
+	Dir   string
+	Store BlockStore
+	// Failed is set once the volume can't be accessed, its blocks are
+	// dropped and it gets no new blocks. It is guarded by DataNode.mu.
+	Failed bool
+	// errs counts the I/O errors on the volume since its last successful
+	// read or write, see checkIO. It is guarded by DataNode.mu.
+	errs int
+}
+
+// NewVolume returns the volume of data directory dir, keeping its blocks
+// in files
+func NewVolume(dir string) *Volume {
+	return &Volume{Dir: dir, Store: NewDiskStore(dir)}
+}
+
+// load loads the metadata of the blocks of v, the caller should hold d.mu
+func (d *DataNode) load(v *Volume) error {
+	blks, err := v.Store.List()
+	if err != nil {
+		return err
+	}
+	for blkID, meta := range blks {
+		if other, ok := d.blkVols[blkID]; ok {
+			logger.Warnf("block %v is on both %v and %v, ignore the latter\n",
+				blkID, other.Dir, v.Dir)
+			continue
+		}
+		d.IDToMetaData[blkID] = meta
+		d.blkVols[blkID] = v
+		logger.Debugf("load metadata of %v: checksum: %v, timestamp: %v, len: %v\n",
+			blkID, meta.Checksum, meta.Timestamp, meta.Length)
+	}
+	return nil
+}
+
+// volumeOf returns the volume holding a block, nil if there is none
+func (d *DataNode) volumeOf(blkID string) *Volume {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.blkVols[blkID]
+}
+
+// chooseVolume returns the volume to write a block to: the one already
+// holding it, otherwise a healthy volume picked by config.VolumeChoice
+func (d *DataNode) chooseVolume(blkID string) (*Volume, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if v, ok := d.blkVols[blkID]; ok && !v.Failed {
+		return v, nil
+	}
+	healthy := make([]*Volume, 0, len(d.Volumes))
+	for _, v := range d.Volumes {
+		if !v.Failed {
+			healthy = append(healthy, v)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil, ErrNoVolume
+	}
+	if config.VolumeChoice == MostFree {
+		best, bestFree := healthy[0], uint64(0)
+		for _, v := range healthy {
+			var stat syscall.Statfs_t
+			if err := syscall.Statfs(v.Dir, &stat); err != nil {
+				continue
+			}
+			if free := stat.Bavail * uint64(stat.Bsize); free > bestFree {
+				best, bestFree = v, free
+			}
+		}
+		return best, nil
+	}
+	v := healthy[d.nextVol%len(healthy)]
+	d.nextVol++
+	return v, nil
+}
+
+// checkVolumes marks the volumes whose store can't be reached failed and
+// drops their blocks, so that the next block report leaves them out
+// and namenode re-replicates them. It returns the blocks lost.
+func (d *DataNode) checkVolumes() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	lost := make([]string, 0)
+	for _, v := range d.Volumes {
+		if v.Failed {
+			continue
+		}
+		c, ok := v.Store.(storeChecker)
+		if !ok {
+			continue
+		}
+		if err := c.Check(); err != nil {
+			lost = append(lost, d.failVolume(v, err)...)
+		}
+	}
+	return lost
+}
+
+// failVolume marks v failed and drops its blocks, which it returns. The
+// caller should hold d.mu.
+func (d *DataNode) failVolume(v *Volume, err error) []string {
+	v.Failed = true
+	lost := make([]string, 0)
+	for blkID, vol := range d.blkVols {
+		if vol == v {
+			lost = append(lost, blkID)
+			delete(d.blkVols, blkID)
+			delete(d.IDToMetaData, blkID)
+			d.cache.invalidate(blkID)
+		}
+	}
+	logger.Errorf("volume %v failed: %v, %v blocks lost\n", v.Dir, err, len(lost))
+	return lost
+}
+
+// isIOError tells whether err comes from the file system rather than
+// from the content of a block. A missing file or a full disk doesn't
+// tell the volume is failing.
+func isIOError(err error) bool {
+	var pathErr *os.PathError
+	var linkErr *os.LinkError
+	if !errors.As(err, &pathErr) && !errors.As(err, &linkErr) {
+		return false
+	}
+	return !os.IsNotExist(err) && !errors.Is(err, syscall.ENOSPC)
+}
+
+// checkIO records the outcome of a read or write of a block of v.
+// After config.VolumeMaxErrors I/O errors in a row the volume is failed
+// and a block report tells namenode its blocks are lost, so that they
+// get re-replicated.
+func (d *DataNode) checkIO(v *Volume, err error) {
+	d.mu.Lock()
+	if err == nil {
+		v.errs = 0
+		d.mu.Unlock()
+		return
+	}
+	if !isIOError(err) || v.Failed {
+		d.mu.Unlock()
+		return
+	}
+	v.errs++
+	logger.Warnf("I/O error %v of %v on volume %v: %v\n", v.errs,
+		config.VolumeMaxErrors, v.Dir, err)
+	if v.errs < config.VolumeMaxErrors {
+		d.mu.Unlock()
+		return
+	}
+	d.failVolume(v, err)
+	d.mu.Unlock()
+	if err := d.sendBlockReport(); err != nil {
+		logger.Errorf("error when reporting the blocks lost on %v: %v\n", v.Dir, err)
+	}
+}
+
+// capacity sums the size and space in use of the file systems of the
+// healthy volumes, each file system counted once
+func (d *DataNode) capacity() (total uint64, fracInUse float64) {
+	d.mu.Lock()
+	dirs := make([]string, 0, len(d.Volumes))
+	for _, v := range d.Volumes {
+		if !v.Failed {
+			dirs = append(dirs, v.Dir)
+		}
+	}
+	d.mu.Unlock()
+	seen := make(map[syscall.Fsid]bool)
+	var used uint64
+	for _, dir := range dirs {
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(dir, &stat); err != nil {
+			logger.Errorf("error when getting fs stat of %v: %v\n", dir, err)
+			continue
+		}
+		if seen[stat.Fsid] {
+			continue
+		}
+		seen[stat.Fsid] = true
+		// total size in bytes = total block number * block size
+		total += stat.Blocks * uint64(stat.Bsize)
+		used += (stat.Blocks - stat.Bavail) * uint64(stat.Bsize)
+	}
+	if total > 0 {
+		fracInUse = float64(used) / float64(total)
+	}
+	return total, fracInUse
+}
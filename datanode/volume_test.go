@@ -0,0 +1,136 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+func TestVolumes(t *testing.T) {
+	dirs := config.DataDirs
+	config.DataDirs = []string{"vol0", "vol1"}
+	defer func() { config.DataDirs = dirs }()
+	d := newTestDataNode(t)
+	n := startNameNode(t)
+	onVolume := make(map[string][]string)
+	for i := 0; i < 4; i++ {
+		blk := testBlkID("vol.txt", i)
+		putBlk(t, d, blk, []byte(fmt.Sprintf("block %v", i)))
+		for _, v := range d.Volumes {
+			if _, err := os.Stat(filepath.Join(v.Store.(*DiskStore).ActPath, blk)); err == nil {
+				onVolume[v.Dir] = append(onVolume[v.Dir], blk)
+			}
+		}
+	}
+	if len(onVolume["vol0"]) != 2 || len(onVolume["vol1"]) != 2 {
+		t.Fatalf("blocks by volume are %v, want 2 on each", onVolume)
+	}
+	join(d)
+
+	// the disk of vol1 goes away
+	if err := os.RemoveAll("vol1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile("vol1", nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	d.reportBlock()
+	if !d.Volumes[1].Failed || d.Volumes[0].Failed {
+		t.Errorf("volumes failed are %v and %v, want only vol1", d.Volumes[0].Failed,
+			d.Volumes[1].Failed)
+	}
+	for dir, blks := range onVolume {
+		for _, blk := range blks {
+			lost := len(n.BlkToDatanodes[blk]) == 0
+			if lost != (dir == "vol1") {
+				t.Errorf("%v on %v is lost %v after the report", blk, dir, lost)
+			}
+		}
+	}
+	for i, blk := range onVolume["vol0"] {
+		if got := string(readTestBlk(t, d, blk)); got != fmt.Sprintf("block %v", 2*i) {
+			t.Errorf("%v reads %q", blk, got)
+		}
+	}
+	// new blocks go to the healthy volume
+	blk := testBlkID("vol.txt", 4)
+	putBlk(t, d, blk, []byte("block 4"))
+	if _, err := os.Stat(filepath.Join("vol0", "actdata", blk)); err != nil {
+		t.Errorf("new block isn't on vol0: %v", err)
+	}
+
+	// a restart keeps the blocks of the healthy volume
+	d = NewDataNode()
+	if !d.Volumes[1].Failed || len(d.IDToMetaData) != 3 {
+		t.Errorf("after restart vol1 failed %v with blocks %v, want 3 blocks of vol0",
+			d.Volumes[1].Failed, d.IDToMetaData)
+	}
+}
+
+func TestVolumeIOErrors(t *testing.T) {
+	dirs, maxErrs := config.DataDirs, config.VolumeMaxErrors
+	config.DataDirs, config.VolumeMaxErrors = []string{"vol0", "vol1"}, 2
+	defer func() { config.DataDirs, config.VolumeMaxErrors = dirs, maxErrs }()
+	d := newTestDataNode(t)
+	n := startNameNode(t)
+	var blks []string
+	for i := 0; i < 4; i++ {
+		blks = append(blks, testBlkID("io.txt", i))
+		putBlk(t, d, blks[i], []byte(fmt.Sprintf("block %v", i)))
+	}
+	join(d)
+	// vol1 holds blocks 1 and 3, its data directory turns unreadable
+	if err := os.RemoveAll(filepath.Join("vol1", "actdata")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join("vol1", "actdata"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	readTestBlk(t, d, blks[1])
+	if d.Volumes[1].Failed {
+		t.Fatalf("vol1 failed after a single I/O error")
+	}
+	readTestBlk(t, d, blks[3])
+	if !d.Volumes[1].Failed || d.Volumes[0].Failed {
+		t.Fatalf("volumes failed are %v and %v, want only vol1", d.Volumes[0].Failed,
+			d.Volumes[1].Failed)
+	}
+	// namenode learns the blocks of vol1 are lost right away
+	for i, blk := range blks {
+		lost := len(n.BlkToDatanodes[blk]) == 0
+		if lost != (i%2 == 1) || d.hasBlk(blk) == lost {
+			t.Errorf("block %v is lost %v, on datanode %v", i, lost, d.hasBlk(blk))
+		}
+	}
+	// vol0 keeps serving and takes every new block
+	for i := 0; i < 4; i += 2 {
+		if got := string(readTestBlk(t, d, blks[i])); got != fmt.Sprintf("block %v", i) {
+			t.Errorf("block %v reads %q", i, got)
+		}
+	}
+	for i := 4; i < 6; i++ {
+		blk := testBlkID("io.txt", i)
+		putBlk(t, d, blk, []byte(fmt.Sprintf("block %v", i)))
+		if _, err := os.Stat(filepath.Join("vol0", "actdata", blk)); err != nil {
+			t.Errorf("block %v isn't on vol0: %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdfspb_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/WineChord/gdfs/datanode"
+	"github.com/WineChord/gdfs/mapreduce"
+	"github.com/WineChord/gdfs/namenode"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// TestConvert converts random values of each type an RPC carries to their
+// message and back, which loses no field as long as they match
+func TestConvert(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, x := range []interface{}{
+		utils.MetaData{}, utils.Edges{}, utils.CalMVArgs{}, utils.CalMVReply{},
+		utils.WordCountArgs{}, utils.WordCountReply{}, utils.GrepArgs{},
+		utils.GrepReply{}, utils.TopNArgs{}, utils.TopNReply{}, utils.BlkData{},
+		utils.BlkChunk{}, utils.TruncateBlkArgs{}, utils.DeleteBlkArgs{},
+		utils.DeleteBlkReply{},
+		mapreduce.MapArgs{}, mapreduce.MapReply{}, mapreduce.FetchArgs{},
+		mapreduce.FetchReply{}, mapreduce.ReduceArgs{}, mapreduce.MapSource{},
+		mapreduce.ReduceReply{}, mapreduce.CommitArgs{}, mapreduce.JobArgs{},
+		namenode.CommandArgs{}, namenode.CommandReply{}, namenode.FileStat{},
+		namenode.DiskUsage{}, namenode.FsckReport{}, namenode.FileHealth{},
+		namenode.DirCount{}, namenode.SubmitJobReply{}, namenode.JobArgs{},
+		namenode.JobStatus{}, namenode.AdminArgs{}, namenode.ClusterStatusReply{},
+		namenode.NodeStatus{}, namenode.ReportNodesReply{}, namenode.NotifyArgs{},
+		namenode.NotifyReply{}, namenode.HandshakeArgs{}, namenode.HandshakeReply{},
+		namenode.RegisterArgs{}, namenode.RegisterReply{}, namenode.HeartBeatArgs{},
+		namenode.HeartBeatReply{}, namenode.ReportBlockArgs{},
+		namenode.ReportBlockReply{}, namenode.ReportCorruptBlockArgs{},
+		namenode.ReportCorruptBlockReply{},
+		datanode.RequestBlkArgs{}, datanode.RequestBlksArgs{},
+		datanode.RequestBlksReply{}, datanode.SendBlkReply{}, datanode.StatBlkArgs{},
+	} {
+		typ := reflect.TypeOf(x)
+		for i := 0; i < 10; i++ {
+			v, ok := quick.Value(typ, rnd)
+			if !ok {
+				t.Fatalf("cannot generate a %v", typ)
+			}
+			in := reflect.New(typ)
+			in.Elem().Set(v)
+			pb := in.MethodByName("ToPB").Call(nil)
+			out := reflect.New(typ)
+			out.MethodByName("FromPB").Call(pb)
+			if !equal(in.Elem(), out.Elem()) {
+				t.Errorf("%v %+v converts back to %+v", typ, in.Elem(), out.Elem())
+				break
+			}
+		}
+	}
+}
+
+// equal is reflect.DeepEqual with empty slices and maps equal to nil ones,
+// as messages don't tell them apart
+func equal(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equal(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for _, k := range a.MapKeys() {
+			bv := b.MapIndex(k)
+			if !bv.IsValid() || !equal(a.MapIndex(k), bv) {
+				return false
+			}
+		}
+		return true
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equal(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !equal(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
@@ -0,0 +1,5894 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Messages mirror the argument and reply types of the RPCs of namenode and
+// datanodes field by field: a field is named as its Go counterpart in
+// snake case, Go ints are int64 and time.Duration is in nanoseconds. The Go
+// types convert to and from their messages with ToPB and FromPB.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: gdfs.proto
+
+package gdfspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Strings is a list of strings as a map value
+type Strings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values []string `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *Strings) Reset() {
+	*x = Strings{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Strings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Strings) ProtoMessage() {}
+
+func (x *Strings) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Strings.ProtoReflect.Descriptor instead.
+func (*Strings) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Strings) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// CommandArgs is namenode.CommandArgs
+type CommandArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CommandType  int64    `protobuf:"varint,1,opt,name=command_type,json=commandType,proto3" json:"command_type,omitempty"`
+	Dpath        string   `protobuf:"bytes,2,opt,name=dpath,proto3" json:"dpath,omitempty"`
+	Dpaths       []string `protobuf:"bytes,3,rep,name=dpaths,proto3" json:"dpaths,omitempty"`
+	FileName     string   `protobuf:"bytes,4,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	FileSize     int64    `protobuf:"varint,5,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
+	Overwrite    bool     `protobuf:"varint,6,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
+	BlockSize    int64    `protobuf:"varint,7,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
+	Replication  int64    `protobuf:"varint,8,opt,name=replication,proto3" json:"replication,omitempty"`
+	Timeout      int64    `protobuf:"varint,9,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Job          string   `protobuf:"bytes,10,opt,name=job,proto3" json:"job,omitempty"`
+	NumReduce    int64    `protobuf:"varint,11,opt,name=num_reduce,json=numReduce,proto3" json:"num_reduce,omitempty"`
+	Output       string   `protobuf:"bytes,12,opt,name=output,proto3" json:"output,omitempty"`
+	Token        string   `protobuf:"bytes,13,opt,name=token,proto3" json:"token,omitempty"`
+	Recursive    bool     `protobuf:"varint,14,opt,name=recursive,proto3" json:"recursive,omitempty"`
+	Long         bool     `protobuf:"varint,15,opt,name=long,proto3" json:"long,omitempty"`
+	Addr         string   `protobuf:"bytes,16,opt,name=addr,proto3" json:"addr,omitempty"`
+	User         string   `protobuf:"bytes,17,opt,name=user,proto3" json:"user,omitempty"`
+	SkipTrash    bool     `protobuf:"varint,18,opt,name=skip_trash,json=skipTrash,proto3" json:"skip_trash,omitempty"`
+	Quota        int64    `protobuf:"varint,19,opt,name=quota,proto3" json:"quota,omitempty"`
+	Digest       string   `protobuf:"bytes,20,opt,name=digest,proto3" json:"digest,omitempty"`
+	ChecksumType string   `protobuf:"bytes,21,opt,name=checksum_type,json=checksumType,proto3" json:"checksum_type,omitempty"`
+	BlkList      []string `protobuf:"bytes,22,rep,name=blk_list,json=blkList,proto3" json:"blk_list,omitempty"`
+	Pattern      string   `protobuf:"bytes,23,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	IgnoreCase   bool     `protobuf:"varint,24,opt,name=ignore_case,json=ignoreCase,proto3" json:"ignore_case,omitempty"`
+	CountOnly    bool     `protobuf:"varint,25,opt,name=count_only,json=countOnly,proto3" json:"count_only,omitempty"`
+	K            int64    `protobuf:"varint,26,opt,name=k,proto3" json:"k,omitempty"`
+	Lexical      bool     `protobuf:"varint,27,opt,name=lexical,proto3" json:"lexical,omitempty"`
+}
+
+func (x *CommandArgs) Reset() {
+	*x = CommandArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommandArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandArgs) ProtoMessage() {}
+
+func (x *CommandArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandArgs.ProtoReflect.Descriptor instead.
+func (*CommandArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CommandArgs) GetCommandType() int64 {
+	if x != nil {
+		return x.CommandType
+	}
+	return 0
+}
+
+func (x *CommandArgs) GetDpath() string {
+	if x != nil {
+		return x.Dpath
+	}
+	return ""
+}
+
+func (x *CommandArgs) GetDpaths() []string {
+	if x != nil {
+		return x.Dpaths
+	}
+	return nil
+}
+
+func (x *CommandArgs) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *CommandArgs) GetFileSize() int64 {
+	if x != nil {
+		return x.FileSize
+	}
+	return 0
+}
+
+func (x *CommandArgs) GetOverwrite() bool {
+	if x != nil {
+		return x.Overwrite
+	}
+	return false
+}
+
+func (x *CommandArgs) GetBlockSize() int64 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+func (x *CommandArgs) GetReplication() int64 {
+	if x != nil {
+		return x.Replication
+	}
+	return 0
+}
+
+func (x *CommandArgs) GetTimeout() int64 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *CommandArgs) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *CommandArgs) GetNumReduce() int64 {
+	if x != nil {
+		return x.NumReduce
+	}
+	return 0
+}
+
+func (x *CommandArgs) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *CommandArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *CommandArgs) GetRecursive() bool {
+	if x != nil {
+		return x.Recursive
+	}
+	return false
+}
+
+func (x *CommandArgs) GetLong() bool {
+	if x != nil {
+		return x.Long
+	}
+	return false
+}
+
+func (x *CommandArgs) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *CommandArgs) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *CommandArgs) GetSkipTrash() bool {
+	if x != nil {
+		return x.SkipTrash
+	}
+	return false
+}
+
+func (x *CommandArgs) GetQuota() int64 {
+	if x != nil {
+		return x.Quota
+	}
+	return 0
+}
+
+func (x *CommandArgs) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+func (x *CommandArgs) GetChecksumType() string {
+	if x != nil {
+		return x.ChecksumType
+	}
+	return ""
+}
+
+func (x *CommandArgs) GetBlkList() []string {
+	if x != nil {
+		return x.BlkList
+	}
+	return nil
+}
+
+func (x *CommandArgs) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *CommandArgs) GetIgnoreCase() bool {
+	if x != nil {
+		return x.IgnoreCase
+	}
+	return false
+}
+
+func (x *CommandArgs) GetCountOnly() bool {
+	if x != nil {
+		return x.CountOnly
+	}
+	return false
+}
+
+func (x *CommandArgs) GetK() int64 {
+	if x != nil {
+		return x.K
+	}
+	return 0
+}
+
+func (x *CommandArgs) GetLexical() bool {
+	if x != nil {
+		return x.Lexical
+	}
+	return false
+}
+
+// CommandReply is namenode.CommandReply
+type CommandReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result         string              `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Files          []string            `protobuf:"bytes,2,rep,name=files,proto3" json:"files,omitempty"`
+	Paths          []string            `protobuf:"bytes,3,rep,name=paths,proto3" json:"paths,omitempty"`
+	BlkList        []string            `protobuf:"bytes,4,rep,name=blk_list,json=blkList,proto3" json:"blk_list,omitempty"`
+	BlkSize        int64               `protobuf:"varint,5,opt,name=blk_size,json=blkSize,proto3" json:"blk_size,omitempty"`
+	FileSize       int64               `protobuf:"varint,6,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
+	BlkLengths     []int64             `protobuf:"varint,7,rep,packed,name=blk_lengths,json=blkLengths,proto3" json:"blk_lengths,omitempty"`
+	SrcBlkList     []string            `protobuf:"bytes,8,rep,name=src_blk_list,json=srcBlkList,proto3" json:"src_blk_list,omitempty"`
+	BlkToDataNodes map[string]*Strings `protobuf:"bytes,9,rep,name=blk_to_data_nodes,json=blkToDataNodes,proto3" json:"blk_to_data_nodes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	GenStamp       int64               `protobuf:"varint,10,opt,name=gen_stamp,json=genStamp,proto3" json:"gen_stamp,omitempty"`
+	Errors         map[string]string   `protobuf:"bytes,11,rep,name=errors,proto3" json:"errors,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Stats          []*FileStat         `protobuf:"bytes,12,rep,name=stats,proto3" json:"stats,omitempty"`
+	Checksums      map[string]string   `protobuf:"bytes,13,rep,name=checksums,proto3" json:"checksums,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	WordCounts     map[string]int64    `protobuf:"bytes,14,rep,name=word_counts,json=wordCounts,proto3" json:"word_counts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	JobResult      map[string]float64  `protobuf:"bytes,15,rep,name=job_result,json=jobResult,proto3" json:"job_result,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	Usage          []*DiskUsage        `protobuf:"bytes,16,rep,name=usage,proto3" json:"usage,omitempty"`
+	Fsck           *FsckReport         `protobuf:"bytes,17,opt,name=fsck,proto3" json:"fsck,omitempty"`
+	Trashed        map[string]string   `protobuf:"bytes,18,rep,name=trashed,proto3" json:"trashed,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Counts         []*DirCount         `protobuf:"bytes,19,rep,name=counts,proto3" json:"counts,omitempty"`
+	Replicated     []string            `protobuf:"bytes,20,rep,name=replicated,proto3" json:"replicated,omitempty"`
+	Digest         string              `protobuf:"bytes,21,opt,name=digest,proto3" json:"digest,omitempty"`
+	DigestType     string              `protobuf:"bytes,22,opt,name=digest_type,json=digestType,proto3" json:"digest_type,omitempty"`
+	Lines          []string            `protobuf:"bytes,23,rep,name=lines,proto3" json:"lines,omitempty"`
+	NumLines       int64               `protobuf:"varint,24,opt,name=num_lines,json=numLines,proto3" json:"num_lines,omitempty"`
+}
+
+func (x *CommandReply) Reset() {
+	*x = CommandReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommandReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandReply) ProtoMessage() {}
+
+func (x *CommandReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandReply.ProtoReflect.Descriptor instead.
+func (*CommandReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CommandReply) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *CommandReply) GetFiles() []string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *CommandReply) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+func (x *CommandReply) GetBlkList() []string {
+	if x != nil {
+		return x.BlkList
+	}
+	return nil
+}
+
+func (x *CommandReply) GetBlkSize() int64 {
+	if x != nil {
+		return x.BlkSize
+	}
+	return 0
+}
+
+func (x *CommandReply) GetFileSize() int64 {
+	if x != nil {
+		return x.FileSize
+	}
+	return 0
+}
+
+func (x *CommandReply) GetBlkLengths() []int64 {
+	if x != nil {
+		return x.BlkLengths
+	}
+	return nil
+}
+
+func (x *CommandReply) GetSrcBlkList() []string {
+	if x != nil {
+		return x.SrcBlkList
+	}
+	return nil
+}
+
+func (x *CommandReply) GetBlkToDataNodes() map[string]*Strings {
+	if x != nil {
+		return x.BlkToDataNodes
+	}
+	return nil
+}
+
+func (x *CommandReply) GetGenStamp() int64 {
+	if x != nil {
+		return x.GenStamp
+	}
+	return 0
+}
+
+func (x *CommandReply) GetErrors() map[string]string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *CommandReply) GetStats() []*FileStat {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *CommandReply) GetChecksums() map[string]string {
+	if x != nil {
+		return x.Checksums
+	}
+	return nil
+}
+
+func (x *CommandReply) GetWordCounts() map[string]int64 {
+	if x != nil {
+		return x.WordCounts
+	}
+	return nil
+}
+
+func (x *CommandReply) GetJobResult() map[string]float64 {
+	if x != nil {
+		return x.JobResult
+	}
+	return nil
+}
+
+func (x *CommandReply) GetUsage() []*DiskUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *CommandReply) GetFsck() *FsckReport {
+	if x != nil {
+		return x.Fsck
+	}
+	return nil
+}
+
+func (x *CommandReply) GetTrashed() map[string]string {
+	if x != nil {
+		return x.Trashed
+	}
+	return nil
+}
+
+func (x *CommandReply) GetCounts() []*DirCount {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *CommandReply) GetReplicated() []string {
+	if x != nil {
+		return x.Replicated
+	}
+	return nil
+}
+
+func (x *CommandReply) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+func (x *CommandReply) GetDigestType() string {
+	if x != nil {
+		return x.DigestType
+	}
+	return ""
+}
+
+func (x *CommandReply) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *CommandReply) GetNumLines() int64 {
+	if x != nil {
+		return x.NumLines
+	}
+	return 0
+}
+
+// FileStat is namenode.FileStat
+type FileStat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path        string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	IsDir       bool   `protobuf:"varint,2,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	Size        int64  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	NumBlks     int64  `protobuf:"varint,4,opt,name=num_blks,json=numBlks,proto3" json:"num_blks,omitempty"`
+	BlkSize     int64  `protobuf:"varint,5,opt,name=blk_size,json=blkSize,proto3" json:"blk_size,omitempty"`
+	Replication int64  `protobuf:"varint,6,opt,name=replication,proto3" json:"replication,omitempty"`
+	ModTime     int64  `protobuf:"varint,7,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	AccessTime  int64  `protobuf:"varint,8,opt,name=access_time,json=accessTime,proto3" json:"access_time,omitempty"`
+}
+
+func (x *FileStat) Reset() {
+	*x = FileStat{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FileStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileStat) ProtoMessage() {}
+
+func (x *FileStat) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileStat.ProtoReflect.Descriptor instead.
+func (*FileStat) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FileStat) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileStat) GetIsDir() bool {
+	if x != nil {
+		return x.IsDir
+	}
+	return false
+}
+
+func (x *FileStat) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileStat) GetNumBlks() int64 {
+	if x != nil {
+		return x.NumBlks
+	}
+	return 0
+}
+
+func (x *FileStat) GetBlkSize() int64 {
+	if x != nil {
+		return x.BlkSize
+	}
+	return 0
+}
+
+func (x *FileStat) GetReplication() int64 {
+	if x != nil {
+		return x.Replication
+	}
+	return 0
+}
+
+func (x *FileStat) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
+}
+
+func (x *FileStat) GetAccessTime() int64 {
+	if x != nil {
+		return x.AccessTime
+	}
+	return 0
+}
+
+// DiskUsage is namenode.DiskUsage
+type DiskUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path       string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Size       int64  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Replicated int64  `protobuf:"varint,3,opt,name=replicated,proto3" json:"replicated,omitempty"`
+}
+
+func (x *DiskUsage) Reset() {
+	*x = DiskUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiskUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskUsage) ProtoMessage() {}
+
+func (x *DiskUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskUsage.ProtoReflect.Descriptor instead.
+func (*DiskUsage) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DiskUsage) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DiskUsage) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DiskUsage) GetReplicated() int64 {
+	if x != nil {
+		return x.Replicated
+	}
+	return 0
+}
+
+// FsckReport is namenode.FsckReport
+type FsckReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Files     int64         `protobuf:"varint,1,opt,name=files,proto3" json:"files,omitempty"`
+	Blks      int64         `protobuf:"varint,2,opt,name=blks,proto3" json:"blks,omitempty"`
+	Unhealthy []*FileHealth `protobuf:"bytes,3,rep,name=unhealthy,proto3" json:"unhealthy,omitempty"`
+}
+
+func (x *FsckReport) Reset() {
+	*x = FsckReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FsckReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FsckReport) ProtoMessage() {}
+
+func (x *FsckReport) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FsckReport.ProtoReflect.Descriptor instead.
+func (*FsckReport) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FsckReport) GetFiles() int64 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+func (x *FsckReport) GetBlks() int64 {
+	if x != nil {
+		return x.Blks
+	}
+	return 0
+}
+
+func (x *FsckReport) GetUnhealthy() []*FileHealth {
+	if x != nil {
+		return x.Unhealthy
+	}
+	return nil
+}
+
+// FileHealth is namenode.FileHealth
+type FileHealth struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path            string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	NumBlks         int64    `protobuf:"varint,2,opt,name=num_blks,json=numBlks,proto3" json:"num_blks,omitempty"`
+	Missing         []string `protobuf:"bytes,3,rep,name=missing,proto3" json:"missing,omitempty"`
+	UnderReplicated []string `protobuf:"bytes,4,rep,name=under_replicated,json=underReplicated,proto3" json:"under_replicated,omitempty"`
+	OverReplicated  []string `protobuf:"bytes,5,rep,name=over_replicated,json=overReplicated,proto3" json:"over_replicated,omitempty"`
+	Corrupt         []string `protobuf:"bytes,6,rep,name=corrupt,proto3" json:"corrupt,omitempty"`
+}
+
+func (x *FileHealth) Reset() {
+	*x = FileHealth{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FileHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileHealth) ProtoMessage() {}
+
+func (x *FileHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileHealth.ProtoReflect.Descriptor instead.
+func (*FileHealth) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FileHealth) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileHealth) GetNumBlks() int64 {
+	if x != nil {
+		return x.NumBlks
+	}
+	return 0
+}
+
+func (x *FileHealth) GetMissing() []string {
+	if x != nil {
+		return x.Missing
+	}
+	return nil
+}
+
+func (x *FileHealth) GetUnderReplicated() []string {
+	if x != nil {
+		return x.UnderReplicated
+	}
+	return nil
+}
+
+func (x *FileHealth) GetOverReplicated() []string {
+	if x != nil {
+		return x.OverReplicated
+	}
+	return nil
+}
+
+func (x *FileHealth) GetCorrupt() []string {
+	if x != nil {
+		return x.Corrupt
+	}
+	return nil
+}
+
+// DirCount is namenode.DirCount
+type DirCount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path          string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Dirs          int64  `protobuf:"varint,2,opt,name=dirs,proto3" json:"dirs,omitempty"`
+	Files         int64  `protobuf:"varint,3,opt,name=files,proto3" json:"files,omitempty"`
+	Bytes         int64  `protobuf:"varint,4,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	NsQuota       int64  `protobuf:"varint,5,opt,name=ns_quota,json=nsQuota,proto3" json:"ns_quota,omitempty"`
+	SpaceQuota    int64  `protobuf:"varint,6,opt,name=space_quota,json=spaceQuota,proto3" json:"space_quota,omitempty"`
+	SpaceConsumed int64  `protobuf:"varint,7,opt,name=space_consumed,json=spaceConsumed,proto3" json:"space_consumed,omitempty"`
+}
+
+func (x *DirCount) Reset() {
+	*x = DirCount{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DirCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirCount) ProtoMessage() {}
+
+func (x *DirCount) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirCount.ProtoReflect.Descriptor instead.
+func (*DirCount) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DirCount) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DirCount) GetDirs() int64 {
+	if x != nil {
+		return x.Dirs
+	}
+	return 0
+}
+
+func (x *DirCount) GetFiles() int64 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+func (x *DirCount) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *DirCount) GetNsQuota() int64 {
+	if x != nil {
+		return x.NsQuota
+	}
+	return 0
+}
+
+func (x *DirCount) GetSpaceQuota() int64 {
+	if x != nil {
+		return x.SpaceQuota
+	}
+	return 0
+}
+
+func (x *DirCount) GetSpaceConsumed() int64 {
+	if x != nil {
+		return x.SpaceConsumed
+	}
+	return 0
+}
+
+// SubmitJobReply is namenode.SubmitJobReply
+type SubmitJobReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+}
+
+func (x *SubmitJobReply) Reset() {
+	*x = SubmitJobReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitJobReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitJobReply) ProtoMessage() {}
+
+func (x *SubmitJobReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitJobReply.ProtoReflect.Descriptor instead.
+func (*SubmitJobReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SubmitJobReply) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// JobArgs is namenode.JobArgs
+type JobArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *JobArgs) Reset() {
+	*x = JobArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobArgs) ProtoMessage() {}
+
+func (x *JobArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobArgs.ProtoReflect.Descriptor instead.
+func (*JobArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *JobArgs) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// JobStatusReply is namenode.JobStatus
+type JobStatusReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId       string             `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Job         string             `protobuf:"bytes,2,opt,name=job,proto3" json:"job,omitempty"`
+	Path        string             `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	State       string             `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	Tasks       map[string]string  `protobuf:"bytes,5,rep,name=tasks,proto3" json:"tasks,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ReduceTasks []string           `protobuf:"bytes,6,rep,name=reduce_tasks,json=reduceTasks,proto3" json:"reduce_tasks,omitempty"`
+	Start       int64              `protobuf:"varint,7,opt,name=start,proto3" json:"start,omitempty"`
+	End         int64              `protobuf:"varint,8,opt,name=end,proto3" json:"end,omitempty"`
+	Err         string             `protobuf:"bytes,9,opt,name=err,proto3" json:"err,omitempty"`
+	Result      string             `protobuf:"bytes,10,opt,name=result,proto3" json:"result,omitempty"`
+	JobResult   map[string]float64 `protobuf:"bytes,11,rep,name=job_result,json=jobResult,proto3" json:"job_result,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	Files       []string           `protobuf:"bytes,12,rep,name=files,proto3" json:"files,omitempty"`
+}
+
+func (x *JobStatusReply) Reset() {
+	*x = JobStatusReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobStatusReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatusReply) ProtoMessage() {}
+
+func (x *JobStatusReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatusReply.ProtoReflect.Descriptor instead.
+func (*JobStatusReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *JobStatusReply) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobStatusReply) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *JobStatusReply) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *JobStatusReply) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *JobStatusReply) GetTasks() map[string]string {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *JobStatusReply) GetReduceTasks() []string {
+	if x != nil {
+		return x.ReduceTasks
+	}
+	return nil
+}
+
+func (x *JobStatusReply) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *JobStatusReply) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *JobStatusReply) GetErr() string {
+	if x != nil {
+		return x.Err
+	}
+	return ""
+}
+
+func (x *JobStatusReply) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *JobStatusReply) GetJobResult() map[string]float64 {
+	if x != nil {
+		return x.JobResult
+	}
+	return nil
+}
+
+func (x *JobStatusReply) GetFiles() []string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+// AdminArgs is namenode.AdminArgs
+type AdminArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *AdminArgs) Reset() {
+	*x = AdminArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminArgs) ProtoMessage() {}
+
+func (x *AdminArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminArgs.ProtoReflect.Descriptor instead.
+func (*AdminArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AdminArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// ClusterStatusReply is namenode.ClusterStatusReply
+type ClusterStatusReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Capacity  uint64        `protobuf:"varint,1,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Used      uint64        `protobuf:"varint,2,opt,name=used,proto3" json:"used,omitempty"`
+	Remaining uint64        `protobuf:"varint,3,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	Nodes     []*NodeStatus `protobuf:"bytes,4,rep,name=nodes,proto3" json:"nodes,omitempty"`
+}
+
+func (x *ClusterStatusReply) Reset() {
+	*x = ClusterStatusReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClusterStatusReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterStatusReply) ProtoMessage() {}
+
+func (x *ClusterStatusReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterStatusReply.ProtoReflect.Descriptor instead.
+func (*ClusterStatusReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ClusterStatusReply) GetCapacity() uint64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *ClusterStatusReply) GetUsed() uint64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *ClusterStatusReply) GetRemaining() uint64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *ClusterStatusReply) GetNodes() []*NodeStatus {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+// NodeStatus is namenode.NodeStatus
+type NodeStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Addr          string  `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+	HostName      string  `protobuf:"bytes,2,opt,name=host_name,json=hostName,proto3" json:"host_name,omitempty"`
+	StorageId     string  `protobuf:"bytes,3,opt,name=storage_id,json=storageId,proto3" json:"storage_id,omitempty"`
+	State         string  `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	Capacity      uint64  `protobuf:"varint,5,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Used          uint64  `protobuf:"varint,6,opt,name=used,proto3" json:"used,omitempty"`
+	Remaining     uint64  `protobuf:"varint,7,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	FracInUse     float64 `protobuf:"fixed64,8,opt,name=frac_in_use,json=fracInUse,proto3" json:"frac_in_use,omitempty"`
+	LastHeartBeat int64   `protobuf:"varint,9,opt,name=last_heart_beat,json=lastHeartBeat,proto3" json:"last_heart_beat,omitempty"`
+	DeadSince     int64   `protobuf:"varint,10,opt,name=dead_since,json=deadSince,proto3" json:"dead_since,omitempty"`
+}
+
+func (x *NodeStatus) Reset() {
+	*x = NodeStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeStatus) ProtoMessage() {}
+
+func (x *NodeStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeStatus.ProtoReflect.Descriptor instead.
+func (*NodeStatus) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *NodeStatus) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *NodeStatus) GetHostName() string {
+	if x != nil {
+		return x.HostName
+	}
+	return ""
+}
+
+func (x *NodeStatus) GetStorageId() string {
+	if x != nil {
+		return x.StorageId
+	}
+	return ""
+}
+
+func (x *NodeStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *NodeStatus) GetCapacity() uint64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetUsed() uint64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetRemaining() uint64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetFracInUse() float64 {
+	if x != nil {
+		return x.FracInUse
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetLastHeartBeat() int64 {
+	if x != nil {
+		return x.LastHeartBeat
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetDeadSince() int64 {
+	if x != nil {
+		return x.DeadSince
+	}
+	return 0
+}
+
+// ReportNodesReply is namenode.ReportNodesReply
+type ReportNodesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nodes []*NodeStatus `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+}
+
+func (x *ReportNodesReply) Reset() {
+	*x = ReportNodesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportNodesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportNodesReply) ProtoMessage() {}
+
+func (x *ReportNodesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportNodesReply.ProtoReflect.Descriptor instead.
+func (*ReportNodesReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReportNodesReply) GetNodes() []*NodeStatus {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+// NotifyArgs is namenode.NotifyArgs
+type NotifyArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Addrs []string `protobuf:"bytes,1,rep,name=addrs,proto3" json:"addrs,omitempty"`
+	Token string   `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *NotifyArgs) Reset() {
+	*x = NotifyArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NotifyArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotifyArgs) ProtoMessage() {}
+
+func (x *NotifyArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotifyArgs.ProtoReflect.Descriptor instead.
+func (*NotifyArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *NotifyArgs) GetAddrs() []string {
+	if x != nil {
+		return x.Addrs
+	}
+	return nil
+}
+
+func (x *NotifyArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// NotifyReply is namenode.NotifyReply
+type NotifyReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status bool `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *NotifyReply) Reset() {
+	*x = NotifyReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NotifyReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotifyReply) ProtoMessage() {}
+
+func (x *NotifyReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotifyReply.ProtoReflect.Descriptor instead.
+func (*NotifyReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *NotifyReply) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+// HandshakeArgs is namenode.HandshakeArgs
+type HandshakeArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NamespaceId int64  `protobuf:"varint,1,opt,name=namespace_id,json=namespaceId,proto3" json:"namespace_id,omitempty"`
+	Addr        string `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	HostName    string `protobuf:"bytes,3,opt,name=host_name,json=hostName,proto3" json:"host_name,omitempty"`
+	Token       string `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	Version     string `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *HandshakeArgs) Reset() {
+	*x = HandshakeArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HandshakeArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandshakeArgs) ProtoMessage() {}
+
+func (x *HandshakeArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandshakeArgs.ProtoReflect.Descriptor instead.
+func (*HandshakeArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *HandshakeArgs) GetNamespaceId() int64 {
+	if x != nil {
+		return x.NamespaceId
+	}
+	return 0
+}
+
+func (x *HandshakeArgs) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *HandshakeArgs) GetHostName() string {
+	if x != nil {
+		return x.HostName
+	}
+	return ""
+}
+
+func (x *HandshakeArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *HandshakeArgs) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+// HandshakeReply is namenode.HandshakeReply
+type HandshakeReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NamespaceId int64  `protobuf:"varint,1,opt,name=namespace_id,json=namespaceId,proto3" json:"namespace_id,omitempty"`
+	Version     string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *HandshakeReply) Reset() {
+	*x = HandshakeReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HandshakeReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandshakeReply) ProtoMessage() {}
+
+func (x *HandshakeReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandshakeReply.ProtoReflect.Descriptor instead.
+func (*HandshakeReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *HandshakeReply) GetNamespaceId() int64 {
+	if x != nil {
+		return x.NamespaceId
+	}
+	return 0
+}
+
+func (x *HandshakeReply) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+// RegisterArgs is namenode.RegisterArgs
+type RegisterArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HostName  string `protobuf:"bytes,1,opt,name=host_name,json=hostName,proto3" json:"host_name,omitempty"`
+	Addr      string `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	StorageId string `protobuf:"bytes,3,opt,name=storage_id,json=storageId,proto3" json:"storage_id,omitempty"`
+	Token     string `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *RegisterArgs) Reset() {
+	*x = RegisterArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterArgs) ProtoMessage() {}
+
+func (x *RegisterArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterArgs.ProtoReflect.Descriptor instead.
+func (*RegisterArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RegisterArgs) GetHostName() string {
+	if x != nil {
+		return x.HostName
+	}
+	return ""
+}
+
+func (x *RegisterArgs) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *RegisterArgs) GetStorageId() string {
+	if x != nil {
+		return x.StorageId
+	}
+	return ""
+}
+
+func (x *RegisterArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// RegisterReply is namenode.RegisterReply
+type RegisterReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StorageId string `protobuf:"bytes,1,opt,name=storage_id,json=storageId,proto3" json:"storage_id,omitempty"`
+}
+
+func (x *RegisterReply) Reset() {
+	*x = RegisterReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterReply) ProtoMessage() {}
+
+func (x *RegisterReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterReply.ProtoReflect.Descriptor instead.
+func (*RegisterReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RegisterReply) GetStorageId() string {
+	if x != nil {
+		return x.StorageId
+	}
+	return ""
+}
+
+// HeartBeatArgs is namenode.HeartBeatArgs
+type HeartBeatArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HostName      string  `protobuf:"bytes,1,opt,name=host_name,json=hostName,proto3" json:"host_name,omitempty"`
+	Addr          string  `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	TotalCapacity uint64  `protobuf:"varint,3,opt,name=total_capacity,json=totalCapacity,proto3" json:"total_capacity,omitempty"`
+	FracInUse     float64 `protobuf:"fixed64,4,opt,name=frac_in_use,json=fracInUse,proto3" json:"frac_in_use,omitempty"`
+	NumDataTrans  int64   `protobuf:"varint,5,opt,name=num_data_trans,json=numDataTrans,proto3" json:"num_data_trans,omitempty"`
+	Token         string  `protobuf:"bytes,6,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *HeartBeatArgs) Reset() {
+	*x = HeartBeatArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HeartBeatArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartBeatArgs) ProtoMessage() {}
+
+func (x *HeartBeatArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartBeatArgs.ProtoReflect.Descriptor instead.
+func (*HeartBeatArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *HeartBeatArgs) GetHostName() string {
+	if x != nil {
+		return x.HostName
+	}
+	return ""
+}
+
+func (x *HeartBeatArgs) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *HeartBeatArgs) GetTotalCapacity() uint64 {
+	if x != nil {
+		return x.TotalCapacity
+	}
+	return 0
+}
+
+func (x *HeartBeatArgs) GetFracInUse() float64 {
+	if x != nil {
+		return x.FracInUse
+	}
+	return 0
+}
+
+func (x *HeartBeatArgs) GetNumDataTrans() int64 {
+	if x != nil {
+		return x.NumDataTrans
+	}
+	return 0
+}
+
+func (x *HeartBeatArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// HeartBeatReply is namenode.HeartBeatReply
+type HeartBeatReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RepBlkToNodes map[string]string `protobuf:"bytes,1,rep,name=rep_blk_to_nodes,json=repBlkToNodes,proto3" json:"rep_blk_to_nodes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	RmBlk         []string          `protobuf:"bytes,2,rep,name=rm_blk,json=rmBlk,proto3" json:"rm_blk,omitempty"`
+	ReRegister    bool              `protobuf:"varint,3,opt,name=re_register,json=reRegister,proto3" json:"re_register,omitempty"`
+	Shutdown      bool              `protobuf:"varint,4,opt,name=shutdown,proto3" json:"shutdown,omitempty"`
+	ReqBlkReport  bool              `protobuf:"varint,5,opt,name=req_blk_report,json=reqBlkReport,proto3" json:"req_blk_report,omitempty"`
+	Format        bool              `protobuf:"varint,6,opt,name=format,proto3" json:"format,omitempty"`
+	FormatId      int64             `protobuf:"varint,7,opt,name=format_id,json=formatId,proto3" json:"format_id,omitempty"`
+}
+
+func (x *HeartBeatReply) Reset() {
+	*x = HeartBeatReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HeartBeatReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartBeatReply) ProtoMessage() {}
+
+func (x *HeartBeatReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartBeatReply.ProtoReflect.Descriptor instead.
+func (*HeartBeatReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *HeartBeatReply) GetRepBlkToNodes() map[string]string {
+	if x != nil {
+		return x.RepBlkToNodes
+	}
+	return nil
+}
+
+func (x *HeartBeatReply) GetRmBlk() []string {
+	if x != nil {
+		return x.RmBlk
+	}
+	return nil
+}
+
+func (x *HeartBeatReply) GetReRegister() bool {
+	if x != nil {
+		return x.ReRegister
+	}
+	return false
+}
+
+func (x *HeartBeatReply) GetShutdown() bool {
+	if x != nil {
+		return x.Shutdown
+	}
+	return false
+}
+
+func (x *HeartBeatReply) GetReqBlkReport() bool {
+	if x != nil {
+		return x.ReqBlkReport
+	}
+	return false
+}
+
+func (x *HeartBeatReply) GetFormat() bool {
+	if x != nil {
+		return x.Format
+	}
+	return false
+}
+
+func (x *HeartBeatReply) GetFormatId() int64 {
+	if x != nil {
+		return x.FormatId
+	}
+	return 0
+}
+
+// ReportBlockArgs is namenode.ReportBlockArgs
+type ReportBlockArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HostName     string               `protobuf:"bytes,1,opt,name=host_name,json=hostName,proto3" json:"host_name,omitempty"`
+	Addr         string               `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	IdToMetaData map[string]*MetaData `protobuf:"bytes,3,rep,name=id_to_meta_data,json=idToMetaData,proto3" json:"id_to_meta_data,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Token        string               `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *ReportBlockArgs) Reset() {
+	*x = ReportBlockArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportBlockArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportBlockArgs) ProtoMessage() {}
+
+func (x *ReportBlockArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportBlockArgs.ProtoReflect.Descriptor instead.
+func (*ReportBlockArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ReportBlockArgs) GetHostName() string {
+	if x != nil {
+		return x.HostName
+	}
+	return ""
+}
+
+func (x *ReportBlockArgs) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *ReportBlockArgs) GetIdToMetaData() map[string]*MetaData {
+	if x != nil {
+		return x.IdToMetaData
+	}
+	return nil
+}
+
+func (x *ReportBlockArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// ReportBlockReply is namenode.ReportBlockReply
+type ReportBlockReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status bool `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *ReportBlockReply) Reset() {
+	*x = ReportBlockReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportBlockReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportBlockReply) ProtoMessage() {}
+
+func (x *ReportBlockReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportBlockReply.ProtoReflect.Descriptor instead.
+func (*ReportBlockReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ReportBlockReply) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+// ReportCorruptBlockArgs is namenode.ReportCorruptBlockArgs
+type ReportCorruptBlockArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Addr   string   `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+	BlkIds []string `protobuf:"bytes,2,rep,name=blk_ids,json=blkIds,proto3" json:"blk_ids,omitempty"`
+	Token  string   `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *ReportCorruptBlockArgs) Reset() {
+	*x = ReportCorruptBlockArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportCorruptBlockArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportCorruptBlockArgs) ProtoMessage() {}
+
+func (x *ReportCorruptBlockArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportCorruptBlockArgs.ProtoReflect.Descriptor instead.
+func (*ReportCorruptBlockArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ReportCorruptBlockArgs) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *ReportCorruptBlockArgs) GetBlkIds() []string {
+	if x != nil {
+		return x.BlkIds
+	}
+	return nil
+}
+
+func (x *ReportCorruptBlockArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// ReportCorruptBlockReply is namenode.ReportCorruptBlockReply
+type ReportCorruptBlockReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status bool `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *ReportCorruptBlockReply) Reset() {
+	*x = ReportCorruptBlockReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportCorruptBlockReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportCorruptBlockReply) ProtoMessage() {}
+
+func (x *ReportCorruptBlockReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportCorruptBlockReply.ProtoReflect.Descriptor instead.
+func (*ReportCorruptBlockReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ReportCorruptBlockReply) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+// MetaData is utils.MetaData
+type MetaData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Checksum     []byte `protobuf:"bytes,1,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	ChecksumType string `protobuf:"bytes,2,opt,name=checksum_type,json=checksumType,proto3" json:"checksum_type,omitempty"`
+	Timestamp    int64  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Length       int64  `protobuf:"varint,4,opt,name=length,proto3" json:"length,omitempty"`
+	GenStamp     int64  `protobuf:"varint,5,opt,name=gen_stamp,json=genStamp,proto3" json:"gen_stamp,omitempty"`
+	Compressed   bool   `protobuf:"varint,6,opt,name=compressed,proto3" json:"compressed,omitempty"`
+}
+
+func (x *MetaData) Reset() {
+	*x = MetaData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetaData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetaData) ProtoMessage() {}
+
+func (x *MetaData) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetaData.ProtoReflect.Descriptor instead.
+func (*MetaData) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *MetaData) GetChecksum() []byte {
+	if x != nil {
+		return x.Checksum
+	}
+	return nil
+}
+
+func (x *MetaData) GetChecksumType() string {
+	if x != nil {
+		return x.ChecksumType
+	}
+	return ""
+}
+
+func (x *MetaData) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *MetaData) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *MetaData) GetGenStamp() int64 {
+	if x != nil {
+		return x.GenStamp
+	}
+	return 0
+}
+
+func (x *MetaData) GetCompressed() bool {
+	if x != nil {
+		return x.Compressed
+	}
+	return false
+}
+
+// Edges is utils.Edges
+type Edges struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Head  string `protobuf:"bytes,1,opt,name=head,proto3" json:"head,omitempty"`
+	Tail  string `protobuf:"bytes,2,opt,name=tail,proto3" json:"tail,omitempty"`
+	Whole bool   `protobuf:"varint,3,opt,name=whole,proto3" json:"whole,omitempty"`
+}
+
+func (x *Edges) Reset() {
+	*x = Edges{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Edges) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Edges) ProtoMessage() {}
+
+func (x *Edges) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Edges.ProtoReflect.Descriptor instead.
+func (*Edges) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Edges) GetHead() string {
+	if x != nil {
+		return x.Head
+	}
+	return ""
+}
+
+func (x *Edges) GetTail() string {
+	if x != nil {
+		return x.Tail
+	}
+	return ""
+}
+
+func (x *Edges) GetWhole() bool {
+	if x != nil {
+		return x.Whole
+	}
+	return false
+}
+
+// MapArgs is mapreduce.MapArgs
+type MapArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Job       string `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	BlkId     string `protobuf:"bytes,2,opt,name=blk_id,json=blkId,proto3" json:"blk_id,omitempty"`
+	JobId     string `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	NumReduce int64  `protobuf:"varint,4,opt,name=num_reduce,json=numReduce,proto3" json:"num_reduce,omitempty"`
+	Token     string `protobuf:"bytes,5,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *MapArgs) Reset() {
+	*x = MapArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MapArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapArgs) ProtoMessage() {}
+
+func (x *MapArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapArgs.ProtoReflect.Descriptor instead.
+func (*MapArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *MapArgs) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *MapArgs) GetBlkId() string {
+	if x != nil {
+		return x.BlkId
+	}
+	return ""
+}
+
+func (x *MapArgs) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *MapArgs) GetNumReduce() int64 {
+	if x != nil {
+		return x.NumReduce
+	}
+	return 0
+}
+
+func (x *MapArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// MapReply is mapreduce.MapReply
+type MapReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result map[string]float64 `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	Edges  *Edges             `protobuf:"bytes,2,opt,name=edges,proto3" json:"edges,omitempty"`
+}
+
+func (x *MapReply) Reset() {
+	*x = MapReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MapReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapReply) ProtoMessage() {}
+
+func (x *MapReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapReply.ProtoReflect.Descriptor instead.
+func (*MapReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *MapReply) GetResult() map[string]float64 {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *MapReply) GetEdges() *Edges {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+// CalMVArgs is utils.CalMVArgs
+type CalMVArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlkId string `protobuf:"bytes,1,opt,name=blk_id,json=blkId,proto3" json:"blk_id,omitempty"`
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *CalMVArgs) Reset() {
+	*x = CalMVArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CalMVArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalMVArgs) ProtoMessage() {}
+
+func (x *CalMVArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalMVArgs.ProtoReflect.Descriptor instead.
+func (*CalMVArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CalMVArgs) GetBlkId() string {
+	if x != nil {
+		return x.BlkId
+	}
+	return ""
+}
+
+func (x *CalMVArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// CalMVReply is utils.CalMVReply
+type CalMVReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cnt    int64   `protobuf:"varint,1,opt,name=cnt,proto3" json:"cnt,omitempty"`
+	Mean   float64 `protobuf:"fixed64,2,opt,name=mean,proto3" json:"mean,omitempty"`
+	MeanSq float64 `protobuf:"fixed64,3,opt,name=mean_sq,json=meanSq,proto3" json:"mean_sq,omitempty"`
+	Edges  *Edges  `protobuf:"bytes,4,opt,name=edges,proto3" json:"edges,omitempty"`
+}
+
+func (x *CalMVReply) Reset() {
+	*x = CalMVReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CalMVReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalMVReply) ProtoMessage() {}
+
+func (x *CalMVReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalMVReply.ProtoReflect.Descriptor instead.
+func (*CalMVReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CalMVReply) GetCnt() int64 {
+	if x != nil {
+		return x.Cnt
+	}
+	return 0
+}
+
+func (x *CalMVReply) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *CalMVReply) GetMeanSq() float64 {
+	if x != nil {
+		return x.MeanSq
+	}
+	return 0
+}
+
+func (x *CalMVReply) GetEdges() *Edges {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+// WordCountArgs is utils.WordCountArgs
+type WordCountArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlkId string `protobuf:"bytes,1,opt,name=blk_id,json=blkId,proto3" json:"blk_id,omitempty"`
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *WordCountArgs) Reset() {
+	*x = WordCountArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WordCountArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WordCountArgs) ProtoMessage() {}
+
+func (x *WordCountArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WordCountArgs.ProtoReflect.Descriptor instead.
+func (*WordCountArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *WordCountArgs) GetBlkId() string {
+	if x != nil {
+		return x.BlkId
+	}
+	return ""
+}
+
+func (x *WordCountArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// WordCountReply is utils.WordCountReply
+type WordCountReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Counts map[string]int64 `protobuf:"bytes,1,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Edges  *Edges           `protobuf:"bytes,2,opt,name=edges,proto3" json:"edges,omitempty"`
+}
+
+func (x *WordCountReply) Reset() {
+	*x = WordCountReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WordCountReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WordCountReply) ProtoMessage() {}
+
+func (x *WordCountReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WordCountReply.ProtoReflect.Descriptor instead.
+func (*WordCountReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *WordCountReply) GetCounts() map[string]int64 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *WordCountReply) GetEdges() *Edges {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+// GrepArgs is utils.GrepArgs
+type GrepArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlkId      string `protobuf:"bytes,1,opt,name=blk_id,json=blkId,proto3" json:"blk_id,omitempty"`
+	Pattern    string `protobuf:"bytes,2,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	IgnoreCase bool   `protobuf:"varint,3,opt,name=ignore_case,json=ignoreCase,proto3" json:"ignore_case,omitempty"`
+	CountOnly  bool   `protobuf:"varint,4,opt,name=count_only,json=countOnly,proto3" json:"count_only,omitempty"`
+	Token      string `protobuf:"bytes,5,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *GrepArgs) Reset() {
+	*x = GrepArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GrepArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrepArgs) ProtoMessage() {}
+
+func (x *GrepArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrepArgs.ProtoReflect.Descriptor instead.
+func (*GrepArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GrepArgs) GetBlkId() string {
+	if x != nil {
+		return x.BlkId
+	}
+	return ""
+}
+
+func (x *GrepArgs) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *GrepArgs) GetIgnoreCase() bool {
+	if x != nil {
+		return x.IgnoreCase
+	}
+	return false
+}
+
+func (x *GrepArgs) GetCountOnly() bool {
+	if x != nil {
+		return x.CountOnly
+	}
+	return false
+}
+
+func (x *GrepArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// GrepReply is utils.GrepReply
+type GrepReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Lines []string `protobuf:"bytes,1,rep,name=lines,proto3" json:"lines,omitempty"`
+	Count int64    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Edges *Edges   `protobuf:"bytes,3,opt,name=edges,proto3" json:"edges,omitempty"`
+}
+
+func (x *GrepReply) Reset() {
+	*x = GrepReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GrepReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrepReply) ProtoMessage() {}
+
+func (x *GrepReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrepReply.ProtoReflect.Descriptor instead.
+func (*GrepReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GrepReply) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *GrepReply) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GrepReply) GetEdges() *Edges {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+// TopNArgs is utils.TopNArgs
+type TopNArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlkId   string `protobuf:"bytes,1,opt,name=blk_id,json=blkId,proto3" json:"blk_id,omitempty"`
+	K       int64  `protobuf:"varint,2,opt,name=k,proto3" json:"k,omitempty"`
+	Lexical bool   `protobuf:"varint,3,opt,name=lexical,proto3" json:"lexical,omitempty"`
+	Token   string `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *TopNArgs) Reset() {
+	*x = TopNArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TopNArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopNArgs) ProtoMessage() {}
+
+func (x *TopNArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopNArgs.ProtoReflect.Descriptor instead.
+func (*TopNArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *TopNArgs) GetBlkId() string {
+	if x != nil {
+		return x.BlkId
+	}
+	return ""
+}
+
+func (x *TopNArgs) GetK() int64 {
+	if x != nil {
+		return x.K
+	}
+	return 0
+}
+
+func (x *TopNArgs) GetLexical() bool {
+	if x != nil {
+		return x.Lexical
+	}
+	return false
+}
+
+func (x *TopNArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// TopNReply is utils.TopNReply
+type TopNReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Top   []string `protobuf:"bytes,1,rep,name=top,proto3" json:"top,omitempty"`
+	Edges *Edges   `protobuf:"bytes,2,opt,name=edges,proto3" json:"edges,omitempty"`
+}
+
+func (x *TopNReply) Reset() {
+	*x = TopNReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TopNReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopNReply) ProtoMessage() {}
+
+func (x *TopNReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopNReply.ProtoReflect.Descriptor instead.
+func (*TopNReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *TopNReply) GetTop() []string {
+	if x != nil {
+		return x.Top
+	}
+	return nil
+}
+
+func (x *TopNReply) GetEdges() *Edges {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+// RequestBlkArgs is datanode.RequestBlkArgs
+type RequestBlkArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlkId    string `protobuf:"bytes,1,opt,name=blk_id,json=blkId,proto3" json:"blk_id,omitempty"`
+	Offset   int64  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length   int64  `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	Compress bool   `protobuf:"varint,4,opt,name=compress,proto3" json:"compress,omitempty"`
+	Token    string `protobuf:"bytes,5,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *RequestBlkArgs) Reset() {
+	*x = RequestBlkArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestBlkArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestBlkArgs) ProtoMessage() {}
+
+func (x *RequestBlkArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestBlkArgs.ProtoReflect.Descriptor instead.
+func (*RequestBlkArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *RequestBlkArgs) GetBlkId() string {
+	if x != nil {
+		return x.BlkId
+	}
+	return ""
+}
+
+func (x *RequestBlkArgs) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *RequestBlkArgs) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *RequestBlkArgs) GetCompress() bool {
+	if x != nil {
+		return x.Compress
+	}
+	return false
+}
+
+func (x *RequestBlkArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// BlkData is utils.BlkData
+type BlkData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlkId        string   `protobuf:"bytes,1,opt,name=blk_id,json=blkId,proto3" json:"blk_id,omitempty"`
+	Data         []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Checksum     []byte   `protobuf:"bytes,3,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Length       int64    `protobuf:"varint,4,opt,name=length,proto3" json:"length,omitempty"`
+	GenStamp     int64    `protobuf:"varint,5,opt,name=gen_stamp,json=genStamp,proto3" json:"gen_stamp,omitempty"`
+	ChecksumType string   `protobuf:"bytes,6,opt,name=checksum_type,json=checksumType,proto3" json:"checksum_type,omitempty"`
+	Targets      []string `protobuf:"bytes,7,rep,name=targets,proto3" json:"targets,omitempty"`
+	Compressed   bool     `protobuf:"varint,8,opt,name=compressed,proto3" json:"compressed,omitempty"`
+	Token        string   `protobuf:"bytes,9,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *BlkData) Reset() {
+	*x = BlkData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlkData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlkData) ProtoMessage() {}
+
+func (x *BlkData) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlkData.ProtoReflect.Descriptor instead.
+func (*BlkData) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *BlkData) GetBlkId() string {
+	if x != nil {
+		return x.BlkId
+	}
+	return ""
+}
+
+func (x *BlkData) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *BlkData) GetChecksum() []byte {
+	if x != nil {
+		return x.Checksum
+	}
+	return nil
+}
+
+func (x *BlkData) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *BlkData) GetGenStamp() int64 {
+	if x != nil {
+		return x.GenStamp
+	}
+	return 0
+}
+
+func (x *BlkData) GetChecksumType() string {
+	if x != nil {
+		return x.ChecksumType
+	}
+	return ""
+}
+
+func (x *BlkData) GetTargets() []string {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *BlkData) GetCompressed() bool {
+	if x != nil {
+		return x.Compressed
+	}
+	return false
+}
+
+func (x *BlkData) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// RequestBlksArgs is datanode.RequestBlksArgs
+type RequestBlksArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlkIds   []string `protobuf:"bytes,1,rep,name=blk_ids,json=blkIds,proto3" json:"blk_ids,omitempty"`
+	Compress bool     `protobuf:"varint,2,opt,name=compress,proto3" json:"compress,omitempty"`
+	Token    string   `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *RequestBlksArgs) Reset() {
+	*x = RequestBlksArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestBlksArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestBlksArgs) ProtoMessage() {}
+
+func (x *RequestBlksArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestBlksArgs.ProtoReflect.Descriptor instead.
+func (*RequestBlksArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *RequestBlksArgs) GetBlkIds() []string {
+	if x != nil {
+		return x.BlkIds
+	}
+	return nil
+}
+
+func (x *RequestBlksArgs) GetCompress() bool {
+	if x != nil {
+		return x.Compress
+	}
+	return false
+}
+
+func (x *RequestBlksArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// RequestBlksReply is datanode.RequestBlksReply
+type RequestBlksReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blks   []*BlkData `protobuf:"bytes,1,rep,name=blks,proto3" json:"blks,omitempty"`
+	Errors []string   `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *RequestBlksReply) Reset() {
+	*x = RequestBlksReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestBlksReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestBlksReply) ProtoMessage() {}
+
+func (x *RequestBlksReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestBlksReply.ProtoReflect.Descriptor instead.
+func (*RequestBlksReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *RequestBlksReply) GetBlks() []*BlkData {
+	if x != nil {
+		return x.Blks
+	}
+	return nil
+}
+
+func (x *RequestBlksReply) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+// SendBlkReply is datanode.SendBlkReply
+type SendBlkReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status bool     `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Acked  []string `protobuf:"bytes,2,rep,name=acked,proto3" json:"acked,omitempty"`
+}
+
+func (x *SendBlkReply) Reset() {
+	*x = SendBlkReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendBlkReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendBlkReply) ProtoMessage() {}
+
+func (x *SendBlkReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendBlkReply.ProtoReflect.Descriptor instead.
+func (*SendBlkReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *SendBlkReply) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+func (x *SendBlkReply) GetAcked() []string {
+	if x != nil {
+		return x.Acked
+	}
+	return nil
+}
+
+// BlkChunk is utils.BlkChunk
+type BlkChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlkId        string   `protobuf:"bytes,1,opt,name=blk_id,json=blkId,proto3" json:"blk_id,omitempty"`
+	Offset       int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data         []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Last         bool     `protobuf:"varint,4,opt,name=last,proto3" json:"last,omitempty"`
+	Checksum     []byte   `protobuf:"bytes,5,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	GenStamp     int64    `protobuf:"varint,6,opt,name=gen_stamp,json=genStamp,proto3" json:"gen_stamp,omitempty"`
+	Targets      []string `protobuf:"bytes,7,rep,name=targets,proto3" json:"targets,omitempty"`
+	ChecksumType string   `protobuf:"bytes,8,opt,name=checksum_type,json=checksumType,proto3" json:"checksum_type,omitempty"`
+	Compressed   bool     `protobuf:"varint,9,opt,name=compressed,proto3" json:"compressed,omitempty"`
+	Token        string   `protobuf:"bytes,10,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *BlkChunk) Reset() {
+	*x = BlkChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlkChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlkChunk) ProtoMessage() {}
+
+func (x *BlkChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlkChunk.ProtoReflect.Descriptor instead.
+func (*BlkChunk) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *BlkChunk) GetBlkId() string {
+	if x != nil {
+		return x.BlkId
+	}
+	return ""
+}
+
+func (x *BlkChunk) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *BlkChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *BlkChunk) GetLast() bool {
+	if x != nil {
+		return x.Last
+	}
+	return false
+}
+
+func (x *BlkChunk) GetChecksum() []byte {
+	if x != nil {
+		return x.Checksum
+	}
+	return nil
+}
+
+func (x *BlkChunk) GetGenStamp() int64 {
+	if x != nil {
+		return x.GenStamp
+	}
+	return 0
+}
+
+func (x *BlkChunk) GetTargets() []string {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *BlkChunk) GetChecksumType() string {
+	if x != nil {
+		return x.ChecksumType
+	}
+	return ""
+}
+
+func (x *BlkChunk) GetCompressed() bool {
+	if x != nil {
+		return x.Compressed
+	}
+	return false
+}
+
+func (x *BlkChunk) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// StatBlkArgs is datanode.StatBlkArgs
+type StatBlkArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlkId string `protobuf:"bytes,1,opt,name=blk_id,json=blkId,proto3" json:"blk_id,omitempty"`
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *StatBlkArgs) Reset() {
+	*x = StatBlkArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatBlkArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatBlkArgs) ProtoMessage() {}
+
+func (x *StatBlkArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatBlkArgs.ProtoReflect.Descriptor instead.
+func (*StatBlkArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *StatBlkArgs) GetBlkId() string {
+	if x != nil {
+		return x.BlkId
+	}
+	return ""
+}
+
+func (x *StatBlkArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// TruncateBlkArgs is utils.TruncateBlkArgs
+type TruncateBlkArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlkId    string `protobuf:"bytes,1,opt,name=blk_id,json=blkId,proto3" json:"blk_id,omitempty"`
+	Length   int64  `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
+	GenStamp int64  `protobuf:"varint,3,opt,name=gen_stamp,json=genStamp,proto3" json:"gen_stamp,omitempty"`
+	Token    string `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *TruncateBlkArgs) Reset() {
+	*x = TruncateBlkArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TruncateBlkArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TruncateBlkArgs) ProtoMessage() {}
+
+func (x *TruncateBlkArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TruncateBlkArgs.ProtoReflect.Descriptor instead.
+func (*TruncateBlkArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *TruncateBlkArgs) GetBlkId() string {
+	if x != nil {
+		return x.BlkId
+	}
+	return ""
+}
+
+func (x *TruncateBlkArgs) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *TruncateBlkArgs) GetGenStamp() int64 {
+	if x != nil {
+		return x.GenStamp
+	}
+	return 0
+}
+
+func (x *TruncateBlkArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// DeleteBlkArgs is utils.DeleteBlkArgs
+type DeleteBlkArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlkId string `protobuf:"bytes,1,opt,name=blk_id,json=blkId,proto3" json:"blk_id,omitempty"`
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *DeleteBlkArgs) Reset() {
+	*x = DeleteBlkArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteBlkArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBlkArgs) ProtoMessage() {}
+
+func (x *DeleteBlkArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBlkArgs.ProtoReflect.Descriptor instead.
+func (*DeleteBlkArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *DeleteBlkArgs) GetBlkId() string {
+	if x != nil {
+		return x.BlkId
+	}
+	return ""
+}
+
+func (x *DeleteBlkArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// DeleteBlkReply is utils.DeleteBlkReply
+type DeleteBlkReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status bool `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *DeleteBlkReply) Reset() {
+	*x = DeleteBlkReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteBlkReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBlkReply) ProtoMessage() {}
+
+func (x *DeleteBlkReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBlkReply.ProtoReflect.Descriptor instead.
+func (*DeleteBlkReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *DeleteBlkReply) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+// FetchArgs is mapreduce.FetchArgs
+type FetchArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId     string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	BlkId     string `protobuf:"bytes,2,opt,name=blk_id,json=blkId,proto3" json:"blk_id,omitempty"`
+	Partition int64  `protobuf:"varint,3,opt,name=partition,proto3" json:"partition,omitempty"`
+	Token     string `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *FetchArgs) Reset() {
+	*x = FetchArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchArgs) ProtoMessage() {}
+
+func (x *FetchArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchArgs.ProtoReflect.Descriptor instead.
+func (*FetchArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *FetchArgs) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *FetchArgs) GetBlkId() string {
+	if x != nil {
+		return x.BlkId
+	}
+	return ""
+}
+
+func (x *FetchArgs) GetPartition() int64 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *FetchArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// FetchReply is mapreduce.FetchReply
+type FetchReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result map[string]float64 `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+}
+
+func (x *FetchReply) Reset() {
+	*x = FetchReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchReply) ProtoMessage() {}
+
+func (x *FetchReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchReply.ProtoReflect.Descriptor instead.
+func (*FetchReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *FetchReply) GetResult() map[string]float64 {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// ReduceArgs is mapreduce.ReduceArgs
+type ReduceArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId     string       `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Job       string       `protobuf:"bytes,2,opt,name=job,proto3" json:"job,omitempty"`
+	Partition int64        `protobuf:"varint,3,opt,name=partition,proto3" json:"partition,omitempty"`
+	Sources   []*MapSource `protobuf:"bytes,4,rep,name=sources,proto3" json:"sources,omitempty"`
+	Extra     []*Result    `protobuf:"bytes,5,rep,name=extra,proto3" json:"extra,omitempty"`
+	Token     string       `protobuf:"bytes,6,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *ReduceArgs) Reset() {
+	*x = ReduceArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReduceArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReduceArgs) ProtoMessage() {}
+
+func (x *ReduceArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReduceArgs.ProtoReflect.Descriptor instead.
+func (*ReduceArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ReduceArgs) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *ReduceArgs) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *ReduceArgs) GetPartition() int64 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *ReduceArgs) GetSources() []*MapSource {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *ReduceArgs) GetExtra() []*Result {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+func (x *ReduceArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// Result is mapreduce.Result
+type Result struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values map[string]float64 `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *Result) GetValues() map[string]float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// MapSource is mapreduce.MapSource
+type MapSource struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlkId string `protobuf:"bytes,1,opt,name=blk_id,json=blkId,proto3" json:"blk_id,omitempty"`
+	Addr  string `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+}
+
+func (x *MapSource) Reset() {
+	*x = MapSource{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MapSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapSource) ProtoMessage() {}
+
+func (x *MapSource) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapSource.ProtoReflect.Descriptor instead.
+func (*MapSource) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *MapSource) GetBlkId() string {
+	if x != nil {
+		return x.BlkId
+	}
+	return ""
+}
+
+func (x *MapSource) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+// ReduceReply is mapreduce.ReduceReply
+type ReduceReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Size int64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+}
+
+func (x *ReduceReply) Reset() {
+	*x = ReduceReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReduceReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReduceReply) ProtoMessage() {}
+
+func (x *ReduceReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReduceReply.ProtoReflect.Descriptor instead.
+func (*ReduceReply) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ReduceReply) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// CommitArgs is mapreduce.CommitArgs
+type CommitArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId          string              `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Partition      int64               `protobuf:"varint,2,opt,name=partition,proto3" json:"partition,omitempty"`
+	BlkList        []string            `protobuf:"bytes,3,rep,name=blk_list,json=blkList,proto3" json:"blk_list,omitempty"`
+	BlkSize        int64               `protobuf:"varint,4,opt,name=blk_size,json=blkSize,proto3" json:"blk_size,omitempty"`
+	BlkToDataNodes map[string]*Strings `protobuf:"bytes,5,rep,name=blk_to_data_nodes,json=blkToDataNodes,proto3" json:"blk_to_data_nodes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	GenStamp       int64               `protobuf:"varint,6,opt,name=gen_stamp,json=genStamp,proto3" json:"gen_stamp,omitempty"`
+	Token          string              `protobuf:"bytes,7,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *CommitArgs) Reset() {
+	*x = CommitArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommitArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitArgs) ProtoMessage() {}
+
+func (x *CommitArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitArgs.ProtoReflect.Descriptor instead.
+func (*CommitArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *CommitArgs) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *CommitArgs) GetPartition() int64 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *CommitArgs) GetBlkList() []string {
+	if x != nil {
+		return x.BlkList
+	}
+	return nil
+}
+
+func (x *CommitArgs) GetBlkSize() int64 {
+	if x != nil {
+		return x.BlkSize
+	}
+	return 0
+}
+
+func (x *CommitArgs) GetBlkToDataNodes() map[string]*Strings {
+	if x != nil {
+		return x.BlkToDataNodes
+	}
+	return nil
+}
+
+func (x *CommitArgs) GetGenStamp() int64 {
+	if x != nil {
+		return x.GenStamp
+	}
+	return 0
+}
+
+func (x *CommitArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// EndJobArgs is mapreduce.JobArgs
+type EndJobArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *EndJobArgs) Reset() {
+	*x = EndJobArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gdfs_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EndJobArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndJobArgs) ProtoMessage() {}
+
+func (x *EndJobArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_gdfs_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndJobArgs.ProtoReflect.Descriptor instead.
+func (*EndJobArgs) Descriptor() ([]byte, []int) {
+	return file_gdfs_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *EndJobArgs) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *EndJobArgs) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+var File_gdfs_proto protoreflect.FileDescriptor
+
+var file_gdfs_proto_rawDesc = []byte{
+	0x0a, 0x0a, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x67, 0x64,
+	0x66, 0x73, 0x22, 0x21, 0x0a, 0x07, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0xd9, 0x05, 0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x41, 0x72, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x63, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x64, 0x70, 0x61, 0x74, 0x68, 0x12, 0x16,
+	0x0a, 0x06, 0x64, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06,
+	0x64, 0x70, 0x61, 0x74, 0x68, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x69, 0x7a, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x6f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x12, 0x1d,
+	0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x20, 0x0a,
+	0x0b, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6a, 0x6f, 0x62,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x12, 0x1d, 0x0a, 0x0a, 0x6e,
+	0x75, 0x6d, 0x5f, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x6e, 0x75, 0x6d, 0x52, 0x65, 0x64, 0x75, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x75,
+	0x72, 0x73, 0x69, 0x76, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x72, 0x65, 0x63,
+	0x75, 0x72, 0x73, 0x69, 0x76, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x18, 0x0f,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x64,
+	0x64, 0x72, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x64, 0x64, 0x72, 0x12, 0x12,
+	0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73,
+	0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x74, 0x72, 0x61, 0x73, 0x68,
+	0x18, 0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x6b, 0x69, 0x70, 0x54, 0x72, 0x61, 0x73,
+	0x68, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x18, 0x13, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x18, 0x14, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12,
+	0x23, 0x0a, 0x0d, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x6c, 0x6b, 0x5f, 0x6c, 0x69, 0x73, 0x74,
+	0x18, 0x16, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x62, 0x6c, 0x6b, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x18, 0x17, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x67, 0x6e,
+	0x6f, 0x72, 0x65, 0x5f, 0x63, 0x61, 0x73, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x43, 0x61, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x19, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x0c, 0x0a, 0x01, 0x6b, 0x18, 0x1a,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x01, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x65, 0x78, 0x69, 0x63,
+	0x61, 0x6c, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x6c, 0x65, 0x78, 0x69, 0x63, 0x61,
+	0x6c, 0x22, 0xbe, 0x0a, 0x0a, 0x0c, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69,
+	0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x6c, 0x6b, 0x5f, 0x6c, 0x69,
+	0x73, 0x74, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x62, 0x6c, 0x6b, 0x4c, 0x69, 0x73,
+	0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x6c, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x07, 0x62, 0x6c, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x66, 0x69, 0x6c, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x08, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x6c, 0x6b,
+	0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x03, 0x52, 0x0a,
+	0x62, 0x6c, 0x6b, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x73, 0x12, 0x20, 0x0a, 0x0c, 0x73, 0x72,
+	0x63, 0x5f, 0x62, 0x6c, 0x6b, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0a, 0x73, 0x72, 0x63, 0x42, 0x6c, 0x6b, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x51, 0x0a, 0x11,
+	0x62, 0x6c, 0x6b, 0x5f, 0x74, 0x6f, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x6e, 0x6f, 0x64, 0x65,
+	0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x43,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x42, 0x6c, 0x6b, 0x54,
+	0x6f, 0x44, 0x61, 0x74, 0x61, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x0e, 0x62, 0x6c, 0x6b, 0x54, 0x6f, 0x44, 0x61, 0x74, 0x61, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12,
+	0x1b, 0x0a, 0x09, 0x67, 0x65, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x67, 0x65, 0x6e, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x36, 0x0a, 0x06,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x67,
+	0x64, 0x66, 0x73, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x12, 0x24, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x0c, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x12, 0x3f, 0x0a, 0x09, 0x63, 0x68,
+	0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e,
+	0x67, 0x64, 0x66, 0x73, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x09, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x73, 0x12, 0x43, 0x0a, 0x0b, 0x77,
+	0x6f, 0x72, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x22, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x2e, 0x57, 0x6f, 0x72, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73,
+	0x12, 0x40, 0x0a, 0x0a, 0x6a, 0x6f, 0x62, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x0f,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x43, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x6a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x25, 0x0a, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x18, 0x10, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x44, 0x69, 0x73, 0x6b, 0x55, 0x73, 0x61,
+	0x67, 0x65, 0x52, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x12, 0x24, 0x0a, 0x04, 0x66, 0x73, 0x63,
+	0x6b, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x46,
+	0x73, 0x63, 0x6b, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x04, 0x66, 0x73, 0x63, 0x6b, 0x12,
+	0x39, 0x0a, 0x07, 0x74, 0x72, 0x61, 0x73, 0x68, 0x65, 0x64, 0x18, 0x12, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x2e, 0x54, 0x72, 0x61, 0x73, 0x68, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x07, 0x74, 0x72, 0x61, 0x73, 0x68, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x06, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x73, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x67, 0x64, 0x66,
+	0x73, 0x2e, 0x44, 0x69, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64,
+	0x18, 0x14, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x15, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x16, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c,
+	0x69, 0x6e, 0x65, 0x73, 0x18, 0x17, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x69, 0x6e, 0x65,
+	0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x75, 0x6d, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x18,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6e, 0x75, 0x6d, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x1a, 0x50,
+	0x0a, 0x13, 0x42, 0x6c, 0x6b, 0x54, 0x6f, 0x44, 0x61, 0x74, 0x61, 0x4e, 0x6f, 0x64, 0x65, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x23, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x53, 0x74,
+	0x72, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x1a, 0x39, 0x0a, 0x0b, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3c, 0x0a, 0x0e, 0x43,
+	0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3d, 0x0a, 0x0f, 0x57, 0x6f, 0x72,
+	0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3c, 0x0a, 0x0e, 0x4a, 0x6f, 0x62, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3a, 0x0a, 0x0c, 0x54, 0x72, 0x61, 0x73, 0x68, 0x65,
+	0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0xdd, 0x01, 0x0a, 0x08, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x12, 0x15, 0x0a, 0x06, 0x69, 0x73, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x05, 0x69, 0x73, 0x44, 0x69, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x19,
+	0x0a, 0x08, 0x6e, 0x75, 0x6d, 0x5f, 0x62, 0x6c, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x07, 0x6e, 0x75, 0x6d, 0x42, 0x6c, 0x6b, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x6c, 0x6b,
+	0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x62, 0x6c, 0x6b,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x6f, 0x64, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6d, 0x6f, 0x64, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x69,
+	0x6d, 0x65, 0x22, 0x53, 0x0a, 0x09, 0x44, 0x69, 0x73, 0x6b, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x72, 0x65, 0x70,
+	0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x22, 0x66, 0x0a, 0x0a, 0x46, 0x73, 0x63, 0x6b, 0x52,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x62,
+	0x6c, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x62, 0x6c, 0x6b, 0x73, 0x12,
+	0x2e, 0x0a, 0x09, 0x75, 0x6e, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x48, 0x65,
+	0x61, 0x6c, 0x74, 0x68, 0x52, 0x09, 0x75, 0x6e, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x22,
+	0xc3, 0x01, 0x0a, 0x0a, 0x46, 0x69, 0x6c, 0x65, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x75, 0x6d, 0x5f, 0x62, 0x6c, 0x6b, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6e, 0x75, 0x6d, 0x42, 0x6c, 0x6b, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x12, 0x29, 0x0a, 0x10, 0x75, 0x6e, 0x64, 0x65, 0x72,
+	0x5f, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0f, 0x75, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x6f, 0x76, 0x65, 0x72, 0x5f, 0x72, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x6f, 0x76, 0x65,
+	0x72, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x6f, 0x72, 0x72, 0x75, 0x70, 0x74, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f,
+	0x72, 0x72, 0x75, 0x70, 0x74, 0x22, 0xc1, 0x01, 0x0a, 0x08, 0x44, 0x69, 0x72, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x69, 0x72, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x64, 0x69, 0x72, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69,
+	0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x73, 0x5f, 0x71, 0x75, 0x6f,
+	0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6e, 0x73, 0x51, 0x75, 0x6f, 0x74,
+	0x61, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x5f, 0x71, 0x75, 0x6f, 0x74, 0x61,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x73, 0x70, 0x61, 0x63, 0x65, 0x51, 0x75, 0x6f,
+	0x74, 0x61, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x73,
+	0x75, 0x6d, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x22, 0x27, 0x0a, 0x0e, 0x53, 0x75, 0x62,
+	0x6d, 0x69, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x15, 0x0a, 0x06, 0x6a,
+	0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62,
+	0x49, 0x64, 0x22, 0x36, 0x0a, 0x07, 0x4a, 0x6f, 0x62, 0x41, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a,
+	0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a,
+	0x6f, 0x62, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xe1, 0x03, 0x0a, 0x0e, 0x4a,
+	0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x15, 0x0a,
+	0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a,
+	0x6f, 0x62, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x35, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1f, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x64, 0x75, 0x63,
+	0x65, 0x5f, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x72,
+	0x65, 0x64, 0x75, 0x63, 0x65, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x65,
+	0x6e, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x72, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x65, 0x72, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x42, 0x0a, 0x0a,
+	0x6a, 0x6f, 0x62, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x23, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x6a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x1a, 0x38, 0x0a, 0x0a, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x1a, 0x3c, 0x0a, 0x0e, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x21,
+	0x0a, 0x09, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x41, 0x72, 0x67, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x22, 0x8a, 0x01, 0x0a, 0x12, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61,
+	0x63, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61,
+	0x63, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x04, 0x75, 0x73, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x6d, 0x61,
+	0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x72, 0x65, 0x6d,
+	0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x26, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x4e, 0x6f, 0x64,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x22, 0xa7,
+	0x02, 0x0a, 0x0a, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x61, 0x64, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x64, 0x64,
+	0x72, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x49, 0x64, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12,
+	0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x75,
+	0x73, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e,
+	0x67, 0x12, 0x1e, 0x0a, 0x0b, 0x66, 0x72, 0x61, 0x63, 0x5f, 0x69, 0x6e, 0x5f, 0x75, 0x73, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x66, 0x72, 0x61, 0x63, 0x49, 0x6e, 0x55, 0x73,
+	0x65, 0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x68, 0x65, 0x61, 0x72, 0x74, 0x5f,
+	0x62, 0x65, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74,
+	0x48, 0x65, 0x61, 0x72, 0x74, 0x42, 0x65, 0x61, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x61,
+	0x64, 0x5f, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x64,
+	0x65, 0x61, 0x64, 0x53, 0x69, 0x6e, 0x63, 0x65, 0x22, 0x3a, 0x0a, 0x10, 0x52, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x26, 0x0a, 0x05,
+	0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x64,
+	0x66, 0x73, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x05, 0x6e,
+	0x6f, 0x64, 0x65, 0x73, 0x22, 0x38, 0x0a, 0x0a, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x41, 0x72,
+	0x67, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x64, 0x64, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x05, 0x61, 0x64, 0x64, 0x72, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x25,
+	0x0a, 0x0b, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x93, 0x01, 0x0a, 0x0d, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x68,
+	0x61, 0x6b, 0x65, 0x41, 0x72, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x6e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x64,
+	0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x64, 0x64, 0x72, 0x12, 0x1b,
+	0x0a, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x4d, 0x0a, 0x0e, 0x48,
+	0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x21, 0x0a,
+	0x0c, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0b, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x74, 0x0a, 0x0c, 0x52, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x41, 0x72, 0x67, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x6f,
+	0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68,
+	0x6f, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x64, 0x64, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x64, 0x64, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x22, 0x2e, 0x0a, 0x0d, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x49, 0x64,
+	0x22, 0xc3, 0x01, 0x0a, 0x0d, 0x48, 0x65, 0x61, 0x72, 0x74, 0x42, 0x65, 0x61, 0x74, 0x41, 0x72,
+	0x67, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61,
+	0x64, 0x64, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x61, 0x70,
+	0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x1e, 0x0a, 0x0b, 0x66, 0x72,
+	0x61, 0x63, 0x5f, 0x69, 0x6e, 0x5f, 0x75, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x09, 0x66, 0x72, 0x61, 0x63, 0x49, 0x6e, 0x55, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x6e, 0x75,
+	0x6d, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0c, 0x6e, 0x75, 0x6d, 0x44, 0x61, 0x74, 0x61, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xd3, 0x02, 0x0a, 0x0e, 0x48, 0x65, 0x61, 0x72, 0x74,
+	0x42, 0x65, 0x61, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x50, 0x0a, 0x10, 0x72, 0x65, 0x70,
+	0x5f, 0x62, 0x6c, 0x6b, 0x5f, 0x74, 0x6f, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x48, 0x65, 0x61, 0x72, 0x74,
+	0x42, 0x65, 0x61, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x42, 0x6c, 0x6b,
+	0x54, 0x6f, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x72, 0x65,
+	0x70, 0x42, 0x6c, 0x6b, 0x54, 0x6f, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x72,
+	0x6d, 0x5f, 0x62, 0x6c, 0x6b, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x72, 0x6d, 0x42,
+	0x6c, 0x6b, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x72, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x12,
+	0x24, 0x0a, 0x0e, 0x72, 0x65, 0x71, 0x5f, 0x62, 0x6c, 0x6b, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x72, 0x65, 0x71, 0x42, 0x6c, 0x6b, 0x52,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x1b, 0x0a,
+	0x09, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x49, 0x64, 0x1a, 0x40, 0x0a, 0x12, 0x52, 0x65,
+	0x70, 0x42, 0x6c, 0x6b, 0x54, 0x6f, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xf9, 0x01, 0x0a,
+	0x0f, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x41, 0x72, 0x67, 0x73,
+	0x12, 0x1b, 0x0a, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x64, 0x64,
+	0x72, 0x12, 0x4e, 0x0a, 0x0f, 0x69, 0x64, 0x5f, 0x74, 0x6f, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x5f,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x67, 0x64, 0x66,
+	0x73, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x41, 0x72, 0x67,
+	0x73, 0x2e, 0x49, 0x64, 0x54, 0x6f, 0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x0c, 0x69, 0x64, 0x54, 0x6f, 0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74,
+	0x61, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x1a, 0x4f, 0x0a, 0x11, 0x49, 0x64, 0x54, 0x6f, 0x4d,
+	0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x24,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x67, 0x64, 0x66, 0x73, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x2a, 0x0a, 0x10, 0x52, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x22, 0x5b, 0x0a, 0x16, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x6f,
+	0x72, 0x72, 0x75, 0x70, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x41, 0x72, 0x67, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x61, 0x64, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x64,
+	0x64, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x62, 0x6c, 0x6b, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x06, 0x62, 0x6c, 0x6b, 0x49, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x22, 0x31, 0x0a, 0x17, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x6f, 0x72, 0x72, 0x75,
+	0x70, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x22, 0xbe, 0x01, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74,
+	0x61, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x12, 0x23, 0x0a,
+	0x0d, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x65, 0x6e, 0x5f,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x67, 0x65, 0x6e,
+	0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72,
+	0x65, 0x73, 0x73, 0x65, 0x64, 0x22, 0x45, 0x0a, 0x05, 0x45, 0x64, 0x67, 0x65, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x68, 0x65, 0x61, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x65,
+	0x61, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x68, 0x6f, 0x6c, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x77, 0x68, 0x6f, 0x6c, 0x65, 0x22, 0x7e, 0x0a, 0x07,
+	0x4d, 0x61, 0x70, 0x41, 0x72, 0x67, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x12, 0x15, 0x0a, 0x06, 0x62, 0x6c, 0x6b,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x6c, 0x6b, 0x49, 0x64,
+	0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x75, 0x6d, 0x5f, 0x72,
+	0x65, 0x64, 0x75, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6e, 0x75, 0x6d,
+	0x52, 0x65, 0x64, 0x75, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x9c, 0x01, 0x0a,
+	0x08, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x32, 0x0a, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x64, 0x66, 0x73,
+	0x2e, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x21, 0x0a,
+	0x05, 0x65, 0x64, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x67,
+	0x64, 0x66, 0x73, 0x2e, 0x45, 0x64, 0x67, 0x65, 0x73, 0x52, 0x05, 0x65, 0x64, 0x67, 0x65, 0x73,
+	0x1a, 0x39, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x38, 0x0a, 0x09, 0x43,
+	0x61, 0x6c, 0x4d, 0x56, 0x41, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x62, 0x6c, 0x6b, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x6c, 0x6b, 0x49, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x6e, 0x0a, 0x0a, 0x43, 0x61, 0x6c, 0x4d, 0x56, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x03, 0x63, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x65, 0x61,
+	0x6e, 0x5f, 0x73, 0x71, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6d, 0x65, 0x61, 0x6e,
+	0x53, 0x71, 0x12, 0x21, 0x0a, 0x05, 0x65, 0x64, 0x67, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0b, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x45, 0x64, 0x67, 0x65, 0x73, 0x52, 0x05,
+	0x65, 0x64, 0x67, 0x65, 0x73, 0x22, 0x3c, 0x0a, 0x0d, 0x57, 0x6f, 0x72, 0x64, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x41, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x62, 0x6c, 0x6b, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x6c, 0x6b, 0x49, 0x64, 0x12, 0x14, 0x0a,
+	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x22, 0xa8, 0x01, 0x0a, 0x0e, 0x57, 0x6f, 0x72, 0x64, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x38, 0x0a, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x57, 0x6f,
+	0x72, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73,
+	0x12, 0x21, 0x0a, 0x05, 0x65, 0x64, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0b, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x45, 0x64, 0x67, 0x65, 0x73, 0x52, 0x05, 0x65, 0x64,
+	0x67, 0x65, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x91,
+	0x01, 0x0a, 0x08, 0x47, 0x72, 0x65, 0x70, 0x41, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x62,
+	0x6c, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x6c, 0x6b,
+	0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x1f, 0x0a, 0x0b,
+	0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x5f, 0x63, 0x61, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0a, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x43, 0x61, 0x73, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x09, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x22, 0x5a, 0x0a, 0x09, 0x47, 0x72, 0x65, 0x70, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05,
+	0x6c, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x05, 0x65,
+	0x64, 0x67, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x67, 0x64, 0x66,
+	0x73, 0x2e, 0x45, 0x64, 0x67, 0x65, 0x73, 0x52, 0x05, 0x65, 0x64, 0x67, 0x65, 0x73, 0x22, 0x5f,
+	0x0a, 0x08, 0x54, 0x6f, 0x70, 0x4e, 0x41, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x62, 0x6c,
+	0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x6c, 0x6b, 0x49,
+	0x64, 0x12, 0x0c, 0x0a, 0x01, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x01, 0x6b, 0x12,
+	0x18, 0x0a, 0x07, 0x6c, 0x65, 0x78, 0x69, 0x63, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x6c, 0x65, 0x78, 0x69, 0x63, 0x61, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22,
+	0x40, 0x0a, 0x09, 0x54, 0x6f, 0x70, 0x4e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x74, 0x6f, 0x70, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x74, 0x6f, 0x70, 0x12, 0x21,
+	0x0a, 0x05, 0x65, 0x64, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
+	0x67, 0x64, 0x66, 0x73, 0x2e, 0x45, 0x64, 0x67, 0x65, 0x73, 0x52, 0x05, 0x65, 0x64, 0x67, 0x65,
+	0x73, 0x22, 0x89, 0x01, 0x0a, 0x0e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6b,
+	0x41, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x62, 0x6c, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x6c, 0x6b, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x63,
+	0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x63,
+	0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xfa, 0x01,
+	0x0a, 0x07, 0x42, 0x6c, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x12, 0x15, 0x0a, 0x06, 0x62, 0x6c, 0x6b,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x6c, 0x6b, 0x49, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d,
+	0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x65, 0x6e, 0x5f,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x67, 0x65, 0x6e,
+	0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75,
+	0x6d, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x68,
+	0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x5c, 0x0a, 0x0f, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6b, 0x73, 0x41, 0x72, 0x67, 0x73, 0x12, 0x17, 0x0a,
+	0x07, 0x62, 0x6c, 0x6b, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06,
+	0x62, 0x6c, 0x6b, 0x49, 0x64, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x4d, 0x0a, 0x10, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x42, 0x6c, 0x6b, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x21, 0x0a, 0x04,
+	0x62, 0x6c, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x67, 0x64, 0x66,
+	0x73, 0x2e, 0x42, 0x6c, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x52, 0x04, 0x62, 0x6c, 0x6b, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x3c, 0x0a, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x42,
+	0x6c, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05,
+	0x61, 0x63, 0x6b, 0x65, 0x64, 0x22, 0x8f, 0x02, 0x0a, 0x08, 0x42, 0x6c, 0x6b, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x12, 0x15, 0x0a, 0x06, 0x62, 0x6c, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x62, 0x6c, 0x6b, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x65, 0x6e, 0x5f, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x67, 0x65, 0x6e, 0x53, 0x74, 0x61,
+	0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x07, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d,
+	0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x3a, 0x0a, 0x0b, 0x53, 0x74, 0x61, 0x74, 0x42,
+	0x6c, 0x6b, 0x41, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x62, 0x6c, 0x6b, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x6c, 0x6b, 0x49, 0x64, 0x12, 0x14, 0x0a,
+	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x22, 0x73, 0x0a, 0x0f, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x42,
+	0x6c, 0x6b, 0x41, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x62, 0x6c, 0x6b, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x6c, 0x6b, 0x49, 0x64, 0x12, 0x16, 0x0a,
+	0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6c,
+	0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x65, 0x6e, 0x5f, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x67, 0x65, 0x6e, 0x53, 0x74, 0x61,
+	0x6d, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x3c, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x42, 0x6c, 0x6b, 0x41, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x62, 0x6c, 0x6b,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x6c, 0x6b, 0x49, 0x64,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x28, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x42, 0x6c, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x22, 0x6d, 0x0a, 0x09, 0x46, 0x65, 0x74, 0x63, 0x68, 0x41, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a,
+	0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a,
+	0x6f, 0x62, 0x49, 0x64, 0x12, 0x15, 0x0a, 0x06, 0x62, 0x6c, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x6c, 0x6b, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x70,
+	0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22,
+	0x7d, 0x0a, 0x0a, 0x46, 0x65, 0x74, 0x63, 0x68, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x34, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
+	0x67, 0x64, 0x66, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x1a, 0x39, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xb8,
+	0x01, 0x0a, 0x0a, 0x52, 0x65, 0x64, 0x75, 0x63, 0x65, 0x41, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a,
+	0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a,
+	0x6f, 0x62, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x4d, 0x61, 0x70,
+	0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12,
+	0x22, 0x0a, 0x05, 0x65, 0x78, 0x74, 0x72, 0x61, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x05, 0x65, 0x78,
+	0x74, 0x72, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x75, 0x0a, 0x06, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x30, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0x36, 0x0a, 0x09, 0x4d, 0x61, 0x70, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x15, 0x0a,
+	0x06, 0x62, 0x6c, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62,
+	0x6c, 0x6b, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x61, 0x64, 0x64, 0x72, 0x22, 0x21, 0x0a, 0x0b, 0x52, 0x65, 0x64, 0x75,
+	0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0xcd, 0x02, 0x0a, 0x0a,
+	0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x41, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f,
+	0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49,
+	0x64, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x19, 0x0a, 0x08, 0x62, 0x6c, 0x6b, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x07, 0x62, 0x6c, 0x6b, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x6c,
+	0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x62, 0x6c,
+	0x6b, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x4f, 0x0a, 0x11, 0x62, 0x6c, 0x6b, 0x5f, 0x74, 0x6f, 0x5f,
+	0x64, 0x61, 0x74, 0x61, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x24, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x41, 0x72,
+	0x67, 0x73, 0x2e, 0x42, 0x6c, 0x6b, 0x54, 0x6f, 0x44, 0x61, 0x74, 0x61, 0x4e, 0x6f, 0x64, 0x65,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x62, 0x6c, 0x6b, 0x54, 0x6f, 0x44, 0x61, 0x74,
+	0x61, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x65, 0x6e, 0x5f, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x67, 0x65, 0x6e, 0x53, 0x74,
+	0x61, 0x6d, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x1a, 0x50, 0x0a, 0x13, 0x42, 0x6c, 0x6b,
+	0x54, 0x6f, 0x44, 0x61, 0x74, 0x61, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x23, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x73,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x39, 0x0a, 0x0a, 0x45,
+	0x6e, 0x64, 0x4a, 0x6f, 0x62, 0x41, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x32, 0xb2, 0x05, 0x0a, 0x08, 0x4e, 0x61, 0x6d, 0x65, 0x4e,
+	0x6f, 0x64, 0x65, 0x12, 0x33, 0x0a, 0x0a, 0x52, 0x75, 0x6e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x12, 0x11, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x41, 0x72, 0x67, 0x73, 0x1a, 0x12, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x43, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x34, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x6d,
+	0x69, 0x74, 0x4a, 0x6f, 0x62, 0x12, 0x11, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x43, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x14, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e,
+	0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x30,
+	0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0d, 0x2e, 0x67, 0x64,
+	0x66, 0x73, 0x2e, 0x4a, 0x6f, 0x62, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x14, 0x2e, 0x67, 0x64, 0x66,
+	0x73, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x30, 0x0a, 0x09, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4a, 0x6f, 0x62, 0x12, 0x0d, 0x2e,
+	0x67, 0x64, 0x66, 0x73, 0x2e, 0x4a, 0x6f, 0x62, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x14, 0x2e, 0x67,
+	0x64, 0x66, 0x73, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x12, 0x3a, 0x0a, 0x0d, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x0f, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e,
+	0x41, 0x72, 0x67, 0x73, 0x1a, 0x18, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x43, 0x6c, 0x75, 0x73,
+	0x74, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x36,
+	0x0a, 0x0b, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x0f, 0x2e,
+	0x67, 0x64, 0x66, 0x73, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x16,
+	0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x4e, 0x6f, 0x64, 0x65,
+	0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2d, 0x0a, 0x06, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79,
+	0x12, 0x10, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x41, 0x72,
+	0x67, 0x73, 0x1a, 0x11, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x36, 0x0a, 0x09, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61,
+	0x6b, 0x65, 0x12, 0x13, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x68,
+	0x61, 0x6b, 0x65, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x14, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x48,
+	0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x33, 0x0a,
+	0x08, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x12, 0x12, 0x2e, 0x67, 0x64, 0x66, 0x73,
+	0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x13, 0x2e,
+	0x67, 0x64, 0x66, 0x73, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x12, 0x36, 0x0a, 0x09, 0x48, 0x65, 0x61, 0x72, 0x74, 0x42, 0x65, 0x61, 0x74, 0x12,
+	0x13, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x42, 0x65, 0x61, 0x74,
+	0x41, 0x72, 0x67, 0x73, 0x1a, 0x14, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x48, 0x65, 0x61, 0x72,
+	0x74, 0x42, 0x65, 0x61, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x3c, 0x0a, 0x0b, 0x52, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x15, 0x2e, 0x67, 0x64, 0x66, 0x73,
+	0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x41, 0x72, 0x67, 0x73,
+	0x1a, 0x16, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x51, 0x0a, 0x12, 0x52, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x43, 0x6f, 0x72, 0x72, 0x75, 0x70, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x1c,
+	0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x6f, 0x72, 0x72,
+	0x75, 0x70, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x1d, 0x2e, 0x67,
+	0x64, 0x66, 0x73, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x6f, 0x72, 0x72, 0x75, 0x70,
+	0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x32, 0xb5, 0x06, 0x0a, 0x08,
+	0x44, 0x61, 0x74, 0x61, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x4d, 0x61, 0x70, 0x42,
+	0x6c, 0x6b, 0x12, 0x0d, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x4d, 0x61, 0x70, 0x41, 0x72, 0x67,
+	0x73, 0x1a, 0x0e, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x12, 0x32, 0x0a, 0x0d, 0x43, 0x61, 0x6c, 0x4d, 0x65, 0x61, 0x6e, 0x56, 0x61, 0x72, 0x4d,
+	0x61, 0x70, 0x12, 0x0f, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x43, 0x61, 0x6c, 0x4d, 0x56, 0x41,
+	0x72, 0x67, 0x73, 0x1a, 0x10, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x43, 0x61, 0x6c, 0x4d, 0x56,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x39, 0x0a, 0x0c, 0x57, 0x6f, 0x72, 0x64, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x4d, 0x61, 0x70, 0x12, 0x13, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x57, 0x6f, 0x72,
+	0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x14, 0x2e, 0x67, 0x64, 0x66,
+	0x73, 0x2e, 0x57, 0x6f, 0x72, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x2a, 0x0a, 0x07, 0x47, 0x72, 0x65, 0x70, 0x4d, 0x61, 0x70, 0x12, 0x0e, 0x2e, 0x67, 0x64,
+	0x66, 0x73, 0x2e, 0x47, 0x72, 0x65, 0x70, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x0f, 0x2e, 0x67, 0x64,
+	0x66, 0x73, 0x2e, 0x47, 0x72, 0x65, 0x70, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2a, 0x0a, 0x07,
+	0x54, 0x6f, 0x70, 0x4e, 0x4d, 0x61, 0x70, 0x12, 0x0e, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x54,
+	0x6f, 0x70, 0x4e, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x0f, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x54,
+	0x6f, 0x70, 0x4e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x31, 0x0a, 0x0a, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x42, 0x6c, 0x6b, 0x12, 0x14, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6b, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x0d, 0x2e, 0x67,
+	0x64, 0x66, 0x73, 0x2e, 0x42, 0x6c, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x12, 0x3c, 0x0a, 0x0b, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6b, 0x73, 0x12, 0x15, 0x2e, 0x67, 0x64, 0x66,
+	0x73, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6b, 0x73, 0x41, 0x72, 0x67,
+	0x73, 0x1a, 0x16, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x42, 0x6c, 0x6b, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2c, 0x0a, 0x07, 0x53, 0x65, 0x6e,
+	0x64, 0x42, 0x6c, 0x6b, 0x12, 0x0d, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x42, 0x6c, 0x6b, 0x44,
+	0x61, 0x74, 0x61, 0x1a, 0x12, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x42,
+	0x6c, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x34, 0x0a, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x42,
+	0x6c, 0x6b, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x0e, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x42,
+	0x6c, 0x6b, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x12, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x53,
+	0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x28, 0x01, 0x12, 0x2c, 0x0a,
+	0x07, 0x53, 0x74, 0x61, 0x74, 0x42, 0x6c, 0x6b, 0x12, 0x11, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e,
+	0x53, 0x74, 0x61, 0x74, 0x42, 0x6c, 0x6b, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x0e, 0x2e, 0x67, 0x64,
+	0x66, 0x73, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x12, 0x34, 0x0a, 0x0b, 0x54,
+	0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6b, 0x12, 0x15, 0x2e, 0x67, 0x64, 0x66,
+	0x73, 0x2e, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6b, 0x41, 0x72, 0x67,
+	0x73, 0x1a, 0x0e, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74,
+	0x61, 0x12, 0x36, 0x0a, 0x09, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c, 0x6b, 0x12, 0x13,
+	0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c, 0x6b, 0x41,
+	0x72, 0x67, 0x73, 0x1a, 0x14, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x42, 0x6c, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x33, 0x0a, 0x0e, 0x46, 0x65, 0x74,
+	0x63, 0x68, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0f, 0x2e, 0x67, 0x64,
+	0x66, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x10, 0x2e, 0x67,
+	0x64, 0x66, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2d,
+	0x0a, 0x06, 0x52, 0x65, 0x64, 0x75, 0x63, 0x65, 0x12, 0x10, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e,
+	0x52, 0x65, 0x64, 0x75, 0x63, 0x65, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x11, 0x2e, 0x67, 0x64, 0x66,
+	0x73, 0x2e, 0x52, 0x65, 0x64, 0x75, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x34, 0x0a,
+	0x0c, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x64, 0x75, 0x63, 0x65, 0x12, 0x10, 0x2e,
+	0x67, 0x64, 0x66, 0x73, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x41, 0x72, 0x67, 0x73, 0x1a,
+	0x12, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6b, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x2e, 0x0a, 0x06, 0x45, 0x6e, 0x64, 0x4a, 0x6f, 0x62, 0x12, 0x10, 0x2e,
+	0x67, 0x64, 0x66, 0x73, 0x2e, 0x45, 0x6e, 0x64, 0x4a, 0x6f, 0x62, 0x41, 0x72, 0x67, 0x73, 0x1a,
+	0x12, 0x2e, 0x67, 0x64, 0x66, 0x73, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6b, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x42, 0x22, 0x5a, 0x20, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x57, 0x69, 0x6e, 0x65, 0x43, 0x68, 0x6f, 0x72, 0x64, 0x2f, 0x67, 0x64, 0x66, 0x73,
+	0x2f, 0x67, 0x64, 0x66, 0x73, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_gdfs_proto_rawDescOnce sync.Once
+	file_gdfs_proto_rawDescData = file_gdfs_proto_rawDesc
+)
+
+func file_gdfs_proto_rawDescGZIP() []byte {
+	file_gdfs_proto_rawDescOnce.Do(func() {
+		file_gdfs_proto_rawDescData = protoimpl.X.CompressGZIP(file_gdfs_proto_rawDescData)
+	})
+	return file_gdfs_proto_rawDescData
+}
+
+var file_gdfs_proto_msgTypes = make([]protoimpl.MessageInfo, 72)
+var file_gdfs_proto_goTypes = []interface{}{
+	(*Strings)(nil),                 // 0: gdfs.Strings
+	(*CommandArgs)(nil),             // 1: gdfs.CommandArgs
+	(*CommandReply)(nil),            // 2: gdfs.CommandReply
+	(*FileStat)(nil),                // 3: gdfs.FileStat
+	(*DiskUsage)(nil),               // 4: gdfs.DiskUsage
+	(*FsckReport)(nil),              // 5: gdfs.FsckReport
+	(*FileHealth)(nil),              // 6: gdfs.FileHealth
+	(*DirCount)(nil),                // 7: gdfs.DirCount
+	(*SubmitJobReply)(nil),          // 8: gdfs.SubmitJobReply
+	(*JobArgs)(nil),                 // 9: gdfs.JobArgs
+	(*JobStatusReply)(nil),          // 10: gdfs.JobStatusReply
+	(*AdminArgs)(nil),               // 11: gdfs.AdminArgs
+	(*ClusterStatusReply)(nil),      // 12: gdfs.ClusterStatusReply
+	(*NodeStatus)(nil),              // 13: gdfs.NodeStatus
+	(*ReportNodesReply)(nil),        // 14: gdfs.ReportNodesReply
+	(*NotifyArgs)(nil),              // 15: gdfs.NotifyArgs
+	(*NotifyReply)(nil),             // 16: gdfs.NotifyReply
+	(*HandshakeArgs)(nil),           // 17: gdfs.HandshakeArgs
+	(*HandshakeReply)(nil),          // 18: gdfs.HandshakeReply
+	(*RegisterArgs)(nil),            // 19: gdfs.RegisterArgs
+	(*RegisterReply)(nil),           // 20: gdfs.RegisterReply
+	(*HeartBeatArgs)(nil),           // 21: gdfs.HeartBeatArgs
+	(*HeartBeatReply)(nil),          // 22: gdfs.HeartBeatReply
+	(*ReportBlockArgs)(nil),         // 23: gdfs.ReportBlockArgs
+	(*ReportBlockReply)(nil),        // 24: gdfs.ReportBlockReply
+	(*ReportCorruptBlockArgs)(nil),  // 25: gdfs.ReportCorruptBlockArgs
+	(*ReportCorruptBlockReply)(nil), // 26: gdfs.ReportCorruptBlockReply
+	(*MetaData)(nil),                // 27: gdfs.MetaData
+	(*Edges)(nil),                   // 28: gdfs.Edges
+	(*MapArgs)(nil),                 // 29: gdfs.MapArgs
+	(*MapReply)(nil),                // 30: gdfs.MapReply
+	(*CalMVArgs)(nil),               // 31: gdfs.CalMVArgs
+	(*CalMVReply)(nil),              // 32: gdfs.CalMVReply
+	(*WordCountArgs)(nil),           // 33: gdfs.WordCountArgs
+	(*WordCountReply)(nil),          // 34: gdfs.WordCountReply
+	(*GrepArgs)(nil),                // 35: gdfs.GrepArgs
+	(*GrepReply)(nil),               // 36: gdfs.GrepReply
+	(*TopNArgs)(nil),                // 37: gdfs.TopNArgs
+	(*TopNReply)(nil),               // 38: gdfs.TopNReply
+	(*RequestBlkArgs)(nil),          // 39: gdfs.RequestBlkArgs
+	(*BlkData)(nil),                 // 40: gdfs.BlkData
+	(*RequestBlksArgs)(nil),         // 41: gdfs.RequestBlksArgs
+	(*RequestBlksReply)(nil),        // 42: gdfs.RequestBlksReply
+	(*SendBlkReply)(nil),            // 43: gdfs.SendBlkReply
+	(*BlkChunk)(nil),                // 44: gdfs.BlkChunk
+	(*StatBlkArgs)(nil),             // 45: gdfs.StatBlkArgs
+	(*TruncateBlkArgs)(nil),         // 46: gdfs.TruncateBlkArgs
+	(*DeleteBlkArgs)(nil),           // 47: gdfs.DeleteBlkArgs
+	(*DeleteBlkReply)(nil),          // 48: gdfs.DeleteBlkReply
+	(*FetchArgs)(nil),               // 49: gdfs.FetchArgs
+	(*FetchReply)(nil),              // 50: gdfs.FetchReply
+	(*ReduceArgs)(nil),              // 51: gdfs.ReduceArgs
+	(*Result)(nil),                  // 52: gdfs.Result
+	(*MapSource)(nil),               // 53: gdfs.MapSource
+	(*ReduceReply)(nil),             // 54: gdfs.ReduceReply
+	(*CommitArgs)(nil),              // 55: gdfs.CommitArgs
+	(*EndJobArgs)(nil),              // 56: gdfs.EndJobArgs
+	nil,                             // 57: gdfs.CommandReply.BlkToDataNodesEntry
+	nil,                             // 58: gdfs.CommandReply.ErrorsEntry
+	nil,                             // 59: gdfs.CommandReply.ChecksumsEntry
+	nil,                             // 60: gdfs.CommandReply.WordCountsEntry
+	nil,                             // 61: gdfs.CommandReply.JobResultEntry
+	nil,                             // 62: gdfs.CommandReply.TrashedEntry
+	nil,                             // 63: gdfs.JobStatusReply.TasksEntry
+	nil,                             // 64: gdfs.JobStatusReply.JobResultEntry
+	nil,                             // 65: gdfs.HeartBeatReply.RepBlkToNodesEntry
+	nil,                             // 66: gdfs.ReportBlockArgs.IdToMetaDataEntry
+	nil,                             // 67: gdfs.MapReply.ResultEntry
+	nil,                             // 68: gdfs.WordCountReply.CountsEntry
+	nil,                             // 69: gdfs.FetchReply.ResultEntry
+	nil,                             // 70: gdfs.Result.ValuesEntry
+	nil,                             // 71: gdfs.CommitArgs.BlkToDataNodesEntry
+}
+var file_gdfs_proto_depIdxs = []int32{
+	57, // 0: gdfs.CommandReply.blk_to_data_nodes:type_name -> gdfs.CommandReply.BlkToDataNodesEntry
+	58, // 1: gdfs.CommandReply.errors:type_name -> gdfs.CommandReply.ErrorsEntry
+	3,  // 2: gdfs.CommandReply.stats:type_name -> gdfs.FileStat
+	59, // 3: gdfs.CommandReply.checksums:type_name -> gdfs.CommandReply.ChecksumsEntry
+	60, // 4: gdfs.CommandReply.word_counts:type_name -> gdfs.CommandReply.WordCountsEntry
+	61, // 5: gdfs.CommandReply.job_result:type_name -> gdfs.CommandReply.JobResultEntry
+	4,  // 6: gdfs.CommandReply.usage:type_name -> gdfs.DiskUsage
+	5,  // 7: gdfs.CommandReply.fsck:type_name -> gdfs.FsckReport
+	62, // 8: gdfs.CommandReply.trashed:type_name -> gdfs.CommandReply.TrashedEntry
+	7,  // 9: gdfs.CommandReply.counts:type_name -> gdfs.DirCount
+	6,  // 10: gdfs.FsckReport.unhealthy:type_name -> gdfs.FileHealth
+	63, // 11: gdfs.JobStatusReply.tasks:type_name -> gdfs.JobStatusReply.TasksEntry
+	64, // 12: gdfs.JobStatusReply.job_result:type_name -> gdfs.JobStatusReply.JobResultEntry
+	13, // 13: gdfs.ClusterStatusReply.nodes:type_name -> gdfs.NodeStatus
+	13, // 14: gdfs.ReportNodesReply.nodes:type_name -> gdfs.NodeStatus
+	65, // 15: gdfs.HeartBeatReply.rep_blk_to_nodes:type_name -> gdfs.HeartBeatReply.RepBlkToNodesEntry
+	66, // 16: gdfs.ReportBlockArgs.id_to_meta_data:type_name -> gdfs.ReportBlockArgs.IdToMetaDataEntry
+	67, // 17: gdfs.MapReply.result:type_name -> gdfs.MapReply.ResultEntry
+	28, // 18: gdfs.MapReply.edges:type_name -> gdfs.Edges
+	28, // 19: gdfs.CalMVReply.edges:type_name -> gdfs.Edges
+	68, // 20: gdfs.WordCountReply.counts:type_name -> gdfs.WordCountReply.CountsEntry
+	28, // 21: gdfs.WordCountReply.edges:type_name -> gdfs.Edges
+	28, // 22: gdfs.GrepReply.edges:type_name -> gdfs.Edges
+	28, // 23: gdfs.TopNReply.edges:type_name -> gdfs.Edges
+	40, // 24: gdfs.RequestBlksReply.blks:type_name -> gdfs.BlkData
+	69, // 25: gdfs.FetchReply.result:type_name -> gdfs.FetchReply.ResultEntry
+	53, // 26: gdfs.ReduceArgs.sources:type_name -> gdfs.MapSource
+	52, // 27: gdfs.ReduceArgs.extra:type_name -> gdfs.Result
+	70, // 28: gdfs.Result.values:type_name -> gdfs.Result.ValuesEntry
+	71, // 29: gdfs.CommitArgs.blk_to_data_nodes:type_name -> gdfs.CommitArgs.BlkToDataNodesEntry
+	0,  // 30: gdfs.CommandReply.BlkToDataNodesEntry.value:type_name -> gdfs.Strings
+	27, // 31: gdfs.ReportBlockArgs.IdToMetaDataEntry.value:type_name -> gdfs.MetaData
+	0,  // 32: gdfs.CommitArgs.BlkToDataNodesEntry.value:type_name -> gdfs.Strings
+	1,  // 33: gdfs.NameNode.RunCommand:input_type -> gdfs.CommandArgs
+	1,  // 34: gdfs.NameNode.SubmitJob:input_type -> gdfs.CommandArgs
+	9,  // 35: gdfs.NameNode.JobStatus:input_type -> gdfs.JobArgs
+	9,  // 36: gdfs.NameNode.CancelJob:input_type -> gdfs.JobArgs
+	11, // 37: gdfs.NameNode.ClusterStatus:input_type -> gdfs.AdminArgs
+	11, // 38: gdfs.NameNode.ReportNodes:input_type -> gdfs.AdminArgs
+	15, // 39: gdfs.NameNode.Notify:input_type -> gdfs.NotifyArgs
+	17, // 40: gdfs.NameNode.Handshake:input_type -> gdfs.HandshakeArgs
+	19, // 41: gdfs.NameNode.Register:input_type -> gdfs.RegisterArgs
+	21, // 42: gdfs.NameNode.HeartBeat:input_type -> gdfs.HeartBeatArgs
+	23, // 43: gdfs.NameNode.ReportBlock:input_type -> gdfs.ReportBlockArgs
+	25, // 44: gdfs.NameNode.ReportCorruptBlock:input_type -> gdfs.ReportCorruptBlockArgs
+	29, // 45: gdfs.DataNode.MapBlk:input_type -> gdfs.MapArgs
+	31, // 46: gdfs.DataNode.CalMeanVarMap:input_type -> gdfs.CalMVArgs
+	33, // 47: gdfs.DataNode.WordCountMap:input_type -> gdfs.WordCountArgs
+	35, // 48: gdfs.DataNode.GrepMap:input_type -> gdfs.GrepArgs
+	37, // 49: gdfs.DataNode.TopNMap:input_type -> gdfs.TopNArgs
+	39, // 50: gdfs.DataNode.RequestBlk:input_type -> gdfs.RequestBlkArgs
+	41, // 51: gdfs.DataNode.RequestBlks:input_type -> gdfs.RequestBlksArgs
+	40, // 52: gdfs.DataNode.SendBlk:input_type -> gdfs.BlkData
+	44, // 53: gdfs.DataNode.SendBlkChunk:input_type -> gdfs.BlkChunk
+	45, // 54: gdfs.DataNode.StatBlk:input_type -> gdfs.StatBlkArgs
+	46, // 55: gdfs.DataNode.TruncateBlk:input_type -> gdfs.TruncateBlkArgs
+	47, // 56: gdfs.DataNode.DeleteBlk:input_type -> gdfs.DeleteBlkArgs
+	49, // 57: gdfs.DataNode.FetchPartition:input_type -> gdfs.FetchArgs
+	51, // 58: gdfs.DataNode.Reduce:input_type -> gdfs.ReduceArgs
+	55, // 59: gdfs.DataNode.CommitReduce:input_type -> gdfs.CommitArgs
+	56, // 60: gdfs.DataNode.EndJob:input_type -> gdfs.EndJobArgs
+	2,  // 61: gdfs.NameNode.RunCommand:output_type -> gdfs.CommandReply
+	8,  // 62: gdfs.NameNode.SubmitJob:output_type -> gdfs.SubmitJobReply
+	10, // 63: gdfs.NameNode.JobStatus:output_type -> gdfs.JobStatusReply
+	10, // 64: gdfs.NameNode.CancelJob:output_type -> gdfs.JobStatusReply
+	12, // 65: gdfs.NameNode.ClusterStatus:output_type -> gdfs.ClusterStatusReply
+	14, // 66: gdfs.NameNode.ReportNodes:output_type -> gdfs.ReportNodesReply
+	16, // 67: gdfs.NameNode.Notify:output_type -> gdfs.NotifyReply
+	18, // 68: gdfs.NameNode.Handshake:output_type -> gdfs.HandshakeReply
+	20, // 69: gdfs.NameNode.Register:output_type -> gdfs.RegisterReply
+	22, // 70: gdfs.NameNode.HeartBeat:output_type -> gdfs.HeartBeatReply
+	24, // 71: gdfs.NameNode.ReportBlock:output_type -> gdfs.ReportBlockReply
+	26, // 72: gdfs.NameNode.ReportCorruptBlock:output_type -> gdfs.ReportCorruptBlockReply
+	30, // 73: gdfs.DataNode.MapBlk:output_type -> gdfs.MapReply
+	32, // 74: gdfs.DataNode.CalMeanVarMap:output_type -> gdfs.CalMVReply
+	34, // 75: gdfs.DataNode.WordCountMap:output_type -> gdfs.WordCountReply
+	36, // 76: gdfs.DataNode.GrepMap:output_type -> gdfs.GrepReply
+	38, // 77: gdfs.DataNode.TopNMap:output_type -> gdfs.TopNReply
+	40, // 78: gdfs.DataNode.RequestBlk:output_type -> gdfs.BlkData
+	42, // 79: gdfs.DataNode.RequestBlks:output_type -> gdfs.RequestBlksReply
+	43, // 80: gdfs.DataNode.SendBlk:output_type -> gdfs.SendBlkReply
+	43, // 81: gdfs.DataNode.SendBlkChunk:output_type -> gdfs.SendBlkReply
+	27, // 82: gdfs.DataNode.StatBlk:output_type -> gdfs.MetaData
+	27, // 83: gdfs.DataNode.TruncateBlk:output_type -> gdfs.MetaData
+	48, // 84: gdfs.DataNode.DeleteBlk:output_type -> gdfs.DeleteBlkReply
+	50, // 85: gdfs.DataNode.FetchPartition:output_type -> gdfs.FetchReply
+	54, // 86: gdfs.DataNode.Reduce:output_type -> gdfs.ReduceReply
+	43, // 87: gdfs.DataNode.CommitReduce:output_type -> gdfs.SendBlkReply
+	43, // 88: gdfs.DataNode.EndJob:output_type -> gdfs.SendBlkReply
+	61, // [61:89] is the sub-list for method output_type
+	33, // [33:61] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
+}
+
+func init() { file_gdfs_proto_init() }
+func file_gdfs_proto_init() {
+	if File_gdfs_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_gdfs_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Strings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommandArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommandReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileStat); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiskUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FsckReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileHealth); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DirCount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitJobReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobStatusReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClusterStatusReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportNodesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NotifyArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NotifyReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HandshakeArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HandshakeReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HeartBeatArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HeartBeatReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportBlockArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportBlockReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportCorruptBlockArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportCorruptBlockReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MetaData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Edges); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MapArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MapReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CalMVArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CalMVReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WordCountArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WordCountReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GrepArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GrepReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TopNArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TopNReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestBlkArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlkData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestBlksArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestBlksReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendBlkReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlkChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatBlkArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TruncateBlkArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteBlkArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteBlkReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReduceArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Result); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MapSource); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReduceReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommitArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gdfs_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EndJobArgs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_gdfs_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   72,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_gdfs_proto_goTypes,
+		DependencyIndexes: file_gdfs_proto_depIdxs,
+		MessageInfos:      file_gdfs_proto_msgTypes,
+	}.Build()
+	File_gdfs_proto = out.File
+	file_gdfs_proto_rawDesc = nil
+	file_gdfs_proto_goTypes = nil
+	file_gdfs_proto_depIdxs = nil
+}
@@ -0,0 +1,1257 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Messages mirror the argument and reply types of the RPCs of namenode and
+// datanodes field by field: a field is named as its Go counterpart in
+// snake case, Go ints are int64 and time.Duration is in nanoseconds. The Go
+// types convert to and from their messages with ToPB and FromPB.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: gdfs.proto
+
+package gdfspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	NameNode_RunCommand_FullMethodName         = "/gdfs.NameNode/RunCommand"
+	NameNode_SubmitJob_FullMethodName          = "/gdfs.NameNode/SubmitJob"
+	NameNode_JobStatus_FullMethodName          = "/gdfs.NameNode/JobStatus"
+	NameNode_CancelJob_FullMethodName          = "/gdfs.NameNode/CancelJob"
+	NameNode_ClusterStatus_FullMethodName      = "/gdfs.NameNode/ClusterStatus"
+	NameNode_ReportNodes_FullMethodName        = "/gdfs.NameNode/ReportNodes"
+	NameNode_Notify_FullMethodName             = "/gdfs.NameNode/Notify"
+	NameNode_Handshake_FullMethodName          = "/gdfs.NameNode/Handshake"
+	NameNode_Register_FullMethodName           = "/gdfs.NameNode/Register"
+	NameNode_HeartBeat_FullMethodName          = "/gdfs.NameNode/HeartBeat"
+	NameNode_ReportBlock_FullMethodName        = "/gdfs.NameNode/ReportBlock"
+	NameNode_ReportCorruptBlock_FullMethodName = "/gdfs.NameNode/ReportCorruptBlock"
+)
+
+// NameNodeClient is the client API for NameNode service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NameNode serves clients and datanodes, see namenode.NameNode
+type NameNodeClient interface {
+	RunCommand(ctx context.Context, in *CommandArgs, opts ...grpc.CallOption) (*CommandReply, error)
+	SubmitJob(ctx context.Context, in *CommandArgs, opts ...grpc.CallOption) (*SubmitJobReply, error)
+	JobStatus(ctx context.Context, in *JobArgs, opts ...grpc.CallOption) (*JobStatusReply, error)
+	CancelJob(ctx context.Context, in *JobArgs, opts ...grpc.CallOption) (*JobStatusReply, error)
+	ClusterStatus(ctx context.Context, in *AdminArgs, opts ...grpc.CallOption) (*ClusterStatusReply, error)
+	ReportNodes(ctx context.Context, in *AdminArgs, opts ...grpc.CallOption) (*ReportNodesReply, error)
+	Notify(ctx context.Context, in *NotifyArgs, opts ...grpc.CallOption) (*NotifyReply, error)
+	Handshake(ctx context.Context, in *HandshakeArgs, opts ...grpc.CallOption) (*HandshakeReply, error)
+	Register(ctx context.Context, in *RegisterArgs, opts ...grpc.CallOption) (*RegisterReply, error)
+	HeartBeat(ctx context.Context, in *HeartBeatArgs, opts ...grpc.CallOption) (*HeartBeatReply, error)
+	ReportBlock(ctx context.Context, in *ReportBlockArgs, opts ...grpc.CallOption) (*ReportBlockReply, error)
+	ReportCorruptBlock(ctx context.Context, in *ReportCorruptBlockArgs, opts ...grpc.CallOption) (*ReportCorruptBlockReply, error)
+}
+
+type nameNodeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNameNodeClient(cc grpc.ClientConnInterface) NameNodeClient {
+	return &nameNodeClient{cc}
+}
+
+func (c *nameNodeClient) RunCommand(ctx context.Context, in *CommandArgs, opts ...grpc.CallOption) (*CommandReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandReply)
+	err := c.cc.Invoke(ctx, NameNode_RunCommand_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameNodeClient) SubmitJob(ctx context.Context, in *CommandArgs, opts ...grpc.CallOption) (*SubmitJobReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitJobReply)
+	err := c.cc.Invoke(ctx, NameNode_SubmitJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameNodeClient) JobStatus(ctx context.Context, in *JobArgs, opts ...grpc.CallOption) (*JobStatusReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatusReply)
+	err := c.cc.Invoke(ctx, NameNode_JobStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameNodeClient) CancelJob(ctx context.Context, in *JobArgs, opts ...grpc.CallOption) (*JobStatusReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatusReply)
+	err := c.cc.Invoke(ctx, NameNode_CancelJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameNodeClient) ClusterStatus(ctx context.Context, in *AdminArgs, opts ...grpc.CallOption) (*ClusterStatusReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClusterStatusReply)
+	err := c.cc.Invoke(ctx, NameNode_ClusterStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameNodeClient) ReportNodes(ctx context.Context, in *AdminArgs, opts ...grpc.CallOption) (*ReportNodesReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportNodesReply)
+	err := c.cc.Invoke(ctx, NameNode_ReportNodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameNodeClient) Notify(ctx context.Context, in *NotifyArgs, opts ...grpc.CallOption) (*NotifyReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotifyReply)
+	err := c.cc.Invoke(ctx, NameNode_Notify_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameNodeClient) Handshake(ctx context.Context, in *HandshakeArgs, opts ...grpc.CallOption) (*HandshakeReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HandshakeReply)
+	err := c.cc.Invoke(ctx, NameNode_Handshake_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameNodeClient) Register(ctx context.Context, in *RegisterArgs, opts ...grpc.CallOption) (*RegisterReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterReply)
+	err := c.cc.Invoke(ctx, NameNode_Register_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameNodeClient) HeartBeat(ctx context.Context, in *HeartBeatArgs, opts ...grpc.CallOption) (*HeartBeatReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeartBeatReply)
+	err := c.cc.Invoke(ctx, NameNode_HeartBeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameNodeClient) ReportBlock(ctx context.Context, in *ReportBlockArgs, opts ...grpc.CallOption) (*ReportBlockReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportBlockReply)
+	err := c.cc.Invoke(ctx, NameNode_ReportBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameNodeClient) ReportCorruptBlock(ctx context.Context, in *ReportCorruptBlockArgs, opts ...grpc.CallOption) (*ReportCorruptBlockReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportCorruptBlockReply)
+	err := c.cc.Invoke(ctx, NameNode_ReportCorruptBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NameNodeServer is the server API for NameNode service.
+// All implementations must embed UnimplementedNameNodeServer
+// for forward compatibility
+//
+// NameNode serves clients and datanodes, see namenode.NameNode
+type NameNodeServer interface {
+	RunCommand(context.Context, *CommandArgs) (*CommandReply, error)
+	SubmitJob(context.Context, *CommandArgs) (*SubmitJobReply, error)
+	JobStatus(context.Context, *JobArgs) (*JobStatusReply, error)
+	CancelJob(context.Context, *JobArgs) (*JobStatusReply, error)
+	ClusterStatus(context.Context, *AdminArgs) (*ClusterStatusReply, error)
+	ReportNodes(context.Context, *AdminArgs) (*ReportNodesReply, error)
+	Notify(context.Context, *NotifyArgs) (*NotifyReply, error)
+	Handshake(context.Context, *HandshakeArgs) (*HandshakeReply, error)
+	Register(context.Context, *RegisterArgs) (*RegisterReply, error)
+	HeartBeat(context.Context, *HeartBeatArgs) (*HeartBeatReply, error)
+	ReportBlock(context.Context, *ReportBlockArgs) (*ReportBlockReply, error)
+	ReportCorruptBlock(context.Context, *ReportCorruptBlockArgs) (*ReportCorruptBlockReply, error)
+	mustEmbedUnimplementedNameNodeServer()
+}
+
+// UnimplementedNameNodeServer must be embedded to have forward compatible implementations.
+type UnimplementedNameNodeServer struct {
+}
+
+func (UnimplementedNameNodeServer) RunCommand(context.Context, *CommandArgs) (*CommandReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunCommand not implemented")
+}
+func (UnimplementedNameNodeServer) SubmitJob(context.Context, *CommandArgs) (*SubmitJobReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitJob not implemented")
+}
+func (UnimplementedNameNodeServer) JobStatus(context.Context, *JobArgs) (*JobStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JobStatus not implemented")
+}
+func (UnimplementedNameNodeServer) CancelJob(context.Context, *JobArgs) (*JobStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
+func (UnimplementedNameNodeServer) ClusterStatus(context.Context, *AdminArgs) (*ClusterStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClusterStatus not implemented")
+}
+func (UnimplementedNameNodeServer) ReportNodes(context.Context, *AdminArgs) (*ReportNodesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportNodes not implemented")
+}
+func (UnimplementedNameNodeServer) Notify(context.Context, *NotifyArgs) (*NotifyReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Notify not implemented")
+}
+func (UnimplementedNameNodeServer) Handshake(context.Context, *HandshakeArgs) (*HandshakeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Handshake not implemented")
+}
+func (UnimplementedNameNodeServer) Register(context.Context, *RegisterArgs) (*RegisterReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedNameNodeServer) HeartBeat(context.Context, *HeartBeatArgs) (*HeartBeatReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HeartBeat not implemented")
+}
+func (UnimplementedNameNodeServer) ReportBlock(context.Context, *ReportBlockArgs) (*ReportBlockReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportBlock not implemented")
+}
+func (UnimplementedNameNodeServer) ReportCorruptBlock(context.Context, *ReportCorruptBlockArgs) (*ReportCorruptBlockReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportCorruptBlock not implemented")
+}
+func (UnimplementedNameNodeServer) mustEmbedUnimplementedNameNodeServer() {}
+
+// UnsafeNameNodeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NameNodeServer will
+// result in compilation errors.
+type UnsafeNameNodeServer interface {
+	mustEmbedUnimplementedNameNodeServer()
+}
+
+func RegisterNameNodeServer(s grpc.ServiceRegistrar, srv NameNodeServer) {
+	s.RegisterService(&NameNode_ServiceDesc, srv)
+}
+
+func _NameNode_RunCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommandArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeServer).RunCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameNode_RunCommand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeServer).RunCommand(ctx, req.(*CommandArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameNode_SubmitJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommandArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeServer).SubmitJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameNode_SubmitJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeServer).SubmitJob(ctx, req.(*CommandArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameNode_JobStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeServer).JobStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameNode_JobStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeServer).JobStatus(ctx, req.(*JobArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameNode_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameNode_CancelJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeServer).CancelJob(ctx, req.(*JobArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameNode_ClusterStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeServer).ClusterStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameNode_ClusterStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeServer).ClusterStatus(ctx, req.(*AdminArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameNode_ReportNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeServer).ReportNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameNode_ReportNodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeServer).ReportNodes(ctx, req.(*AdminArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameNode_Notify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotifyArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeServer).Notify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameNode_Notify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeServer).Notify(ctx, req.(*NotifyArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameNode_Handshake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandshakeArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeServer).Handshake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameNode_Handshake_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeServer).Handshake(ctx, req.(*HandshakeArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameNode_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameNode_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeServer).Register(ctx, req.(*RegisterArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameNode_HeartBeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartBeatArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeServer).HeartBeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameNode_HeartBeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeServer).HeartBeat(ctx, req.(*HeartBeatArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameNode_ReportBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportBlockArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeServer).ReportBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameNode_ReportBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeServer).ReportBlock(ctx, req.(*ReportBlockArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameNode_ReportCorruptBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportCorruptBlockArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeServer).ReportCorruptBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameNode_ReportCorruptBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeServer).ReportCorruptBlock(ctx, req.(*ReportCorruptBlockArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// NameNode_ServiceDesc is the grpc.ServiceDesc for NameNode service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NameNode_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gdfs.NameNode",
+	HandlerType: (*NameNodeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RunCommand",
+			Handler:    _NameNode_RunCommand_Handler,
+		},
+		{
+			MethodName: "SubmitJob",
+			Handler:    _NameNode_SubmitJob_Handler,
+		},
+		{
+			MethodName: "JobStatus",
+			Handler:    _NameNode_JobStatus_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _NameNode_CancelJob_Handler,
+		},
+		{
+			MethodName: "ClusterStatus",
+			Handler:    _NameNode_ClusterStatus_Handler,
+		},
+		{
+			MethodName: "ReportNodes",
+			Handler:    _NameNode_ReportNodes_Handler,
+		},
+		{
+			MethodName: "Notify",
+			Handler:    _NameNode_Notify_Handler,
+		},
+		{
+			MethodName: "Handshake",
+			Handler:    _NameNode_Handshake_Handler,
+		},
+		{
+			MethodName: "Register",
+			Handler:    _NameNode_Register_Handler,
+		},
+		{
+			MethodName: "HeartBeat",
+			Handler:    _NameNode_HeartBeat_Handler,
+		},
+		{
+			MethodName: "ReportBlock",
+			Handler:    _NameNode_ReportBlock_Handler,
+		},
+		{
+			MethodName: "ReportCorruptBlock",
+			Handler:    _NameNode_ReportCorruptBlock_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gdfs.proto",
+}
+
+const (
+	DataNode_MapBlk_FullMethodName         = "/gdfs.DataNode/MapBlk"
+	DataNode_CalMeanVarMap_FullMethodName  = "/gdfs.DataNode/CalMeanVarMap"
+	DataNode_WordCountMap_FullMethodName   = "/gdfs.DataNode/WordCountMap"
+	DataNode_GrepMap_FullMethodName        = "/gdfs.DataNode/GrepMap"
+	DataNode_TopNMap_FullMethodName        = "/gdfs.DataNode/TopNMap"
+	DataNode_RequestBlk_FullMethodName     = "/gdfs.DataNode/RequestBlk"
+	DataNode_RequestBlks_FullMethodName    = "/gdfs.DataNode/RequestBlks"
+	DataNode_SendBlk_FullMethodName        = "/gdfs.DataNode/SendBlk"
+	DataNode_SendBlkChunk_FullMethodName   = "/gdfs.DataNode/SendBlkChunk"
+	DataNode_StatBlk_FullMethodName        = "/gdfs.DataNode/StatBlk"
+	DataNode_TruncateBlk_FullMethodName    = "/gdfs.DataNode/TruncateBlk"
+	DataNode_DeleteBlk_FullMethodName      = "/gdfs.DataNode/DeleteBlk"
+	DataNode_FetchPartition_FullMethodName = "/gdfs.DataNode/FetchPartition"
+	DataNode_Reduce_FullMethodName         = "/gdfs.DataNode/Reduce"
+	DataNode_CommitReduce_FullMethodName   = "/gdfs.DataNode/CommitReduce"
+	DataNode_EndJob_FullMethodName         = "/gdfs.DataNode/EndJob"
+)
+
+// DataNodeClient is the client API for DataNode service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DataNode serves clients, namenode and other datanodes, see
+// datanode.DataNode
+type DataNodeClient interface {
+	MapBlk(ctx context.Context, in *MapArgs, opts ...grpc.CallOption) (*MapReply, error)
+	CalMeanVarMap(ctx context.Context, in *CalMVArgs, opts ...grpc.CallOption) (*CalMVReply, error)
+	WordCountMap(ctx context.Context, in *WordCountArgs, opts ...grpc.CallOption) (*WordCountReply, error)
+	GrepMap(ctx context.Context, in *GrepArgs, opts ...grpc.CallOption) (*GrepReply, error)
+	TopNMap(ctx context.Context, in *TopNArgs, opts ...grpc.CallOption) (*TopNReply, error)
+	RequestBlk(ctx context.Context, in *RequestBlkArgs, opts ...grpc.CallOption) (*BlkData, error)
+	RequestBlks(ctx context.Context, in *RequestBlksArgs, opts ...grpc.CallOption) (*RequestBlksReply, error)
+	SendBlk(ctx context.Context, in *BlkData, opts ...grpc.CallOption) (*SendBlkReply, error)
+	// SendBlkChunk streams the chunks of a block in order, from offset 0 to
+	// the last one, the reply comes once the pipeline committed the block
+	SendBlkChunk(ctx context.Context, opts ...grpc.CallOption) (DataNode_SendBlkChunkClient, error)
+	StatBlk(ctx context.Context, in *StatBlkArgs, opts ...grpc.CallOption) (*MetaData, error)
+	TruncateBlk(ctx context.Context, in *TruncateBlkArgs, opts ...grpc.CallOption) (*MetaData, error)
+	DeleteBlk(ctx context.Context, in *DeleteBlkArgs, opts ...grpc.CallOption) (*DeleteBlkReply, error)
+	FetchPartition(ctx context.Context, in *FetchArgs, opts ...grpc.CallOption) (*FetchReply, error)
+	Reduce(ctx context.Context, in *ReduceArgs, opts ...grpc.CallOption) (*ReduceReply, error)
+	CommitReduce(ctx context.Context, in *CommitArgs, opts ...grpc.CallOption) (*SendBlkReply, error)
+	EndJob(ctx context.Context, in *EndJobArgs, opts ...grpc.CallOption) (*SendBlkReply, error)
+}
+
+type dataNodeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDataNodeClient(cc grpc.ClientConnInterface) DataNodeClient {
+	return &dataNodeClient{cc}
+}
+
+func (c *dataNodeClient) MapBlk(ctx context.Context, in *MapArgs, opts ...grpc.CallOption) (*MapReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MapReply)
+	err := c.cc.Invoke(ctx, DataNode_MapBlk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) CalMeanVarMap(ctx context.Context, in *CalMVArgs, opts ...grpc.CallOption) (*CalMVReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CalMVReply)
+	err := c.cc.Invoke(ctx, DataNode_CalMeanVarMap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) WordCountMap(ctx context.Context, in *WordCountArgs, opts ...grpc.CallOption) (*WordCountReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WordCountReply)
+	err := c.cc.Invoke(ctx, DataNode_WordCountMap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) GrepMap(ctx context.Context, in *GrepArgs, opts ...grpc.CallOption) (*GrepReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GrepReply)
+	err := c.cc.Invoke(ctx, DataNode_GrepMap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) TopNMap(ctx context.Context, in *TopNArgs, opts ...grpc.CallOption) (*TopNReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TopNReply)
+	err := c.cc.Invoke(ctx, DataNode_TopNMap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) RequestBlk(ctx context.Context, in *RequestBlkArgs, opts ...grpc.CallOption) (*BlkData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BlkData)
+	err := c.cc.Invoke(ctx, DataNode_RequestBlk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) RequestBlks(ctx context.Context, in *RequestBlksArgs, opts ...grpc.CallOption) (*RequestBlksReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestBlksReply)
+	err := c.cc.Invoke(ctx, DataNode_RequestBlks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) SendBlk(ctx context.Context, in *BlkData, opts ...grpc.CallOption) (*SendBlkReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendBlkReply)
+	err := c.cc.Invoke(ctx, DataNode_SendBlk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) SendBlkChunk(ctx context.Context, opts ...grpc.CallOption) (DataNode_SendBlkChunkClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DataNode_ServiceDesc.Streams[0], DataNode_SendBlkChunk_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataNodeSendBlkChunkClient{ClientStream: stream}
+	return x, nil
+}
+
+type DataNode_SendBlkChunkClient interface {
+	Send(*BlkChunk) error
+	CloseAndRecv() (*SendBlkReply, error)
+	grpc.ClientStream
+}
+
+type dataNodeSendBlkChunkClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataNodeSendBlkChunkClient) Send(m *BlkChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *dataNodeSendBlkChunkClient) CloseAndRecv() (*SendBlkReply, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SendBlkReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dataNodeClient) StatBlk(ctx context.Context, in *StatBlkArgs, opts ...grpc.CallOption) (*MetaData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MetaData)
+	err := c.cc.Invoke(ctx, DataNode_StatBlk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) TruncateBlk(ctx context.Context, in *TruncateBlkArgs, opts ...grpc.CallOption) (*MetaData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MetaData)
+	err := c.cc.Invoke(ctx, DataNode_TruncateBlk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) DeleteBlk(ctx context.Context, in *DeleteBlkArgs, opts ...grpc.CallOption) (*DeleteBlkReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteBlkReply)
+	err := c.cc.Invoke(ctx, DataNode_DeleteBlk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) FetchPartition(ctx context.Context, in *FetchArgs, opts ...grpc.CallOption) (*FetchReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FetchReply)
+	err := c.cc.Invoke(ctx, DataNode_FetchPartition_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) Reduce(ctx context.Context, in *ReduceArgs, opts ...grpc.CallOption) (*ReduceReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReduceReply)
+	err := c.cc.Invoke(ctx, DataNode_Reduce_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) CommitReduce(ctx context.Context, in *CommitArgs, opts ...grpc.CallOption) (*SendBlkReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendBlkReply)
+	err := c.cc.Invoke(ctx, DataNode_CommitReduce_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeClient) EndJob(ctx context.Context, in *EndJobArgs, opts ...grpc.CallOption) (*SendBlkReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendBlkReply)
+	err := c.cc.Invoke(ctx, DataNode_EndJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DataNodeServer is the server API for DataNode service.
+// All implementations must embed UnimplementedDataNodeServer
+// for forward compatibility
+//
+// DataNode serves clients, namenode and other datanodes, see
+// datanode.DataNode
+type DataNodeServer interface {
+	MapBlk(context.Context, *MapArgs) (*MapReply, error)
+	CalMeanVarMap(context.Context, *CalMVArgs) (*CalMVReply, error)
+	WordCountMap(context.Context, *WordCountArgs) (*WordCountReply, error)
+	GrepMap(context.Context, *GrepArgs) (*GrepReply, error)
+	TopNMap(context.Context, *TopNArgs) (*TopNReply, error)
+	RequestBlk(context.Context, *RequestBlkArgs) (*BlkData, error)
+	RequestBlks(context.Context, *RequestBlksArgs) (*RequestBlksReply, error)
+	SendBlk(context.Context, *BlkData) (*SendBlkReply, error)
+	// SendBlkChunk streams the chunks of a block in order, from offset 0 to
+	// the last one, the reply comes once the pipeline committed the block
+	SendBlkChunk(DataNode_SendBlkChunkServer) error
+	StatBlk(context.Context, *StatBlkArgs) (*MetaData, error)
+	TruncateBlk(context.Context, *TruncateBlkArgs) (*MetaData, error)
+	DeleteBlk(context.Context, *DeleteBlkArgs) (*DeleteBlkReply, error)
+	FetchPartition(context.Context, *FetchArgs) (*FetchReply, error)
+	Reduce(context.Context, *ReduceArgs) (*ReduceReply, error)
+	CommitReduce(context.Context, *CommitArgs) (*SendBlkReply, error)
+	EndJob(context.Context, *EndJobArgs) (*SendBlkReply, error)
+	mustEmbedUnimplementedDataNodeServer()
+}
+
+// UnimplementedDataNodeServer must be embedded to have forward compatible implementations.
+type UnimplementedDataNodeServer struct {
+}
+
+func (UnimplementedDataNodeServer) MapBlk(context.Context, *MapArgs) (*MapReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MapBlk not implemented")
+}
+func (UnimplementedDataNodeServer) CalMeanVarMap(context.Context, *CalMVArgs) (*CalMVReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CalMeanVarMap not implemented")
+}
+func (UnimplementedDataNodeServer) WordCountMap(context.Context, *WordCountArgs) (*WordCountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WordCountMap not implemented")
+}
+func (UnimplementedDataNodeServer) GrepMap(context.Context, *GrepArgs) (*GrepReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrepMap not implemented")
+}
+func (UnimplementedDataNodeServer) TopNMap(context.Context, *TopNArgs) (*TopNReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TopNMap not implemented")
+}
+func (UnimplementedDataNodeServer) RequestBlk(context.Context, *RequestBlkArgs) (*BlkData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestBlk not implemented")
+}
+func (UnimplementedDataNodeServer) RequestBlks(context.Context, *RequestBlksArgs) (*RequestBlksReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestBlks not implemented")
+}
+func (UnimplementedDataNodeServer) SendBlk(context.Context, *BlkData) (*SendBlkReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendBlk not implemented")
+}
+func (UnimplementedDataNodeServer) SendBlkChunk(DataNode_SendBlkChunkServer) error {
+	return status.Errorf(codes.Unimplemented, "method SendBlkChunk not implemented")
+}
+func (UnimplementedDataNodeServer) StatBlk(context.Context, *StatBlkArgs) (*MetaData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StatBlk not implemented")
+}
+func (UnimplementedDataNodeServer) TruncateBlk(context.Context, *TruncateBlkArgs) (*MetaData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TruncateBlk not implemented")
+}
+func (UnimplementedDataNodeServer) DeleteBlk(context.Context, *DeleteBlkArgs) (*DeleteBlkReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBlk not implemented")
+}
+func (UnimplementedDataNodeServer) FetchPartition(context.Context, *FetchArgs) (*FetchReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchPartition not implemented")
+}
+func (UnimplementedDataNodeServer) Reduce(context.Context, *ReduceArgs) (*ReduceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reduce not implemented")
+}
+func (UnimplementedDataNodeServer) CommitReduce(context.Context, *CommitArgs) (*SendBlkReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitReduce not implemented")
+}
+func (UnimplementedDataNodeServer) EndJob(context.Context, *EndJobArgs) (*SendBlkReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EndJob not implemented")
+}
+func (UnimplementedDataNodeServer) mustEmbedUnimplementedDataNodeServer() {}
+
+// UnsafeDataNodeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DataNodeServer will
+// result in compilation errors.
+type UnsafeDataNodeServer interface {
+	mustEmbedUnimplementedDataNodeServer()
+}
+
+func RegisterDataNodeServer(s grpc.ServiceRegistrar, srv DataNodeServer) {
+	s.RegisterService(&DataNode_ServiceDesc, srv)
+}
+
+func _DataNode_MapBlk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MapArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).MapBlk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_MapBlk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).MapBlk(ctx, req.(*MapArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_CalMeanVarMap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalMVArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).CalMeanVarMap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_CalMeanVarMap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).CalMeanVarMap(ctx, req.(*CalMVArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_WordCountMap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WordCountArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).WordCountMap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_WordCountMap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).WordCountMap(ctx, req.(*WordCountArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_GrepMap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrepArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).GrepMap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_GrepMap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).GrepMap(ctx, req.(*GrepArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_TopNMap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopNArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).TopNMap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_TopNMap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).TopNMap(ctx, req.(*TopNArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_RequestBlk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestBlkArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).RequestBlk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_RequestBlk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).RequestBlk(ctx, req.(*RequestBlkArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_RequestBlks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestBlksArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).RequestBlks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_RequestBlks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).RequestBlks(ctx, req.(*RequestBlksArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_SendBlk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlkData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).SendBlk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_SendBlk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).SendBlk(ctx, req.(*BlkData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_SendBlkChunk_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DataNodeServer).SendBlkChunk(&dataNodeSendBlkChunkServer{ServerStream: stream})
+}
+
+type DataNode_SendBlkChunkServer interface {
+	SendAndClose(*SendBlkReply) error
+	Recv() (*BlkChunk, error)
+	grpc.ServerStream
+}
+
+type dataNodeSendBlkChunkServer struct {
+	grpc.ServerStream
+}
+
+func (x *dataNodeSendBlkChunkServer) SendAndClose(m *SendBlkReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *dataNodeSendBlkChunkServer) Recv() (*BlkChunk, error) {
+	m := new(BlkChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _DataNode_StatBlk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatBlkArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).StatBlk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_StatBlk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).StatBlk(ctx, req.(*StatBlkArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_TruncateBlk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TruncateBlkArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).TruncateBlk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_TruncateBlk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).TruncateBlk(ctx, req.(*TruncateBlkArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_DeleteBlk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBlkArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).DeleteBlk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_DeleteBlk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).DeleteBlk(ctx, req.(*DeleteBlkArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_FetchPartition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).FetchPartition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_FetchPartition_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).FetchPartition(ctx, req.(*FetchArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_Reduce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReduceArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).Reduce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_Reduce_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).Reduce(ctx, req.(*ReduceArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_CommitReduce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).CommitReduce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_CommitReduce_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).CommitReduce(ctx, req.(*CommitArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNode_EndJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EndJobArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeServer).EndJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataNode_EndJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeServer).EndJob(ctx, req.(*EndJobArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DataNode_ServiceDesc is the grpc.ServiceDesc for DataNode service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DataNode_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gdfs.DataNode",
+	HandlerType: (*DataNodeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "MapBlk",
+			Handler:    _DataNode_MapBlk_Handler,
+		},
+		{
+			MethodName: "CalMeanVarMap",
+			Handler:    _DataNode_CalMeanVarMap_Handler,
+		},
+		{
+			MethodName: "WordCountMap",
+			Handler:    _DataNode_WordCountMap_Handler,
+		},
+		{
+			MethodName: "GrepMap",
+			Handler:    _DataNode_GrepMap_Handler,
+		},
+		{
+			MethodName: "TopNMap",
+			Handler:    _DataNode_TopNMap_Handler,
+		},
+		{
+			MethodName: "RequestBlk",
+			Handler:    _DataNode_RequestBlk_Handler,
+		},
+		{
+			MethodName: "RequestBlks",
+			Handler:    _DataNode_RequestBlks_Handler,
+		},
+		{
+			MethodName: "SendBlk",
+			Handler:    _DataNode_SendBlk_Handler,
+		},
+		{
+			MethodName: "StatBlk",
+			Handler:    _DataNode_StatBlk_Handler,
+		},
+		{
+			MethodName: "TruncateBlk",
+			Handler:    _DataNode_TruncateBlk_Handler,
+		},
+		{
+			MethodName: "DeleteBlk",
+			Handler:    _DataNode_DeleteBlk_Handler,
+		},
+		{
+			MethodName: "FetchPartition",
+			Handler:    _DataNode_FetchPartition_Handler,
+		},
+		{
+			MethodName: "Reduce",
+			Handler:    _DataNode_Reduce_Handler,
+		},
+		{
+			MethodName: "CommitReduce",
+			Handler:    _DataNode_CommitReduce_Handler,
+		},
+		{
+			MethodName: "EndJob",
+			Handler:    _DataNode_EndJob_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SendBlkChunk",
+			Handler:       _DataNode_SendBlkChunk_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "gdfs.proto",
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logger logs leveled messages through the standard log package,
+// so log.SetOutput and log.SetFlags apply. Messages below config.LogLevel
+// are dropped, config.LogJSON writes them as JSON objects instead.
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+// Level is the severity of a message
+type Level int
+
+// levels from the most verbose
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+var names = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < Debug || l > Error {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return names[l]
+}
+
+// ParseLevel returns the level named s, case insensitive
+func ParseLevel(s string) (Level, error) {
+	for i, name := range names {
+		if strings.EqualFold(s, name) {
+			return Level(i), nil
+		}
+	}
+	return Info, fmt.Errorf("Unknown log level %q", s)
+}
+
+// Enabled tells whether messages of level l are logged, an unknown
+// config.LogLevel counts as info
+func Enabled(l Level) bool {
+	min, _ := ParseLevel(config.LogLevel)
+	return l >= min
+}
+
+// mu keeps JSON lines whole, log.Output locks on its own
+var mu sync.Mutex
+
+func output(l Level, format string, v ...interface{}) {
+	if !Enabled(l) {
+		return
+	}
+	msg := strings.TrimSuffix(fmt.Sprintf(format, v...), "\n")
+	if !config.LogJSON {
+		// 3 skips output and the exported function to report their caller
+		log.Output(3, strings.ToUpper(l.String())+" "+msg)
+		return
+	}
+	line, err := json.Marshal(struct {
+		Time  string `json:"time"`
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+	}{time.Now().Format(time.RFC3339Nano), l.String(), msg})
+	if err != nil {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	log.Writer().Write(append(line, '\n'))
+}
+
+// Debugf logs a message tracing the work of a node, e.g. each block sent
+func Debugf(format string, v ...interface{}) {
+	output(Debug, format, v...)
+}
+
+// Infof logs a message about the state of a node
+func Infof(format string, v ...interface{}) {
+	output(Info, format, v...)
+}
+
+// Warnf logs something wrong the node works around, e.g. a dead datanode
+func Warnf(format string, v ...interface{}) {
+	output(Warn, format, v...)
+}
+
+// Errorf logs a failed operation, e.g. a write to disk
+func Errorf(format string, v ...interface{}) {
+	output(Error, format, v...)
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+// capture returns what fn logs
+func capture(t *testing.T, fn func()) string {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	flags := log.Flags()
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetFlags(flags)
+	}()
+	fn()
+	return buf.String()
+}
+
+func TestLevels(t *testing.T) {
+	defer func(level string) { config.LogLevel = level }(config.LogLevel)
+	config.LogLevel = "warn"
+	got := capture(t, func() {
+		Debugf("sending %v\n", "blk")
+		Infof("heartbeat from %v\n", "dn1")
+		Warnf("datanode %v is dead\n", "dn2")
+		Errorf("error when writing %v\n", "meta")
+	})
+	want := "WARN datanode dn2 is dead\nERROR error when writing meta\n"
+	if got != want {
+		t.Errorf("logged at level warn:\n%q\nwant\n%q", got, want)
+	}
+	config.LogLevel = "DEBUG"
+	if got := capture(t, func() { Debugf("sending blk\n") }); got != "DEBUG sending blk\n" {
+		t.Errorf("logged at level debug %q", got)
+	}
+	// an unknown level falls back to info
+	config.LogLevel = "verbose"
+	if !Enabled(Info) || Enabled(Debug) {
+		t.Errorf("unknown level enables info %v, debug %v", Enabled(Info), Enabled(Debug))
+	}
+}
+
+func TestJSON(t *testing.T) {
+	defer func(json bool) { config.LogJSON = json }(config.LogJSON)
+	config.LogJSON = true
+	got := capture(t, func() { Errorf("error when dialing %q\n", "dn1") })
+	var line struct {
+		Time, Level, Msg string
+	}
+	if err := json.Unmarshal([]byte(got), &line); err != nil {
+		t.Fatalf("log line %q isn't JSON: %v", got, err)
+	}
+	if line.Level != "error" || line.Msg != `error when dialing "dn1"` ||
+		line.Time == "" || !strings.HasSuffix(got, "}\n") {
+		t.Errorf("JSON log line = %q", got)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapreduce
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/WineChord/gdfs/utils"
+)
+
+// names of the built-in jobs
+const (
+	MeanVarJob   = "meanvar"
+	WordCountJob = "wordcount"
+)
+
+func init() {
+	Register(MeanVarJob, MeanVar{})
+	Register(WordCountJob, WordCount{})
+}
+
+// MeanVar computes mean and variance of the integers of a file, one per
+// line. Blank lines are ignored and other lines are skipped with a warning.
+type MeanVar struct{}
+
+// IsSep separates lines
+func (MeanVar) IsSep(r rune) bool {
+	return utils.IsNewline(r)
+}
+
+// Map counts and sums the numbers and their squares
+func (MeanVar) Map(data []byte) (Result, error) {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	cnt, tot, sq := float64(0), float64(0), float64(0)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			log.Printf("skip non-numeric line %q\n", line)
+			continue
+		}
+		cnt++
+		tot += float64(n)
+		sq += float64(n) * float64(n)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return Result{"cnt": cnt, "sum": tot, "sumsq": sq}, nil
+}
+
+// Reduce computes mean and variance as MEANSQ - MEAN^2
+func (MeanVar) Reduce(partials []Result) (Result, error) {
+	cnt, tot, sq := float64(0), float64(0), float64(0)
+	for _, p := range partials {
+		cnt += p["cnt"]
+		tot += p["sum"]
+		sq += p["sumsq"]
+	}
+	if cnt == 0 {
+		return nil, errors.New("No number in file")
+	}
+	mean := tot / cnt
+	return Result{"cnt": cnt, "mean": mean, "variance": sq/cnt - mean*mean}, nil
+}
+
+// Format renders mean and variance
+func (MeanVar) Format(res Result) string {
+	return fmt.Sprintf("mean: %v, variance: %v\n", res["mean"], res["variance"])
+}
+
+// WordCount counts the occurrences of each white space separated word
+type WordCount struct{}
+
+// IsSep separates words
+func (WordCount) IsSep(r rune) bool {
+	return unicode.IsSpace(r)
+}
+
+// Map counts the words of data
+func (WordCount) Map(data []byte) (Result, error) {
+	res := make(Result)
+	for _, word := range bytes.Fields(data) {
+		res[string(word)]++
+	}
+	return res, nil
+}
+
+// Reduce sums up the counts of each word
+func (WordCount) Reduce(partials []Result) (Result, error) {
+	res := make(Result)
+	for _, p := range partials {
+		for word, cnt := range p {
+			res[word] += cnt
+		}
+	}
+	return res, nil
+}
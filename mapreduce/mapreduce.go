@@ -0,0 +1,177 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mapreduce defines distributed computations over dfs files. A job
+// maps each block of a file on a datanode holding it, then namenode
+// reduces the partial results of all blocks.
+/** Jobs are looked up by name on both sides, so a job must be registered
+ * in the namenode and the datanodes, e.g. from an init function of a
+ * package linked in both binaries:
+ * 	func init() { mapreduce.Register("myjob", myJob{}) }
+ * Records may straddle block boundaries. A datanode only maps the records
+ * lying entirely in its block and sends back the partial records at both
+ * ends of the block, namenode glues them together and maps them as well
+ * before reducing, see utils.Edges.
+ * */
+package mapreduce
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/WineChord/gdfs/utils"
+)
+
+// Result is the partial result of a map task or the result of a job
+type Result map[string]float64
+
+// Mapper computes partial results
+type Mapper interface {
+	// IsSep tells whether r separates records
+	IsSep(r rune) bool
+	// Map computes the partial result of data, which holds whole records
+	// only, possibly surrounded by separators
+	Map(data []byte) (Result, error)
+}
+
+// Reducer combines partial results
+type Reducer interface {
+	// Reduce combines the partial results of all blocks of a file
+	Reduce(partials []Result) (Result, error)
+}
+
+// Job is a distributed computation
+type Job interface {
+	Mapper
+	Reducer
+}
+
+// Formatter is implemented by jobs rendering their result for users,
+// see Format
+type Formatter interface {
+	Format(res Result) string
+}
+
+var (
+	mu   sync.Mutex
+	jobs = make(map[string]Job)
+)
+
+// Register makes job available under name, it panics if name is taken
+func Register(name string, job Job) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := jobs[name]; ok {
+		panic("mapreduce: job " + name + " registered twice")
+	}
+	jobs[name] = job
+}
+
+// Lookup returns the job registered under name
+func Lookup(name string) (Job, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	job, ok := jobs[name]
+	if !ok {
+		return nil, fmt.Errorf("Unknown job %q", name)
+	}
+	return job, nil
+}
+
+// Jobs returns the names of the registered jobs, sorted
+func Jobs() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	names := make([]string, 0, len(jobs))
+	for name := range jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Format renders the result of job name, with the Format method of the
+// job if it has one, as sorted "key\tvalue" lines otherwise
+func Format(name string, res Result) string {
+	if job, err := Lookup(name); err == nil {
+		if f, ok := job.(Formatter); ok {
+			return f.Format(res)
+		}
+	}
+	return string(Lines(res))
+}
+
+// MapArgs asks a datanode to run the map task of Job on block BlkID. With
+// NumReduce set, the datanode keeps the partial result for the reduce
+// tasks of job JobID rather than sending it back, see Split.
+type MapArgs struct {
+	Job       string
+	BlkID     string
+	JobID     string
+	NumReduce int
+	Token     string // see config.AuthToken
+}
+
+// MapReply is the partial result of the records lying entirely in the
+// block, unless kept by the datanode, and the partial records at its ends
+type MapReply struct {
+	Result Result
+	utils.Edges
+}
+
+// MapBlk runs the map task of job over the data of a block
+func MapBlk(job Job, data []byte) (*MapReply, error) {
+	edges, inner := utils.SplitEdges(data, job.IsSep)
+	res, err := job.Map(inner)
+	if err != nil {
+		return nil, err
+	}
+	return &MapReply{Result: res, Edges: edges}, nil
+}
+
+// MapEdges maps the records glued from the edges of the blocks, given in
+// block order
+func MapEdges(job Mapper, replies []*MapReply) ([]Result, error) {
+	edges := make([]utils.Edges, 0, len(replies))
+	for _, reply := range replies {
+		edges = append(edges, reply.Edges)
+	}
+	var records []string
+	utils.JoinEdges(edges, func(record string) {
+		records = append(records, record)
+	})
+	partials := make([]Result, 0, len(records))
+	for _, record := range records {
+		res, err := job.Map([]byte(record))
+		if err != nil {
+			return nil, err
+		}
+		partials = append(partials, res)
+	}
+	return partials, nil
+}
+
+// Reduce maps the records glued from the edges of the blocks, given in
+// block order, then reduces them with the partial results of the blocks
+func Reduce(job Job, replies []*MapReply) (Result, error) {
+	partials, err := MapEdges(job, replies)
+	if err != nil {
+		return nil, err
+	}
+	for _, reply := range replies {
+		partials = append(partials, reply.Result)
+	}
+	return job.Reduce(partials)
+}
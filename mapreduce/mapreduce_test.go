@@ -0,0 +1,77 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapreduce
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/WineChord/gdfs/utils"
+)
+
+func TestReduceWordCounts(t *testing.T) {
+	// "the cat sat on the mat" cut at arbitrary points, then "onthe"
+	// spanning three blocks without any space
+	replies := []*MapReply{
+		{Result: Result{}, Edges: utils.Edges{Head: "the", Tail: ""}},        // "the "
+		{Result: Result{}, Edges: utils.Edges{Head: "cat", Tail: ""}},        // "cat "
+		{Result: Result{}, Edges: utils.Edges{Head: "sat", Tail: "o"}},       // "sat o"
+		{Result: Result{"the": 1}, Edges: utils.Edges{Head: "n", Tail: "m"}}, // "n the m"
+		{Result: Result{}, Edges: utils.Edges{Head: "at", Tail: "ont"}},      // "at ont"
+		{Result: Result{}, Edges: utils.Edges{Head: "he", Whole: true}},      // "he"
+		{Result: Result{}, Edges: utils.Edges{Head: "", Whole: true}},        // ""
+		{Result: Result{"x": 2}, Edges: utils.Edges{Head: "", Tail: "end"}},  // " x x end"
+	}
+	want := Result{"the": 2, "cat": 1, "sat": 1, "on": 1, "mat": 1,
+		"onthe": 1, "x": 2, "end": 1}
+	got, err := Reduce(WordCount{}, replies)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Reduce(WordCount) = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestMeanVar(t *testing.T) {
+	// "1\n2\n3\n4\n" in blocks of 3 bytes, with junk in the middle block
+	var replies []*MapReply
+	for _, data := range []string{"1\n2", "\nx\n", "3\n4", "\n"} {
+		reply, err := MapBlk(MeanVar{}, []byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		replies = append(replies, reply)
+	}
+	res, err := Reduce(MeanVar{}, replies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Format(MeanVarJob, res); got != "mean: 2.5, variance: 1.25\n" {
+		t.Errorf("meanvar of 1..4 = %q", got)
+	}
+	if _, err := Reduce(MeanVar{}, nil); err == nil {
+		t.Errorf("meanvar of an empty file succeeded")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if _, err := Lookup("nosuchjob"); err == nil {
+		t.Errorf("Lookup of an unregistered job succeeded")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("registering %v twice did not panic", WordCountJob)
+		}
+	}()
+	Register(WordCountJob, WordCount{})
+}
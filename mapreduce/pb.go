@@ -0,0 +1,200 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapreduce
+
+import (
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// ToPB converts m to gdfspb.MapArgs
+func (m *MapArgs) ToPB() *gdfspb.MapArgs {
+	return &gdfspb.MapArgs{
+		Job:       m.Job,
+		BlkId:     m.BlkID,
+		JobId:     m.JobID,
+		NumReduce: int64(m.NumReduce),
+		Token:     m.Token,
+	}
+}
+
+// FromPB sets m to pb
+func (m *MapArgs) FromPB(pb *gdfspb.MapArgs) {
+	*m = MapArgs{
+		Job:       pb.GetJob(),
+		BlkID:     pb.GetBlkId(),
+		JobID:     pb.GetJobId(),
+		NumReduce: int(pb.GetNumReduce()),
+		Token:     pb.GetToken(),
+	}
+}
+
+// ToPB converts m to gdfspb.MapReply
+func (m *MapReply) ToPB() *gdfspb.MapReply {
+	return &gdfspb.MapReply{
+		Result: m.Result,
+		Edges:  m.Edges.ToPB(),
+	}
+}
+
+// FromPB sets m to pb
+func (m *MapReply) FromPB(pb *gdfspb.MapReply) {
+	*m = MapReply{
+		Result: pb.GetResult(),
+	}
+	m.Edges.FromPB(pb.GetEdges())
+}
+
+// ToPB converts f to gdfspb.FetchArgs
+func (f *FetchArgs) ToPB() *gdfspb.FetchArgs {
+	return &gdfspb.FetchArgs{
+		JobId:     f.JobID,
+		BlkId:     f.BlkID,
+		Partition: int64(f.Partition),
+		Token:     f.Token,
+	}
+}
+
+// FromPB sets f to pb
+func (f *FetchArgs) FromPB(pb *gdfspb.FetchArgs) {
+	*f = FetchArgs{
+		JobID:     pb.GetJobId(),
+		BlkID:     pb.GetBlkId(),
+		Partition: int(pb.GetPartition()),
+		Token:     pb.GetToken(),
+	}
+}
+
+// ToPB converts f to gdfspb.FetchReply
+func (f *FetchReply) ToPB() *gdfspb.FetchReply {
+	return &gdfspb.FetchReply{
+		Result: f.Result,
+	}
+}
+
+// FromPB sets f to pb
+func (f *FetchReply) FromPB(pb *gdfspb.FetchReply) {
+	*f = FetchReply{
+		Result: pb.GetResult(),
+	}
+}
+
+// ToPB converts r to gdfspb.ReduceArgs
+func (r *ReduceArgs) ToPB() *gdfspb.ReduceArgs {
+	pb := &gdfspb.ReduceArgs{
+		JobId:     r.JobID,
+		Job:       r.Job,
+		Partition: int64(r.Partition),
+		Token:     r.Token,
+	}
+	for i := range r.Sources {
+		pb.Sources = append(pb.Sources, r.Sources[i].ToPB())
+	}
+	for _, v := range r.Extra {
+		pb.Extra = append(pb.Extra, &gdfspb.Result{Values: v})
+	}
+	return pb
+}
+
+// FromPB sets r to pb
+func (r *ReduceArgs) FromPB(pb *gdfspb.ReduceArgs) {
+	*r = ReduceArgs{
+		JobID:     pb.GetJobId(),
+		Job:       pb.GetJob(),
+		Partition: int(pb.GetPartition()),
+		Token:     pb.GetToken(),
+	}
+	if vs := pb.GetSources(); len(vs) > 0 {
+		r.Sources = make([]MapSource, len(vs))
+		for i, v := range vs {
+			r.Sources[i].FromPB(v)
+		}
+	}
+	for _, v := range pb.GetExtra() {
+		r.Extra = append(r.Extra, v.GetValues())
+	}
+}
+
+// ToPB converts m to gdfspb.MapSource
+func (m *MapSource) ToPB() *gdfspb.MapSource {
+	return &gdfspb.MapSource{
+		BlkId: m.BlkID,
+		Addr:  m.Addr,
+	}
+}
+
+// FromPB sets m to pb
+func (m *MapSource) FromPB(pb *gdfspb.MapSource) {
+	*m = MapSource{
+		BlkID: pb.GetBlkId(),
+		Addr:  pb.GetAddr(),
+	}
+}
+
+// ToPB converts r to gdfspb.ReduceReply
+func (r *ReduceReply) ToPB() *gdfspb.ReduceReply {
+	return &gdfspb.ReduceReply{
+		Size: r.Size,
+	}
+}
+
+// FromPB sets r to pb
+func (r *ReduceReply) FromPB(pb *gdfspb.ReduceReply) {
+	*r = ReduceReply{
+		Size: pb.GetSize(),
+	}
+}
+
+// ToPB converts c to gdfspb.CommitArgs
+func (c *CommitArgs) ToPB() *gdfspb.CommitArgs {
+	return &gdfspb.CommitArgs{
+		JobId:          c.JobID,
+		Partition:      int64(c.Partition),
+		BlkList:        c.BlkList,
+		BlkSize:        c.BlkSize,
+		BlkToDataNodes: utils.StringsToPB(c.BlkToDataNodes),
+		GenStamp:       c.GenStamp,
+		Token:          c.Token,
+	}
+}
+
+// FromPB sets c to pb
+func (c *CommitArgs) FromPB(pb *gdfspb.CommitArgs) {
+	*c = CommitArgs{
+		JobID:          pb.GetJobId(),
+		Partition:      int(pb.GetPartition()),
+		BlkList:        pb.GetBlkList(),
+		BlkSize:        pb.GetBlkSize(),
+		BlkToDataNodes: utils.StringsFromPB(pb.GetBlkToDataNodes()),
+		GenStamp:       pb.GetGenStamp(),
+		Token:          pb.GetToken(),
+	}
+}
+
+// ToPB converts j to gdfspb.EndJobArgs
+func (j *JobArgs) ToPB() *gdfspb.EndJobArgs {
+	return &gdfspb.EndJobArgs{
+		JobId: j.JobID,
+		Token: j.Token,
+	}
+}
+
+// FromPB sets j to pb
+func (j *JobArgs) FromPB(pb *gdfspb.EndJobArgs) {
+	*j = JobArgs{
+		JobID: pb.GetJobId(),
+		Token: pb.GetToken(),
+	}
+}
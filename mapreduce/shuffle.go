@@ -0,0 +1,119 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapreduce
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"sort"
+)
+
+/** A job run with reduce tasks doesn't send its map outputs to namenode.
+ * Each datanode running a map task splits its partial result by key hash
+ * into one partition per reduce task and keeps them in memory (MapArgs
+ * with NumReduce set). Reduce task p then runs on a datanode which
+ * fetches partition p of every block from the datanodes which mapped
+ * them, reduces them and keeps the result as the lines of Lines. Namenode
+ * finally allocates the blocks of the output file part-p and the reducer
+ * sends them to their datanodes. Namenode only coordinates and maps the
+ * records straddling block boundaries, which are few.
+ * Since a reduce task only sees its own keys, the Reduce of such a job
+ * must work key by key, as WordCount does.
+ * */
+
+// Partition returns the reduce task of key among n
+func Partition(key string, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(n))
+}
+
+// Split partitions res among n reduce tasks
+func Split(res Result, n int) []Result {
+	parts := make([]Result, n)
+	for i := range parts {
+		parts[i] = make(Result)
+	}
+	for key, val := range res {
+		parts[Partition(key, n)][key] = val
+	}
+	return parts
+}
+
+// Lines renders res as "key\tvalue" lines sorted by key
+func Lines(res Result) []byte {
+	keys := make([]string, 0, len(res))
+	for key := range res {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b bytes.Buffer
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%v\t%v\n", key, res[key])
+	}
+	return b.Bytes()
+}
+
+// MapSource is a block and the datanode holding its map output
+type MapSource struct {
+	BlkID string
+	Addr  string
+}
+
+// FetchArgs asks a datanode for a partition of the map output of a block
+type FetchArgs struct {
+	JobID     string
+	BlkID     string
+	Partition int
+	Token     string // see config.AuthToken
+}
+
+// FetchReply is a partition of the map output of a block
+type FetchReply struct {
+	Result Result
+}
+
+// ReduceArgs asks a datanode to run reduce task Partition of a job
+type ReduceArgs struct {
+	JobID     string
+	Job       string
+	Partition int
+	Sources   []MapSource
+	Extra     []Result // the partition of the records mapped by namenode
+	Token     string   // see config.AuthToken
+}
+
+// ReduceReply is the size of the output of a reduce task in byte
+type ReduceReply struct {
+	Size int64
+}
+
+// CommitArgs asks a reducer to store its output as the blocks of BlkList
+type CommitArgs struct {
+	JobID          string
+	Partition      int
+	BlkList        []string
+	BlkSize        int64
+	BlkToDataNodes map[string][]string
+	GenStamp       int64  // generation stamp to write the blocks with
+	Token          string // see config.AuthToken
+}
+
+// JobArgs names a job
+type JobArgs struct {
+	JobID string
+	Token string // see config.AuthToken
+}
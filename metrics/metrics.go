@@ -0,0 +1,84 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics exposes the Prometheus metrics of namenode and datanodes
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+)
+
+// Namespace prefixes the name of every metric
+const Namespace = "gdfs"
+
+// NewRPCLatency returns the histogram of the time node, "namenode" or
+// "datanode", takes to serve each RPC, labeled by method
+func NewRPCLatency(node string) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: node,
+		Name:      "rpc_duration_seconds",
+		Help:      "Time taken to serve RPCs.",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 4, 10),
+	}, []string{"method"})
+}
+
+// Serve serves the metrics of reg at /metrics on a plain HTTP listener at
+// addr, it returns the listener so that the caller can close it
+func Serve(addr string, reg *prometheus.Registry) (net.Listener, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	go http.Serve(l, mux)
+	return l, nil
+}
+
+// ServerOptions observe the duration of each call to a gRPC server in
+// latency, labeled by method, e.g. "NameNode.RunCommand"
+func ServerOptions(latency *prometheus.HistogramVec) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{},
+			info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			defer observe(latency, info.FullMethod, time.Now())
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream,
+			info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			defer observe(latency, info.FullMethod, time.Now())
+			return handler(srv, ss)
+		}),
+	}
+}
+
+// observe records a call to the gRPC method named fullMethod, e.g.
+// "/gdfs.NameNode/RunCommand", started at start
+func observe(latency *prometheus.HistogramVec, fullMethod string, start time.Time) {
+	method := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.Index(method, "."); i >= 0 { // package
+		method = method[i+1:]
+	}
+	method = strings.Replace(method, "/", ".", 1)
+	latency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
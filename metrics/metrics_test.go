@@ -0,0 +1,87 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/utils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// deleter serves DeleteBlk only, failing on an empty block id
+type deleter struct {
+	gdfspb.UnimplementedDataNodeServer
+}
+
+func (deleter) DeleteBlk(ctx context.Context, args *gdfspb.DeleteBlkArgs) (*gdfspb.DeleteBlkReply, error) {
+	if args.BlkId == "" {
+		return nil, errors.New("Empty block id")
+	}
+	return &gdfspb.DeleteBlkReply{Status: true}, nil
+}
+
+func TestRPCLatency(t *testing.T) {
+	latency := NewRPCLatency("test")
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(latency)
+	serv := utils.NewServer(ServerOptions(latency)...)
+	gdfspb.RegisterDataNodeServer(serv, deleter{})
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go serv.Serve(l)
+	defer serv.Stop()
+	cc, err := utils.Dial(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+	dn := gdfspb.NewDataNodeClient(cc)
+	for _, blk := range []string{"a", "b", ""} {
+		reply, err := dn.DeleteBlk(context.Background(), &gdfspb.DeleteBlkArgs{BlkId: blk})
+		if (blk == "") != (err != nil) || reply.GetStatus() != (blk != "") {
+			t.Errorf("DeleteBlk(%q) = %v, %v", blk, reply, err)
+		}
+	}
+
+	ml, err := Serve("127.0.0.1:0", reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ml.Close()
+	resp, err := http.Get("http://" + ml.Addr().String() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// failed calls are timed as well
+	want := `gdfs_test_rpc_duration_seconds_count{method="DataNode.DeleteBlk"} 3`
+	if !strings.Contains(string(body), want) {
+		t.Errorf("metrics miss %q:\n%s", want, body)
+	}
+}
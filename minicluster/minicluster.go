@@ -0,0 +1,169 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package minicluster runs a whole cluster, a namenode and datanodes, in
+// the test process on free loopback ports, for end-to-end tests
+package minicluster
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/WineChord/gdfs/client"
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/datanode"
+	"github.com/WineChord/gdfs/namenode"
+)
+
+// Timeout is how long Start and WaitHealthy wait for the cluster
+var Timeout = 10 * time.Second
+
+// Cluster is a namenode with its datanodes, each datanode running as it
+// does in its own process, and a client of the namenode
+type Cluster struct {
+	NameNode  *namenode.NameNode
+	DataNodes []*datanode.DataNode
+	Client    *client.Client
+	// stopped[i] is closed once Run of DataNodes[i] returns
+	stopped []chan struct{}
+}
+
+// Start starts a namenode and numNodes datanodes keeping their files in
+// a temporary directory, which is the working directory until the test
+// ends, and waits for every datanode to send a heartbeat. Datanodes i
+// keeps its files in dn<i>. The cluster is stopped and the configuration
+// restored when the test ends.
+func Start(t *testing.T, numNodes int) *Cluster {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	t.Cleanup(overrideConfig())
+	c := &Cluster{NameNode: namenode.NewNameNode()}
+	if err := c.NameNode.Start(); err != nil {
+		t.Fatal(err)
+	}
+	config.NameNodeAddress = c.NameNode.Addr()
+	t.Cleanup(c.stop)
+	for i := 0; i < numNodes; i++ {
+		c.startDataNode(fmt.Sprintf("dn%v", i))
+	}
+	c.Client, err = client.New(config.NameNodeAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.waitLive(numNodes); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// overrideConfig points the configuration to free loopback ports and
+// disables the metrics and web UI servers, it returns the function
+// restoring the previous configuration
+func overrideConfig() (restore func()) {
+	nnAddr, nnMetrics, nnWeb := config.NameNodeAddress, config.NameNodeMetricsPort,
+		config.NameNodeWebPort
+	dnHost, dnPort, dnMetrics := config.DataNodeHost, config.DataNodePort,
+		config.DataNodeMetricsPort
+	dataPath, nidPath, sidPath, keyFile, dataDirs := config.DataPath,
+		config.NamespaceIDPath, config.StorageIDPath, config.BlkKeyFile, config.DataDirs
+	heartBeat := config.HeartBeatInSec
+	config.NameNodeAddress = "127.0.0.1:0"
+	config.NameNodeMetricsPort, config.NameNodeWebPort = "", ""
+	config.DataNodeHost, config.DataNodePort, config.DataNodeMetricsPort = "127.0.0.1", "0", ""
+	// blocks get reported within a second of being written
+	config.HeartBeatInSec = 1
+	return func() {
+		config.NameNodeAddress, config.NameNodeMetricsPort, config.NameNodeWebPort =
+			nnAddr, nnMetrics, nnWeb
+		config.DataNodeHost, config.DataNodePort, config.DataNodeMetricsPort =
+			dnHost, dnPort, dnMetrics
+		config.DataPath, config.NamespaceIDPath, config.StorageIDPath, config.BlkKeyFile,
+			config.DataDirs = dataPath, nidPath, sidPath, keyFile, dataDirs
+		config.HeartBeatInSec = heartBeat
+	}
+}
+
+// startDataNode runs a datanode keeping its files in dir
+func (c *Cluster) startDataNode(dir string) {
+	config.SetDataPath(dir)
+	d := datanode.NewDataNode()
+	stopped := make(chan struct{})
+	go func() {
+		d.Run()
+		close(stopped)
+	}()
+	c.DataNodes = append(c.DataNodes, d)
+	c.stopped = append(c.stopped, stopped)
+}
+
+// waitLive waits until num datanodes are live and sent a heartbeat
+func (c *Cluster) waitLive(num int) error {
+	var nodes []namenode.NodeStatus
+	var err error
+	for start := time.Now(); time.Since(start) < Timeout; time.Sleep(10 * time.Millisecond) {
+		nodes, err = c.Client.ReportNodes()
+		live := 0
+		for _, node := range nodes {
+			if node.State == namenode.NodeLive && node.LastHeartBeat > 0 {
+				live++
+			}
+		}
+		if err == nil && live == num {
+			return nil
+		}
+	}
+	return fmt.Errorf("datanodes are %+v after %v, want %v live: %v", nodes, Timeout,
+		num, err)
+}
+
+// WaitHealthy waits until every block of the files under dfs path p has
+// its replication factor of replicas, i.e. until datanodes reported the
+// blocks written
+func (c *Cluster) WaitHealthy(p string) error {
+	var report *namenode.FsckReport
+	var err error
+	for start := time.Now(); time.Since(start) < Timeout; time.Sleep(10 * time.Millisecond) {
+		report, err = c.Client.Fsck(p)
+		if err == nil && len(report.Unhealthy) == 0 {
+			return nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("files under %v are still unhealthy after %v: %+v", p, Timeout,
+		report.Unhealthy)
+}
+
+// stop stops the datanodes, then the namenode
+func (c *Cluster) stop() {
+	for i, d := range c.DataNodes {
+		d.Stop()
+		<-c.stopped[i]
+	}
+	if c.Client != nil {
+		c.Client.Close()
+	}
+	c.NameNode.Stop()
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minicluster
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/WineChord/gdfs/client"
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/utils"
+)
+
+func TestMain(m *testing.M) {
+	// every node of the cluster logs to the same output
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+func TestRoundTrip(t *testing.T) {
+	roundTrip(t)
+}
+
+// roundTrip formats a cluster, uploads a file of a few blocks to it and
+// downloads it back
+func roundTrip(t *testing.T) {
+	c := Start(t, 3)
+	if err := c.Client.Format(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Client.Mkdir("/a/b", true); err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, 2*config.ChunkSize+100)
+	rand.New(rand.NewSource(1)).Read(data)
+	if err := ioutil.WriteFile("f.bin", data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	// a few blocks, each streamed in chunks
+	opts := client.WriteOptions{BlockSize: int64(config.ChunkSize) + 1000}
+	if err := c.Client.CopyFromLocalOpts("f.bin", "/a/b", opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WaitHealthy("/a/b"); err != nil {
+		t.Fatal(err)
+	}
+	files, err := c.Client.Ls("/a/b")
+	if err != nil || !reflect.DeepEqual(files, []string{"f.bin"}) {
+		t.Fatalf("ls /a/b = %q, %v, want [f.bin]", files, err)
+	}
+	if err := c.Client.CopyToLocal("/a/b/f.bin", "back.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ioutil.ReadFile("back.bin"); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("copyToLocal got %v bytes differing from the %v uploaded, %v",
+			len(got), len(data), err)
+	}
+}
+
+// TestDataNodesOnOneHost runs two datanodes on the same host, each with
+// its own port and data directory given by a config file
+func TestDataNodesOnOneHost(t *testing.T) {
+	c := Start(t, 0)
+	var want []string
+	for i := 0; i < 2; i++ {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, port, _ := utils.SplitHostPort(l.Addr().String())
+		l.Close()
+		want = append(want, "127.0.0.1:"+port)
+		conf := fmt.Sprintf("dn%v.json", i)
+		data := fmt.Sprintf(`{"DataNodePort": %q, "DataPath": "dn%v"}`, port, i)
+		if err := ioutil.WriteFile(conf, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := config.Load(conf); err != nil {
+			t.Fatal(err)
+		}
+		c.startDataNode(config.DataPath)
+	}
+	if err := c.waitLive(2); err != nil {
+		t.Fatal(err)
+	}
+	nodes, err := c.Client.ReportNodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, node := range nodes {
+		got = append(got, node.Addr)
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("datanodes serve at %v, want %v", got, want)
+	}
+
+	if err := ioutil.WriteFile("f.txt", []byte("on both nodes"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts := client.WriteOptions{Replication: 2}
+	if err := c.Client.CopyFromLocalOpts("f.txt", "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WaitHealthy("/f.txt"); err != nil {
+		t.Fatal(err)
+	}
+}
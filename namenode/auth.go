@@ -0,0 +1,41 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/logger"
+)
+
+// ErrUnauthorized is returned for calls without a valid token
+var ErrUnauthorized = errors.New("Invalid token")
+
+// CheckToken validates the token of a call against config.AuthToken, the
+// secret shared by clients, datanodes and namenode. Datanodes check the
+// calls they serve with it as well. Every call is accepted if no secret
+// is configured.
+func CheckToken(method, token string) error {
+	if config.AuthToken == "" {
+		return nil
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(config.AuthToken)) != 1 {
+		logger.Warnf("reject %v with an invalid token\n", method)
+		return ErrUnauthorized
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/utils"
+)
+
+func TestAuthToken(t *testing.T) {
+	n := newTestNameNode(t)
+	defer func(token string) { config.AuthToken = token }(config.AuthToken)
+	config.AuthToken = "s3cret"
+	addr := "127.0.0.1:11170"
+	for _, token := range []string{"", "wrong", "s3cret0", "s3cre"} {
+		err := n.Handshake(&HandshakeArgs{NamespaceID: -1, Addr: addr, Token: token},
+			&HandshakeReply{})
+		if err != ErrUnauthorized {
+			t.Errorf("handshake with token %q = %v, want %v", token, err, ErrUnauthorized)
+		}
+		err = n.Register(&RegisterArgs{HostName: addr, Addr: addr, Token: token},
+			&RegisterReply{})
+		if err != ErrUnauthorized {
+			t.Errorf("register with token %q = %v, want %v", token, err, ErrUnauthorized)
+		}
+		err = n.RunCommand(&CommandArgs{CommandType: config.Format, Token: token},
+			&CommandReply{})
+		if err != ErrUnauthorized {
+			t.Errorf("format with token %q = %v, want %v", token, err, ErrUnauthorized)
+		}
+		err = n.CancelJob(&JobArgs{JobID: "x", Token: token}, &JobStatus{})
+		if err != ErrUnauthorized {
+			t.Errorf("cancelJob with token %q = %v, want %v", token, err, ErrUnauthorized)
+		}
+		err = n.Notify(&NotifyArgs{Addrs: []string{addr}, Token: token}, &NotifyReply{})
+		if err != ErrUnauthorized {
+			t.Errorf("notify with token %q = %v, want %v", token, err, ErrUnauthorized)
+		}
+	}
+	n.mu.Lock()
+	joined := len(n.Addr2SID)
+	n.mu.Unlock()
+	if joined != 0 {
+		t.Errorf("%v datanodes joined without a valid token", joined)
+	}
+
+	err := n.Handshake(&HandshakeArgs{NamespaceID: -1, Addr: addr, Token: "s3cret"},
+		&HandshakeReply{})
+	if err != nil {
+		t.Errorf("handshake with the token: %v", err)
+	}
+	err = n.Register(&RegisterArgs{HostName: addr, Addr: addr, Token: "s3cret"},
+		&RegisterReply{})
+	if err != nil {
+		t.Errorf("register with the token: %v", err)
+	}
+	// a registered datanode cannot be impersonated without the token
+	blk := "a.txt-00000000-1-1"
+	metas := map[string]utils.MetaData{blk: {Length: 10}}
+	for _, token := range []string{"", "wrong"} {
+		err = n.HeartBeat(&HeartBeatArgs{HostName: addr, Addr: addr, Token: token},
+			&HeartBeatReply{})
+		if err != ErrUnauthorized {
+			t.Errorf("heartbeat with token %q = %v, want %v", token, err, ErrUnauthorized)
+		}
+		err = n.ReportBlock(&ReportBlockArgs{Addr: addr, IDToMetaData: metas, Token: token},
+			&ReportBlockReply{})
+		if err != ErrUnauthorized {
+			t.Errorf("block report with token %q = %v, want %v", token, err, ErrUnauthorized)
+		}
+	}
+	if got := n.BlkToDatanodes[blk]; len(got) != 0 {
+		t.Errorf("replicas %v recorded from block reports without a valid token", got)
+	}
+	err = n.ReportBlock(&ReportBlockArgs{Addr: addr, IDToMetaData: metas, Token: "s3cret"},
+		&ReportBlockReply{})
+	if err != nil || len(n.BlkToDatanodes[blk]) != 1 {
+		t.Errorf("block report with the token = %v, replicas %v", err, n.BlkToDatanodes[blk])
+	}
+	err = n.ReportCorruptBlock(&ReportCorruptBlockArgs{Addr: addr, BlkIDs: []string{blk},
+		Token: "wrong"}, &ReportCorruptBlockReply{})
+	if err != ErrUnauthorized || len(n.BlkToDatanodes[blk]) != 1 {
+		t.Errorf("corrupt block report with a wrong token = %v, replicas %v", err,
+			n.BlkToDatanodes[blk])
+	}
+	err = n.HeartBeat(&HeartBeatArgs{HostName: addr, Addr: addr, Token: "s3cret"},
+		&HeartBeatReply{})
+	if err != nil {
+		t.Errorf("heartbeat with the token: %v", err)
+	}
+	runCommand(t, n, &CommandArgs{CommandType: config.Mkdir, DPath: "/a", Token: "s3cret"})
+	ls := runCommand(t, n, &CommandArgs{CommandType: config.Ls, DPath: "/", Token: "s3cret"})
+	if len(ls.Files) != 1 {
+		t.Errorf("ls / with the token = %q, want [a]", ls.Files)
+	}
+}
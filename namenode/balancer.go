@@ -0,0 +1,140 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"sort"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// blkMove moves a replica from datanode Src to datanode Dst (storage ids).
+// The block is first replicated to Dst through the heartbeat of Src, and
+// only when Dst has reported it, Src is told to remove its replica, so the
+// block never drops below its replication factor during the move.
+type blkMove struct {
+	Src       string
+	Dst       string
+	Scheduled int64 // time in ms when the copy was last sent to Src
+}
+
+// Balance plans block moves from over-utilized datanodes to under-utilized
+// ones, until every datanode is expected to be within config.BalanceThreshold
+// of the cluster average utilization. Utilization is FracInUse from the
+// last heartbeat. Moves are carried out through heartbeat replies, it
+// returns the number of moves planned. Previously planned moves which are
+// not done yet are dropped.
+func (n *NameNode) Balance() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.Moves = make(map[string]*blkMove)
+	util := make(map[string]float64)    // storage id -> fraction in use
+	capacity := make(map[string]uint64) // storage id -> capacity in byte
+	for addr, sid := range n.Addr2SID {
+		stat, ok := n.NodeStats[addr]
+		if !ok || stat.TotalCapacity == 0 || !n.inService(addr) {
+			continue
+		}
+		util[sid] = stat.FracInUse
+		capacity[sid] = stat.TotalCapacity
+	}
+	if len(util) < 2 {
+		logger.Warnf("balancer: not enough datanodes with stats\n")
+		return 0
+	}
+	avg := 0.0
+	for _, u := range util {
+		avg += u
+	}
+	avg /= float64(len(util))
+	holds := make(map[string][]string) // storage id -> blocks
+	for blk, nodes := range n.BlkToDatanodes {
+		for _, sid := range nodes {
+			holds[sid] = append(holds[sid], blk)
+		}
+	}
+	sids := make([]string, 0, len(util))
+	for sid := range util {
+		sids = append(sids, sid)
+	}
+	sort.Slice(sids, func(i, j int) bool { return util[sids[i]] > util[sids[j]] })
+	logger.Infof("balancer: average utilization %.4f\n", avg)
+	for _, src := range sids {
+		for _, blk := range holds[src] {
+			if util[src] <= avg+config.BalanceThreshold {
+				break
+			}
+			nodes := n.BlkToDatanodes[blk]
+			if _, ok := n.Moves[blk]; ok || len(nodes) < n.replicationOf(blk) {
+				continue // under-replicated blocks are left to re-replication
+			}
+			// the emptiest datanode below average lacking the block
+			dst := ""
+			for _, sid := range sids {
+				if util[sid] < avg && !contains(nodes, sid) &&
+					(dst == "" || util[sid] < util[dst]) {
+					dst = sid
+				}
+			}
+			if dst == "" {
+				continue
+			}
+			size := float64(n.BlkMeta[blk].Length)
+			util[src] -= size / float64(capacity[src])
+			util[dst] += size / float64(capacity[dst])
+			n.Moves[blk] = &blkMove{Src: src, Dst: dst}
+		}
+	}
+	logger.Infof("balancer: %v block moves planned\n", len(n.Moves))
+	return len(n.Moves)
+}
+
+// scheduleMoves returns the balancer moves whose source is the datanode
+// at addr and whose copy is not done yet, mapping block id to target
+// address. A datanode with config.BalanceMaxTrans transfers in progress
+// gets its moves on a later heartbeat. The caller should hold n.mu.
+func (n *NameNode) scheduleMoves(addr string) map[string]string {
+	res := make(map[string]string)
+	sid, ok := n.Addr2SID[addr]
+	if !ok {
+		return res
+	}
+	if n.NodeStats[addr].NumDataTrans >= config.BalanceMaxTrans {
+		logger.Warnf("balancer: %v is busy, postpone its moves\n", addr)
+		return res
+	}
+	now := utils.GetCurrentTimeInMs()
+	for blk, mv := range n.Moves {
+		if mv.Src != sid {
+			continue
+		}
+		nodes := n.BlkToDatanodes[blk]
+		dstAddr := n.SID2Addr[mv.Dst]
+		if _, live := n.Addr2SID[dstAddr]; !live || !contains(nodes, sid) {
+			delete(n.Moves, blk) // the move cannot be done anymore
+			continue
+		}
+		if contains(nodes, mv.Dst) ||
+			now-mv.Scheduled < int64(config.RepPendingInSec)*1000 {
+			continue
+		}
+		logger.Infof("balancer: move block %v from %v to %v\n", blk, addr, dstAddr)
+		mv.Scheduled = now
+		res[blk] = dstAddr
+	}
+	return res
+}
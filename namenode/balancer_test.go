@@ -0,0 +1,59 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import "testing"
+
+func TestBalanceConverges(t *testing.T) {
+	c := newFakeCluster(t, 0)
+	const capacity, blks, blkSize = 100000, 20, 2000
+	full := c.addNode(capacity, 0)
+	reply := c.write("/", "a.bin", blks*blkSize, blkSize, 1)
+	for i := 0; i < 3; i++ {
+		c.addNode(capacity, 0)
+	}
+	for _, addr := range c.addrs {
+		c.heartbeat(addr)
+	}
+	if c.n.Balance() == 0 {
+		t.Fatalf("no moves planned with every block on %v", full)
+	}
+	for round := 0; round < 5; round++ {
+		for _, addr := range c.addrs {
+			c.heartbeat(addr)
+			for _, blk := range reply.BlkList {
+				if len(c.holders(blk)) == 0 {
+					t.Fatalf("%v has no replica during balancing", blk)
+				}
+			}
+		}
+	}
+	// the average is 0.1 and so is the threshold
+	for _, addr := range c.addrs {
+		if frac := float64(c.used(addr)) / capacity; frac > 0.2 {
+			t.Errorf("%v is %.2f full after balancing", addr, frac)
+		}
+	}
+	for _, blk := range reply.BlkList {
+		if holders := c.holders(blk); len(holders) != 1 {
+			t.Errorf("%v is held by %v after balancing, want one datanode", blk, holders)
+		}
+	}
+	c.n.mu.Lock()
+	defer c.n.mu.Unlock()
+	if len(c.n.Moves) != 0 {
+		t.Errorf("%v moves left after balancing", len(c.n.Moves))
+	}
+}
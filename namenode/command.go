@@ -15,20 +15,19 @@
 package namenode
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"log"
-	"math/rand"
-	"net/rpc"
-	"os"
-	"path/filepath"
-	"strconv"
+	"path"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/mapreduce"
 	"github.com/WineChord/gdfs/utils"
 )
 
@@ -39,19 +38,134 @@ type CommandArgs struct {
 	DPaths      []string // paths in distributed file system
 	FileName    string   // file name (both local and dist)
 	FileSize    int64    // file size in byte
+	Overwrite   bool     // overwrite destination file if it exists
+	BlockSize   int64    // block size of a new file in byte, 0 for default
+	Replication int      // replication factor of a file, 0 for default
+	// Timeout is how long the client waits for the command, namenode gives
+	// up on datanodes before, 0 for config.RPCTimeoutInSec
+	Timeout time.Duration
+	Job     string // name of the mapreduce job to run
+	// NumReduce is the number of reduce tasks run on datanodes, 0 to
+	// reduce on namenode
+	NumReduce int
+	Output    string // dfs directory of the reduce outputs
+	Token     string // see config.AuthToken
+	Recursive bool   // ls walks the whole subtree, listing full paths
+	Long      bool   // ls reports a FileStat per entry in Stats
+	Addr      string // datanode address of admin commands
+	User      string // user running the command, owner of its trash
+	SkipTrash bool   // rm deletes files even if the trash is enabled
+	Quota     int64  // name or space quota of setquota, 0 to remove it
+	// Digest is the digest of the file uploaded to DPath for SetDigest,
+	// over the ChecksumType checksums of the blocks of BlkList
+	Digest       string
+	ChecksumType string
+	BlkList      []string
+	// Pattern is the regular expression of grep, matched regardless of
+	// case with IgnoreCase, CountOnly leaves the matching lines out
+	Pattern    string
+	IgnoreCase bool
+	CountOnly  bool
+	// K is the number of records kept by topN, compared as strings
+	// rather than integers with Lexical
+	K       int
+	Lexical bool
 }
 
 // CommandReply stores reply for RPC
 type CommandReply struct {
 	Result         string
 	Files          []string
+	Paths          []string            // path arguments after glob expansion
 	BlkList        []string            // the block names of a file
+	BlkSize        int64               // block size of the file in byte
+	FileSize       int64               // file size in byte, from block reports
+	BlkLengths     []int64             // length of each block of BlkList, from block reports
+	SrcBlkList     []string            // the block names of the source file
 	BlkToDataNodes map[string][]string // map blockname to datanodes list
+	GenStamp       int64               // generation stamp to write the blocks of BlkList with
+	Errors         map[string]string   // per path error for multi-path commands
+	Stats          []FileStat          // metadata for each path of stat or ls -l
+	Checksums      map[string]string   // whole-file checksum keyed by path
+	WordCounts     map[string]int      // number of occurrences of each word
+	JobResult      mapreduce.Result    // result of a mapreduce job
+	Usage          []DiskUsage         // space used by each entry of du
+	Fsck           *FsckReport         // health of the files of fsck
+	Trashed        map[string]string   // path in trash of files moved by rm
+	Counts         []DirCount          // totals and quotas of count
+	Replicated     []string            // blocks of BlkList fully replicated, see UploadStatus
+	Digest         string              // digest of the file recorded at upload, "" if none
+	DigestType     string              // checksum type of the blocks Digest is computed over
+	Lines          []string            // lines matched by grep in file order, or records of topN
+	NumLines       int                 // number of lines matched by grep
+}
+
+// FileStat stores metadata of a dfs file or directory
+type FileStat struct {
+	Path        string
+	IsDir       bool
+	Size        int64 // file size in byte
+	NumBlks     int   // number of blocks
+	BlkSize     int64 // block size in byte
+	Replication int   // replication factor
+	ModTime     int64 // modification time in ms
+	AccessTime  int64 // access time of a file in ms, see config.AccessTimeInSec
+}
+
+// DiskUsage stores the space used by a dfs file or directory subtree
+type DiskUsage struct {
+	Path       string
+	Size       int64 // sum of the file sizes in byte
+	Replicated int64 // sum of size times replication factor in byte
+}
+
+// DirCount stores the totals of a dfs subtree and the quotas of its root
+type DirCount struct {
+	Path          string
+	Dirs          int64 // number of directories, the root included
+	Files         int64 // number of files
+	Bytes         int64 // sum of the file sizes in byte
+	NsQuota       int64 // 0 for none
+	SpaceQuota    int64 // in byte, 0 for none
+	SpaceConsumed int64 // Bytes times replication, counted against SpaceQuota
+}
+
+// checkArgs validates every path and file name in args before any
+// command touches the namespace
+func checkArgs(args *CommandArgs) error {
+	if args.DPath != "" {
+		if err := checkPath(args.DPath); err != nil {
+			return err
+		}
+	}
+	for _, p := range args.DPaths {
+		if err := checkPath(p); err != nil {
+			return err
+		}
+	}
+	if args.Output != "" {
+		if err := checkPath(args.Output); err != nil {
+			return err
+		}
+	}
+	if args.CommandType == config.CopyFromLocal {
+		return checkName(args.FileName)
+	}
+	return nil
 }
 
 // RunCommand runs a command on data node
 func (n *NameNode) RunCommand(args *CommandArgs, reply *CommandReply) error {
-	log.Printf("inside RunCommand\n")
+	logger.Debugf("inside RunCommand\n")
+	if err := CheckToken("RunCommand", args.Token); err != nil {
+		return err
+	}
+	if err := checkArgs(args); err != nil {
+		return err
+	}
+	if err := n.expandGlobs(args, reply); err != nil {
+		return err
+	}
 	switch args.CommandType {
 	case config.CalMeanVar:
 		return n.runCalMeanVar(args, reply)
@@ -75,104 +189,322 @@ func (n *NameNode) RunCommand(args *CommandArgs, reply *CommandReply) error {
 		return n.runTouch(args, reply)
 	case config.Format:
 		return n.runFormat(args, reply)
+	case config.AppendToFile:
+		return n.runAppendToFile(args, reply)
+	case config.Stat:
+		return n.runStat(args, reply)
+	case config.Cp:
+		return n.runCp(args, reply)
+	case config.Mv:
+		return n.runMv(args, reply)
+	case config.SetRep:
+		return n.runSetRep(args, reply)
+	case config.Balance:
+		return n.runBalance(args, reply)
+	case config.Checksum:
+		return n.runChecksum(args, reply)
+	case config.WordCount:
+		return n.runWordCount(args, reply)
+	case config.RunJob:
+		return n.runJob(args, reply)
+	case config.Du:
+		return n.runDu(args, reply)
+	case config.Decommission:
+		return n.runDecommission(args, reply)
+	case config.Fsck:
+		return n.runFsck(args, reply)
+	case config.Expunge:
+		return n.runExpunge(args, reply)
+	case config.SetQuota, config.SetSpaceQuota:
+		return n.runSetQuota(args, reply)
+	case config.Count:
+		return n.runCount(args, reply)
+	case config.UploadStatus:
+		return n.runUploadStatus(args, reply)
+	case config.SetDigest:
+		return n.runSetDigest(args, reply)
+	case config.Truncate:
+		return n.runTruncate(args, reply)
+	case config.Grep:
+		return n.runGrep(args, reply)
+	case config.TopN:
+		return n.runTopN(args, reply)
 	default:
 		return errors.New("Unsupport command type")
 	}
 }
 
 func (n *NameNode) runCalMeanVar(args *CommandArgs, reply *CommandReply) error {
-	log.Printf("inside runCalMeanVar\n")
-	// path := n.makePath(args.DPath) // meta/gdfs/perline.txt
-	blkList := n.readDfsFile(args.DPath)
+	logger.Debugf("inside runCalMeanVar\n")
 	/** In order to calculate the mean and variance, we need map and reduce
 	 * tasks. For map tasks, each segment gets calculated by the datanode holding
-	 * that segment. The results are count, mean, and mean square for each segment.
-	 * This will result in three files for each segment (count/mean/meansq)
-	 * Then we start two reduce tasks:
-	 * 	1. read every count and mean files to calculate MEAN (mean of total) and MEAN^2
-	 *  2. read every count and meansq files to calculate MEANSQ (mean square of total)
-	 * finally we can get variance by MEANSQ - MEAN^2
+	 * that segment. The results are count, sum, and sum of squares for each
+	 * segment. Then the reduce task adds them up to get MEAN (mean of total)
+	 * and MEANSQ (mean square of total), finally we can get variance by
+	 * MEANSQ - MEAN^2, see mapreduce.MeanVar
 	 * */
-	// Now we've got list of segments to process
-	totCnt := int64(0)
-	totMean := float64(0)
-	totSQ := float64(0)
-	var mu sync.Mutex
-	finished := 0
-	cond := sync.NewCond(&mu)
+	args.Job = mapreduce.MeanVarJob
+	return n.runJob(args, reply)
+}
+
+// mapBlks runs the map task fn for each block of blkList in parallel.
+/** Each block is given to its datanodes in turn until fn succeeds on one,
+ * fn is passed the index of the block in blkList, the address of the
+ * datanode and how long it may wait for the datanode. A datanode which
+ * fails or doesn't answer before config.BlkReadTimeoutInSec or the
+ * deadline of the command counts as failed and the next one is tried.
+ * If fn fails on every datanode of a block, mapBlks fails rather than
+ * letting the command reduce a part of the file.
+ * The state of each task is tracked in run, once run is canceled no
+ * datanode is tried anymore.
+ * */
+func (n *NameNode) mapBlks(run *jobRun, args *CommandArgs, blkList []string,
+	fn func(i int, blk, addr string, timeout time.Duration) error) error {
+	timeout := args.Timeout
+	if timeout <= 0 {
+		timeout = utils.RPCTimeout()
+	}
+	deadline := time.Now().Add(timeout)
+	blkToAddrs := make(map[string][]string)
+	n.mu.Lock()
 	for _, blk := range blkList {
-		nodes := n.BlkToDatanodes[blk]
-		go func(s string, ns []string) {
-			for _, nd := range ns {
-				if nd == "" {
-					continue
-				}
-				reply, ok := n.reqCalMeanVar(s, n.SID2Addr[nd])
-				if ok {
-					log.Printf("map result for %v: %v\n", s, reply)
-					totCnt += reply.Cnt
-					totMean += reply.Mean * float64(reply.Cnt)
-					totSQ += reply.MeanSQ * float64(reply.Cnt)
+		for _, sid := range n.BlkToDatanodes[blk] {
+			if sid != "" {
+				blkToAddrs[blk] = append(blkToAddrs[blk], n.SID2Addr[sid])
+			}
+		}
+	}
+	n.mu.Unlock()
+	for _, blk := range blkList {
+		run.setTask(blk, TaskPending)
+	}
+	var failed []string
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, blk := range blkList {
+		wg.Add(1)
+		go func(i int, blk string, addrs []string) {
+			defer wg.Done()
+			for _, addr := range addrs {
+				if run.canceled() {
 					break
 				}
+				run.setTask(blk, TaskRunning)
+				timeout := time.Duration(config.BlkReadTimeoutInSec) * time.Second
+				if left := time.Until(deadline); left < timeout {
+					timeout = left
+				}
+				err := fn(i, blk, addr, timeout)
+				if err == nil {
+					run.setTask(blk, TaskDone)
+					return
+				}
+				logger.Warnf("map task of %v on %v failed: %v\n", blk, addr, err)
 			}
-			finished++
-			cond.Broadcast()
-		}(blk, nodes)
-	}
-	mu.Lock()
-	for finished != len(blkList) {
-		cond.Wait()
-		log.Printf("calMeanVar map done %v\n", finished)
-	}
-	mu.Unlock()
-	totMean /= float64(totCnt)
-	totSQ /= float64(totCnt)
-	variance := totSQ - totMean*totMean
-	reply.Result = fmt.Sprintf("mean: %v, variance: %v\n", totMean, variance)
+			run.setTask(blk, TaskFailed)
+			mu.Lock()
+			failed = append(failed, blk)
+			mu.Unlock()
+		}(i, blk, blkToAddrs[blk])
+	}
+	wg.Wait()
+	logger.Debugf("map done for %v blocks\n", len(blkList))
+	if run.canceled() {
+		return ErrJobCanceled
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("No datanode answered for blocks %v", failed)
+	}
 	return nil
 }
 
-func (n *NameNode) reqCalMeanVar(blk string, addr string) (utils.CalMVReply, bool) {
-	args := utils.CalMVArgs{}
-	args.BlkID = blk
-	reply := utils.CalMVReply{}
-	c, err := rpc.DialHTTP("tcp", addr)
-	log.Printf("request calMeanVar for %v from %v\n", blk, addr)
+func (n *NameNode) runWordCount(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runWordCount\n")
+	/** wordCount works like calMeanVar: each block is mapped to the count
+	 * of its words by a datanode holding it, namenode reduces the counts
+	 * of all blocks. A word may straddle a block boundary, so the map of a
+	 * block leaves out its first and last words if they touch the block
+	 * boundaries (see utils.Edges), and the reducer glues them together
+	 * with the neighbouring blocks in block order.
+	 * */
+	args.Job = mapreduce.WordCountJob
+	run := n.newJob(args)
+	res, err := n.mapReduce(run, args)
+	if err == nil {
+		reply.WordCounts = make(map[string]int, len(res))
+		for word, cnt := range res {
+			reply.WordCounts[word] = int(cnt)
+		}
+		reply.Result = fmt.Sprintf("%v distinct words\n", len(reply.WordCounts))
+	}
+	return run.finish(reply, err)
+}
+
+// grepJob names grep in the job status
+const grepJob = "grep"
+
+func (n *NameNode) runGrep(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runGrep\n")
+	/** grep ships the pattern to a datanode holding each block, which
+	 * matches the lines lying entirely in the block (GrepMap). A line may
+	 * straddle a block boundary, so the datanodes send back the partial
+	 * lines at both ends of their block, namenode glues them together in
+	 * block order and matches them itself. The lines are gathered in file
+	 * order.
+	 * */
+	re, err := utils.GrepRegexp(args.Pattern, args.IgnoreCase)
 	if err != nil {
-		log.Fatal("dialing: ", err)
+		return err
 	}
-	err = c.Call("DataNode.CalMeanVarMap", &args, &reply)
+	file, err := n.getFile(args.DPath)
 	if err != nil {
-		log.Fatal("Calling: ", err)
+		return err
+	}
+	args.Job = grepJob
+	run := n.newJob(args)
+	replies := make([]utils.GrepReply, len(file.BlkList))
+	err = n.mapBlks(run, args, file.BlkList, func(i int, blk, addr string, timeout time.Duration) error {
+		logger.Debugf("request grep of %v from %v\n", blk, addr)
+		gargs := utils.GrepArgs{BlkID: blk, Pattern: args.Pattern,
+			IgnoreCase: args.IgnoreCase, CountOnly: args.CountOnly, Token: config.AuthToken}
+		return run.call(addr, timeout, func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+			reply, err := dn.GrepMap(ctx, gargs.ToPB())
+			replies[i].FromPB(reply)
+			return err
+		})
+	})
+	if err == nil {
+		/** lines are glued as in JoinEdges, but an empty one is a line
+		 * as well: a block starting with a newline ends the line seen so
+		 * far, possibly empty
+		 * */
+		pending := "" // the part of a line seen so far
+		match := func(line string) {
+			if re.MatchString(line) {
+				reply.NumLines++
+				if !args.CountOnly {
+					reply.Lines = append(reply.Lines, line)
+				}
+			}
+		}
+		for _, r := range replies {
+			if r.Whole {
+				pending += r.Head
+				continue
+			}
+			match(pending + r.Head)
+			reply.NumLines += r.Count
+			reply.Lines = append(reply.Lines, r.Lines...)
+			pending = r.Tail
+		}
+		if pending != "" { // the file does not end with a newline
+			match(pending)
+		}
+		reply.Result = fmt.Sprintf("%v matching lines\n", reply.NumLines)
 	}
-	return reply, true
+	return run.finish(reply, err)
+}
+
+// topNJob names topN in the job status
+const topNJob = "topN"
+
+func (n *NameNode) runTopN(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runTopN\n")
+	/** topN has a datanode holding each block rank the lines lying
+	 * entirely in the block and send back its K greatest (TopNMap), so the
+	 * file is never shipped as a whole. The global K greatest are among
+	 * the partial ones and the lines straddling block boundaries, which
+	 * namenode glues from the edges of the blocks as in wordCount.
+	 * */
+	if args.K <= 0 {
+		return errors.New("The count of topN should be positive")
+	}
+	file, err := n.getFile(args.DPath)
+	if err != nil {
+		return err
+	}
+	args.Job = topNJob
+	run := n.newJob(args)
+	replies := make([]utils.TopNReply, len(file.BlkList))
+	err = n.mapBlks(run, args, file.BlkList, func(i int, blk, addr string, timeout time.Duration) error {
+		logger.Debugf("request top %v of %v from %v\n", args.K, blk, addr)
+		targs := utils.TopNArgs{BlkID: blk, K: args.K, Lexical: args.Lexical,
+			Token: config.AuthToken}
+		return run.call(addr, timeout, func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+			reply, err := dn.TopNMap(ctx, targs.ToPB())
+			replies[i].FromPB(reply)
+			return err
+		})
+	})
+	if err == nil {
+		var records []string
+		edges := make([]utils.Edges, len(replies))
+		for i, r := range replies {
+			records = append(records, r.Top...)
+			edges[i] = r.Edges
+		}
+		utils.JoinEdges(edges, func(record string) {
+			records = append(records, record)
+		})
+		reply.Lines = utils.TopN(records, args.K, args.Lexical)
+		reply.Result = fmt.Sprintf("%v greatest records\n", len(reply.Lines))
+	}
+	return run.finish(reply, err)
 }
 
 func (n *NameNode) runCat(args *CommandArgs, reply *CommandReply) error {
-	//
-	log.Printf("inside runCat\n")
+	logger.Debugf("inside runCat\n")
+	/** cat works the same way as copyToLocal from namenode's perspective:
+	 * we return the ordered block list of the file together with the
+	 * datanodes holding each block, the client does the actual reading.
+	 * The only difference is that we refuse directories and missing paths
+	 * explicitly, so the client won't silently print nothing.
+	 * */
+	file, err := n.getFile(args.DPath)
+	if err != nil {
+		return err
+	}
+	n.accessed(args.DPath)
+	reply.BlkList = file.BlkList
+	reply.BlkSize = file.blkSize()
+	n.fillBlkLocations(reply)
 	return nil
 }
 
 func (n *NameNode) runCopyFromLocal(args *CommandArgs, reply *CommandReply) error {
-	log.Printf("inside runCopyFromLocal\n")
-	path := n.makePath(args.DPath) // meta/gdfs/
-	fileinfo, err := os.Stat(path)
-	if err != nil {
-		return err
+	logger.Debugf("inside runCopyFromLocal\n")
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	dir := n.root.lookup(args.DPath)
+	if dir == nil {
+		return ErrNotFound
 	}
-	if fileinfo.IsDir() == false {
+	if dir.IsDir == false {
 		return errors.New("The destination of copyFromLocal should be a directory")
 	}
-	distFilePath := filepath.Join(path, args.FileName)
-	// distFilePath := path + string(os.PathSeparator) + args.FileName // meta/gdfs//
-	log.Printf("local file name: %v\n", args.FileName)
-	log.Printf("distFilePath: %v\n", distFilePath)
-	fileinfo, err = os.Stat(distFilePath)
-	if err == nil && fileinfo.IsDir() == false {
+	distFilePath := path.Join(cleanPath(args.DPath), args.FileName)
+	logger.Debugf("local file name: %v\n", args.FileName)
+	logger.Debugf("distFilePath: %v\n", distFilePath)
+	old := n.root.lookup(distFilePath)
+	if old != nil && (!args.Overwrite || old.IsDir) {
 		return errors.New("File exists")
 	}
+	if args.BlockSize < 0 {
+		return errors.New("Invalid block size")
+	}
+	if err := n.checkReplication(args.Replication); err != nil {
+		return err
+	}
+	file := &inode{BlkSize: args.BlockSize, Replication: args.Replication}
+	names := int64(1)
+	if old != nil {
+		names = 0 // the new file takes the place of the old one
+	}
+	err := n.checkQuota(distFilePath, names, args.FileSize*int64(file.replication()))
+	if err != nil {
+		return err
+	}
 	/** Should divide files into segments, segment size see configuration (e.g. 4KB)
 	 * We maintain a file -> list of segments map
 	 * each segment's name is of format:
@@ -193,149 +525,729 @@ func (n *NameNode) runCopyFromLocal(args *CommandArgs, reply *CommandReply) erro
 	 * data split and it will not send any data segments directly to datanode.
 	 * Therefore, the only crucial thing in argument from client is FileSize.
 	 * */
-	numBlks := int((args.FileSize-1)/int64(config.BlkSize) + 1)
-	reply.BlkToDataNodes = make(map[string][]string)
-	reply.BlkList = make([]string, 0)
-	log.Printf("number of blocks: %v, totalsize: %v, block size: %v\n", numBlks,
-		args.FileSize, config.BlkSize)
-	log.Printf("current nodes available: %v\n", len(n.Addr2SID))
-	log.Printf("%v\n", n.Addr2SID)
-	for i := 0; i < numBlks; i++ {
-		segmentName := generateSegName(args.FileName, i)
-		// reply.BlkList is needed because we need an orded list of segment
-		// file names. The map itself is unordered.
-		reply.BlkList = append(reply.BlkList, segmentName)
-		nodeList := make([]string, 0)
-		for addr := range n.Addr2SID {
-			// because map is random in Go, therefore we directly use for to
-			// generate 3 random nodes
-			if len(nodeList) >= config.ReplicationFactor {
-				break
-			}
-			nodeList = append(nodeList, addr)
+	reply.BlkSize = file.blkSize()
+	numBlks := int((args.FileSize-1)/reply.BlkSize + 1)
+	if args.FileSize == 0 { // an empty file owns no block at all
+		numBlks = 0
+	}
+	logger.Debugf("number of blocks: %v, totalsize: %v, block size: %v\n", numBlks,
+		args.FileSize, reply.BlkSize)
+	// allocate before dropping an overwritten file, so a failure leaves it
+	err = n.allocateBlks(args.FileName, 0, numBlks, file, reply)
+	if err != nil {
+		return err
+	}
+	if old != nil {
+		logger.Debugf("overwrite %v\n", distFilePath)
+		oldBlks, err := n.deleteFile(distFilePath)
+		if err != nil {
+			return err
 		}
-		reply.BlkToDataNodes[segmentName] = nodeList
-		log.Printf("%v seg: %v, list: %v\n", args.FileName, segmentName, nodeList)
+		n.dropBlks(oldBlks)
 	}
 	// here namenode should not update its BlkToDatanodes map, since data hasn't
 	// been stored on datanode yet. the information will be updated when datanode
 	// has stored the replica.
-	// However, it will store the file->blocks map on disk
-	// file->blocks will be stored as json files on disk
-	file, err := os.Create(distFilePath)
+	// However, it will store the file->blocks map in the namespace tree
+	// which is persisted by the edit log
+	e := &journalEntry{Op: opWrite, Path: distFilePath, BlkList: reply.BlkList}
+	e.BlkSize = args.BlockSize
+	e.Replication = args.Replication
+	return n.logAndApply(e)
+}
+
+// runUploadStatus lists in reply.Replicated the blocks of the file at
+// args.DPath which have as many replicas as its replication factor, so
+// that a client resuming the upload of the file skips them. The other
+// blocks of reply.BlkList are placed again in reply.BlkToDataNodes, to be
+// written with reply.GenStamp.
+func (n *NameNode) runUploadStatus(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runUploadStatus\n")
+	file, err := n.getFile(args.DPath)
 	if err != nil {
-		log.Printf("error when creating dist file: %v\n", err)
+		return err
+	}
+	reply.BlkList = file.BlkList
+	reply.BlkSize = file.blkSize()
+	reply.BlkToDataNodes = make(map[string][]string)
+	reply.Replicated = []string{}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, blk := range file.BlkList {
+		if len(n.BlkToDatanodes[blk]) >= file.replication() {
+			reply.Replicated = append(reply.Replicated, blk)
+			continue
+		}
+		addrs, err := n.choosePlacement(file.replication(), file.blkSize())
+		if err != nil {
+			return err
+		}
+		reply.BlkToDataNodes[blk] = addrs
+	}
+	if len(reply.BlkToDataNodes) > 0 {
+		reply.GenStamp = n.nextGenStamp()
 	}
-	bytes, err := json.Marshal(reply.BlkList)
-	_, err = file.Write(bytes)
+	return nil
+}
+
+// runSetDigest records the digest of the file at args.DPath computed by
+// the client which uploaded it, see utils.FileDigest. It fails if the
+// file no longer has the blocks the client wrote.
+func (n *NameNode) runSetDigest(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runSetDigest\n")
+	if err := utils.CheckChecksumType(args.ChecksumType); err != nil {
+		return err
+	}
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	file, err := n.lookupFile(args.DPath)
 	if err != nil {
-		log.Printf("error when writing seg names to json file: %v\n", err)
+		return err
+	}
+	if len(file.BlkList) != len(args.BlkList) {
+		return errors.New("File changed during the upload")
+	}
+	for i, blk := range file.BlkList {
+		if blk != args.BlkList[i] {
+			return errors.New("File changed during the upload")
+		}
+	}
+	return n.logAndApply(&journalEntry{Op: opSetDigest, Path: cleanPath(args.DPath),
+		Digest: args.Digest, DigestType: args.ChecksumType})
+}
+
+// allocateBlks generates numBlks segment names for filename with index
+// starting at start, and chooses datanodes for each of them according to
+// the replication factor and block size of file. The result is stored in
+// reply.BlkList and reply.BlkToDataNodes. It fails if there are not enough
+// live datanodes to hold every replica.
+func (n *NameNode) allocateBlks(filename string, start, numBlks int, file *inode, reply *CommandReply) error {
+	reply.BlkToDataNodes = make(map[string][]string)
+	reply.BlkList = make([]string, 0)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	logger.Debugf("current nodes available: %v\n", len(n.Addr2SID))
+	logger.Debugf("%v\n", n.Addr2SID)
+	if numBlks == 0 {
+		return nil // nothing to place
+	}
+	reply.GenStamp = n.nextGenStamp()
+	live := len(n.Addr2SID)
+	if live == 0 || live < config.MinDataNodes {
+		return fmt.Errorf("Not enough live datanodes: %v, need at least %v",
+			live, config.MinDataNodes)
+	}
+	for i := start; i < start+numBlks; i++ {
+		segmentName := generateSegName(filename, i)
+		// reply.BlkList is needed because we need an orded list of segment
+		// file names. The map itself is unordered.
+		reply.BlkList = append(reply.BlkList, segmentName)
+		nodeList, err := n.choosePlacement(file.replication(), file.blkSize())
+		if err != nil {
+			return err
+		}
+		reply.BlkToDataNodes[segmentName] = nodeList
+		logger.Debugf("%v seg: %v, list: %v\n", filename, segmentName, nodeList)
 	}
-	file.Sync()
-	file.Close()
 	return nil
 }
 
 func generateSegName(filename string, index int) string {
-	timestamp := strconv.Itoa(int(utils.GetCurrentTimeInMs()))
-	random := strconv.Itoa(rand.Int())
-	// of format: filename-index-timestamp-random
-	return filename + "-" + fmt.Sprintf("%08d", index) + "-" + timestamp + "-" + random
+	// of format: filename-index-timestamp-random, the random part is a
+	// uuid, since datanodes key blocks by id only and must never see a
+	// block id twice
+	id := utils.BlockID{FileName: filename, Index: index}
+	id.Timestamp = utils.GetCurrentTimeInMs()
+	id.Random = utils.NewUUID()
+	return id.String()
 }
 
 func (n *NameNode) runCopyToLocal(args *CommandArgs, reply *CommandReply) error {
-	log.Printf("inside runCopyToLocal\n")
+	logger.Debugf("inside runCopyToLocal\n")
 	/** called by client, the crucial argument is dfs path
 	 * namenode will retrieve [segment files] from that file (json format)
 	 * and the construct a map from segment file -> [datanods]
 	 * */
-	dfsPath := args.DPath
-	reply.BlkList = n.readDfsFile(dfsPath)
+	file, err := n.getFile(args.DPath)
+	if err != nil {
+		return err
+	}
+	n.accessed(args.DPath)
+	reply.BlkList = file.BlkList
+	reply.BlkSize = file.blkSize()
+	reply.Digest, reply.DigestType = file.Digest, file.DigestType
+	n.fillBlkLocations(reply)
+	return nil
+}
+
+// accessed records a read of file p, see config.AccessTimeInSec
+func (n *NameNode) accessed(p string) {
+	if config.AccessTimeInSec <= 0 {
+		return
+	}
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	node := n.root.lookup(p)
+	now := utils.GetCurrentTimeInMs()
+	if node == nil || node.IsDir || now-node.AccessTime < int64(config.AccessTimeInSec)*1000 {
+		return
+	}
+	err := n.logAndApply(&journalEntry{Op: opSetAccessTime, Path: cleanPath(p)})
+	if err != nil {
+		logger.Errorf("error when recording access to %v: %v\n", p, err)
+	}
+}
+
+// fillBlkLocations maps each block in reply.BlkList to the addresses
+// of datanodes currently holding it, and sums up the file size
+func (n *NameNode) fillBlkLocations(reply *CommandReply) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
 	reply.BlkToDataNodes = make(map[string][]string)
-	for _, blk := range reply.BlkList {
+	reply.FileSize = 0
+	reply.BlkLengths = make([]int64, len(reply.BlkList))
+	for i, blk := range reply.BlkList {
+		reply.BlkLengths[i] = n.BlkMeta[blk].Length
+		reply.FileSize += n.BlkMeta[blk].Length
 		reply.BlkToDataNodes[blk] = make([]string, 0)
 		for _, sid := range n.BlkToDatanodes[blk] {
 			reply.BlkToDataNodes[blk] = append(reply.BlkToDataNodes[blk], n.SID2Addr[sid])
 		}
 	}
+}
+
+func (n *NameNode) runAppendToFile(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runAppendToFile\n")
+	/** appendToFile allocates FileSize bytes worth of new blocks at the end
+	 * of an existing dfs file. The new segments are indexed from the current
+	 * block count and appended to the file's block list, the client then
+	 * sends the data the same way as copyFromLocal.
+	 * For now an append always starts on a fresh block boundary, i.e. if the
+	 * last existing block is partially full, it stays partially full and the
+	 * appended data begins in a new block.
+	 * */
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	file, err := n.lookupFile(args.DPath)
+	if err != nil {
+		return err
+	}
+	blkList := file.BlkList
+	err = n.checkQuota(args.DPath, 0, args.FileSize*int64(file.replication()))
+	if err != nil {
+		return err
+	}
+	reply.BlkSize = file.blkSize()
+	numBlks := int((args.FileSize-1)/reply.BlkSize + 1)
+	if args.FileSize == 0 {
+		numBlks = 0
+	}
+	logger.Debugf("append %v blocks to %v which has %v blocks\n", numBlks,
+		args.DPath, len(blkList))
+	err = n.allocateBlks(path.Base(cleanPath(args.DPath)), len(blkList), numBlks,
+		file, reply)
+	if err != nil {
+		return err
+	}
+	e := &journalEntry{Op: opWrite, Path: cleanPath(args.DPath)}
+	e.BlkList = append(blkList, reply.BlkList...)
+	e.BlkSize = file.BlkSize
+	e.Replication = file.Replication
+	return n.logAndApply(e)
+}
+
+func (n *NameNode) runTruncate(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runTruncate\n")
+	/** truncate cuts the file at args.DPath to args.FileSize bytes. The
+	 * blocks past the new end are dropped like those of rm. Unless the new
+	 * end falls on a block boundary, the datanodes holding the last
+	 * retained block cut it to the remainder under a new generation stamp,
+	 * so that a replica which missed the truncation is found stale.
+	 * Block lengths come from block reports since appended files may have
+	 * partial blocks in the middle.
+	 * */
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	file, err := n.lookupFile(args.DPath)
+	if err != nil {
+		return err
+	}
+	if args.FileSize < 0 {
+		return errors.New("Invalid length")
+	}
+	var size int64
+	keep, rest := len(file.BlkList), int64(0)
+	n.mu.Lock()
+	for i, blk := range file.BlkList {
+		meta, ok := n.BlkMeta[blk]
+		if !ok {
+			n.mu.Unlock()
+			return fmt.Errorf("Block %v not reported yet", blk)
+		}
+		if size < args.FileSize && size+meta.Length >= args.FileSize {
+			keep, rest = i+1, args.FileSize-size
+			if rest == meta.Length {
+				rest = 0 // on a block boundary
+			}
+		}
+		size += meta.Length
+	}
+	n.mu.Unlock()
+	if args.FileSize > size {
+		return fmt.Errorf("Length %v exceeds file size %v", args.FileSize, size)
+	}
+	if args.FileSize == size {
+		reply.Result = fmt.Sprintf("%v is already %v bytes", args.DPath, size)
+		return nil
+	}
+	if args.FileSize == 0 {
+		keep = 0
+	}
+	if rest > 0 {
+		if err := n.truncateBlk(file.BlkList[keep-1], rest); err != nil {
+			return err
+		}
+	}
+	reply.Result = fmt.Sprintf("%v truncated to %v bytes", args.DPath, args.FileSize)
+	e := &journalEntry{Op: opWrite, Path: cleanPath(args.DPath)}
+	e.BlkList = append([]string(nil), file.BlkList[:keep]...)
+	e.BlkSize = file.BlkSize
+	e.Replication = file.Replication
+	if err := n.logAndApply(e); err != nil {
+		return err
+	}
+	n.dropBlks(file.BlkList[keep:])
+	return nil
+}
+
+// truncateBlk asks the datanodes holding blk to cut it to length bytes
+// under a new generation stamp. The replicas which did are the only ones
+// kept, it fails if there are none.
+func (n *NameNode) truncateBlk(blk string, length int64) error {
+	n.mu.Lock()
+	args := utils.TruncateBlkArgs{BlkID: blk, Length: length, GenStamp: n.nextGenStamp(),
+		Token: config.AuthToken}
+	addrs := make([]string, 0)
+	for _, sid := range n.BlkToDatanodes[blk] {
+		if addr := n.SID2Addr[sid]; addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	n.mu.Unlock()
+	sids := make([]string, 0, len(addrs))
+	var meta utils.MetaData
+	for _, addr := range addrs {
+		var reply utils.MetaData
+		err := n.callDataNode(addr, utils.RPCTimeout(), func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+			m, err := dn.TruncateBlk(ctx, args.ToPB())
+			reply.FromPB(m)
+			return err
+		})
+		if err != nil {
+			logger.Warnf("error when calling DataNode.TruncateBlk on %v: %v\n", addr, err)
+			continue
+		}
+		meta = reply
+		n.mu.Lock()
+		sids = append(sids, n.Addr2SID[addr])
+		n.mu.Unlock()
+	}
+	if len(sids) == 0 {
+		return fmt.Errorf("No datanode truncated block %v", blk)
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.BlkMeta[blk] = meta
+	n.BlkToDatanodes[blk] = sids
+	n.saveState()
 	return nil
 }
 
-func (n *NameNode) readDfsFile(dfsPath string) []string {
-	log.Printf("read dfs file %v\n", dfsPath)
-	path := n.makePath(dfsPath) // meta/gdfs/mytext.txt
-	log.Printf("read dfs actual path: %v\n", path)
-	file, err := os.Open(path)
+func (n *NameNode) runCp(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runCp\n")
+	/** cp copies a single file, DPaths is [src, dst]. If dst is a
+	 * directory, the copy is placed under dst/basename(src).
+	 * Copy semantics: cp is a deep copy. Blocks are never shared between
+	 * files (rm deletes the blocks of a file), therefore namenode allocates
+	 * a fresh segment for each source block, the client reads the source
+	 * segments (SrcBlkList) and writes them to the new ones (BlkList).
+	 * BlkToDataNodes holds locations of both source and new segments.
+	 * */
+	if len(args.DPaths) != 2 {
+		return errors.New("cp expects a source and a destination")
+	}
+	src, dst := cleanPath(args.DPaths[0]), cleanPath(args.DPaths[1])
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	srcFile, err := n.lookupFile(src)
 	if err != nil {
-		log.Printf("error when opening dfs file: %v\n", err)
+		return err
+	}
+	srcBlks := srcFile.BlkList
+	node := n.root.lookup(dst)
+	if node != nil && node.IsDir {
+		dst = path.Join(dst, path.Base(src))
+		node = n.root.lookup(dst)
+	}
+	if dst == src {
+		// overwriting would drop the blocks the copy reads from
+		return errors.New("Source and destination are the same file")
+	}
+	if node != nil && !args.Overwrite {
+		return errors.New("File exists")
 	}
-	var res []string
-	bytes, err := ioutil.ReadAll(file)
+	names := int64(1)
+	if node != nil {
+		names = 0 // the copy takes the place of dst
+	}
+	if err := n.checkQuota(dst, names, n.diskUsage(src, srcFile).Replicated); err != nil {
+		return err
+	}
+	// allocate before dropping an overwritten file, so a failure leaves it
+	err = n.allocateBlks(path.Base(dst), 0, len(srcBlks), srcFile, reply)
 	if err != nil {
-		log.Printf("error reading dfs file: %v\n", err)
+		return err
 	}
-	json.Unmarshal(bytes, &res)
-	log.Printf("reading dfs file seg list: %v\n", res)
-	return res
+	if node != nil {
+		old, err := n.deleteFile(dst)
+		if err != nil {
+			return err
+		}
+		n.dropBlks(old)
+	}
+	reply.SrcBlkList = srcBlks
+	n.mu.Lock()
+	for _, blk := range srcBlks {
+		reply.BlkToDataNodes[blk] = make([]string, 0)
+		for _, sid := range n.BlkToDatanodes[blk] {
+			reply.BlkToDataNodes[blk] = append(reply.BlkToDataNodes[blk], n.SID2Addr[sid])
+		}
+	}
+	n.mu.Unlock()
+	reply.Result = fmt.Sprintf("deep copy of %v to %v, %v blocks duplicated",
+		src, dst, len(srcBlks))
+	e := &journalEntry{Op: opWrite, Path: dst, BlkList: reply.BlkList}
+	e.BlkSize = srcFile.BlkSize
+	e.Replication = srcFile.Replication
+	// the copy holds the same data, so it has the same digest
+	e.Digest, e.DigestType = srcFile.Digest, srcFile.DigestType
+	return n.logAndApply(e)
+}
+
+func (n *NameNode) runMv(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runMv\n")
+	/** DPaths is [src ..., dst]. With a single source, dst is either the
+	 * new name or an existing directory to move into. With multiple
+	 * sources, dst must be an existing directory.
+	 * Since datanodes key blocks by BlkID only, moving is a rename in the
+	 * namespace tree, blocks stay untouched.
+	 * */
+	if len(args.DPaths) < 2 {
+		return errors.New("mv expects a source and a destination")
+	}
+	srcs, dst := args.DPaths[:len(args.DPaths)-1], cleanPath(args.DPaths[len(args.DPaths)-1])
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	node := n.root.lookup(dst)
+	dstIsDir := node != nil && node.IsDir
+	if len(srcs) > 1 && !dstIsDir {
+		return errors.New("Destination is not a directory")
+	}
+	reply.Errors = make(map[string]string)
+	for _, src := range srcs {
+		target := dst
+		if dstIsDir {
+			target = path.Join(dst, path.Base(cleanPath(src)))
+		}
+		err := n.moveEntry(src, target)
+		if err != nil {
+			logger.Debugf("error when moving %v: %v\n", src, err)
+			reply.Errors[src] = err.Error()
+		}
+	}
+	return nil
+}
+
+// moveEntry renames src to dst, the caller should hold n.nsMu
+func (n *NameNode) moveEntry(src, dst string) error {
+	src, dst = cleanPath(src), cleanPath(dst)
+	if src == "/" {
+		return errors.New("Cannot move the root directory")
+	}
+	node := n.root.lookup(src)
+	if node == nil {
+		return ErrNotFound
+	}
+	if src == dst {
+		return errors.New("Source and destination are the same")
+	}
+	if node.IsDir && strings.HasPrefix(dst, src+"/") {
+		return errors.New("Cannot move a directory into itself")
+	}
+	// checked before journaling, an entry failing to apply would be
+	// replayed on every restart
+	if _, _, err := n.root.parentOf(dst); err != nil {
+		return err
+	}
+	if n.root.lookup(dst) != nil {
+		return errors.New("File exists")
+	}
+	return n.logAndApply(&journalEntry{Op: opRename, Path: src, Dest: dst})
+}
+
+// lookupFile returns a copy of file p, the caller should hold n.nsMu
+func (n *NameNode) lookupFile(p string) (*inode, error) {
+	node := n.root.lookup(p)
+	if node == nil {
+		return nil, ErrNotFound
+	}
+	if node.IsDir {
+		return nil, errors.New("Is a directory")
+	}
+	file := *node
+	file.BlkList = append([]string{}, node.BlkList...)
+	return &file, nil
+}
+
+// getFile returns a copy of file p
+func (n *NameNode) getFile(p string) (*inode, error) {
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	return n.lookupFile(p)
 }
 
 func (n *NameNode) runLs(args *CommandArgs, reply *CommandReply) error {
-	log.Printf("inside runLs\n")
+	logger.Debugf("inside runLs\n")
 	reply.Result = "running ls"
-	path := n.makePath(args.DPath)
-	fileinfo, err := os.Stat(path)
-	if err != nil {
-		return errors.New("No such file or directory")
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	/** Without -R we list the names of the children, with -R the full
+	 * paths of every entry below the directory, depth first and each
+	 * directory right before its contents. With -l Stats holds the
+	 * metadata of each entry, in the order of Files.
+	 * A glob pattern lists the full paths matching it instead, with -R
+	 * each matching directory is followed by the entries below it.
+	 * */
+	reply.Files = []string{}
+	if args.Long {
+		reply.Stats = []FileStat{}
 	}
-	if fileinfo.IsDir() == false {
-		return errors.New("Not a directory")
+	var walk func(dir string, node *inode)
+	walk = func(dir string, node *inode) {
+		names := make([]string, 0, len(node.Children))
+		for name := range node.Children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child := node.Children[name]
+			p := path.Join(dir, name)
+			if args.Recursive {
+				reply.Files = append(reply.Files, p)
+			} else {
+				reply.Files = append(reply.Files, name)
+			}
+			if args.Long {
+				reply.Stats = append(reply.Stats, n.statNode(p, child))
+			}
+			if args.Recursive && child.IsDir {
+				walk(p, child)
+			}
+		}
+	}
+	if hasGlob(args.DPath) {
+		matches, err := n.glob(args.DPath)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return ErrNotFound
+		}
+		for _, p := range matches {
+			node := n.root.lookup(p)
+			reply.Files = append(reply.Files, p)
+			if args.Long {
+				reply.Stats = append(reply.Stats, n.statNode(p, node))
+			}
+			if args.Recursive && node.IsDir {
+				walk(p, node)
+			}
+		}
+		return nil
 	}
-	files, err := ioutil.ReadDir(path)
-	if reply.Files == nil {
-		reply.Files = []string{}
+	node := n.root.lookup(args.DPath)
+	if node == nil {
+		return ErrNotFound
 	}
-	for _, file := range files {
-		reply.Files = append(reply.Files, file.Name())
+	if node.IsDir == false {
+		return errors.New("Not a directory")
 	}
-	return err
+	walk(cleanPath(args.DPath), node)
+	return nil
 }
 
 func (n *NameNode) runMkdir(args *CommandArgs, reply *CommandReply) error {
 	//
-	log.Printf("inside runMkdir\n")
+	logger.Debugf("inside runMkdir\n")
 	reply.Result = "running mkdir"
-	err := os.Mkdir(n.makePath(args.DPath), 0700)
-	return err
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	parent, name, err := n.root.parentOf(args.DPath)
+	if err != nil {
+		return err
+	}
+	if parent.Children[name] != nil {
+		return errors.New("File exists")
+	}
+	if err := n.checkQuota(args.DPath, 1, 0); err != nil {
+		return err
+	}
+	return n.logAndApply(&journalEntry{Op: opMkdir, Path: cleanPath(args.DPath)})
 }
 
 func (n *NameNode) runMkdirP(args *CommandArgs, reply *CommandReply) error {
 	//
-	log.Printf("inside runMkdirP\n")
+	logger.Debugf("inside runMkdirP\n")
 	reply.Result = "running mkdirP"
-	err := os.MkdirAll(n.makePath(args.DPath), 0700)
-	return err
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	cur := n.root
+	names := splitPath(args.DPath)
+	missing := 0 // index of the first missing directory
+	for ; missing < len(names); missing++ {
+		cur = cur.Children[names[missing]]
+		if cur == nil {
+			break
+		}
+		if cur.IsDir == false {
+			return errors.New("File exists")
+		}
+	}
+	if cur != nil {
+		return nil // nothing to do
+	}
+	first := "/" + path.Join(names[:missing+1]...)
+	if err := n.checkQuota(first, int64(len(names)-missing), 0); err != nil {
+		return err
+	}
+	return n.logAndApply(&journalEntry{Op: opMkdirP, Path: cleanPath(args.DPath)})
 }
 
 func (n *NameNode) runRm(args *CommandArgs, reply *CommandReply) error {
-	//
-	log.Printf("inside runRm\n")
+	logger.Debugf("inside runRm\n")
 	reply.Result = "running rm"
+	/** rm removes every file in DPaths independently, a failure on one
+	 * path will not stop the others. Failures are reported back in
+	 * reply.Errors keyed by path, paths absent from it are removed.
+	 * For each file:
+	 * 	1. read its block list from the namespace tree
+	 * 	2. remove the file from the namespace
+	 * 	3. drop the blocks from BlkToDatanodes
+	 * 	4. ask datanodes holding the blocks to delete them
+	 * With the trash enabled, files are moved into it instead unless
+	 * SkipTrash is set, reply.Trashed tells where. Files already in
+	 * the trash are deleted.
+	 * */
+	reply.Errors = make(map[string]string)
+	reply.Trashed = make(map[string]string)
+	stamp := utils.GetCurrentTimeInMs()
 	for _, file := range args.DPaths {
-		err := os.Remove(n.makePath(file))
+		var err error
+		if config.TrashInSec > 0 && !args.SkipTrash && !inTrash(file) {
+			var target string
+			n.nsMu.Lock()
+			target, err = n.moveToTrash(file, trashUser(args), stamp)
+			n.nsMu.Unlock()
+			if err == nil {
+				reply.Trashed[file] = target
+			}
+		} else {
+			err = n.removeFile(file)
+		}
 		if err != nil {
-			return err
+			logger.Debugf("error when removing %v: %v\n", file, err)
+			reply.Errors[file] = err.Error()
 		}
 	}
 	return nil
 }
 
+// removeFile deletes a file from namespace and its blocks from datanodes
+func (n *NameNode) removeFile(dfsPath string) error {
+	n.nsMu.Lock()
+	blkList, err := n.deleteFile(dfsPath)
+	n.nsMu.Unlock()
+	if err != nil {
+		return err
+	}
+	n.dropBlks(blkList)
+	return nil
+}
+
+// deleteFile removes file p from namespace and returns its blocks,
+// the caller should hold n.nsMu
+func (n *NameNode) deleteFile(p string) ([]string, error) {
+	file, err := n.lookupFile(p)
+	if err != nil {
+		return nil, err
+	}
+	return file.BlkList, n.logAndApply(&journalEntry{Op: opDelete, Path: cleanPath(p)})
+}
+
+// dropBlks forgets blocks and asks datanodes holding them to delete them
+func (n *NameNode) dropBlks(blkList []string) {
+	n.mu.Lock()
+	blkToAddrs := make(map[string][]string)
+	for _, blk := range blkList {
+		for _, sid := range n.BlkToDatanodes[blk] {
+			blkToAddrs[blk] = append(blkToAddrs[blk], n.SID2Addr[sid])
+		}
+		delete(n.BlkToDatanodes, blk)
+		delete(n.BlkMeta, blk)
+		delete(n.BlkRep, blk)
+		delete(n.CorruptBlks, blk)
+	}
+	n.saveState()
+	n.mu.Unlock()
+	for blk, addrs := range blkToAddrs {
+		for _, addr := range addrs {
+			if addr == "" {
+				continue
+			}
+			n.reqDeleteBlk(blk, addr)
+		}
+	}
+}
+
+func (n *NameNode) reqDeleteBlk(blk string, addr string) bool {
+	args := utils.DeleteBlkArgs{}
+	args.BlkID = blk
+	args.Token = config.AuthToken
+	reply := utils.DeleteBlkReply{}
+	logger.Debugf("request delete %v on %v\n", blk, addr)
+	err := n.callDataNode(addr, utils.RPCTimeout(), func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+		m, err := dn.DeleteBlk(ctx, args.ToPB())
+		reply.FromPB(m)
+		return err
+	})
+	if err != nil {
+		// the block becomes orphaned on that datanode, but the file
+		// has already been removed from namespace, so don't fail here
+		logger.Warnf("error when calling DataNode.DeleteBlk on %v: %v\n", addr, err)
+		return false
+	}
+	return reply.Status
+}
+
 func (n *NameNode) runRmdir(args *CommandArgs, reply *CommandReply) error {
-	//
-	log.Printf("inside runRmdir\n")
+	logger.Debugf("inside runRmdir\n")
 	reply.Result = "running rmdir"
+	/** rmdir removes each directory with everything below it. The blocks
+	 * of the files in the subtree are dropped and deleted from datanodes
+	 * like those of rm, see removeTree, they would be orphaned otherwise.
+	 * */
 	for _, dir := range args.DPaths {
-		err := os.RemoveAll(n.makePath(dir))
-		if err != nil {
+		if err := n.removeTree(dir); err != nil {
 			return err
 		}
 	}
@@ -343,27 +1255,253 @@ func (n *NameNode) runRmdir(args *CommandArgs, reply *CommandReply) error {
 }
 
 func (n *NameNode) runTouch(args *CommandArgs, reply *CommandReply) error {
-	//
-	log.Printf("inside runTouch\n")
+	logger.Debugf("inside runTouch\n")
 	reply.Result = "running touch"
+	// touch creates a zero-length file for each path, i.e. a dfs file
+	// with an empty block list. Failures are reported per path.
+	reply.Errors = make(map[string]string)
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	for _, file := range args.DPaths {
+		err := n.touchFile(file)
+		if err != nil {
+			logger.Debugf("error when touching %v: %v\n", file, err)
+			reply.Errors[file] = err.Error()
+		}
+	}
 	return nil
 }
 
+// touchFile creates an empty file, the caller should hold n.nsMu
+func (n *NameNode) touchFile(dfsPath string) error {
+	parent, name, err := n.root.parentOf(dfsPath)
+	if err != nil {
+		return err
+	}
+	if parent.Children[name] != nil {
+		return errors.New("File exists")
+	}
+	if err := n.checkQuota(dfsPath, 1, 0); err != nil {
+		return err
+	}
+	e := &journalEntry{Op: opWrite, Path: cleanPath(dfsPath), BlkList: []string{}}
+	return n.logAndApply(e)
+}
+
 func (n *NameNode) runFormat(args *CommandArgs, reply *CommandReply) error {
 	//
-	log.Printf("inside runFormat\n")
+	logger.Debugf("inside runFormat\n")
 	reply.Result = "running format"
 	n.format()
 	return nil
 }
 
-func (n *NameNode) makePath(path string) string {
-	return filepath.Join(n.DFSRootPath, path)
+func (n *NameNode) runSetRep(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runSetRep\n")
+	/** setrep only records the new replication factor of the file, the
+	 * blocks are re-replicated or their excess replicas removed later
+	 * through heartbeat replies, see scheduleReplication and
+	 * scheduleRemoval.
+	 * */
+	if args.Replication <= 0 {
+		return errors.New("Invalid replication factor")
+	}
+	if err := n.checkReplication(args.Replication); err != nil {
+		return err
+	}
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	_, err := n.lookupFile(args.DPath)
+	if err != nil {
+		return err
+	}
+	reply.Result = fmt.Sprintf("replication of %v set to %v", args.DPath,
+		args.Replication)
+	e := &journalEntry{Op: opSetRep, Path: cleanPath(args.DPath)}
+	e.Replication = args.Replication
+	return n.logAndApply(e)
+}
+
+// checkReplication refuses a replication factor that cannot be satisfied
+// by the live datanodes, 0 stands for the default and is always accepted
+func (n *NameNode) checkReplication(rep int) error {
+	if rep < 0 {
+		return errors.New("Invalid replication factor")
+	}
+	n.mu.Lock()
+	live := len(n.Addr2SID)
+	n.mu.Unlock()
+	if rep > live {
+		return fmt.Errorf("Replication factor %v exceeds number of live datanodes %v",
+			rep, live)
+	}
+	return nil
+}
+
+func (n *NameNode) runBalance(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runBalance\n")
+	reply.Result = fmt.Sprintf("balancer planned %v block moves", n.Balance())
+	return nil
+}
+
+func (n *NameNode) runStat(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runStat\n")
+	/** for each path we report whether it is a directory, its size,
+	 * number of blocks, replication factor and modification time.
+	 * File size is the sum of block lengths reported by datanodes,
+	 * so a block that hasn't been reported yet counts as 0 bytes.
+	 * */
+	reply.Errors = make(map[string]string)
+	reply.Stats = make([]FileStat, 0)
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	for _, path := range args.DPaths {
+		node := n.root.lookup(path)
+		if node == nil {
+			reply.Errors[path] = ErrNotFound.Error()
+			continue
+		}
+		reply.Stats = append(reply.Stats, n.statNode(path, node))
+	}
+	return nil
+}
+
+func (n *NameNode) runDu(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runDu\n")
+	/** like unix du we report the usage of each child of a directory,
+	 * sorted by name, and then the total of the directory itself.
+	 * Sizes come from the block lengths reported by datanodes.
+	 * */
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	node := n.root.lookup(args.DPath)
+	if node == nil {
+		return ErrNotFound
+	}
+	dir := cleanPath(args.DPath)
+	reply.Usage = []DiskUsage{}
+	if node.IsDir {
+		names := make([]string, 0, len(node.Children))
+		for name := range node.Children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			reply.Usage = append(reply.Usage, n.diskUsage(path.Join(dir, name),
+				node.Children[name]))
+		}
+	}
+	reply.Usage = append(reply.Usage, n.diskUsage(dir, node))
+	return nil
+}
+
+func (n *NameNode) runCount(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runCount\n")
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	node := n.root.lookup(args.DPath)
+	if node == nil {
+		return ErrNotFound
+	}
+	count := DirCount{Path: cleanPath(args.DPath), NsQuota: node.NsQuota,
+		SpaceQuota: node.SpaceQuota}
+	node.walk("/", func(_ string, node *inode) {
+		if node.IsDir {
+			count.Dirs++
+		} else {
+			count.Files++
+		}
+	})
+	du := n.diskUsage(count.Path, node)
+	count.Bytes, count.SpaceConsumed = du.Size, du.Replicated
+	reply.Counts = []DirCount{count}
+	return nil
+}
+
+// diskUsage sums the space used by the subtree of node at path p, the
+// caller holds nsMu
+func (n *NameNode) diskUsage(p string, node *inode) DiskUsage {
+	du := DiskUsage{Path: p}
+	if node.IsDir {
+		for _, child := range node.Children {
+			sub := n.diskUsage(p, child)
+			du.Size += sub.Size
+			du.Replicated += sub.Replicated
+		}
+		return du
+	}
+	n.mu.Lock()
+	for _, blk := range node.BlkList {
+		du.Size += n.BlkMeta[blk].Length
+	}
+	n.mu.Unlock()
+	du.Replicated = du.Size * int64(node.replication())
+	return du
+}
+
+// statNode returns the metadata of node at path p, the caller holds nsMu
+func (n *NameNode) statNode(p string, node *inode) FileStat {
+	stat := FileStat{Path: p, IsDir: node.IsDir, ModTime: node.ModTime,
+		AccessTime: node.AccessTime}
+	if !stat.IsDir {
+		blkList := node.BlkList
+		stat.NumBlks = len(blkList)
+		stat.BlkSize = node.blkSize()
+		stat.Replication = node.replication()
+		n.mu.Lock()
+		for _, blk := range blkList {
+			stat.Size += n.BlkMeta[blk].Length
+		}
+		n.mu.Unlock()
+	}
+	return stat
+}
+
+func (n *NameNode) runChecksum(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runChecksum\n")
+	/** The file checksum is an MD5 over the checksum of each block in
+	 * block order (MD5-of-CRC32 for crc32 blocks), each crc32 is written as
+	 * 4 big endian bytes. Block checksums come from datanodes' block
+	 * reports, so we don't need to read any data here.
+	 * */
+	reply.Errors = make(map[string]string)
+	reply.Checksums = make(map[string]string)
+	for _, path := range args.DPaths {
+		file, err := n.getFile(path)
+		if err != nil {
+			reply.Errors[path] = err.Error()
+			continue
+		}
+		checksum, err := n.fileChecksum(file.BlkList)
+		if err != nil {
+			reply.Errors[path] = err.Error()
+			continue
+		}
+		reply.Checksums[path] = checksum
+	}
+	return nil
+}
+
+func (n *NameNode) fileChecksum(blkList []string) (string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	sums := make([]utils.Sum, 0, len(blkList))
+	for _, blk := range blkList {
+		meta, ok := n.BlkMeta[blk]
+		if !ok {
+			return "", fmt.Errorf("checksum of block %v is not reported yet", blk)
+		}
+		sums = append(sums, meta.Checksum)
+	}
+	return utils.FileDigest(sums), nil
 }
 
 // NotifyArgs for client to notify namenode
 type NotifyArgs struct {
-	// empty
+	// addresses of datanodes which just received blocks,
+	// empty means every datanode
+	Addrs []string
+	Token string // see config.AuthToken
 }
 
 // NotifyReply reply status
@@ -383,9 +1521,21 @@ func (n *NameNode) notify() {
 	n.mu.Unlock()
 }
 
-// Notify is called by client
+// Notify is called by client after data transfer, datanodes listed in
+// args will be asked for an immediate block report in their next heartbeat
 func (n *NameNode) Notify(args *NotifyArgs, reply *NotifyReply) error {
-	go n.notify()
+	if err := CheckToken("Notify", args.Token); err != nil {
+		return err
+	}
+	if len(args.Addrs) == 0 {
+		go n.notify()
+	} else {
+		n.mu.Lock()
+		for _, addr := range args.Addrs {
+			n.ReqReport[addr] = true
+		}
+		n.mu.Unlock()
+	}
 	reply.Status = true
 	return nil
 }
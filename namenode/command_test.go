@@ -0,0 +1,384 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/utils"
+)
+
+func TestPerFileBlockSize(t *testing.T) {
+	c := newFakeCluster(t, 3)
+	reply := c.write("/", "small.bin", 2500, 1000, 0)
+	if len(reply.BlkList) != 3 || reply.BlkSize != 1000 {
+		t.Fatalf("2500 bytes in blocks of 1000 got %v blocks of %v bytes",
+			len(reply.BlkList), reply.BlkSize)
+	}
+	c.write("/", "default.bin", 2500, 0, 0)
+	stat := runCommand(t, c.n, &CommandArgs{CommandType: config.Stat,
+		DPaths: []string{"/small.bin", "/default.bin"}})
+	want := []struct {
+		blkSize int64
+		numBlks int
+	}{{1000, 3}, {int64(config.BlkSize), 1}}
+	for i, s := range stat.Stats {
+		if s.BlkSize != want[i].blkSize || s.NumBlks != want[i].numBlks || s.Size != 2500 {
+			t.Errorf("stat of %v is %+v, want %v blocks of %v bytes, 2500 in total",
+				s.Path, s, want[i].numBlks, want[i].blkSize)
+		}
+	}
+	// readers take the block size from the file, not from config.BlkSize
+	for _, n := range []*NameNode{c.n, NewNameNode()} {
+		get := runCommand(t, n, &CommandArgs{CommandType: config.CopyToLocal,
+			DPath: "/small.bin"})
+		if get.BlkSize != 1000 || len(get.BlkList) != 3 {
+			t.Errorf("copyToLocal got %v blocks of %v bytes, want 3 of 1000",
+				len(get.BlkList), get.BlkSize)
+		}
+	}
+}
+
+func TestCopyFromLocalNeedsDataNodes(t *testing.T) {
+	c := newFakeCluster(t, 0)
+	put := func(name string, rep int) error {
+		args := CommandArgs{CommandType: config.CopyFromLocal, DPath: "/",
+			FileName: name, FileSize: 10, Replication: rep}
+		return c.n.RunCommand(&args, &CommandReply{})
+	}
+	if err := put("none.txt", 1); err == nil {
+		t.Errorf("copyFromLocal without datanodes succeeded")
+	}
+	c.addNode(1<<30, 0)
+	c.addNode(1<<30, 0)
+	if err := put("under.txt", 3); err == nil {
+		t.Errorf("copyFromLocal of 3 replicas on 2 datanodes succeeded")
+	}
+	defer func(min int) { config.MinDataNodes = min }(config.MinDataNodes)
+	config.MinDataNodes = 3
+	if err := put("min.txt", 1); err == nil {
+		t.Errorf("copyFromLocal with fewer datanodes than MinDataNodes succeeded")
+	}
+	// a failed allocation leaves no file behind
+	ls := runCommand(t, c.n, &CommandArgs{CommandType: config.Ls, DPath: "/"})
+	if len(ls.Files) != 0 {
+		t.Errorf("ls / = %q after failed copies, want none", ls.Files)
+	}
+	config.MinDataNodes = 2
+	if err := put("ok.txt", 2); err != nil {
+		t.Errorf("copyFromLocal of 2 replicas on 2 datanodes: %v", err)
+	}
+}
+
+func TestSegNamesUniqueAcrossRestarts(t *testing.T) {
+	newTestNameNode(t)
+	seen := make(map[string]bool)
+	for restart := 0; restart < 10; restart++ {
+		NewNameNode()
+		// the same file and indexes, likely within the same millisecond
+		for i := 0; i < 1000; i++ {
+			name := generateSegName("a.txt", i)
+			if seen[name] {
+				t.Fatalf("block id %v generated twice", name)
+			}
+			seen[name] = true
+		}
+	}
+}
+
+func TestPathTraversalRejected(t *testing.T) {
+	c := newFakeCluster(t, 3)
+	for _, args := range []*CommandArgs{
+		{CommandType: config.Rm, DPaths: []string{"../../victim"}},
+		{CommandType: config.Rm, DPaths: []string{"/ok", "/../../victim"}},
+		{CommandType: config.MkdirP, DPath: "a/../../../escape"},
+		{CommandType: config.Ls, DPath: "/.."},
+		{CommandType: config.Cp, DPaths: []string{"/a", "/b/../../c"}},
+		{CommandType: config.Cat, DPath: "/a\x00b"},
+		{CommandType: config.CopyFromLocal, DPath: "/..", FileName: "x"},
+		{CommandType: config.CopyFromLocal, DPath: "/", FileName: "../../x"},
+		{CommandType: config.CopyFromLocal, DPath: "/", FileName: "a/b"},
+		{CommandType: config.CopyFromLocal, DPath: "/", FileName: ".."},
+		{CommandType: config.CopyFromLocal, DPath: "/", FileName: "."},
+		{CommandType: config.CopyFromLocal, DPath: "/", FileName: ""},
+	} {
+		if err := c.n.RunCommand(args, &CommandReply{}); err == nil {
+			t.Errorf("%v %q %q %q succeeded", args.CommandType, args.DPath,
+				args.DPaths, args.FileName)
+		}
+	}
+	if err := c.n.SubmitJob(&CommandArgs{Job: "wordcount", DPath: "/in",
+		Output: "/../out"}, &SubmitJobReply{}); err == nil {
+		t.Errorf("job with output /../out was submitted")
+	}
+	if files := runCommand(t, c.n, &CommandArgs{CommandType: config.Ls, DPath: "/"}).Files; len(files) != 0 {
+		t.Errorf("rejected commands left %q in the namespace", files)
+	}
+	// encoded forms are never decoded, they stay literal names in the
+	// namespace and in the block ids
+	for _, name := range []string{"..%2f..%2fx", "%2e%2e", "..\\..\\x"} {
+		reply := c.write("/", name, 10, 0, 1)
+		blk := reply.BlkList[0]
+		var id utils.BlockID
+		if err := id.Parse(blk); err != nil || id.FileName != name || id.String() != blk {
+			t.Errorf("block %q of %q doesn't round trip: %+v %v", blk, name, id, err)
+		}
+	}
+	ls := runCommand(t, c.n, &CommandArgs{CommandType: config.Ls, DPath: "/"})
+	if len(ls.Files) != 3 {
+		t.Errorf("ls / = %q, want the 3 encoded names", ls.Files)
+	}
+}
+
+func TestLsRecursiveLong(t *testing.T) {
+	c := newFakeCluster(t, 3)
+	runCommand(t, c.n, &CommandArgs{CommandType: config.MkdirP, DPath: "/a/b/c"})
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Mkdir, DPath: "/d"})
+	c.write("/a", "x.bin", 2500, 1000, 2)
+	c.write("/a/b", "y.bin", 10, 0, 0)
+	c.write("/", "z.bin", 0, 0, 0)
+	ls := runCommand(t, c.n, &CommandArgs{CommandType: config.Ls, DPath: "/a",
+		Recursive: true})
+	want := []string{"/a/b", "/a/b/c", "/a/b/y.bin", "/a/x.bin"}
+	if strings.Join(ls.Files, " ") != strings.Join(want, " ") {
+		t.Errorf("ls -R /a = %q, want %q", ls.Files, want)
+	}
+	if ls.Stats != nil {
+		t.Errorf("ls -R without -l returned stats %+v", ls.Stats)
+	}
+	ls = runCommand(t, c.n, &CommandArgs{CommandType: config.Ls, DPath: "/",
+		Recursive: true, Long: true})
+	want = []string{"/a", "/a/b", "/a/b/c", "/a/b/y.bin", "/a/x.bin", "/d", "/z.bin"}
+	if strings.Join(ls.Files, " ") != strings.Join(want, " ") || len(ls.Stats) != len(want) {
+		t.Fatalf("ls -R -l / = %q with %v stats, want %q", ls.Files, len(ls.Stats), want)
+	}
+	for i, s := range ls.Stats {
+		if s.Path != want[i] {
+			t.Errorf("stat %v is of %v, want %v", i, s.Path, want[i])
+		}
+	}
+	if x := ls.Stats[4]; x.IsDir || x.Size != 2500 || x.NumBlks != 3 || x.Replication != 2 ||
+		x.ModTime == 0 {
+		t.Errorf("ls -l of /a/x.bin = %+v, want 2500 bytes in 3 blocks, 2 replicas", x)
+	}
+	if d := ls.Stats[0]; !d.IsDir || d.Size != 0 {
+		t.Errorf("ls -l of /a = %+v, want an empty directory entry", d)
+	}
+	// without -R entries are names, stats still carry full paths
+	ls = runCommand(t, c.n, &CommandArgs{CommandType: config.Ls, DPath: "/a/b", Long: true})
+	if strings.Join(ls.Files, " ") != "c y.bin" || len(ls.Stats) != 2 ||
+		ls.Stats[1].Path != "/a/b/y.bin" || ls.Stats[1].Size != 10 {
+		t.Errorf("ls -l /a/b = %q %+v", ls.Files, ls.Stats)
+	}
+}
+
+func TestDu(t *testing.T) {
+	c := newFakeCluster(t, 3)
+	runCommand(t, c.n, &CommandArgs{CommandType: config.MkdirP, DPath: "/a/b/empty"})
+	c.write("/a", "x.bin", 2500, 1000, 2)
+	c.write("/a/b", "y.bin", 300, 0, 3)
+	c.write("/a/b", "z.bin", 40, 0, 1)
+	c.write("/", "w.bin", 7, 0, 1)
+	du := runCommand(t, c.n, &CommandArgs{CommandType: config.Du, DPath: "/a"})
+	want := []DiskUsage{
+		{"/a/b", 340, 300*3 + 40},
+		{"/a/x.bin", 2500, 5000},
+		{"/a", 2840, 5940},
+	}
+	if len(du.Usage) != len(want) {
+		t.Fatalf("du /a = %+v, want %+v", du.Usage, want)
+	}
+	for i := range want {
+		if du.Usage[i] != want[i] {
+			t.Errorf("du /a entry %v = %+v, want %+v", i, du.Usage[i], want[i])
+		}
+	}
+	du = runCommand(t, c.n, &CommandArgs{CommandType: config.Du, DPath: "/"})
+	if total := du.Usage[len(du.Usage)-1]; total != (DiskUsage{"/", 2847, 5947}) {
+		t.Errorf("du / total = %+v, want 2847 bytes, 5947 replicated", total)
+	}
+	du = runCommand(t, c.n, &CommandArgs{CommandType: config.Du, DPath: "/a/b/y.bin"})
+	if len(du.Usage) != 1 || du.Usage[0] != (DiskUsage{"/a/b/y.bin", 300, 900}) {
+		t.Errorf("du of a file = %+v, want its own usage", du.Usage)
+	}
+	if err := c.n.RunCommand(&CommandArgs{CommandType: config.Du, DPath: "/nope"},
+		&CommandReply{}); err == nil {
+		t.Errorf("du of a missing path succeeded")
+	}
+}
+
+func TestCount(t *testing.T) {
+	c := newFakeCluster(t, 3)
+	runCommand(t, c.n, &CommandArgs{CommandType: config.MkdirP, DPath: "/t/a/b"})
+	runCommand(t, c.n, &CommandArgs{CommandType: config.MkdirP, DPath: "/t/c"})
+	c.write("/t", "x.bin", 2500, 1000, 2)
+	c.write("/t/a", "y.bin", 300, 0, 3)
+	c.write("/t/a/b", "z.bin", 40, 0, 1)
+	c.write("/t/c", "empty.bin", 0, 0, 1)
+	c.write("/", "outside.bin", 7, 0, 1)
+	for p, want := range map[string]DirCount{
+		"/t":       {Path: "/t", Dirs: 4, Files: 4, Bytes: 2840, SpaceConsumed: 5940},
+		"/t/a":     {Path: "/t/a", Dirs: 2, Files: 2, Bytes: 340, SpaceConsumed: 940},
+		"/t/c":     {Path: "/t/c", Dirs: 1, Files: 1},
+		"/t/x.bin": {Path: "/t/x.bin", Files: 1, Bytes: 2500, SpaceConsumed: 5000},
+		"/t/./a/":  {Path: "/t/a", Dirs: 2, Files: 2, Bytes: 340, SpaceConsumed: 940},
+	} {
+		counts := runCommand(t, c.n, &CommandArgs{CommandType: config.Count, DPath: p}).Counts
+		if len(counts) != 1 || counts[0] != want {
+			t.Errorf("count %v = %+v, want %+v", p, counts, want)
+		}
+	}
+	if err := c.n.RunCommand(&CommandArgs{CommandType: config.Count, DPath: "/nope"},
+		&CommandReply{}); err == nil {
+		t.Errorf("count of a missing path succeeded")
+	}
+}
+
+func TestModAndAccessTimes(t *testing.T) {
+	c := newFakeCluster(t, 3)
+	c.write("/", "a.txt", 10, 0, 0)
+	stat := func(n *NameNode) FileStat {
+		t.Helper()
+		return runCommand(t, n, &CommandArgs{CommandType: config.Stat,
+			DPaths: []string{"/a.txt"}}).Stats[0]
+	}
+	created := stat(c.n)
+	if created.ModTime == 0 || created.AccessTime != created.ModTime {
+		t.Fatalf("new file has mtime %v and atime %v, want both set to its creation",
+			created.ModTime, created.AccessTime)
+	}
+	time.Sleep(5 * time.Millisecond)
+	runCommand(t, c.n, &CommandArgs{CommandType: config.AppendToFile, DPath: "/a.txt",
+		FileSize: 10})
+	appended := stat(c.n)
+	if appended.ModTime <= created.ModTime || appended.AccessTime != created.AccessTime {
+		t.Errorf("append changed mtime %v -> %v and atime %v -> %v, want only mtime to advance",
+			created.ModTime, appended.ModTime, created.AccessTime, appended.AccessTime)
+	}
+	// reads record their time only with access times on, at their precision
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Cat, DPath: "/a.txt"})
+	if got := stat(c.n).AccessTime; got != created.AccessTime {
+		t.Errorf("cat changed atime to %v with access times off", got)
+	}
+	defer func(sec int) { config.AccessTimeInSec = sec }(config.AccessTimeInSec)
+	config.AccessTimeInSec = 3600
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Cat, DPath: "/a.txt"})
+	if got := stat(c.n).AccessTime; got != created.AccessTime {
+		t.Errorf("cat within the precision changed atime to %v", got)
+	}
+	// as if the last read was two hours ago
+	c.n.root.lookup("/a.txt").AccessTime -= 2 * 3600 * 1000
+	runCommand(t, c.n, &CommandArgs{CommandType: config.CopyToLocal, DPath: "/a.txt"})
+	read := stat(c.n)
+	if read.AccessTime < appended.ModTime || read.AccessTime == created.AccessTime ||
+		read.ModTime != appended.ModTime {
+		t.Errorf("copyToLocal left atime %v, mtime %v, want atime past %v",
+			read.AccessTime, read.ModTime, appended.ModTime)
+	}
+	// both are kept in the edit log
+	if restarted := stat(NewNameNode()); restarted.ModTime != read.ModTime ||
+		restarted.AccessTime != read.AccessTime {
+		t.Errorf("times after restart are %v, %v, want %v, %v", restarted.ModTime,
+			restarted.AccessTime, read.ModTime, read.AccessTime)
+	}
+}
+
+func TestUploadStatus(t *testing.T) {
+	c := newFakeCluster(t, 3)
+	put := c.write("/", "f.bin", 2500, 1000, 2)
+	// the second block lost a replica
+	lost := put.BlkList[1]
+	addr := put.BlkToDataNodes[lost][0]
+	delete(c.blks[addr], lost)
+	c.report(addr)
+	reply := runCommand(t, c.n, &CommandArgs{CommandType: config.UploadStatus,
+		DPath: "/f.bin"})
+	if !reflect.DeepEqual(reply.BlkList, put.BlkList) || reply.BlkSize != 1000 {
+		t.Errorf("upload status lists blocks %v of %v bytes, want %v of 1000",
+			reply.BlkList, reply.BlkSize, put.BlkList)
+	}
+	want := []string{put.BlkList[0], put.BlkList[2]}
+	if !reflect.DeepEqual(reply.Replicated, want) {
+		t.Errorf("replicated blocks are %v, want %v", reply.Replicated, want)
+	}
+	if len(reply.BlkToDataNodes) != 1 || len(reply.BlkToDataNodes[lost]) != 2 {
+		t.Errorf("placed %v, want 2 datanodes for %v", reply.BlkToDataNodes, lost)
+	}
+	if reply.GenStamp <= put.GenStamp {
+		t.Errorf("blocks to send get generation stamp %v, not newer than %v",
+			reply.GenStamp, put.GenStamp)
+	}
+	err := c.n.RunCommand(&CommandArgs{CommandType: config.UploadStatus,
+		DPath: "/missing"}, &CommandReply{})
+	if err != ErrNotFound {
+		t.Errorf("upload status of a missing file: %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestSetDigest(t *testing.T) {
+	c := newFakeCluster(t, 2)
+	put := c.write("/", "f.bin", 2500, 1000, 1)
+	set := &CommandArgs{CommandType: config.SetDigest, DPath: "/f.bin",
+		BlkList: put.BlkList[:2], Digest: "d1g", ChecksumType: utils.SHA256}
+	if err := c.n.RunCommand(set, &CommandReply{}); err == nil {
+		t.Errorf("digest of other blocks than the file's recorded")
+	}
+	set.BlkList = put.BlkList
+	runCommand(t, c.n, set)
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Cp, DPaths: []string{"/f.bin", "/g.bin"}})
+	set.DPath, set.BlkList = "/h.bin", c.write("/", "h.bin", 10, 0, 1).BlkList
+	runCommand(t, c.n, set)
+	runCommand(t, c.n, &CommandArgs{CommandType: config.AppendToFile, DPath: "/h.bin",
+		FileSize: 10})
+	// the digest is journaled, a copy has the digest of its source and
+	// appending drops it
+	n := NewNameNode()
+	for p, want := range map[string]string{"/f.bin": "d1g", "/g.bin": "d1g", "/h.bin": ""} {
+		reply := runCommand(t, n, &CommandArgs{CommandType: config.CopyToLocal, DPath: p})
+		if reply.Digest != want || (want != "" && reply.DigestType != utils.SHA256) {
+			t.Errorf("%v has digest %q of type %q, want %q", p, reply.Digest,
+				reply.DigestType, want)
+		}
+	}
+	set.ChecksumType = "md5"
+	if err := c.n.RunCommand(set, &CommandReply{}); err == nil {
+		t.Errorf("digest of checksum type md5 recorded")
+	}
+}
+
+func TestMvRejectedBeforeJournaling(t *testing.T) {
+	c := newFakeCluster(t, 1)
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Mkdir, DPath: "/a"})
+	c.write("/", "f.txt", 10, 0, 1)
+	last := c.n.journal.LastTxID()
+	for _, paths := range [][]string{{"/", "/x"}, {"/a", "/missing/b"},
+		{"/a", "/f.txt/b"}, {"/a", "/a/b"}} {
+		mv := runCommand(t, c.n, &CommandArgs{CommandType: config.Mv, DPaths: paths})
+		if mv.Errors[paths[0]] == "" {
+			t.Errorf("mv %v %v succeeded", paths[0], paths[1])
+		}
+	}
+	if got := c.n.journal.LastTxID(); got != last {
+		t.Errorf("rejected moves journaled %v entries", got-last)
+	}
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Mv, DPaths: []string{"/a", "/b"}})
+	if NewNameNode().root.lookup("/b") == nil {
+		t.Errorf("moved directory is missing after restart")
+	}
+}
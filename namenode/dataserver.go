@@ -19,10 +19,11 @@ package namenode
 
 import (
 	"errors"
-	"log"
 	"math/rand"
 	"strconv"
 
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/logger"
 	"github.com/WineChord/gdfs/utils"
 )
 
@@ -31,31 +32,43 @@ type HandshakeArgs struct {
 	NamespaceID int
 	Addr        string
 	HostName    string
+	Token       string // see config.AuthToken
+	Version     string // software version of the datanode, see config.Version
 }
 
 // HandshakeReply is reply for handshake from datanodes
 type HandshakeReply struct {
 	NamespaceID int
+	Version     string // software version of the namenode
 }
 
 // Handshake check whether datanode's nid is ok
 func (n *NameNode) Handshake(args *HandshakeArgs, reply *HandshakeReply) error {
-	log.Printf("namenode receives handshake from %v, %v with %v\n",
+	if err := CheckToken("Handshake", args.Token); err != nil {
+		return err
+	}
+	logger.Infof("namenode receives handshake from %v, %v with %v\n",
 		args.HostName, args.Addr, args.NamespaceID)
+	reply.Version = config.Version
+	if err := checkVersion(args.Addr, args.Version); err != nil {
+		return err
+	}
+	n.mu.Lock()
+	nid := n.NamespaceID
+	n.mu.Unlock()
 	if args.NamespaceID == -1 { // datanode newly joined
-		log.Printf("datanode %v newly joined, give it %v\n", args.HostName,
-			n.NamespaceID)
+		logger.Infof("datanode %v newly joined, give it %v\n", args.HostName, nid)
 		// no problem, give it namenode's nid
-		reply.NamespaceID = n.NamespaceID
-	} else if args.NamespaceID != n.NamespaceID {
-		log.Printf("datanode nid %v mismatches namenode nid %v, refuse to join\n",
-			args.NamespaceID, n.NamespaceID)
+		reply.NamespaceID = nid
+	} else if args.NamespaceID != nid {
+		logger.Warnf("datanode nid %v mismatches namenode nid %v, refuse to join\n",
+			args.NamespaceID, nid)
 		// too bad, you cannot join this cluster :(
 		return errors.New("NID mismatch")
 	} else {
-		log.Printf("NamespaceID matches: %v, accept join\n", n.NamespaceID)
+		logger.Infof("NamespaceID matches: %v, accept join\n", nid)
 		// nid match, you can join the cluster :)
-		reply.NamespaceID = n.NamespaceID
+		reply.NamespaceID = nid
 	}
 	return nil
 }
@@ -66,6 +79,7 @@ type RegisterArgs struct {
 	HostName  string
 	Addr      string
 	StorageID string
+	Token     string // see config.AuthToken
 }
 
 // RegisterReply contains StorageID uniquely generated
@@ -79,6 +93,9 @@ type RegisterReply struct {
 // if datanode doesn't have one. Storage id will be persistent
 // both on namenode and datanode.
 func (n *NameNode) Register(args *RegisterArgs, reply *RegisterReply) error {
+	if err := CheckToken("Register", args.Token); err != nil {
+		return err
+	}
 	if args.StorageID == "" { // need to generate a new storage id
 		// generate a random unique token
 		// send to datanode and persist to disk
@@ -87,8 +104,17 @@ func (n *NameNode) Register(args *RegisterArgs, reply *RegisterReply) error {
 	} else {
 		reply.StorageID = args.StorageID
 	}
+	n.mu.Lock()
 	n.SID2Addr[reply.StorageID] = args.Addr
 	n.Addr2SID[args.Addr] = reply.StorageID
+	n.LastHeartBeat[args.Addr] = utils.GetCurrentTimeInMs()
+	delete(n.DeadNodes, args.Addr)
+	// a decommissioned datanode which is started again is back in service
+	if n.Decommission[args.Addr] == NodeDecommissioned {
+		delete(n.Decommission, args.Addr)
+	}
+	n.saveState()
+	n.mu.Unlock()
 	return nil
 }
 
@@ -108,6 +134,7 @@ type HeartBeatArgs struct {
 	TotalCapacity uint64  // in bytes
 	FracInUse     float64 // fraction in use
 	NumDataTrans  int     // number of data in transfer
+	Token         string  // see config.AuthToken
 }
 
 // HeartBeatReply contains
@@ -144,17 +171,37 @@ type HeartBeatReply struct {
 //  3. re-register or shutdown the node
 //  4. request datanode to send an immediate block report
 func (n *NameNode) HeartBeat(args *HeartBeatArgs, reply *HeartBeatReply) error {
-	log.Printf("receive heartbeat from %v %v, with \n\ttot cap:%v, "+
+	logger.Debugf("receive heartbeat from %v %v, with \n\ttot cap:%v, "+
 		"frac: %v, data trans: %v\n", args.HostName, args.Addr, args.TotalCapacity,
 		args.FracInUse, args.NumDataTrans)
+	if err := CheckToken("HeartBeat", args.Token); err != nil {
+		return err
+	}
 	reply.RepBlkToNodes = make(map[string]string)
 	reply.RmBlk = make([]string, 0)
 	reply.ReRegister = false
 	// RequestBlk will be set after each data transfer
 	n.mu.Lock()
-	reply.ReqBlkReport = n.RequestBlk
+	if _, ok := n.Addr2SID[args.Addr]; ok {
+		n.LastHeartBeat[args.Addr] = utils.GetCurrentTimeInMs()
+		n.NodeStats[args.Addr] = *args
+	} else {
+		// unknown or evicted datanode, it should register again
+		reply.ReRegister = true
+	}
+	reply.RepBlkToNodes = n.scheduleReplication(args.Addr)
+	for blk, target := range n.scheduleMoves(args.Addr) {
+		reply.RepBlkToNodes[blk] = target
+	}
+	reply.RmBlk = append(n.scheduleRemoval(args.Addr), n.StaleReplicas[args.Addr]...)
+	reply.RmBlk = append(reply.RmBlk, n.Orphans[args.Addr]...)
+	delete(n.StaleReplicas, args.Addr)
+	delete(n.Orphans, args.Addr)
+	reply.ReqBlkReport = n.RequestBlk || n.ReqReport[args.Addr]
+	delete(n.ReqReport, args.Addr)
 	reply.Format = n.Format
 	reply.FormatID = n.NamespaceID
+	reply.Shutdown = n.checkDecommission(args.Addr)
 	n.mu.Unlock()
 	return nil
 }
@@ -166,6 +213,7 @@ type ReportBlockArgs struct {
 	HostName     string
 	Addr         string
 	IDToMetaData map[string]utils.MetaData
+	Token        string // see config.AuthToken
 }
 
 // ReportBlockReply contains status: true or false
@@ -173,18 +221,97 @@ type ReportBlockReply struct {
 	Status bool
 }
 
+// ErrNotRegistered is returned to a datanode namenode doesn't know, e.g.
+// one evicted as dead, it should register again
+var ErrNotRegistered = errors.New("Datanode not registered")
+
 // ReportBlock will update namenode's BlkToDatanodes
+// A block report is an authoritative snapshot of the blocks held by a
+// datanode, so all existing entries of that datanode are dropped first
+// and only the reported blocks are added back. Replicas with a stale
+// generation stamp are left out, see staleReplica, and so are orphans
+// waiting to be removed, see collectOrphans.
 func (n *NameNode) ReportBlock(args *ReportBlockArgs, reply *ReportBlockReply) error {
-	log.Printf("receive block report from %v of length: %v\n", args.HostName, len(args.IDToMetaData))
-	for id := range args.IDToMetaData {
-		if n.BlkToDatanodes[id] == nil {
-			n.BlkToDatanodes[id] = make([]string, 0)
+	logger.Debugf("receive block report from %v of length: %v\n", args.HostName, len(args.IDToMetaData))
+	if err := CheckToken("ReportBlock", args.Token); err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	sid, ok := n.Addr2SID[args.Addr]
+	if !ok {
+		logger.Warnf("block report from unregistered datanode %v\n", args.Addr)
+		return ErrNotRegistered
+	}
+	for id, nodes := range n.BlkToDatanodes {
+		nodes = remove(nodes, sid)
+		if len(nodes) == 0 {
+			delete(n.BlkToDatanodes, id)
+		} else {
+			n.BlkToDatanodes[id] = nodes
 		}
-		if contains(n.BlkToDatanodes[id], n.Addr2SID[args.Addr]) == false {
-			// BlkToDatanodes maps block id to storage id
-			n.BlkToDatanodes[id] = append(n.BlkToDatanodes[id], n.Addr2SID[args.Addr])
+	}
+	for id, meta := range args.IDToMetaData {
+		if n.staleReplica(args.Addr, id, meta) || contains(n.Orphans[args.Addr], id) {
+			continue
 		}
+		n.BlkMeta[id] = meta
+		// BlkToDatanodes maps block id to storage id
+		n.BlkToDatanodes[id] = append(n.BlkToDatanodes[id], sid)
 	}
+	// a corrupt block is healed once it is fully replicated again
+	for id := range n.CorruptBlks {
+		if len(n.BlkToDatanodes[id]) >= n.replicationOf(id) {
+			delete(n.CorruptBlks, id)
+		}
+	}
+	n.saveState()
+	reply.Status = true
+	return nil
+}
+
+// ReportCorruptBlockArgs contains blocks which failed verification
+// on the datanode at Addr
+type ReportCorruptBlockArgs struct {
+	Addr   string
+	BlkIDs []string
+	Token  string // see config.AuthToken
+}
+
+// ReportCorruptBlockReply contains status: true or false
+type ReportCorruptBlockReply struct {
+	Status bool
+}
+
+// ReportCorruptBlock is called by a datanode's block scanner. The corrupt
+// replicas are dropped from BlkToDatanodes, so the blocks become
+// under-replicated and get replicated again from healthy replicas.
+func (n *NameNode) ReportCorruptBlock(args *ReportCorruptBlockArgs, reply *ReportCorruptBlockReply) error {
+	logger.Warnf("receive %v corrupt blocks from %v\n", len(args.BlkIDs), args.Addr)
+	if err := CheckToken("ReportCorruptBlock", args.Token); err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	sid, ok := n.Addr2SID[args.Addr]
+	if !ok {
+		return errors.New("Unknown datanode")
+	}
+	for _, blk := range args.BlkIDs {
+		nodes := remove(n.BlkToDatanodes[blk], sid)
+		if len(nodes) == 0 {
+			logger.Warnf("block %v has no healthy replica left\n", blk)
+			delete(n.BlkToDatanodes, blk)
+		} else {
+			n.BlkToDatanodes[blk] = nodes
+		}
+		if !contains(n.CorruptBlks[blk], sid) {
+			n.CorruptBlks[blk] = append(n.CorruptBlks[blk], sid)
+		}
+		// re-replicate right away
+		delete(n.PendingRep, blk)
+	}
+	n.saveState()
 	reply.Status = true
 	return nil
 }
@@ -197,3 +324,14 @@ func contains(list []string, elem string) bool {
 	}
 	return false 
 }
+
+// remove returns list without any occurrence of elem
+func remove(list []string, elem string) []string {
+	res := make([]string, 0, len(list))
+	for _, e := range list {
+		if e != elem {
+			res = append(res, e)
+		}
+	}
+	return res
+}
@@ -0,0 +1,190 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// reportBlocks sends a block report of the datanode at addr holding blks
+func reportBlocks(t *testing.T, n *NameNode, addr string, blks ...string) {
+	t.Helper()
+	metas := make(map[string]utils.MetaData)
+	for _, blk := range blks {
+		metas[blk] = utils.MetaData{Length: 10}
+	}
+	args := ReportBlockArgs{HostName: addr, Addr: addr, IDToMetaData: metas}
+	if err := n.ReportBlock(&args, &ReportBlockReply{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReportBlockReplaces(t *testing.T) {
+	n := newTestNameNode(t)
+	addr := "127.0.0.1:11170"
+	sid := addDataNode(t, n, addr, 1<<30, 0)
+	a, b := "a.txt-00000000-1-1", "b.txt-00000000-1-1"
+	reportBlocks(t, n, addr, a, b)
+	reportBlocks(t, n, addr, a, b)
+	for _, blk := range []string{a, b} {
+		if got := n.BlkToDatanodes[blk]; len(got) != 1 || got[0] != sid {
+			t.Errorf("replicas of %v are %v after reporting twice, want [%v]",
+				blk, got, sid)
+		}
+	}
+	// a report is a snapshot, a block missing from it is gone
+	reportBlocks(t, n, addr, a)
+	if got := n.BlkToDatanodes[b]; len(got) != 0 {
+		t.Errorf("replicas of %v are %v after it was not reported, want none",
+			b, got)
+	}
+	// an unknown or evicted datanode registers before its reports count
+	args := ReportBlockArgs{Addr: "127.0.0.1:11171",
+		IDToMetaData: map[string]utils.MetaData{a: {Length: 10}}}
+	if err := n.ReportBlock(&args, &ReportBlockReply{}); err != ErrNotRegistered {
+		t.Errorf("report from an unregistered datanode = %v, want %v", err,
+			ErrNotRegistered)
+	}
+	if got := n.BlkToDatanodes[a]; len(got) != 1 || got[0] != sid {
+		t.Errorf("replicas of %v are %v after an unregistered report, want [%v]",
+			a, got, sid)
+	}
+}
+
+// TestConcurrentRPCs hammers the RPCs of datanodes and clients at once,
+// it is meant to be run with -race (make race)
+func TestConcurrentRPCs(t *testing.T) {
+	n := newTestNameNode(t)
+	const nodes, rounds = 8, 50
+	// format waits a heartbeat for datanodes to see it
+	hb := config.HeartBeatInSec
+	config.HeartBeatInSec = 0
+	defer func() { config.HeartBeatInSec = hb }()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for r := 0; r < 5; r++ {
+			n.RunCommand(&CommandArgs{CommandType: config.Format}, &CommandReply{})
+		}
+	}()
+	for i := 0; i < nodes; i++ {
+		addr := fmt.Sprintf("127.0.0.1:%v", 11170+i)
+		wg.Add(1)
+		go func(i int, addr string) {
+			defer wg.Done()
+			reg := RegisterReply{}
+			for r := 0; r < rounds; r++ {
+				n.Handshake(&HandshakeArgs{NamespaceID: -1, Addr: addr},
+					&HandshakeReply{})
+				args := RegisterArgs{HostName: addr, Addr: addr,
+					StorageID: reg.StorageID}
+				if err := n.Register(&args, &reg); err != nil {
+					t.Error(err)
+					return
+				}
+				blk := fmt.Sprintf("f%v.txt-%08d-1-1", i, r)
+				metas := map[string]utils.MetaData{blk: {Length: 10}}
+				rep := ReportBlockArgs{HostName: addr, Addr: addr, IDToMetaData: metas}
+				if err := n.ReportBlock(&rep, &ReportBlockReply{}); err != nil {
+					t.Error(err)
+					return
+				}
+				hb := HeartBeatArgs{HostName: addr, Addr: addr, TotalCapacity: 1 << 30}
+				if err := n.HeartBeat(&hb, &HeartBeatReply{}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i, addr)
+	}
+	for i := 0; i < nodes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				args := CommandArgs{CommandType: config.CopyFromLocal, DPath: "/",
+					FileName: fmt.Sprintf("c%v-%v", i, r), FileSize: 10, Replication: 1}
+				n.RunCommand(&args, &CommandReply{})
+				n.RunCommand(&CommandArgs{CommandType: config.CopyToLocal,
+					DPath: "/" + args.FileName}, &CommandReply{})
+				n.RunCommand(&CommandArgs{CommandType: config.Stat,
+					DPaths: []string{"/" + args.FileName}}, &CommandReply{})
+				n.Notify(&NotifyArgs{Addrs: []string{"127.0.0.1:11170"}}, &NotifyReply{})
+				n.checkDeadNodes(utils.GetCurrentTimeInMs())
+				n.flushState()
+				n.LiveNodes()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if live := n.LiveNodes(); len(live) != nodes {
+		t.Errorf("live nodes are %v, want %v of them", live, nodes)
+	}
+}
+
+func TestHandshakeVersion(t *testing.T) {
+	n := newTestNameNode(t)
+	defer func(version, check string) {
+		config.Version, config.VersionCheck = version, check
+	}(config.Version, config.VersionCheck)
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(ioutil.Discard)
+	config.Version = "2.3.1"
+	for _, tc := range []struct {
+		check, version string
+		ok, warn       bool
+	}{
+		{VersionMajor, "2.3.1", true, false},
+		{VersionMajor, "v2.3.0", true, false}, // patch versions are compatible
+		{VersionMajor, "2.4.0", true, true},
+		{VersionMajor, "", true, true},
+		{VersionMajor, "3.3.1", false, true},
+		{VersionMajor, "1.9", false, true},
+		{VersionMajor, "two", false, false},
+		{VersionStrict, "2.3.7", true, false},
+		{VersionStrict, "2.2.0", false, true},
+		{VersionStrict, "", false, false},
+		{VersionOff, "3.0.0", true, true},
+		{VersionOff, "2.9.0", true, true},
+	} {
+		config.VersionCheck = tc.check
+		logs.Reset()
+		reply := HandshakeReply{}
+		err := n.Handshake(&HandshakeArgs{NamespaceID: -1, Addr: "127.0.0.1:11170",
+			Version: tc.version}, &reply)
+		if (err == nil) != tc.ok {
+			t.Errorf("handshake of version %q with policy %v: %v, want accepted %v",
+				tc.version, tc.check, err, tc.ok)
+		}
+		if warn := strings.Contains(logs.String(), "WARN"); warn != tc.warn {
+			t.Errorf("handshake of version %q with policy %v logged a warning %v, want %v",
+				tc.version, tc.check, warn, tc.warn)
+		}
+		if reply.Version != config.Version {
+			t.Errorf("handshake replies version %q, want %v", reply.Version, config.Version)
+		}
+	}
+}
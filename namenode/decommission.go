@@ -0,0 +1,93 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// runDecommission starts retiring the datanode at args.Addr. A
+// decommissioning datanode gets no new blocks, heartbeats of the datanodes
+// holding its blocks copy them to the datanodes in service. Once every
+// block it holds has enough replicas elsewhere it is decommissioned and
+// told to shut down, see checkDecommission.
+func (n *NameNode) runDecommission(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runDecommission %v\n", args.Addr)
+	// datanodes advertise IPv6 addresses in canonical form
+	addr, err := utils.NormalizeAddr(args.Addr)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.Addr2SID[addr]; !ok {
+		return errors.New("Unknown datanode")
+	}
+	if state, ok := n.Decommission[addr]; ok {
+		reply.Result = fmt.Sprintf("%v is already %v", addr, state)
+		return nil
+	}
+	n.Decommission[addr] = NodeDecommissioning
+	n.saveState()
+	reply.Result = fmt.Sprintf("decommissioning %v", addr)
+	return nil
+}
+
+// inService tells whether the datanode at addr may get new blocks, i.e.
+// it isn't decommissioning or decommissioned. The caller holds n.mu.
+func (n *NameNode) inService(addr string) bool {
+	_, ok := n.Decommission[addr]
+	return !ok
+}
+
+// inServiceReplicas counts the replicas of nodes, a list of storage ids,
+// on datanodes in service. The caller holds n.mu.
+func (n *NameNode) inServiceReplicas(nodes []string) int {
+	res := 0
+	for _, sid := range nodes {
+		if n.inService(n.SID2Addr[sid]) {
+			res++
+		}
+	}
+	return res
+}
+
+// checkDecommission marks the decommissioning datanode at addr
+// decommissioned once each of its blocks has its replication factor of
+// replicas on datanodes in service. It tells whether the datanode is
+// decommissioned. The caller holds n.mu.
+func (n *NameNode) checkDecommission(addr string) bool {
+	switch n.Decommission[addr] {
+	case NodeDecommissioned:
+		return true
+	case NodeDecommissioning:
+	default:
+		return false
+	}
+	sid := n.Addr2SID[addr]
+	for blk, nodes := range n.BlkToDatanodes {
+		if contains(nodes, sid) && n.inServiceReplicas(nodes) < n.replicationOf(blk) {
+			return false
+		}
+	}
+	logger.Infof("every block of %v is replicated elsewhere, it is decommissioned\n", addr)
+	n.Decommission[addr] = NodeDecommissioned
+	n.saveState()
+	return true
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+func TestDecommission(t *testing.T) {
+	c := newFakeCluster(t, 4)
+	reply := c.write("/", "a.bin", 5000, 1000, 2)
+	// retire the holder of the first block
+	addr := c.holders(reply.BlkList[0])[0]
+	var held []string
+	for _, blk := range reply.BlkList {
+		if contains(c.holders(blk), addr) {
+			held = append(held, blk)
+		}
+	}
+	if err := c.n.RunCommand(&CommandArgs{CommandType: config.Decommission,
+		Addr: "127.0.0.1:1"}, &CommandReply{}); err == nil {
+		t.Errorf("decommissioning an unknown datanode succeeded")
+	}
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Decommission, Addr: addr})
+	// no new block goes to it
+	for i := 0; i < 20; i++ {
+		put := runCommand(t, c.n, &CommandArgs{CommandType: config.CopyFromLocal,
+			DPath: "/", FileName: fmt.Sprintf("new%v.bin", i), FileSize: 10,
+			Replication: 3})
+		for _, addrs := range put.BlkToDataNodes {
+			if contains(addrs, addr) {
+				t.Fatalf("new block placed on decommissioning %v", addr)
+			}
+		}
+	}
+	if hb := c.heartbeat(addr); hb.Shutdown {
+		t.Fatalf("%v is shut down before its blocks are copied", addr)
+	}
+	for _, blk := range held {
+		holders := c.holders(blk)
+		others := 0
+		for _, h := range holders {
+			if h != addr {
+				others++
+			}
+		}
+		if others < 2 {
+			t.Errorf("%v is on %v, want 2 replicas besides %v", blk, holders, addr)
+		}
+	}
+	// the others don't drop the copies, the retiring one keeps its own
+	for _, other := range c.addrs {
+		if other != addr {
+			if hb := c.heartbeat(other); len(hb.RmBlk) != 0 {
+				t.Errorf("%v told to remove %v", other, hb.RmBlk)
+			}
+		}
+	}
+	if hb := c.heartbeat(addr); !hb.Shutdown {
+		t.Errorf("%v is not shut down once its blocks are copied", addr)
+	}
+	report := ReportNodesReply{}
+	if err := c.n.ReportNodes(&AdminArgs{}, &report); err != nil {
+		t.Fatal(err)
+	}
+	last := report.Nodes[len(report.Nodes)-1]
+	if last.Addr != addr || last.State != NodeDecommissioned {
+		t.Errorf("report ends with %+v, want %v decommissioned", last, addr)
+	}
+	// it is back in service once it registers again
+	if err := c.n.Register(&RegisterArgs{HostName: addr, Addr: addr,
+		StorageID: c.sids[addr]}, &RegisterReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if !c.n.inService(addr) {
+		t.Errorf("%v is not in service after registering again", addr)
+	}
+}
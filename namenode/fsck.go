@@ -0,0 +1,94 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"sort"
+
+	"github.com/WineChord/gdfs/logger"
+)
+
+// FileHealth lists the unhealthy blocks of a dfs file by condition
+type FileHealth struct {
+	Path    string
+	NumBlks int
+	Missing []string // blocks without a live replica
+	// blocks with fewer replicas on datanodes in service than the
+	// replication factor, missing ones excluded
+	UnderReplicated []string
+	OverReplicated  []string // blocks with more replicas than the replication factor
+	Corrupt         []string // blocks with replicas reported corrupt by scanners
+}
+
+// Healthy tells whether no block of the file has a problem
+func (h *FileHealth) Healthy() bool {
+	return len(h.Missing)+len(h.UnderReplicated)+len(h.OverReplicated)+len(h.Corrupt) == 0
+}
+
+// FsckReport stores the health of the files under a dfs path
+type FsckReport struct {
+	Files     int          // number of files checked
+	Blks      int          // number of blocks checked
+	Unhealthy []FileHealth // files with a problem, sorted by path
+}
+
+func (n *NameNode) runFsck(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runFsck %v\n", args.DPath)
+	/** the replicas of a block are the live ones of BlkToDatanodes, since
+	 * dead datanodes are purged from it. Replicas on decommissioning
+	 * datanodes keep a block from missing, not from being
+	 * under-replicated.
+	 * */
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	node := n.root.lookup(args.DPath)
+	if node == nil {
+		return ErrNotFound
+	}
+	report := &FsckReport{Unhealthy: []FileHealth{}}
+	n.mu.Lock()
+	node.walk(cleanPath(args.DPath), func(p string, node *inode) {
+		if node.IsDir {
+			return
+		}
+		report.Files++
+		report.Blks += len(node.BlkList)
+		health := FileHealth{Path: p, NumBlks: len(node.BlkList)}
+		rep := node.replication()
+		for _, blk := range node.BlkList {
+			nodes := n.BlkToDatanodes[blk]
+			switch {
+			case len(nodes) == 0:
+				health.Missing = append(health.Missing, blk)
+			case n.inServiceReplicas(nodes) < rep:
+				health.UnderReplicated = append(health.UnderReplicated, blk)
+			case len(nodes) > rep:
+				health.OverReplicated = append(health.OverReplicated, blk)
+			}
+			if len(n.CorruptBlks[blk]) > 0 {
+				health.Corrupt = append(health.Corrupt, blk)
+			}
+		}
+		if !health.Healthy() {
+			report.Unhealthy = append(report.Unhealthy, health)
+		}
+	})
+	n.mu.Unlock()
+	sort.Slice(report.Unhealthy, func(i, j int) bool {
+		return report.Unhealthy[i].Path < report.Unhealthy[j].Path
+	})
+	reply.Fsck = report
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+// drop makes the datanode at addr lose its replica of blk
+func (c *fakeCluster) drop(addr, blk string) {
+	c.t.Helper()
+	delete(c.blks[addr], blk)
+	delete(c.stamps[addr], blk)
+	c.report(addr)
+}
+
+func TestFsck(t *testing.T) {
+	c := newFakeCluster(t, 4)
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Mkdir, DPath: "/d"})
+	c.write("/d", "healthy.bin", 10, 0, 2)
+	missing := c.write("/d", "missing.bin", 10, 0, 2).BlkList[0]
+	under := c.write("/d", "under.bin", 10, 0, 2).BlkList[0]
+	over := c.write("/d", "over.bin", 10, 0, 2).BlkList[0]
+	corrupt := c.write("/", "corrupt.bin", 10, 0, 2).BlkList[0]
+	for _, addr := range c.holders(missing) {
+		c.drop(addr, missing)
+	}
+	c.drop(c.holders(under)[0], under)
+	for _, addr := range c.addrs {
+		if !contains(c.holders(over), addr) {
+			c.store(addr, over, 10)
+			break
+		}
+	}
+	bad := c.holders(corrupt)[0]
+	err := c.n.ReportCorruptBlock(&ReportCorruptBlockArgs{Addr: bad,
+		BlkIDs: []string{corrupt}}, &ReportCorruptBlockReply{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsck := runCommand(t, c.n, &CommandArgs{CommandType: config.Fsck, DPath: "/"}).Fsck
+	if fsck.Files != 5 || fsck.Blks != 5 {
+		t.Errorf("fsck / checked %v files of %v blocks, want 5 and 5", fsck.Files, fsck.Blks)
+	}
+	want := []struct {
+		path                 string
+		missing, under, over []string
+		corrupt              []string
+	}{
+		{"/corrupt.bin", nil, []string{corrupt}, nil, []string{corrupt}},
+		{"/d/missing.bin", []string{missing}, nil, nil, nil},
+		{"/d/over.bin", nil, nil, []string{over}, nil},
+		{"/d/under.bin", nil, []string{under}, nil, nil},
+	}
+	if len(fsck.Unhealthy) != len(want) {
+		t.Fatalf("fsck / found %+v unhealthy, want %v files", fsck.Unhealthy, len(want))
+	}
+	same := func(a, b []string) bool {
+		return len(a) == len(b) && (len(a) == 0 || a[0] == b[0])
+	}
+	for i, w := range want {
+		h := fsck.Unhealthy[i]
+		if h.Path != w.path || !same(h.Missing, w.missing) ||
+			!same(h.UnderReplicated, w.under) || !same(h.OverReplicated, w.over) ||
+			!same(h.Corrupt, w.corrupt) {
+			t.Errorf("fsck entry %v is %+v, want %+v", i, h, w)
+		}
+	}
+	// only the files under the path are checked
+	fsck = runCommand(t, c.n, &CommandArgs{CommandType: config.Fsck,
+		DPath: "/d/healthy.bin"}).Fsck
+	if fsck.Files != 1 || len(fsck.Unhealthy) != 0 {
+		t.Errorf("fsck of a healthy file = %+v", fsck)
+	}
+	// a corrupt block is healed by its replication
+	for _, addr := range c.addrs {
+		if !contains(c.holders(corrupt), addr) && addr != bad {
+			c.store(addr, corrupt, 10)
+			break
+		}
+	}
+	fsck = runCommand(t, c.n, &CommandArgs{CommandType: config.Fsck,
+		DPath: "/corrupt.bin"}).Fsck
+	if len(fsck.Unhealthy) != 0 {
+		t.Errorf("fsck of a re-replicated block = %+v", fsck.Unhealthy)
+	}
+}
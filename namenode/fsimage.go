@@ -0,0 +1,136 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/utils"
+)
+
+/** fsimage is a checkpoint of the namespace. It contains the whole inode
+ * tree plus block maps, and the TxID of the last edit log entry included.
+ * On startup, the fsimage is loaded and edit log entries after its TxID are
+ * replayed on top of it. After each checkpoint the edit log is truncated.
+ * */
+type fsimage struct {
+	TxID           int64
+	Root           *inode
+	BlkToDatanodes map[string][]string
+	BlkMeta        map[string]utils.MetaData
+}
+
+// checkpoint dumps the namespace to fsimage and truncates the edit log
+func (n *NameNode) checkpoint() {
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	n.mu.Lock()
+	image := fsimage{n.journal.LastTxID(), n.root, n.BlkToDatanodes, n.BlkMeta}
+	bytes, err := json.Marshal(image)
+	n.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error when marshaling fsimage: %v\n", err)
+		return
+	}
+	err = utils.WriteFileAtomic(config.NFSImagePath, bytes, 0600)
+	if err != nil {
+		logger.Errorf("error when writing fsimage: %v\n", err)
+		return
+	}
+	// every entry up to image.TxID is in the fsimage now
+	n.journal.Truncate()
+	logger.Infof("checkpoint at txid %v done\n", image.TxID)
+}
+
+func (n *NameNode) checkpointPeriodically() {
+	for n.sleep(config.CheckpointInSec) {
+		n.checkpoint()
+	}
+}
+
+// loadImage loads the fsimage and returns its TxID. If there is no fsimage
+// yet, the tree is migrated from the legacy metadata layout under
+// DFSRootPath, which already reflects every entry of the edit log.
+func (n *NameNode) loadImage() int64 {
+	bytes, err := ioutil.ReadFile(config.NFSImagePath)
+	if err == nil {
+		image := fsimage{}
+		err = json.Unmarshal(bytes, &image)
+		if err == nil && image.Root != nil {
+			// Children of empty directories are omitted in json
+			image.Root.walk("/", func(p string, node *inode) {
+				if node.IsDir && node.Children == nil {
+					node.Children = make(map[string]*inode)
+				}
+			})
+			n.root = image.Root
+			for blk, nodes := range image.BlkToDatanodes {
+				n.BlkToDatanodes[blk] = nodes
+			}
+			for blk, meta := range image.BlkMeta {
+				n.BlkMeta[blk] = meta
+			}
+			logger.Infof("loaded fsimage at txid %v\n", image.TxID)
+			return image.TxID
+		}
+		logger.Errorf("error when unmarshaling fsimage: %v\n", err)
+	} else if !os.IsNotExist(err) {
+		logger.Errorf("error when reading fsimage: %v\n", err)
+	}
+	logger.Warnf("no usable fsimage, build namespace from %v\n", n.DFSRootPath)
+	n.root = n.buildTree()
+	// persist the migrated tree, the legacy layout is not updated anymore
+	n.checkpoint()
+	return n.journal.LastTxID()
+}
+
+// buildTree constructs the inode tree from the legacy directory layout
+// on disk, where each file is a json list of its blocks
+func (n *NameNode) buildTree() *inode {
+	root := newDir("", utils.GetCurrentTimeInMs())
+	filepath.Walk(n.DFSRootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == n.DFSRootPath {
+			return nil
+		}
+		dfsPath := strings.TrimPrefix(path, n.DFSRootPath)
+		dfsPath = filepath.ToSlash(dfsPath)
+		modTime := info.ModTime().UnixNano() / int64(time.Millisecond)
+		if info.IsDir() {
+			root.mkdir(dfsPath, true, modTime)
+		} else {
+			root.write(dfsPath, &inode{BlkList: readLegacyFile(path)}, modTime)
+		}
+		return nil
+	})
+	return root
+}
+
+func readLegacyFile(path string) []string {
+	var res []string
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		logger.Errorf("error reading legacy dfs file: %v\n", err)
+		return res
+	}
+	json.Unmarshal(bytes, &res)
+	return res
+}
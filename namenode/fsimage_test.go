@@ -0,0 +1,89 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// marshalTree returns the json of the inode tree of n, to compare trees
+func marshalTree(t *testing.T, n *NameNode) string {
+	t.Helper()
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	bytes, err := json.Marshal(n.root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(bytes)
+}
+
+func TestCheckpointRestart(t *testing.T) {
+	c := newFakeCluster(t, 3)
+	n := c.n
+	runCommand(t, n, &CommandArgs{CommandType: config.MkdirP, DPath: "/a/b/empty"})
+	c.write("/a/b", "x.txt", 2500, 0, 0)
+	c.write("/", "y.txt", 10, 0, 0)
+	n.checkpoint()
+	if _, err := os.Stat(config.NEditLogPath); !os.IsNotExist(err) {
+		t.Errorf("edit log is kept after checkpoint: %v", err)
+	}
+	want := marshalTree(t, n)
+	restarted := NewNameNode()
+	if got := marshalTree(t, restarted); got != want {
+		t.Errorf("tree after restart is\n%v\nwant\n%v", got, want)
+	}
+	if !reflect.DeepEqual(restarted.BlkToDatanodes, n.BlkToDatanodes) {
+		t.Errorf("replicas after restart are %v, want %v",
+			restarted.BlkToDatanodes, n.BlkToDatanodes)
+	}
+	if !reflect.DeepEqual(restarted.BlkMeta, n.BlkMeta) {
+		t.Errorf("block metadata after restart is %v, want %v",
+			restarted.BlkMeta, n.BlkMeta)
+	}
+	// changes after the checkpoint come from the edit log on top of it,
+	// with TxIDs continuing those in the fsimage
+	last := restarted.journal.lastTxID
+	runCommand(t, restarted, &CommandArgs{CommandType: config.Mkdir, DPath: "/c"})
+	if got := restarted.journal.lastTxID; got != last+1 {
+		t.Errorf("TxID after checkpoint is %v, want %v", got, last+1)
+	}
+	want = marshalTree(t, restarted)
+	if got := marshalTree(t, NewNameNode()); got != want {
+		t.Errorf("tree after another restart is\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestCheckpointCrashBeforeRename(t *testing.T) {
+	n := newTestNameNode(t)
+	runCommand(t, n, &CommandArgs{CommandType: config.Mkdir, DPath: "/a"})
+	n.checkpoint()
+	want := marshalTree(t, n)
+	// a crash while writing the next fsimage leaves its temp file only
+	tmp := config.NFSImagePath + utils.TmpSuffix
+	if err := ioutil.WriteFile(tmp, []byte(`{"TxID":9,"Ro`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := marshalTree(t, NewNameNode()); got != want {
+		t.Errorf("tree after restart is\n%v\nwant\n%v", got, want)
+	}
+}
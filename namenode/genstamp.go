@@ -0,0 +1,68 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/utils"
+)
+
+/** Every write of a block carries a generation stamp handed out by
+ * namenode, datanodes keep it in the metadata of the replica and report
+ * it. BlkMeta holds the newest stamp reported for each block. When a block
+ * is written again, a datanode which missed the write still holds the old
+ * content under a valid checksum, its older stamp is what tells the
+ * replica apart.
+ * */
+
+// nextGenStamp returns a generation stamp greater than any handed out
+// before. Stamps follow the clock in ms, so they keep increasing across
+// restarts of namenode. The caller holds n.mu.
+func (n *NameNode) nextGenStamp() int64 {
+	n.genStamp++
+	if now := utils.GetCurrentTimeInMs(); now > n.genStamp {
+		n.genStamp = now
+	}
+	return n.genStamp
+}
+
+// staleReplica compares the generation stamp of the replica of blk
+// reported by the datanode at addr with the one of the block. An older
+// replica is stale, its removal is scheduled for the next heartbeat of the
+// datanode and true is returned. A newer one means the block was written
+// again, the replicas known so far are dropped until their datanodes
+// report again, so that the stale ones among them are found. The caller
+// holds n.mu.
+func (n *NameNode) staleReplica(addr, blk string, meta utils.MetaData) bool {
+	known, ok := n.BlkMeta[blk]
+	if !ok || meta.GenStamp == known.GenStamp {
+		return false
+	}
+	if meta.GenStamp < known.GenStamp {
+		logger.Warnf("replica of %v on %v is stale, generation stamp %v < %v\n",
+			blk, addr, meta.GenStamp, known.GenStamp)
+		if !contains(n.StaleReplicas[addr], blk) {
+			n.StaleReplicas[addr] = append(n.StaleReplicas[addr], blk)
+		}
+		return true
+	}
+	logger.Infof("block %v has a new generation stamp %v, re-check its replicas\n",
+		blk, meta.GenStamp)
+	for _, sid := range n.BlkToDatanodes[blk] {
+		n.ReqReport[n.SID2Addr[sid]] = true
+	}
+	delete(n.BlkToDatanodes, blk)
+	return false
+}
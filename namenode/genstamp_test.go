@@ -0,0 +1,71 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"testing"
+)
+
+func TestStaleReplicaRemoved(t *testing.T) {
+	c := newFakeCluster(t, 3)
+	reply := c.write("/", "a.txt", 10, 0, 3)
+	blk := reply.BlkList[0]
+	if reply.GenStamp == 0 || c.n.BlkMeta[blk].GenStamp != reply.GenStamp {
+		t.Fatalf("block written with stamp %v is known with stamp %v",
+			reply.GenStamp, c.n.BlkMeta[blk].GenStamp)
+	}
+	// the block is written again, the last datanode misses the write
+	c.n.mu.Lock()
+	gs := c.n.nextGenStamp()
+	c.n.mu.Unlock()
+	if gs <= reply.GenStamp {
+		t.Fatalf("next generation stamp %v isn't newer than %v", gs, reply.GenStamp)
+	}
+	a, b, stale := c.addrs[0], c.addrs[1], c.addrs[2]
+	c.stamps[a][blk] = gs
+	c.report(a)
+	if holders := c.holders(blk); len(holders) != 1 || holders[0] != a {
+		t.Errorf("holders after a newer replica = %v, want [%v]", holders, a)
+	}
+	if !c.n.ReqReport[b] || !c.n.ReqReport[stale] {
+		t.Errorf("other holders aren't asked for a block report: %v", c.n.ReqReport)
+	}
+	c.report(stale)
+	c.stamps[b][blk] = gs
+	c.report(b)
+	holders := c.holders(blk)
+	if len(holders) != 2 || contains(holders, stale) {
+		t.Errorf("holders = %v, want %v and %v", holders, a, b)
+	}
+	if c.n.BlkMeta[blk].GenStamp != gs {
+		t.Errorf("block has stamp %v, want %v", c.n.BlkMeta[blk].GenStamp, gs)
+	}
+	hb := c.heartbeat(stale)
+	if !contains(hb.RmBlk, blk) {
+		t.Errorf("heartbeat of %v removes %v, want the stale %v", stale, hb.RmBlk, blk)
+	}
+	if _, ok := c.blks[stale][blk]; ok {
+		t.Errorf("stale replica is still on %v", stale)
+	}
+	if hb = c.heartbeat(stale); len(hb.RmBlk) != 0 {
+		t.Errorf("stale replica is removed twice: %v", hb.RmBlk)
+	}
+	// the block gets its third replica back from an up-to-date one
+	c.heartbeat(a)
+	if len(c.holders(blk)) != 3 || c.stamps[stale][blk] != gs {
+		t.Errorf("block is on %v, stamp on %v is %v", c.holders(blk), stale,
+			c.stamps[stale][blk])
+	}
+}
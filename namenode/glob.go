@@ -0,0 +1,126 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"errors"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+/** Glob patterns in path arguments are expanded here rather than by the
+ * client, since only namenode sees the namespace. Each element of a
+ * pattern is matched with path.Match, i.e. * and ? never match a /, and
+ * [a-z] or [^a-z] match a character class. Expansion happens before the
+ * command runs:
+ * - multi-path commands (rm, rmdir, stat, checksum) run on every match of
+ *   each pattern, a pattern matching nothing is kept as is and fails as a
+ *   missing path. reply.Paths lists the paths they ran on.
+ * - ls lists the matching paths themselves, see runLs.
+ * - the other read commands take a single path, their pattern must match
+ *   exactly one.
+ * */
+
+// ErrAmbiguousGlob is returned when a pattern of a single-path command
+// matches more than one path
+var ErrAmbiguousGlob = errors.New("Pattern matches more than one path")
+
+// hasGlob tells whether dfs path p has a glob pattern in it
+func hasGlob(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// glob returns the dfs paths matching pattern, sorted. The caller holds
+// n.nsMu.
+func (n *NameNode) glob(pattern string) ([]string, error) {
+	matches := []string{"/"}
+	for _, elem := range splitPath(pattern) {
+		var next []string
+		for _, dir := range matches {
+			node := n.root.lookup(dir)
+			if node == nil || !node.IsDir {
+				continue
+			}
+			if !hasGlob(elem) {
+				if node.Children[elem] != nil {
+					next = append(next, path.Join(dir, elem))
+				}
+				continue
+			}
+			for name := range node.Children {
+				ok, err := path.Match(elem, name)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					next = append(next, path.Join(dir, name))
+				}
+			}
+		}
+		matches = next
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// expandGlobs replaces the patterns in the path arguments of args with
+// the paths they match, as described above
+func (n *NameNode) expandGlobs(args *CommandArgs, reply *CommandReply) error {
+	glob := func(pattern string) ([]string, error) {
+		n.nsMu.Lock()
+		defer n.nsMu.Unlock()
+		return n.glob(pattern)
+	}
+	switch args.CommandType {
+	case config.Rm, config.Rmdir, config.Stat, config.Checksum:
+		paths := make([]string, 0, len(args.DPaths))
+		for _, p := range args.DPaths {
+			if !hasGlob(p) {
+				paths = append(paths, p)
+				continue
+			}
+			matches, err := glob(p)
+			if err != nil {
+				return err
+			}
+			if len(matches) == 0 {
+				matches = []string{p}
+			}
+			paths = append(paths, matches...)
+		}
+		args.DPaths = paths
+		reply.Paths = paths
+	case config.Cat, config.CopyToLocal, config.Du, config.Count, config.Fsck:
+		if !hasGlob(args.DPath) {
+			return nil
+		}
+		matches, err := glob(args.DPath)
+		if err != nil {
+			return err
+		}
+		switch len(matches) {
+		case 0:
+			return ErrNotFound
+		case 1:
+			args.DPath = matches[0]
+		default:
+			return ErrAmbiguousGlob
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+func TestGlob(t *testing.T) {
+	n := newTestNameNode(t)
+	for _, dir := range []string{"/logs", "/data/2020-01", "/data/2020-02", "/data/2021-01"} {
+		runCommand(t, n, &CommandArgs{CommandType: config.MkdirP, DPath: dir})
+	}
+	runCommand(t, n, &CommandArgs{CommandType: config.Touch, DPaths: []string{
+		"/logs/a.tmp", "/logs/b.tmp", "/logs/c.log", "/data/x1", "/data/x2",
+		"/data/x10", "/data/2020-01/part"}})
+	ls := func(pattern string) []string {
+		t.Helper()
+		return runCommand(t, n, &CommandArgs{CommandType: config.Ls, DPath: pattern}).Files
+	}
+	for _, tc := range []struct {
+		pattern string
+		want    []string
+	}{
+		{"/data/2020*", []string{"/data/2020-01", "/data/2020-02"}},
+		{"/data/x?", []string{"/data/x1", "/data/x2"}},
+		{"/data/202[01]-01", []string{"/data/2020-01", "/data/2021-01"}},
+		{"/data/x[^1]", []string{"/data/x2"}},
+		{"/*/*-01/part", []string{"/data/2020-01/part"}},
+	} {
+		if got := ls(tc.pattern); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ls %v = %q, want %q", tc.pattern, got, tc.want)
+		}
+	}
+	// ls -R of a pattern lists the entries below the matching directories
+	args := CommandArgs{CommandType: config.Ls, DPath: "/data/2020-0?", Recursive: true}
+	want := []string{"/data/2020-01", "/data/2020-01/part", "/data/2020-02"}
+	if got := runCommand(t, n, &args).Files; !reflect.DeepEqual(got, want) {
+		t.Errorf("ls -R /data/2020-0? = %q, want %q", got, want)
+	}
+	stat := runCommand(t, n, &CommandArgs{CommandType: config.Stat,
+		DPaths: []string{"/data/x*", "/logs/c.log"}})
+	want = []string{"/data/x1", "/data/x10", "/data/x2", "/logs/c.log"}
+	if !reflect.DeepEqual(stat.Paths, want) || len(stat.Stats) != 4 {
+		t.Errorf("stat /data/x* /logs/c.log ran on %q with %v stats, want %q",
+			stat.Paths, len(stat.Stats), want)
+	}
+	rm := runCommand(t, n, &CommandArgs{CommandType: config.Rm,
+		DPaths: []string{"/logs/*.tmp"}})
+	if len(rm.Errors) != 0 || !reflect.DeepEqual(rm.Paths, []string{"/logs/a.tmp", "/logs/b.tmp"}) {
+		t.Errorf("rm /logs/*.tmp ran on %q with errors %v", rm.Paths, rm.Errors)
+	}
+	if got := ls("/logs"); !reflect.DeepEqual(got, []string{"c.log"}) {
+		t.Errorf("ls /logs = %q after rm /logs/*.tmp, want [c.log]", got)
+	}
+	// a pattern matching nothing fails like a missing path
+	rm = runCommand(t, n, &CommandArgs{CommandType: config.Rm,
+		DPaths: []string{"/logs/*.tmp"}})
+	if _, ok := rm.Errors["/logs/*.tmp"]; !ok || len(rm.Paths) != 1 {
+		t.Errorf("rm of a pattern matching nothing ran on %q with errors %v",
+			rm.Paths, rm.Errors)
+	}
+	err := n.RunCommand(&CommandArgs{CommandType: config.Ls, DPath: "/nothing*"},
+		&CommandReply{})
+	if err != ErrNotFound {
+		t.Errorf("ls of a pattern matching nothing = %v, want %v", err, ErrNotFound)
+	}
+	// single-path commands need a single match
+	err = n.RunCommand(&CommandArgs{CommandType: config.Du, DPath: "/data/x?"},
+		&CommandReply{})
+	if err != ErrAmbiguousGlob {
+		t.Errorf("du of a pattern matching 2 paths = %v, want %v", err, ErrAmbiguousGlob)
+	}
+	du := runCommand(t, n, &CommandArgs{CommandType: config.Du, DPath: "/l*"})
+	if total := du.Usage[len(du.Usage)-1]; total.Path != "/logs" {
+		t.Errorf("du /l* summed %v, want /logs", total.Path)
+	}
+	err = n.RunCommand(&CommandArgs{CommandType: config.Ls, DPath: "/data/["},
+		&CommandReply{})
+	if err == nil {
+		t.Errorf("ls of a malformed pattern succeeded")
+	}
+}
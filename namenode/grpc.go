@@ -0,0 +1,154 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"context"
+
+	"github.com/WineChord/gdfs/gdfspb"
+	"google.golang.org/grpc"
+)
+
+// RegisterServer serves n as the NameNode service of s, see Start
+func RegisterServer(s *grpc.Server, n *NameNode) {
+	gdfspb.RegisterNameNodeServer(s, grpcServer{n: n})
+}
+
+// grpcServer serves the methods of namenode as the NameNode service of
+// gdfspb, converting their args and replies
+type grpcServer struct {
+	gdfspb.UnimplementedNameNodeServer
+	n *NameNode
+}
+
+func (s grpcServer) RunCommand(ctx context.Context, pb *gdfspb.CommandArgs) (*gdfspb.CommandReply, error) {
+	var args CommandArgs
+	var reply CommandReply
+	args.FromPB(pb)
+	if err := s.n.RunCommand(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) SubmitJob(ctx context.Context, pb *gdfspb.CommandArgs) (*gdfspb.SubmitJobReply, error) {
+	var args CommandArgs
+	var reply SubmitJobReply
+	args.FromPB(pb)
+	if err := s.n.SubmitJob(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) JobStatus(ctx context.Context, pb *gdfspb.JobArgs) (*gdfspb.JobStatusReply, error) {
+	var args JobArgs
+	var reply JobStatus
+	args.FromPB(pb)
+	if err := s.n.JobStatus(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) CancelJob(ctx context.Context, pb *gdfspb.JobArgs) (*gdfspb.JobStatusReply, error) {
+	var args JobArgs
+	var reply JobStatus
+	args.FromPB(pb)
+	if err := s.n.CancelJob(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) ClusterStatus(ctx context.Context, pb *gdfspb.AdminArgs) (*gdfspb.ClusterStatusReply, error) {
+	var args AdminArgs
+	var reply ClusterStatusReply
+	args.FromPB(pb)
+	if err := s.n.ClusterStatus(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) ReportNodes(ctx context.Context, pb *gdfspb.AdminArgs) (*gdfspb.ReportNodesReply, error) {
+	var args AdminArgs
+	var reply ReportNodesReply
+	args.FromPB(pb)
+	if err := s.n.ReportNodes(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) Notify(ctx context.Context, pb *gdfspb.NotifyArgs) (*gdfspb.NotifyReply, error) {
+	var args NotifyArgs
+	var reply NotifyReply
+	args.FromPB(pb)
+	if err := s.n.Notify(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) Handshake(ctx context.Context, pb *gdfspb.HandshakeArgs) (*gdfspb.HandshakeReply, error) {
+	var args HandshakeArgs
+	var reply HandshakeReply
+	args.FromPB(pb)
+	if err := s.n.Handshake(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) Register(ctx context.Context, pb *gdfspb.RegisterArgs) (*gdfspb.RegisterReply, error) {
+	var args RegisterArgs
+	var reply RegisterReply
+	args.FromPB(pb)
+	if err := s.n.Register(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) HeartBeat(ctx context.Context, pb *gdfspb.HeartBeatArgs) (*gdfspb.HeartBeatReply, error) {
+	var args HeartBeatArgs
+	var reply HeartBeatReply
+	args.FromPB(pb)
+	if err := s.n.HeartBeat(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) ReportBlock(ctx context.Context, pb *gdfspb.ReportBlockArgs) (*gdfspb.ReportBlockReply, error) {
+	var args ReportBlockArgs
+	var reply ReportBlockReply
+	args.FromPB(pb)
+	if err := s.n.ReportBlock(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
+
+func (s grpcServer) ReportCorruptBlock(ctx context.Context, pb *gdfspb.ReportCorruptBlockArgs) (*gdfspb.ReportCorruptBlockReply, error) {
+	var args ReportCorruptBlockArgs
+	var reply ReportCorruptBlockReply
+	args.FromPB(pb)
+	if err := s.n.ReportCorruptBlock(&args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.ToPB(), nil
+}
@@ -0,0 +1,295 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+// ErrNotFound is returned for a dfs path which doesn't exist. RPCs only
+// carry the message, so clients match it by ErrNotFound.Error().
+var ErrNotFound = errors.New("No such file or directory")
+
+// inode is a node of the namespace tree, either a directory
+// holding children or a file holding an ordered block list
+type inode struct {
+	Name        string
+	IsDir       bool
+	Children    map[string]*inode `json:",omitempty"`
+	BlkList     []string          `json:",omitempty"`
+	BlkSize     int64             `json:",omitempty"` // 0 means config.BlkSize
+	Replication int               `json:",omitempty"` // 0 means config.ReplicationFactor
+	ModTime     int64             // modification time in ms
+	AccessTime  int64             `json:",omitempty"` // access time of a file in ms
+	// quotas of a directory, 0 means none, see checkQuota
+	NsQuota    int64 `json:",omitempty"` // max number of files and directories
+	SpaceQuota int64 `json:",omitempty"` // max bytes counting every replica
+	// digest of a file written by a client, over the DigestType checksums
+	// of its blocks, see utils.FileDigest. Appending drops it.
+	Digest     string `json:",omitempty"`
+	DigestType string `json:",omitempty"`
+}
+
+// blkSize returns the block size of a file in byte
+func (node *inode) blkSize() int64 {
+	if node.BlkSize <= 0 {
+		return int64(config.BlkSize)
+	}
+	return node.BlkSize
+}
+
+// replication returns the target replication factor of a file
+func (node *inode) replication() int {
+	if node.Replication <= 0 {
+		return config.ReplicationFactor
+	}
+	return node.Replication
+}
+
+func newDir(name string, modTime int64) *inode {
+	return &inode{Name: name, IsDir: true, Children: make(map[string]*inode),
+		ModTime: modTime}
+}
+
+// splitPath turns a dfs path into its components, "/" gives none
+func splitPath(p string) []string {
+	p = cleanPath(p)
+	if p == "/" {
+		return []string{}
+	}
+	return strings.Split(p[1:], "/")
+}
+
+// cleanPath returns the canonical form of dfs path p. Paths from clients
+// went through checkPath first, so p never holds a ".." to resolve.
+func cleanPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// checkPath rejects dfs paths which are empty, contain a NUL byte or a
+// ".." component, rather than letting cleanPath clamp them at "/"
+func checkPath(p string) error {
+	if p == "" || strings.IndexByte(p, 0) >= 0 {
+		return errors.New("Invalid path")
+	}
+	for _, name := range strings.Split(p, "/") {
+		if name == ".." {
+			return errors.New("Invalid path: .. is not allowed")
+		}
+	}
+	return nil
+}
+
+// checkName rejects file names which are not a single path component,
+// the name ends up in block ids and so in datanode file names
+func checkName(name string) error {
+	if name == "" || name == "." || name == ".." ||
+		strings.ContainsAny(name, "/\x00") {
+		return errors.New("Invalid file name")
+	}
+	return nil
+}
+
+// lookup returns the inode at p, or nil if there is none
+func (root *inode) lookup(p string) *inode {
+	cur := root
+	for _, name := range splitPath(p) {
+		if !cur.IsDir {
+			return nil
+		}
+		cur = cur.Children[name]
+		if cur == nil {
+			return nil
+		}
+	}
+	return cur
+}
+
+// parentOf returns the parent directory of p and the last component
+func (root *inode) parentOf(p string) (*inode, string, error) {
+	names := splitPath(p)
+	if len(names) == 0 {
+		return nil, "", errors.New("Invalid path")
+	}
+	parent := root.lookup(strings.Join(names[:len(names)-1], "/"))
+	if parent == nil {
+		return nil, "", ErrNotFound
+	}
+	if !parent.IsDir {
+		return nil, "", errors.New("Not a directory")
+	}
+	return parent, names[len(names)-1], nil
+}
+
+// mkdir creates directory p, with parents its missing ancestors are created
+func (root *inode) mkdir(p string, parents bool, modTime int64) error {
+	if !parents {
+		parent, name, err := root.parentOf(p)
+		if err != nil {
+			return err
+		}
+		if parent.Children[name] != nil {
+			return errors.New("File exists")
+		}
+		parent.Children[name] = newDir(name, modTime)
+		parent.ModTime = modTime
+		return nil
+	}
+	cur := root
+	for _, name := range splitPath(p) {
+		child := cur.Children[name]
+		if child == nil {
+			child = newDir(name, modTime)
+			cur.Children[name] = child
+			cur.ModTime = modTime
+		}
+		if !child.IsDir {
+			return errors.New("Not a directory")
+		}
+		cur = child
+	}
+	return nil
+}
+
+// write creates or overwrites file p with the content of file
+func (root *inode) write(p string, file *inode, modTime int64) error {
+	parent, name, err := root.parentOf(p)
+	if err != nil {
+		return err
+	}
+	child := parent.Children[name]
+	if child != nil && child.IsDir {
+		return errors.New("Is a directory")
+	}
+	accessTime := modTime
+	if child == nil {
+		parent.ModTime = modTime
+	} else {
+		accessTime = child.AccessTime // writing is no read
+	}
+	parent.Children[name] = &inode{Name: name, BlkList: file.BlkList,
+		BlkSize: file.BlkSize, Replication: file.Replication, ModTime: modTime,
+		AccessTime: accessTime, Digest: file.Digest, DigestType: file.DigestType}
+	return nil
+}
+
+// setAccessTime records a read of file p at accessTime
+func (root *inode) setAccessTime(p string, accessTime int64) error {
+	node := root.lookup(p)
+	if node == nil {
+		return ErrNotFound
+	}
+	if node.IsDir {
+		return errors.New("Is a directory")
+	}
+	node.AccessTime = accessTime
+	return nil
+}
+
+// setReplication changes the replication factor of file p
+func (root *inode) setReplication(p string, rep int) error {
+	node := root.lookup(p)
+	if node == nil {
+		return ErrNotFound
+	}
+	if node.IsDir {
+		return errors.New("Is a directory")
+	}
+	node.Replication = rep
+	return nil
+}
+
+// setDigest records the digest of file p
+func (root *inode) setDigest(p, digest, typ string) error {
+	node := root.lookup(p)
+	if node == nil {
+		return ErrNotFound
+	}
+	if node.IsDir {
+		return errors.New("Is a directory")
+	}
+	node.Digest, node.DigestType = digest, typ
+	return nil
+}
+
+// setQuota sets the name quota, or the space quota if space is set, of
+// directory p
+func (root *inode) setQuota(p string, quota int64, space bool) error {
+	node := root.lookup(p)
+	if node == nil {
+		return ErrNotFound
+	}
+	if !node.IsDir {
+		return errors.New("Not a directory")
+	}
+	if space {
+		node.SpaceQuota = quota
+	} else {
+		node.NsQuota = quota
+	}
+	return nil
+}
+
+// delete removes p, directories are removed recursively
+func (root *inode) delete(p string, modTime int64) error {
+	parent, name, err := root.parentOf(p)
+	if err != nil {
+		return err
+	}
+	if parent.Children[name] == nil {
+		return ErrNotFound
+	}
+	delete(parent.Children, name)
+	parent.ModTime = modTime
+	return nil
+}
+
+// rename moves src to dst, dst should not exist
+func (root *inode) rename(src, dst string, modTime int64) error {
+	srcParent, srcName, err := root.parentOf(src)
+	if err != nil {
+		return err
+	}
+	node := srcParent.Children[srcName]
+	if node == nil {
+		return ErrNotFound
+	}
+	dstParent, dstName, err := root.parentOf(dst)
+	if err != nil {
+		return err
+	}
+	if dstParent.Children[dstName] != nil {
+		return errors.New("File exists")
+	}
+	delete(srcParent.Children, srcName)
+	node.Name = dstName
+	dstParent.Children[dstName] = node
+	srcParent.ModTime = modTime
+	dstParent.ModTime = modTime
+	return nil
+}
+
+// walk calls fn on every inode under root with its dfs path, in
+// depth-first order
+func (root *inode) walk(p string, fn func(p string, node *inode)) {
+	fn(p, root)
+	for name, child := range root.Children {
+		child.walk(path.Join(p, name), fn)
+	}
+}
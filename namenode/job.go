@@ -0,0 +1,277 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/mapreduce"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// runJob runs the mapreduce job args.Job over the file args.DPath
+func (n *NameNode) runJob(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runJob %v\n", args.Job)
+	run := n.newJob(args)
+	return run.finish(reply, n.execJob(run, args, reply))
+}
+
+// execJob runs the job of args, with reduce tasks on datanodes if
+// args.NumReduce is set
+func (n *NameNode) execJob(run *jobRun, args *CommandArgs, reply *CommandReply) error {
+	if args.NumReduce > 0 {
+		return n.runDistJob(run, args, reply)
+	}
+	res, err := n.mapReduce(run, args)
+	if err != nil {
+		return err
+	}
+	reply.JobResult = res
+	reply.Result = mapreduce.Format(args.Job, res)
+	return nil
+}
+
+// mapReduce runs the map task of job args.Job on a datanode holding each
+// block of args.DPath, then reduces the partial results in block order
+func (n *NameNode) mapReduce(run *jobRun, args *CommandArgs) (mapreduce.Result, error) {
+	job, err := mapreduce.Lookup(args.Job)
+	if err != nil {
+		return nil, err
+	}
+	file, err := n.getFile(args.DPath)
+	if err != nil {
+		return nil, err
+	}
+	replies := make([]*mapreduce.MapReply, len(file.BlkList))
+	err = n.mapBlks(run, args, file.BlkList, func(i int, blk, addr string, timeout time.Duration) error {
+		logger.Debugf("request %v map task for %v from %v\n", args.Job, blk, addr)
+		mapArgs := mapreduce.MapArgs{Job: args.Job, BlkID: blk, Token: config.AuthToken}
+		reply := mapreduce.MapReply{}
+		err := run.call(addr, timeout, func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+			m, err := dn.MapBlk(ctx, mapArgs.ToPB())
+			reply.FromPB(m)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		replies[i] = &reply
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapreduce.Reduce(job, replies)
+}
+
+// callDataNode calls f with the datanode at addr, giving up after timeout
+func (n *NameNode) callDataNode(addr string, timeout time.Duration,
+	f func(ctx context.Context, dn gdfspb.DataNodeClient) error) error {
+	return n.dataNodes.CallDataNode(addr, timeout, nil, f)
+}
+
+// runDistJob runs the mapreduce job args.Job over the file args.DPath with
+// args.NumReduce reduce tasks on datanodes, see mapreduce.Split. The output
+// of reduce task p is the file part-p of directory args.Output.
+func (n *NameNode) runDistJob(run *jobRun, args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runDistJob %v with %v reducers\n", args.Job, args.NumReduce)
+	job, err := mapreduce.Lookup(args.Job)
+	if err != nil {
+		return err
+	}
+	file, err := n.getFile(args.DPath)
+	if err != nil {
+		return err
+	}
+	if args.Output == "" {
+		return errors.New("No output directory")
+	}
+	n.nsMu.Lock()
+	exists := n.root.lookup(args.Output) != nil
+	n.nsMu.Unlock()
+	if exists {
+		return errors.New("Output exists")
+	}
+	timeout := args.Timeout
+	if timeout <= 0 {
+		timeout = utils.RPCTimeout()
+	}
+	deadline := time.Now().Add(timeout)
+	jobID := run.status.JobID
+	sources := make([]mapreduce.MapSource, len(file.BlkList))
+	edges := make([]*mapreduce.MapReply, len(file.BlkList))
+	defer n.endJob(jobID, sources)
+	err = n.mapBlks(run, args, file.BlkList, func(i int, blk, addr string, timeout time.Duration) error {
+		logger.Debugf("request %v map task for %v from %v\n", args.Job, blk, addr)
+		mapArgs := mapreduce.MapArgs{Job: args.Job, BlkID: blk, JobID: jobID,
+			NumReduce: args.NumReduce, Token: config.AuthToken}
+		mapReply := mapreduce.MapReply{}
+		err := run.call(addr, timeout, func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+			m, err := dn.MapBlk(ctx, mapArgs.ToPB())
+			mapReply.FromPB(m)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		sources[i] = mapreduce.MapSource{BlkID: blk, Addr: addr}
+		edges[i] = &mapReply
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// the records straddling block boundaries are mapped here, every
+	// reducer gets its partition of them
+	extra, err := mapreduce.MapEdges(job, edges)
+	if err != nil {
+		return err
+	}
+	err = n.runMkdirP(&CommandArgs{DPath: args.Output}, &CommandReply{})
+	if err != nil {
+		return err
+	}
+	reducers := n.liveAddrs()
+	if len(reducers) == 0 {
+		return errors.New("No live datanode to reduce")
+	}
+	reply.Files = make([]string, args.NumReduce)
+	run.mu.Lock()
+	run.status.ReduceTasks = make([]string, args.NumReduce)
+	for p := range run.status.ReduceTasks {
+		run.status.ReduceTasks[p] = TaskPending
+	}
+	run.mu.Unlock()
+	errs := make([]error, args.NumReduce)
+	var wg sync.WaitGroup
+	for p := 0; p < args.NumReduce; p++ {
+		reply.Files[p] = fmt.Sprintf("part-%05d", p)
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			rargs := mapreduce.ReduceArgs{JobID: jobID, Job: args.Job, Partition: p,
+				Sources: sources, Token: config.AuthToken}
+			for _, res := range extra {
+				rargs.Extra = append(rargs.Extra, mapreduce.Split(res, args.NumReduce)[p])
+			}
+			// reduce task p is given to the datanodes in turn from the p-th on
+			run.setReduceTask(p, TaskRunning)
+			for i := 0; i < len(reducers) && !run.canceled(); i++ {
+				addr := reducers[(p+i)%len(reducers)]
+				errs[p] = n.reduceOn(run, addr, &rargs, args, reply.Files[p], deadline)
+				if errs[p] == nil {
+					run.setReduceTask(p, TaskDone)
+					return
+				}
+				logger.Warnf("reduce task %v on %v failed: %v\n", p, addr, errs[p])
+			}
+			if errs[p] == nil {
+				errs[p] = ErrJobCanceled
+			}
+			run.setReduceTask(p, TaskFailed)
+		}(p)
+	}
+	wg.Wait()
+	for p, err := range errs {
+		if err != nil {
+			return fmt.Errorf("Reduce task %v failed: %v", p, err)
+		}
+	}
+	reply.Result = fmt.Sprintf("%v reduce outputs in %v\n", args.NumReduce, args.Output)
+	return nil
+}
+
+// reduceOn runs reduce task rargs on the datanode at addr, then creates
+// the output file name in args.Output and has the datanode fill it
+func (n *NameNode) reduceOn(run *jobRun, addr string, rargs *mapreduce.ReduceArgs, args *CommandArgs,
+	name string, deadline time.Time) error {
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return utils.ErrTimeout
+	}
+	rreply := mapreduce.ReduceReply{}
+	err := run.call(addr, timeout, func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+		m, err := dn.Reduce(ctx, rargs.ToPB())
+		rreply.FromPB(m)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	fargs := CommandArgs{DPath: args.Output, FileName: name, FileSize: rreply.Size,
+		Replication: args.Replication}
+	freply := CommandReply{}
+	if err := n.runCopyFromLocal(&fargs, &freply); err != nil {
+		return err
+	}
+	cargs := mapreduce.CommitArgs{JobID: rargs.JobID, Partition: rargs.Partition,
+		BlkList: freply.BlkList, BlkSize: freply.BlkSize,
+		BlkToDataNodes: freply.BlkToDataNodes, GenStamp: freply.GenStamp,
+		Token: config.AuthToken}
+	err = run.call(addr, time.Until(deadline), func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+		_, err := dn.CommitReduce(ctx, cargs.ToPB())
+		return err
+	})
+	if err != nil {
+		// the next reducer creates the file again
+		n.removeFile(path.Join(args.Output, name))
+	}
+	return err
+}
+
+// liveAddrs returns the addresses of the live datanodes, sorted
+func (n *NameNode) liveAddrs() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	addrs := make([]string, 0, len(n.Addr2SID))
+	for addr := range n.Addr2SID {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	return addrs
+}
+
+// endJob asks the datanodes of a job to drop its map and reduce outputs
+func (n *NameNode) endJob(jobID string, sources []mapreduce.MapSource) {
+	addrs := make(map[string]bool)
+	for _, src := range sources {
+		if src.Addr != "" {
+			addrs[src.Addr] = true
+		}
+	}
+	for _, addr := range n.liveAddrs() {
+		addrs[addr] = true
+	}
+	for addr := range addrs {
+		args := mapreduce.JobArgs{JobID: jobID, Token: config.AuthToken}
+		timeout := time.Duration(config.BlkReadTimeoutInSec) * time.Second
+		err := n.callDataNode(addr, timeout, func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+			_, err := dn.EndJob(ctx, args.ToPB())
+			return err
+		})
+		if err != nil {
+			logger.Infof("end job %v on %v: %v\n", jobID, addr, err)
+		}
+	}
+}
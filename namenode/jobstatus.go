@@ -0,0 +1,240 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/mapreduce"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// states of a job
+const (
+	JobRunning  = "running"
+	JobDone     = "done"
+	JobFailed   = "failed"
+	JobCanceled = "canceled"
+)
+
+// states of a map or reduce task
+const (
+	TaskPending = "pending"
+	TaskRunning = "running"
+	TaskDone    = "done"
+	TaskFailed  = "failed"
+)
+
+// ErrJobCanceled is returned by the commands of a canceled job
+var ErrJobCanceled = errors.New("Job canceled")
+
+// JobStatus is the progress of a mapreduce job
+type JobStatus struct {
+	JobID       string
+	Job         string            // name of the mapreduce job
+	Path        string            // dfs file the job runs over
+	State       string            // see JobRunning
+	Tasks       map[string]string // map task state by block, see TaskPending
+	ReduceTasks []string          // reduce task states of a distributed job
+	Start       int64             // in ms
+	End         int64             // in ms, 0 while running
+	Err         string            // why the job failed
+	// the reply of the command, once done
+	Result    string
+	JobResult mapreduce.Result
+	Files     []string
+}
+
+// JobArgs names a job
+type JobArgs struct {
+	JobID string
+	Token string // see config.AuthToken
+}
+
+// SubmitJobReply is the id of a job started by SubmitJob
+type SubmitJobReply struct {
+	JobID string
+}
+
+// jobRun tracks a running job. Canceling closes cancel, which stops the
+// dispatch of its tasks and abandons the RPCs in flight, see call.
+type jobRun struct {
+	mu     sync.Mutex
+	status JobStatus
+	cancel chan struct{}
+	once   sync.Once
+	// dataNodes is the pool of namenode, see call
+	dataNodes *utils.Pool
+}
+
+// newJob registers a job running args.Job over args.DPath, the oldest
+// finished jobs are forgotten beyond config.RetainedJobs
+func (n *NameNode) newJob(args *CommandArgs) *jobRun {
+	run := &jobRun{cancel: make(chan struct{}), dataNodes: n.dataNodes}
+	run.status = JobStatus{JobID: utils.NewUUID(), Job: args.Job, Path: args.DPath,
+		State: JobRunning, Tasks: make(map[string]string),
+		Start: utils.GetCurrentTimeInMs()}
+	n.jobMu.Lock()
+	defer n.jobMu.Unlock()
+	n.jobs[run.status.JobID] = run
+	for len(n.jobs) > config.RetainedJobs {
+		oldest := ""
+		var end int64
+		for id, r := range n.jobs {
+			r.mu.Lock()
+			if r.status.End > 0 && (oldest == "" || r.status.End < end) {
+				oldest, end = id, r.status.End
+			}
+			r.mu.Unlock()
+		}
+		if oldest == "" {
+			break // every job is running
+		}
+		delete(n.jobs, oldest)
+	}
+	logger.Infof("job %v: %v over %v\n", run.status.JobID, args.Job, args.DPath)
+	return run
+}
+
+// setTask sets the state of the map task of blk
+func (r *jobRun) setTask(blk, state string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status.Tasks[blk] = state
+}
+
+// setReduceTask sets the state of reduce task p
+func (r *jobRun) setReduceTask(p int, state string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status.ReduceTasks[p] = state
+}
+
+// canceled tells whether the job was canceled
+func (r *jobRun) canceled() bool {
+	select {
+	case <-r.cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+// finish records the outcome of the job, err is returned as is, or as
+// ErrJobCanceled if the job was canceled
+func (r *jobRun) finish(reply *CommandReply, err error) error {
+	if r.canceled() {
+		err = ErrJobCanceled
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status.End = utils.GetCurrentTimeInMs()
+	switch {
+	case err == ErrJobCanceled:
+		r.status.State = JobCanceled
+	case err != nil:
+		r.status.State = JobFailed
+		r.status.Err = err.Error()
+	default:
+		r.status.State = JobDone
+		r.status.Result = reply.Result
+		r.status.JobResult = reply.JobResult
+		r.status.Files = reply.Files
+	}
+	logger.Infof("job %v %v\n", r.status.JobID, r.status.State)
+	return err
+}
+
+// call calls f with the datanode at addr for the job, giving up after
+// timeout or once the job is canceled
+func (r *jobRun) call(addr string, timeout time.Duration,
+	f func(ctx context.Context, dn gdfspb.DataNodeClient) error) error {
+	return r.dataNodes.CallDataNode(addr, timeout, r.cancel, f)
+}
+
+// SubmitJob starts the mapreduce job args.Job over the file args.DPath in
+// the background, JobStatus then tells its progress and result. Like a
+// command, the job fails if it doesn't finish within args.Timeout.
+func (n *NameNode) SubmitJob(args *CommandArgs, reply *SubmitJobReply) error {
+	if err := CheckToken("SubmitJob", args.Token); err != nil {
+		return err
+	}
+	if err := checkArgs(args); err != nil {
+		return err
+	}
+	if _, err := mapreduce.Lookup(args.Job); err != nil {
+		return err
+	}
+	run := n.newJob(args)
+	go func() {
+		res := CommandReply{}
+		run.finish(&res, n.execJob(run, args, &res))
+	}()
+	reply.JobID = run.status.JobID
+	return nil
+}
+
+// JobStatus tells the progress of a job
+func (n *NameNode) JobStatus(args *JobArgs, reply *JobStatus) error {
+	if err := CheckToken("JobStatus", args.Token); err != nil {
+		return err
+	}
+	run, err := n.getJob(args.JobID)
+	if err != nil {
+		return err
+	}
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	*reply = run.status
+	// copy the maps, they keep changing once the lock is released
+	reply.Tasks = make(map[string]string, len(run.status.Tasks))
+	for blk, state := range run.status.Tasks {
+		reply.Tasks[blk] = state
+	}
+	reply.ReduceTasks = append([]string(nil), run.status.ReduceTasks...)
+	return nil
+}
+
+// CancelJob cancels a running job, no new task is dispatched and the
+// tasks in flight are abandoned. The job is reported canceled once its
+// command returns. Canceling a finished job does nothing.
+func (n *NameNode) CancelJob(args *JobArgs, reply *JobStatus) error {
+	if err := CheckToken("CancelJob", args.Token); err != nil {
+		return err
+	}
+	run, err := n.getJob(args.JobID)
+	if err != nil {
+		return err
+	}
+	logger.Infof("cancel job %v\n", args.JobID)
+	run.once.Do(func() { close(run.cancel) })
+	return n.JobStatus(args, reply)
+}
+
+func (n *NameNode) getJob(jobID string) (*jobRun, error) {
+	n.jobMu.Lock()
+	defer n.jobMu.Unlock()
+	run, ok := n.jobs[jobID]
+	if !ok {
+		return nil, errors.New("No such job")
+	}
+	return run, nil
+}
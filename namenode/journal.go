@@ -0,0 +1,254 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// namespace mutations recorded in the edit log
+const (
+	opMkdir         = iota // create a directory, its parent should exist
+	opMkdirP               // create a directory with its parents
+	opWrite                // create or overwrite a file with BlkList
+	opDelete               // delete a file
+	opDeleteDir            // delete a directory recursively
+	opRename               // rename Path to Dest
+	opSetRep               // set replication factor of a file
+	opSetNsQuota           // set name quota of a directory
+	opSetSpaceQuota        // set space quota of a directory
+	opSetAccessTime        // record a read of a file at Timestamp
+	opSetDigest            // record the digest of a file
+)
+
+// journalEntry is one mutation of the namespace, paths are dfs paths
+type journalEntry struct {
+	TxID        int64
+	Op          int
+	Path        string
+	Dest        string   // destination of rename
+	BlkList     []string // full block list of the file for write
+	BlkSize     int64    // block size of the file for write
+	Replication int      // replication factor of the file for write and setrep
+	Quota       int64    // quota of the directory for the setquota ops
+	Timestamp   int64    // in ms
+	Digest      string   // digest of the file for write and setdigest
+	DigestType  string   // checksum type of Digest
+}
+
+/** journal is a write-ahead edit log of namespace mutations.
+ * Each mutation is appended (and synced) to the log file as a json line
+ * before being applied to the in memory inode tree. On startup, the fsimage
+ * is loaded and entries after its TxID are replayed, so every mutation
+ * acknowledged to a client survives a crash.
+ * */
+type journal struct {
+	path     string // edit log file
+	lastTxID int64
+	mu       sync.Mutex
+}
+
+func newJournal(path string) *journal {
+	j := &journal{path: path}
+	j.dropPartial()
+	j.Replay(0, func(e *journalEntry) error {
+		j.lastTxID = e.TxID
+		return nil
+	})
+	return j
+}
+
+// dropPartial cuts a truncated last line left by a crash during Append off
+// the edit log, the next entry would be appended to it otherwise
+func (j *journal) dropPartial() {
+	bytes, err := ioutil.ReadFile(j.path)
+	if err != nil || len(bytes) == 0 || bytes[len(bytes)-1] == '\n' {
+		return
+	}
+	size := int64(strings.LastIndexByte(string(bytes), '\n') + 1)
+	logger.Warnf("drop %v bytes of a partial entry from the edit log\n",
+		int64(len(bytes))-size)
+	if err := os.Truncate(j.path, size); err != nil {
+		logger.Errorf("error when truncating edit log: %v\n", err)
+	}
+}
+
+// Append writes an entry to the edit log and assigns its TxID
+func (j *journal) Append(e *journalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	e.TxID = j.lastTxID + 1
+	e.Timestamp = utils.GetCurrentTimeInMs()
+	bytes, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		logger.Errorf("error when opening edit log: %v\n", err)
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(bytes, '\n'))
+	if err != nil {
+		logger.Errorf("error when writing edit log: %v\n", err)
+		return err
+	}
+	err = file.Sync()
+	if err != nil {
+		return err
+	}
+	j.lastTxID = e.TxID
+	return nil
+}
+
+// Replay calls apply on every entry with TxID larger than after in order.
+// A truncated last line (crash during Append) is ignored.
+func (j *journal) Replay(after int64, apply func(e *journalEntry) error) error {
+	file, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	s := bufio.NewScanner(file)
+	s.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for s.Scan() {
+		e := journalEntry{}
+		err := json.Unmarshal(s.Bytes(), &e)
+		if err != nil {
+			logger.Warnf("skip malformed edit log entry: %v\n", err)
+			continue
+		}
+		if e.TxID <= after {
+			continue
+		}
+		err = apply(&e)
+		if err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// LastTxID returns TxID of the last appended entry
+func (j *journal) LastTxID() int64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.lastTxID
+}
+
+// advance makes sure new entries get TxID larger than txID, since
+// the edit log may have been truncated after a checkpoint
+func (j *journal) advance(txID int64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.lastTxID < txID {
+		j.lastTxID = txID
+	}
+}
+
+// Truncate drops every entry from the edit log, it is called after they
+// are included in a checkpoint. TxID keeps increasing.
+func (j *journal) Truncate() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	err := os.Remove(j.path)
+	if err != nil && !os.IsNotExist(err) {
+		logger.Errorf("error when truncating edit log: %v\n", err)
+	}
+}
+
+// Reset empties the edit log, e.g. after format
+func (j *journal) Reset() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	os.Remove(j.path)
+	j.lastTxID = 0
+}
+
+// logAndApply journals a mutation and applies it to the inode tree,
+// the caller should hold n.nsMu
+func (n *NameNode) logAndApply(e *journalEntry) error {
+	err := n.journal.Append(e)
+	if err != nil {
+		return err
+	}
+	return n.applyTree(e)
+}
+
+// replayJournal loads the fsimage and replays the edit log on top of it
+func (n *NameNode) replayJournal() {
+	imageTxID := n.loadImage()
+	n.journal.advance(imageTxID)
+	err := n.journal.Replay(imageTxID, func(e *journalEntry) error {
+		logger.Debugf("replay edit log entry %v: op %v on %v\n", e.TxID, e.Op, e.Path)
+		err := n.applyTree(e)
+		if err != nil {
+			logger.Errorf("error when replaying entry %v: %v\n", e.TxID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("error when replaying edit log: %v\n", err)
+	}
+}
+
+// applyTree performs a journaled mutation on the in memory inode tree
+func (n *NameNode) applyTree(e *journalEntry) error {
+	err := n.applyToTree(e)
+	if err == nil && (e.Op == opWrite || e.Op == opSetRep) {
+		n.setBlkRep(e.Path)
+	}
+	return err
+}
+
+func (n *NameNode) applyToTree(e *journalEntry) error {
+	switch e.Op {
+	case opMkdir:
+		return n.root.mkdir(e.Path, false, e.Timestamp)
+	case opMkdirP:
+		return n.root.mkdir(e.Path, true, e.Timestamp)
+	case opWrite:
+		file := &inode{BlkList: e.BlkList, BlkSize: e.BlkSize,
+			Replication: e.Replication, Digest: e.Digest, DigestType: e.DigestType}
+		return n.root.write(e.Path, file, e.Timestamp)
+	case opDelete, opDeleteDir:
+		return n.root.delete(e.Path, e.Timestamp)
+	case opRename:
+		return n.root.rename(e.Path, e.Dest, e.Timestamp)
+	case opSetRep:
+		return n.root.setReplication(e.Path, e.Replication)
+	case opSetAccessTime:
+		return n.root.setAccessTime(e.Path, e.Timestamp)
+	case opSetDigest:
+		return n.root.setDigest(e.Path, e.Digest, e.DigestType)
+	case opSetNsQuota, opSetSpaceQuota:
+		return n.root.setQuota(e.Path, e.Quota, e.Op == opSetSpaceQuota)
+	default:
+		return errors.New("Unknown edit log op")
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"os"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+func TestJournalReplay(t *testing.T) {
+	n := newTestNameNode(t)
+	runCommand(t, n, &CommandArgs{CommandType: config.Mkdir, DPath: "/a"})
+	// a crash right after journaling a mutation, before applying it
+	err := n.journal.Append(&journalEntry{Op: opMkdir, Path: "/a/b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.root.lookup("/a/b") != nil {
+		t.Fatal("journaled entry applied")
+	}
+	// and another one in the middle of writing an entry
+	file, err := os.OpenFile(config.NEditLogPath, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"TxID":3,"Op":0,"Pa`)
+	file.Close()
+	restarted := NewNameNode()
+	for _, p := range []string{"/a", "/a/b"} {
+		if node := restarted.root.lookup(p); node == nil || !node.IsDir {
+			t.Errorf("directory %v is missing after replay", p)
+		}
+	}
+	if got := restarted.journal.LastTxID(); got != 2 {
+		t.Errorf("last TxID is %v after replay, want 2", got)
+	}
+	// new entries are not glued to the partial one
+	runCommand(t, restarted, &CommandArgs{CommandType: config.Mkdir, DPath: "/c"})
+	if NewNameNode().root.lookup("/c") == nil {
+		t.Errorf("directory made after replay is missing after another restart")
+	}
+}
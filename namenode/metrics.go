@@ -0,0 +1,64 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"github.com/WineChord/gdfs/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// registerMetrics creates the registry of the metrics served at
+// config.NameNodeMetricsPort. The gauges are read off the block and
+// datanode maps on each scrape.
+func (n *NameNode) registerMetrics() {
+	n.registry = prometheus.NewRegistry()
+	n.rpcLatency = metrics.NewRPCLatency("namenode")
+	gauge := func(name, help string, value func() int) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "namenode",
+			Name:      name,
+			Help:      help,
+		}, func() float64 {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			return float64(value())
+		})
+	}
+	n.registry.MustRegister(n.rpcLatency,
+		gauge("live_datanodes", "Datanodes sending heartbeats.",
+			func() int { return len(n.Addr2SID) }),
+		gauge("dead_datanodes", "Datanodes declared dead.",
+			func() int { return len(n.DeadNodes) }),
+		gauge("blocks", "Blocks with a replica on a live datanode.",
+			func() int { return len(n.BlkToDatanodes) }),
+		gauge("under_replicated_blocks",
+			"Blocks with fewer replicas in service than their replication factor.",
+			n.numUnderReplicated),
+		gauge("pending_replications", "Replications scheduled but not reported yet.",
+			func() int { return len(n.PendingRep) }))
+}
+
+// numUnderReplicated counts the blocks scheduleReplication would copy,
+// missing ones aside. The caller should hold n.mu.
+func (n *NameNode) numUnderReplicated() int {
+	res := 0
+	for blk, nodes := range n.BlkToDatanodes {
+		if n.inServiceReplicas(nodes) < n.replicationOf(blk) {
+			res++
+		}
+	}
+	return res
+}
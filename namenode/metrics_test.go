@@ -0,0 +1,62 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/WineChord/gdfs/metrics"
+	"github.com/WineChord/gdfs/utils"
+)
+
+func TestMetrics(t *testing.T) {
+	c := newFakeCluster(t, 3)
+	c.write("/", "a.bin", 30, 10, 2)
+	under := c.write("/", "b.bin", 10, 0, 2).BlkList[0]
+	c.drop(c.holders(under)[0], under)
+	l, err := metrics.Serve("127.0.0.1:0", c.n.registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	check := func(want ...string) {
+		t.Helper()
+		resp, err := http.Get("http://" + l.Addr().String() + "/metrics")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !strings.Contains(string(body), w+"\n") {
+				t.Errorf("metrics miss %q:\n%s", w, body)
+			}
+		}
+	}
+	check("gdfs_namenode_live_datanodes 3", "gdfs_namenode_dead_datanodes 0",
+		"gdfs_namenode_blocks 4", "gdfs_namenode_under_replicated_blocks 1")
+
+	c.n.mu.Lock()
+	c.n.LastHeartBeat[c.addrs[0]] = 0
+	c.n.mu.Unlock()
+	c.n.checkDeadNodes(utils.GetCurrentTimeInMs())
+	check("gdfs_namenode_live_datanodes 2", "gdfs_namenode_dead_datanodes 1")
+}
@@ -16,17 +16,22 @@ package namenode
 
 import (
 	"bufio"
+	"encoding/json"
+	"io/ioutil"
 	"log"
 	"net"
 	"net/http"
-	"net/rpc"
 	"os"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/metrics"
 	"github.com/WineChord/gdfs/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
 )
 
 // NameNode stores namespace tree and block to datanodes map
@@ -38,53 +43,224 @@ type NameNode struct {
 	DFSRootPath string
 	// maps to storage id rather that address
 	BlkToDatanodes map[string][]string
+	// block metadata (length, checksum), learned from block reports
+	BlkMeta map[string]utils.MetaData
+	// block id to target replication factor of the file owning it
+	BlkRep         map[string]int
 	diskSpaceQuote float32
 	NamespaceID    int
 	// map storage id to address(ip:port)
 	SID2Addr map[string]string
-	// map address to storage id
-	Addr2SID   map[string]string
+	// map address to storage id, only live datanodes are kept
+	Addr2SID map[string]string
+	// map address to time of last heartbeat in ms
+	LastHeartBeat map[string]int64
+	// map address to capacity stats in the last heartbeat
+	NodeStats map[string]HeartBeatArgs
+	// addresses of datanodes considered dead, mapped to time of death in ms
+	DeadNodes map[string]int64
+	// block id to storage ids of the replicas block scanners reported
+	// corrupt, until the block has its replicas back
+	CorruptBlks map[string][]string
+	// block id to time in ms when its re-replication was scheduled
+	PendingRep map[string]int64
+	// block id to balancer move in progress
+	Moves map[string]*blkMove
+	// address to decommission state of datanodes taken out of service,
+	// see runDecommission
+	Decommission map[string]string
+	// address to blocks whose replica on the datanode has an older
+	// generation stamp than the block, removed on next heartbeat
+	StaleReplicas map[string][]string
+	// address to blocks no file references, removed on next heartbeat,
+	// see collectOrphans
+	Orphans map[string][]string
+	// addresses of datanodes to request a block report from on next heartbeat
+	ReqReport  map[string]bool
 	RequestBlk bool
 	Format     bool
-	mu         sync.Mutex
+	// write-ahead edit log of namespace mutations
+	journal *journal
+	// root of the namespace tree, kept in sync with the edit log
+	root *inode
+	// last generation stamp handed out, see nextGenStamp
+	genStamp int64
+	// the cluster state changed since it was last dumped, see saveState
+	stateDirty bool
+	// stateMu serializes dumps of the cluster state, it is taken before mu
+	stateMu sync.Mutex
+	// nsMu serializes namespace mutations and checkpoints
+	nsMu sync.Mutex
+	// mu guards the block and datanode maps above, NamespaceID, RequestBlk
+	// and Format, RPCs are served concurrently
+	mu sync.Mutex
+	// mapreduce jobs by id, running or among the last finished ones,
+	// guarded by jobMu
+	jobs  map[string]*jobRun
+	jobMu sync.Mutex
+	// dataNodes keeps the connections to datanodes of map tasks and
+	// block deletions
+	dataNodes *utils.Pool
+	// placement chooses the datanodes of new replicas, see
+	// config.BlockPlacement
+	placement PlacementPolicy
+	// metrics served at config.NameNodeMetricsPort, see registerMetrics
+	registry   *prometheus.Registry
+	rpcLatency *prometheus.HistogramVec
+	// listeners of Start, the one of RPCs first
+	listeners []net.Listener
+	server    *grpc.Server // serving RPCs, see Start
+	// done is closed by Stop, ending the periodic tasks
+	done  chan struct{}
+	tasks sync.WaitGroup
 }
 
 // NewNameNode initializes a namenode
 func NewNameNode() *NameNode {
-	n := &NameNode{}
+	n := &NameNode{done: make(chan struct{}), dataNodes: utils.NewPool(),
+		placement: placementPolicy(config.BlockPlacement)}
 	n.BlkToDatanodes = make(map[string][]string)
+	n.BlkMeta = make(map[string]utils.MetaData)
+	n.BlkRep = make(map[string]int)
 	n.SID2Addr = make(map[string]string)
 	n.Addr2SID = make(map[string]string)
+	n.LastHeartBeat = make(map[string]int64)
+	n.NodeStats = make(map[string]HeartBeatArgs)
+	n.DeadNodes = make(map[string]int64)
+	n.CorruptBlks = make(map[string][]string)
+	n.PendingRep = make(map[string]int64)
+	n.Moves = make(map[string]*blkMove)
+	n.StaleReplicas = make(map[string][]string)
+	n.Orphans = make(map[string][]string)
+	n.ReqReport = make(map[string]bool)
+	n.Decommission = make(map[string]string)
+	n.jobs = make(map[string]*jobRun)
+	n.registerMetrics()
 	n.init()
 	return n
 }
 
 func (n *NameNode) init() {
-	log.Printf("namenode starts to initialize\n")
+	logger.Infof("namenode starts to initialize\n")
 	n.DFSRootPath = config.DFSRootPath
 	n.RequestBlk = false
 	ex, err := utils.Exists(n.DFSRootPath)
 	if err != nil {
-		log.Printf("error with dfs root path: %v\n", err)
+		logger.Errorf("error with dfs root path: %v\n", err)
 	}
-	log.Printf("set dfs root path as %v\n", n.DFSRootPath)
+	logger.Infof("set dfs root path as %v\n", n.DFSRootPath)
 	if !ex {
-		log.Printf("auto format dfs on start\n")
+		logger.Infof("auto format dfs on start\n")
 		os.MkdirAll(n.DFSRootPath, 0700)
 	}
 	ex, err = utils.Exists(config.NNamespaceIDPath)
 	if err != nil {
-		log.Printf("error with namenode nid file: %v\n", err)
+		logger.Errorf("error with namenode nid file: %v\n", err)
 	}
 	if ex {
-		log.Printf("namenode NamespaceID file %v exists, starts reading\n",
+		logger.Infof("namenode NamespaceID file %v exists, starts reading\n",
 			config.NNamespaceIDPath)
 		n.readNID()
 	} else {
-		log.Printf("namenode NamespaceID file %v doesn't exist, starts creating\n",
+		logger.Infof("namenode NamespaceID file %v doesn't exist, starts creating\n",
 			config.NNamespaceIDPath)
 		n.initNID()
 	}
+	n.journal = newJournal(config.NEditLogPath)
+	n.replayJournal()
+	n.indexBlkRep()
+	n.loadState()
+}
+
+// clusterState is the part of namenode's in memory state persisted to disk,
+// so that a restarted namenode can serve reads before datanodes re-report
+type clusterState struct {
+	SID2Addr       map[string]string
+	BlkToDatanodes map[string][]string
+	BlkMeta        map[string]utils.MetaData
+	Decommission   map[string]string
+}
+
+// saveState marks storage id map and last-known replicas to be dumped to
+// disk by flushState. Block reports and heartbeats change them all the
+// time, so they are dumped at most every StateFlushInSec instead of on
+// every change. The caller should hold n.mu.
+func (n *NameNode) saveState() {
+	n.stateDirty = true
+}
+
+// flushState dumps the state marked by saveState, if any. It is encoded
+// under n.mu and written to disk without it.
+func (n *NameNode) flushState() {
+	n.stateMu.Lock()
+	defer n.stateMu.Unlock()
+	n.mu.Lock()
+	if !n.stateDirty {
+		n.mu.Unlock()
+		return
+	}
+	n.stateDirty = false
+	state := clusterState{n.SID2Addr, n.BlkToDatanodes, n.BlkMeta, n.Decommission}
+	bytes, err := json.Marshal(state)
+	n.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error when marshaling namenode state: %v\n", err)
+		return
+	}
+	err = utils.WriteFileAtomic(config.NStatePath, bytes, 0600)
+	if err != nil {
+		logger.Errorf("error when writing namenode state: %v\n", err)
+		n.mu.Lock()
+		n.stateDirty = true // try again next time
+		n.mu.Unlock()
+	}
+}
+
+// flushStatePeriodically dumps the cluster state every StateFlushInSec
+// if it changed
+func (n *NameNode) flushStatePeriodically() {
+	for n.sleep(config.StateFlushInSec) {
+		n.flushState()
+	}
+}
+
+// loadState restores the state dumped by saveState if there is one.
+// Restored datanodes are treated as if they had just sent a heartbeat,
+// so they will be evicted if they don't show up again.
+func (n *NameNode) loadState() {
+	bytes, err := ioutil.ReadFile(config.NStatePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Errorf("error when reading namenode state: %v\n", err)
+		}
+		return
+	}
+	state := clusterState{}
+	err = json.Unmarshal(bytes, &state)
+	if err != nil {
+		logger.Errorf("error when unmarshaling namenode state: %v\n", err)
+		return
+	}
+	now := utils.GetCurrentTimeInMs()
+	for sid, addr := range state.SID2Addr {
+		n.SID2Addr[sid] = addr
+		n.Addr2SID[addr] = sid
+		n.LastHeartBeat[addr] = now
+	}
+	for blk, nodes := range state.BlkToDatanodes {
+		n.BlkToDatanodes[blk] = nodes
+	}
+	for blk, meta := range state.BlkMeta {
+		n.BlkMeta[blk] = meta
+		if meta.GenStamp > n.genStamp {
+			n.genStamp = meta.GenStamp
+		}
+	}
+	for addr, st := range state.Decommission {
+		n.Decommission[addr] = st
+	}
+	logger.Infof("loaded state of %v datanodes and %v blocks\n",
+		len(n.SID2Addr), len(n.BlkToDatanodes))
 }
 
 func (n *NameNode) readNID() {
@@ -97,16 +273,16 @@ func (n *NameNode) readNID() {
 	if s.Scan() { // file not empty, read nid directly
 		n.NamespaceID, err = strconv.Atoi(s.Text())
 		if err != nil { // file content not int
-			log.Printf("error when reading from nid file, dump new nid\n")
+			logger.Warnf("error when reading from nid file, dump new nid\n")
 			n.NamespaceID = 1
 			n.dumpNID()
 		}
 	} else { // file empty, dump directly
-		log.Printf("nid file is empty, dump new nid\n")
+		logger.Infof("nid file is empty, dump new nid\n")
 		n.NamespaceID = 1
 		n.dumpNID()
 	}
-	log.Printf("readNID reads %v\n", n.NamespaceID)
+	logger.Debugf("readNID reads %v\n", n.NamespaceID)
 }
 
 func (n *NameNode) initNID() {
@@ -119,19 +295,19 @@ func (n *NameNode) initNID() {
 	n.NamespaceID = 1 // initialize namespace id to 1
 	var cnt int
 	cnt, err = w.WriteString(strconv.Itoa(n.NamespaceID))
-	log.Printf("%v bytes written to nid file\n", cnt)
+	logger.Debugf("%v bytes written to nid file\n", cnt)
 	if err != nil {
-		log.Printf("error when writing nid to file: %v\n", err)
+		logger.Errorf("error when writing nid to file: %v\n", err)
 	}
 	err = w.Flush()
 	if err != nil {
-		log.Printf("error when flush nid to disk: %v\n", err)
+		logger.Errorf("error when flush nid to disk: %v\n", err)
 	}
-	log.Printf("initNID init nid %v\n", n.NamespaceID)
+	logger.Debugf("initNID init nid %v\n", n.NamespaceID)
 }
 
 func (n *NameNode) dumpNID() {
-	log.Printf("insed dumpNID: dump nid %v to %v\n", n.NamespaceID, config.NNamespaceIDPath)
+	logger.Debugf("insed dumpNID: dump nid %v to %v\n", n.NamespaceID, config.NNamespaceIDPath)
 	f, err := os.OpenFile(config.NNamespaceIDPath, os.O_RDWR, 0700)
 	defer f.Close()
 	if err != nil {
@@ -140,32 +316,45 @@ func (n *NameNode) dumpNID() {
 	w := bufio.NewWriter(f)
 	var cnt int
 	cnt, err = w.WriteString(strconv.Itoa(n.NamespaceID))
-	log.Printf("%v bytes dump to nid file\n", cnt)
+	logger.Debugf("%v bytes dump to nid file\n", cnt)
 	if err != nil {
-		log.Printf("error when writing nid file: %v\n", err)
+		logger.Errorf("error when writing nid file: %v\n", err)
 	}
 	err = w.Flush()
 	if err != nil {
-		log.Printf("error when flushing nid to disk: %v\n", err)
+		logger.Errorf("error when flushing nid to disk: %v\n", err)
 	}
 }
 
 func (n *NameNode) format() {
-	log.Printf("start formatting\n")
+	logger.Infof("start formatting\n")
 	os.RemoveAll(n.DFSRootPath) // meta/gdfs
 	os.MkdirAll(n.DFSRootPath, 0700)
+	// edits before format are meaningless now
+	n.nsMu.Lock()
+	n.journal.Reset()
+	n.root = newDir("", utils.GetCurrentTimeInMs())
+	n.nsMu.Unlock()
 	// erase in memory blk -> datanodes map
+	n.mu.Lock()
 	n.BlkToDatanodes = make(map[string][]string)
+	n.BlkMeta = make(map[string]utils.MetaData)
+	n.BlkRep = make(map[string]int)
+	n.Moves = make(map[string]*blkMove)
+	n.saveState()
 	// namespace id should change when formatted
 	// and it should be persistent to disk
 	n.NamespaceID++
+	n.mu.Unlock()
+	n.flushState()
 	n.dumpNID()
-	log.Printf("NamespaceID changes to %v after formatting\n", n.NamespaceID)
+	n.checkpoint()
+	logger.Infof("NamespaceID changes to %v after formatting\n", n.NamespaceID)
 	n.setFormat()
 }
 
 func (n *NameNode) setFormat() {
-	log.Printf("set format\n")
+	logger.Debugf("set format\n")
 	n.mu.Lock()
 	n.Format = true
 	n.mu.Unlock()
@@ -177,25 +366,147 @@ func (n *NameNode) setFormat() {
 	n.mu.Lock()
 	n.Format = false
 	n.mu.Unlock()
-	log.Printf("unset format\n")
+	logger.Debugf("unset format\n")
 }
 
-// Run starts a RPC server
+// Run serves namenode until the process exits, see Start
 func (n *NameNode) Run() {
-	serv := rpc.NewServer()
-	serv.Register(n)
-	oldMux := http.DefaultServeMux
-	mux := http.NewServeMux()
-	http.DefaultServeMux = mux
-	serv.HandleHTTP(rpc.DefaultRPCPath, rpc.DefaultDebugPath)
-	http.DefaultServeMux = oldMux
-	l, e := net.Listen("tcp", config.NameNodeAddress)
-	log.Printf("NameNode listening to %v\n", config.NameNodeAddress)
-	if e != nil {
-		log.Fatal("listen err: ", e)
-	}
-	go http.Serve(l, mux)
-	for {
-		// wait
+	if err := n.Start(); err != nil {
+		log.Fatal("listen err: ", err)
+	}
+	select {}
+}
+
+// Start serves the RPCs of namenode at config.NameNodeAddress along with
+// its metrics and web UI, and starts its periodic tasks. A port 0 gets a
+// free port, see Addr. Stop undoes it.
+func (n *NameNode) Start() error {
+	l, err := utils.Listen(config.NameNodeAddress)
+	if err != nil {
+		return err
+	}
+	n.listeners = append(n.listeners, l)
+	n.server = utils.NewServer(metrics.ServerOptions(n.rpcLatency)...)
+	RegisterServer(n.server, n)
+	logger.Infof("NameNode listening to %v\n", l.Addr())
+	go n.server.Serve(l)
+	if config.NameNodeMetricsPort != "" {
+		addr := utils.JoinHostPort("", config.NameNodeMetricsPort)
+		if ml, err := metrics.Serve(addr, n.registry); err != nil {
+			logger.Errorf("error when serving metrics at %v: %v\n", addr, err)
+		} else {
+			n.listeners = append(n.listeners, ml)
+			logger.Infof("NameNode serving metrics at %v/metrics\n", ml.Addr())
+		}
+	}
+	if config.NameNodeWebPort != "" {
+		addr := utils.JoinHostPort("", config.NameNodeWebPort)
+		if wl, err := net.Listen("tcp", addr); err != nil {
+			logger.Errorf("error when serving web UI at %v: %v\n", addr, err)
+		} else {
+			n.listeners = append(n.listeners, wl)
+			logger.Infof("NameNode serving web UI at %v\n", wl.Addr())
+			go http.Serve(wl, n.webUI())
+		}
+	}
+	for _, task := range []func(){n.sweepDeadNodes, n.flushStatePeriodically,
+		n.checkpointPeriodically, n.purgeTrashPeriodically, n.collectOrphansPeriodically} {
+		n.tasks.Add(1)
+		go func(task func()) {
+			defer n.tasks.Done()
+			task()
+		}(task)
+	}
+	return nil
+}
+
+// Addr returns the address namenode serves RPCs at, once started
+func (n *NameNode) Addr() string {
+	return n.listeners[0].Addr().String()
+}
+
+// Stop closes the listeners and connections of Start and waits for the
+// periodic tasks to end
+func (n *NameNode) Stop() {
+	close(n.done)
+	if n.server != nil {
+		n.server.Stop()
+	}
+	for _, l := range n.listeners {
+		l.Close()
+	}
+	n.tasks.Wait()
+	n.dataNodes.Close()
+}
+
+// sleep waits for sec seconds, it returns false if namenode is stopped
+// meanwhile
+func (n *NameNode) sleep(sec int) bool {
+	select {
+	case <-n.done:
+		return false
+	case <-time.After(time.Second * time.Duration(sec)):
+		return true
+	}
+}
+
+// sweepDeadNodes periodically evicts datanodes which haven't sent
+// heartbeat for DeadNodeInSec
+func (n *NameNode) sweepDeadNodes() {
+	for n.sleep(config.HeartBeatInSec) {
+		n.checkDeadNodes(utils.GetCurrentTimeInMs())
+	}
+}
+
+// checkDeadNodes marks datanodes silent for too long as dead. A dead node
+// is removed from SID2Addr/Addr2SID and its replicas are purged from
+// BlkToDatanodes. If it comes back, it has to register again.
+func (n *NameNode) checkDeadNodes(now int64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	timeout := int64(config.DeadNodeInSec) * 1000
+	for addr, last := range n.LastHeartBeat {
+		if now-last <= timeout {
+			continue
+		}
+		logger.Warnf("datanode %v has been silent for %v ms, mark it dead\n",
+			addr, now-last)
+		sid := n.Addr2SID[addr]
+		delete(n.LastHeartBeat, addr)
+		delete(n.NodeStats, addr)
+		delete(n.Addr2SID, addr)
+		delete(n.SID2Addr, sid)
+		n.DeadNodes[addr] = now
+		for blk, nodes := range n.BlkToDatanodes {
+			nodes = remove(nodes, sid)
+			if len(nodes) == 0 {
+				delete(n.BlkToDatanodes, blk)
+			} else {
+				n.BlkToDatanodes[blk] = nodes
+			}
+		}
+		n.saveState()
+	}
+}
+
+// LiveNodes returns addresses of live datanodes
+func (n *NameNode) LiveNodes() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	nodes := make([]string, 0, len(n.Addr2SID))
+	for addr := range n.Addr2SID {
+		nodes = append(nodes, addr)
+	}
+	return nodes
+}
+
+// Dead returns addresses of datanodes considered dead
+func (n *NameNode) Dead() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	nodes := make([]string, 0, len(n.DeadNodes))
+	for addr := range n.DeadNodes {
+		nodes = append(nodes, addr)
 	}
+	return nodes
 }
@@ -0,0 +1,276 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/utils"
+)
+
+func TestMain(m *testing.M) {
+	// namenode logs every call it serves
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+// chdir changes the working directory to dir until the test ends
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// newTestNameNode starts a namenode without any datanode, keeping its
+// metadata in a temporary directory
+func newTestNameNode(t *testing.T) *NameNode {
+	t.Helper()
+	chdir(t, t.TempDir())
+	return NewNameNode()
+}
+
+// addDataNode registers a datanode at addr as if it had just started and
+// sent a heartbeat with capacity bytes of which frac are in use, it
+// returns the storage id of the datanode
+func addDataNode(t *testing.T, n *NameNode, addr string, capacity uint64, frac float64) string {
+	t.Helper()
+	reg := RegisterReply{}
+	err := n.Register(&RegisterArgs{HostName: addr, Addr: addr}, &reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hb := HeartBeatArgs{HostName: addr, Addr: addr, TotalCapacity: capacity,
+		FracInUse: frac}
+	if err := n.HeartBeat(&hb, &HeartBeatReply{}); err != nil {
+		t.Fatal(err)
+	}
+	return reg.StorageID
+}
+
+// runCommand runs a client command on n and fails the test on error
+func runCommand(t *testing.T, n *NameNode, args *CommandArgs) *CommandReply {
+	t.Helper()
+	reply := &CommandReply{}
+	if err := n.RunCommand(args, reply); err != nil {
+		t.Fatalf("command %v on %v: %v", args.CommandType, args.DPaths, err)
+	}
+	return reply
+}
+
+func TestCheckDeadNodes(t *testing.T) {
+	n := newTestNameNode(t)
+	quiet, alive := "127.0.0.1:11170", "127.0.0.1:11171"
+	addDataNode(t, n, quiet, 1<<30, 0)
+	sid := addDataNode(t, n, alive, 1<<30, 0)
+	blk := "a.txt-00000000-1-1"
+	reportBlocks(t, n, quiet, blk)
+	reportBlocks(t, n, alive, blk)
+	timeout := int64(config.DeadNodeInSec) * 1000
+	last := n.LastHeartBeat[quiet]
+	// just within the timeout nothing happens
+	n.LastHeartBeat[alive] = last + timeout
+	n.checkDeadNodes(last + timeout)
+	if len(n.Dead()) != 0 {
+		t.Fatalf("dead nodes %v within the timeout", n.Dead())
+	}
+	n.checkDeadNodes(last + timeout + 1)
+	if dead := n.Dead(); len(dead) != 1 || dead[0] != quiet {
+		t.Fatalf("dead nodes are %v, want [%v]", dead, quiet)
+	}
+	if live := n.LiveNodes(); len(live) != 1 || live[0] != alive {
+		t.Errorf("live nodes are %v, want [%v]", live, alive)
+	}
+	if got := n.BlkToDatanodes[blk]; len(got) != 1 || got[0] != sid {
+		t.Errorf("replicas of %v are %v, want [%v]", blk, got, sid)
+	}
+	// an evicted datanode is told to register again
+	reply := HeartBeatReply{}
+	hb := HeartBeatArgs{Addr: quiet}
+	if err := n.HeartBeat(&hb, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.ReRegister {
+		t.Errorf("heartbeat of an evicted datanode is not told to register again")
+	}
+}
+
+// fakeCluster is a namenode with datanodes simulated by the test, which
+// tracks the blocks each of them holds to send complete block reports
+type fakeCluster struct {
+	t     *testing.T
+	n     *NameNode
+	addrs []string
+	sids  map[string]string           // address to storage id
+	stats map[string]HeartBeatArgs    // address to its heartbeats
+	blks  map[string]map[string]int64 // address to block to length
+	// address to block to generation stamp of the replica
+	stamps map[string]map[string]int64
+}
+
+// newFakeCluster starts a namenode with nodes datanodes of 1 GB each
+func newFakeCluster(t *testing.T, nodes int) *fakeCluster {
+	t.Helper()
+	c := &fakeCluster{t: t, n: newTestNameNode(t), sids: make(map[string]string),
+		stats: make(map[string]HeartBeatArgs),
+		blks:  make(map[string]map[string]int64), stamps: make(map[string]map[string]int64)}
+	for i := 0; i < nodes; i++ {
+		c.addNode(1<<30, 0)
+	}
+	return c
+}
+
+// addNode adds a datanode of capacity bytes of which frac are in use
+// besides the blocks it holds, it returns its address
+func (c *fakeCluster) addNode(capacity uint64, frac float64) string {
+	c.t.Helper()
+	addr := fmt.Sprintf("127.0.0.1:%v", 11170+len(c.addrs))
+	c.addrs = append(c.addrs, addr)
+	c.sids[addr] = addDataNode(c.t, c.n, addr, capacity, frac)
+	c.stats[addr] = HeartBeatArgs{HostName: addr, Addr: addr,
+		TotalCapacity: capacity, FracInUse: frac}
+	c.blks[addr] = make(map[string]int64)
+	c.stamps[addr] = make(map[string]int64)
+	return addr
+}
+
+// write creates file name of size bytes in dir with blocks of blkSize
+// bytes, 0 for the default, and rep replicas each. The datanodes chosen by
+// the namenode store and report the blocks.
+func (c *fakeCluster) write(dir, name string, size, blkSize int64, rep int) *CommandReply {
+	c.t.Helper()
+	reply := runCommand(c.t, c.n, &CommandArgs{CommandType: config.CopyFromLocal,
+		DPath: dir, FileName: name, FileSize: size, BlockSize: blkSize,
+		Replication: rep})
+	for i, blk := range reply.BlkList {
+		length := reply.BlkSize
+		if rest := size - int64(i)*reply.BlkSize; rest < length {
+			length = rest
+		}
+		for _, addr := range reply.BlkToDataNodes[blk] {
+			c.stamps[addr][blk] = reply.GenStamp
+			c.store(addr, blk, length)
+		}
+	}
+	return reply
+}
+
+// store puts a replica of blk on the datanode at addr and reports it
+func (c *fakeCluster) store(addr, blk string, length int64) {
+	c.t.Helper()
+	c.blks[addr][blk] = length
+	if _, ok := c.stamps[addr][blk]; !ok {
+		// a replica of unknown origin is as recent as the block
+		c.n.mu.Lock()
+		c.stamps[addr][blk] = c.n.BlkMeta[blk].GenStamp
+		c.n.mu.Unlock()
+	}
+	c.report(addr)
+}
+
+// report sends a block report of the datanode at addr
+func (c *fakeCluster) report(addr string) {
+	c.t.Helper()
+	metas := make(map[string]utils.MetaData)
+	for blk, length := range c.blks[addr] {
+		metas[blk] = utils.MetaData{Length: length, GenStamp: c.stamps[addr][blk]}
+	}
+	args := ReportBlockArgs{HostName: addr, Addr: addr, IDToMetaData: metas}
+	if err := c.n.ReportBlock(&args, &ReportBlockReply{}); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// heartbeat sends a heartbeat of the datanode at addr and carries out
+// the replications and removals of the reply
+func (c *fakeCluster) heartbeat(addr string) *HeartBeatReply {
+	c.t.Helper()
+	reply := &HeartBeatReply{}
+	args := c.stats[addr]
+	args.FracInUse += float64(c.used(addr)) / float64(args.TotalCapacity)
+	if err := c.n.HeartBeat(&args, reply); err != nil {
+		c.t.Fatal(err)
+	}
+	for blk, target := range reply.RepBlkToNodes {
+		c.stamps[target][blk] = c.stamps[addr][blk]
+		c.store(target, blk, c.blks[addr][blk])
+	}
+	if len(reply.RmBlk) > 0 {
+		for _, blk := range reply.RmBlk {
+			delete(c.blks[addr], blk)
+			delete(c.stamps[addr], blk)
+		}
+		c.report(addr)
+	}
+	return reply
+}
+
+// used returns the bytes of the blocks the datanode at addr holds
+func (c *fakeCluster) used(addr string) int64 {
+	var res int64
+	for _, length := range c.blks[addr] {
+		res += length
+	}
+	return res
+}
+
+// holders returns the addresses of the datanodes namenode knows to hold
+// blk, in no particular order
+func (c *fakeCluster) holders(blk string) []string {
+	c.n.mu.Lock()
+	defer c.n.mu.Unlock()
+	var addrs []string
+	for _, sid := range c.n.BlkToDatanodes[blk] {
+		addrs = append(addrs, c.n.SID2Addr[sid])
+	}
+	return addrs
+}
+
+func TestStateSurvivesRestart(t *testing.T) {
+	n := newTestNameNode(t)
+	addr := "127.0.0.1:11170"
+	sid := addDataNode(t, n, addr, 1<<30, 0)
+	blk := "a.txt-00000000-1-1"
+	report := ReportBlockArgs{Addr: addr,
+		IDToMetaData: map[string]utils.MetaData{blk: {Length: 10}}}
+	if err := n.ReportBlock(&report, &ReportBlockReply{}); err != nil {
+		t.Fatal(err)
+	}
+	// changes are dumped in the background, not by the calls themselves
+	if _, err := os.Stat(config.NStatePath); !os.IsNotExist(err) {
+		t.Fatalf("state dumped before flushing: %v", err)
+	}
+	n.flushState()
+	restarted := NewNameNode()
+	if got := restarted.SID2Addr[sid]; got != addr {
+		t.Errorf("SID2Addr[%v] = %q after restart, want %q", sid, got, addr)
+	}
+	if got := restarted.BlkToDatanodes[blk]; len(got) != 1 || got[0] != sid {
+		t.Errorf("replicas of %v are %v after restart, want [%v]", blk, got, sid)
+	}
+	if got := restarted.BlkMeta[blk].Length; got != 10 {
+		t.Errorf("length of %v is %v after restart, want 10", blk, got)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/utils"
+)
+
+/** Blocks no file references are left on datanodes by crashes, deletions
+ * failing to reach a datanode or uploads overwritten halfway. The orphan
+ * collector compares the blocks of the namespace with those datanodes
+ * report and has the datanodes remove the others on their next heartbeat.
+ * A block is only taken for an orphan config.OrphanGraceInSec after it was
+ * allocated, the time in its id, so that a block reported while its file
+ * is being created is left alone.
+ * */
+
+// collectOrphans schedules the removal of the replicas of the blocks no
+// file references which were allocated before before (in ms), it returns
+// the number of such blocks
+func (n *NameNode) collectOrphans(before int64) int {
+	referenced := make(map[string]bool)
+	n.nsMu.Lock()
+	n.root.walk("/", func(_ string, node *inode) {
+		for _, blk := range node.BlkList {
+			referenced[blk] = true
+		}
+	})
+	n.nsMu.Unlock()
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	num := 0
+	for blk, sids := range n.BlkToDatanodes {
+		id := utils.BlockID{}
+		if referenced[blk] || id.Parse(blk) != nil || id.Timestamp >= before {
+			continue
+		}
+		logger.Infof("block %v belongs to no file, remove its %v replicas\n", blk, len(sids))
+		for _, sid := range sids {
+			addr, ok := n.SID2Addr[sid]
+			if ok && !contains(n.Orphans[addr], blk) {
+				n.Orphans[addr] = append(n.Orphans[addr], blk)
+			}
+		}
+		delete(n.BlkToDatanodes, blk)
+		delete(n.BlkMeta, blk)
+		delete(n.BlkRep, blk)
+		delete(n.CorruptBlks, blk)
+		delete(n.PendingRep, blk)
+		num++
+	}
+	if num > 0 {
+		n.saveState()
+	}
+	return num
+}
+
+// collectOrphansPeriodically runs collectOrphans every
+// config.OrphanCheckInSec
+func (n *NameNode) collectOrphansPeriodically() {
+	for n.sleep(config.OrphanCheckInSec) {
+		grace := int64(config.OrphanGraceInSec) * 1000
+		if num := n.collectOrphans(utils.GetCurrentTimeInMs() - grace); num > 0 {
+			logger.Infof("%v orphan blocks to remove\n", num)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"testing"
+
+	"github.com/WineChord/gdfs/utils"
+)
+
+func TestCollectOrphans(t *testing.T) {
+	c := newFakeCluster(t, 2)
+	put := c.write("/", "f.bin", 2500, 1000, 2)
+	now := utils.GetCurrentTimeInMs()
+	// a block left behind by a file removed long ago, and one allocated
+	// for a file which may still be created
+	orphan := utils.BlockID{FileName: "gone.bin", Timestamp: now - 10000,
+		Random: utils.NewUUID()}.String()
+	young := utils.BlockID{FileName: "new.bin", Timestamp: now,
+		Random: utils.NewUUID()}.String()
+	for _, addr := range c.addrs {
+		c.store(addr, orphan, 100)
+	}
+	c.store(c.addrs[0], young, 100)
+	if num := c.n.collectOrphans(now - 5000); num != 1 {
+		t.Errorf("collected %v orphans, want 1", num)
+	}
+	for _, addr := range c.addrs {
+		c.heartbeat(addr)
+	}
+	for _, addr := range c.addrs {
+		if _, ok := c.blks[addr][orphan]; ok {
+			t.Errorf("%v still holds orphan %v", addr, orphan)
+		}
+		for _, blk := range put.BlkList {
+			if _, ok := c.blks[addr][blk]; !ok {
+				t.Errorf("%v lost %v of /f.bin", addr, blk)
+			}
+		}
+	}
+	if _, ok := c.blks[c.addrs[0]][young]; !ok {
+		t.Errorf("%v allocated within the grace period is removed", young)
+	}
+	if holders := c.holders(orphan); len(holders) != 0 {
+		t.Errorf("namenode still knows replicas of %v on %v", orphan, holders)
+	}
+	// once old enough, the young block goes too
+	if num := c.n.collectOrphans(now + 1); num != 1 {
+		t.Errorf("collected %v orphans, want 1", num)
+	}
+}
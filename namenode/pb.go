@@ -0,0 +1,698 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"time"
+
+	"github.com/WineChord/gdfs/gdfspb"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// ToPB converts c to gdfspb.CommandArgs
+func (c *CommandArgs) ToPB() *gdfspb.CommandArgs {
+	return &gdfspb.CommandArgs{
+		CommandType:  int64(c.CommandType),
+		Dpath:        c.DPath,
+		Dpaths:       c.DPaths,
+		FileName:     c.FileName,
+		FileSize:     c.FileSize,
+		Overwrite:    c.Overwrite,
+		BlockSize:    c.BlockSize,
+		Replication:  int64(c.Replication),
+		Timeout:      int64(c.Timeout),
+		Job:          c.Job,
+		NumReduce:    int64(c.NumReduce),
+		Output:       c.Output,
+		Token:        c.Token,
+		Recursive:    c.Recursive,
+		Long:         c.Long,
+		Addr:         c.Addr,
+		User:         c.User,
+		SkipTrash:    c.SkipTrash,
+		Quota:        c.Quota,
+		Digest:       c.Digest,
+		ChecksumType: c.ChecksumType,
+		BlkList:      c.BlkList,
+		Pattern:      c.Pattern,
+		IgnoreCase:   c.IgnoreCase,
+		CountOnly:    c.CountOnly,
+		K:            int64(c.K),
+		Lexical:      c.Lexical,
+	}
+}
+
+// FromPB sets c to pb
+func (c *CommandArgs) FromPB(pb *gdfspb.CommandArgs) {
+	*c = CommandArgs{
+		CommandType:  int(pb.GetCommandType()),
+		DPath:        pb.GetDpath(),
+		DPaths:       pb.GetDpaths(),
+		FileName:     pb.GetFileName(),
+		FileSize:     pb.GetFileSize(),
+		Overwrite:    pb.GetOverwrite(),
+		BlockSize:    pb.GetBlockSize(),
+		Replication:  int(pb.GetReplication()),
+		Timeout:      time.Duration(pb.GetTimeout()),
+		Job:          pb.GetJob(),
+		NumReduce:    int(pb.GetNumReduce()),
+		Output:       pb.GetOutput(),
+		Token:        pb.GetToken(),
+		Recursive:    pb.GetRecursive(),
+		Long:         pb.GetLong(),
+		Addr:         pb.GetAddr(),
+		User:         pb.GetUser(),
+		SkipTrash:    pb.GetSkipTrash(),
+		Quota:        pb.GetQuota(),
+		Digest:       pb.GetDigest(),
+		ChecksumType: pb.GetChecksumType(),
+		BlkList:      pb.GetBlkList(),
+		Pattern:      pb.GetPattern(),
+		IgnoreCase:   pb.GetIgnoreCase(),
+		CountOnly:    pb.GetCountOnly(),
+		K:            int(pb.GetK()),
+		Lexical:      pb.GetLexical(),
+	}
+}
+
+// ToPB converts c to gdfspb.CommandReply
+func (c *CommandReply) ToPB() *gdfspb.CommandReply {
+	pb := &gdfspb.CommandReply{
+		Result:         c.Result,
+		Files:          c.Files,
+		Paths:          c.Paths,
+		BlkList:        c.BlkList,
+		BlkSize:        c.BlkSize,
+		FileSize:       c.FileSize,
+		BlkLengths:     c.BlkLengths,
+		SrcBlkList:     c.SrcBlkList,
+		BlkToDataNodes: utils.StringsToPB(c.BlkToDataNodes),
+		GenStamp:       c.GenStamp,
+		Errors:         c.Errors,
+		Checksums:      c.Checksums,
+		JobResult:      c.JobResult,
+		Trashed:        c.Trashed,
+		Replicated:     c.Replicated,
+		Digest:         c.Digest,
+		DigestType:     c.DigestType,
+		Lines:          c.Lines,
+		NumLines:       int64(c.NumLines),
+	}
+	for i := range c.Stats {
+		pb.Stats = append(pb.Stats, c.Stats[i].ToPB())
+	}
+	if len(c.WordCounts) > 0 {
+		pb.WordCounts = make(map[string]int64, len(c.WordCounts))
+		for k, v := range c.WordCounts {
+			pb.WordCounts[k] = int64(v)
+		}
+	}
+	for i := range c.Usage {
+		pb.Usage = append(pb.Usage, c.Usage[i].ToPB())
+	}
+	if c.Fsck != nil {
+		pb.Fsck = c.Fsck.ToPB()
+	}
+	for i := range c.Counts {
+		pb.Counts = append(pb.Counts, c.Counts[i].ToPB())
+	}
+	return pb
+}
+
+// FromPB sets c to pb
+func (c *CommandReply) FromPB(pb *gdfspb.CommandReply) {
+	*c = CommandReply{
+		Result:         pb.GetResult(),
+		Files:          pb.GetFiles(),
+		Paths:          pb.GetPaths(),
+		BlkList:        pb.GetBlkList(),
+		BlkSize:        pb.GetBlkSize(),
+		FileSize:       pb.GetFileSize(),
+		BlkLengths:     pb.GetBlkLengths(),
+		SrcBlkList:     pb.GetSrcBlkList(),
+		BlkToDataNodes: utils.StringsFromPB(pb.GetBlkToDataNodes()),
+		GenStamp:       pb.GetGenStamp(),
+		Errors:         pb.GetErrors(),
+		Checksums:      pb.GetChecksums(),
+		JobResult:      pb.GetJobResult(),
+		Trashed:        pb.GetTrashed(),
+		Replicated:     pb.GetReplicated(),
+		Digest:         pb.GetDigest(),
+		DigestType:     pb.GetDigestType(),
+		Lines:          pb.GetLines(),
+		NumLines:       int(pb.GetNumLines()),
+	}
+	if vs := pb.GetStats(); len(vs) > 0 {
+		c.Stats = make([]FileStat, len(vs))
+		for i, v := range vs {
+			c.Stats[i].FromPB(v)
+		}
+	}
+	if vs := pb.GetWordCounts(); len(vs) > 0 {
+		c.WordCounts = make(map[string]int, len(vs))
+		for k, v := range vs {
+			c.WordCounts[k] = int(v)
+		}
+	}
+	if vs := pb.GetUsage(); len(vs) > 0 {
+		c.Usage = make([]DiskUsage, len(vs))
+		for i, v := range vs {
+			c.Usage[i].FromPB(v)
+		}
+	}
+	if v := pb.GetFsck(); v != nil {
+		c.Fsck = &FsckReport{}
+		c.Fsck.FromPB(v)
+	}
+	if vs := pb.GetCounts(); len(vs) > 0 {
+		c.Counts = make([]DirCount, len(vs))
+		for i, v := range vs {
+			c.Counts[i].FromPB(v)
+		}
+	}
+}
+
+// ToPB converts f to gdfspb.FileStat
+func (f *FileStat) ToPB() *gdfspb.FileStat {
+	return &gdfspb.FileStat{
+		Path:        f.Path,
+		IsDir:       f.IsDir,
+		Size:        f.Size,
+		NumBlks:     int64(f.NumBlks),
+		BlkSize:     f.BlkSize,
+		Replication: int64(f.Replication),
+		ModTime:     f.ModTime,
+		AccessTime:  f.AccessTime,
+	}
+}
+
+// FromPB sets f to pb
+func (f *FileStat) FromPB(pb *gdfspb.FileStat) {
+	*f = FileStat{
+		Path:        pb.GetPath(),
+		IsDir:       pb.GetIsDir(),
+		Size:        pb.GetSize(),
+		NumBlks:     int(pb.GetNumBlks()),
+		BlkSize:     pb.GetBlkSize(),
+		Replication: int(pb.GetReplication()),
+		ModTime:     pb.GetModTime(),
+		AccessTime:  pb.GetAccessTime(),
+	}
+}
+
+// ToPB converts d to gdfspb.DiskUsage
+func (d *DiskUsage) ToPB() *gdfspb.DiskUsage {
+	return &gdfspb.DiskUsage{
+		Path:       d.Path,
+		Size:       d.Size,
+		Replicated: d.Replicated,
+	}
+}
+
+// FromPB sets d to pb
+func (d *DiskUsage) FromPB(pb *gdfspb.DiskUsage) {
+	*d = DiskUsage{
+		Path:       pb.GetPath(),
+		Size:       pb.GetSize(),
+		Replicated: pb.GetReplicated(),
+	}
+}
+
+// ToPB converts f to gdfspb.FsckReport
+func (f *FsckReport) ToPB() *gdfspb.FsckReport {
+	pb := &gdfspb.FsckReport{
+		Files: int64(f.Files),
+		Blks:  int64(f.Blks),
+	}
+	for i := range f.Unhealthy {
+		pb.Unhealthy = append(pb.Unhealthy, f.Unhealthy[i].ToPB())
+	}
+	return pb
+}
+
+// FromPB sets f to pb
+func (f *FsckReport) FromPB(pb *gdfspb.FsckReport) {
+	*f = FsckReport{
+		Files: int(pb.GetFiles()),
+		Blks:  int(pb.GetBlks()),
+	}
+	if vs := pb.GetUnhealthy(); len(vs) > 0 {
+		f.Unhealthy = make([]FileHealth, len(vs))
+		for i, v := range vs {
+			f.Unhealthy[i].FromPB(v)
+		}
+	}
+}
+
+// ToPB converts f to gdfspb.FileHealth
+func (f *FileHealth) ToPB() *gdfspb.FileHealth {
+	return &gdfspb.FileHealth{
+		Path:            f.Path,
+		NumBlks:         int64(f.NumBlks),
+		Missing:         f.Missing,
+		UnderReplicated: f.UnderReplicated,
+		OverReplicated:  f.OverReplicated,
+		Corrupt:         f.Corrupt,
+	}
+}
+
+// FromPB sets f to pb
+func (f *FileHealth) FromPB(pb *gdfspb.FileHealth) {
+	*f = FileHealth{
+		Path:            pb.GetPath(),
+		NumBlks:         int(pb.GetNumBlks()),
+		Missing:         pb.GetMissing(),
+		UnderReplicated: pb.GetUnderReplicated(),
+		OverReplicated:  pb.GetOverReplicated(),
+		Corrupt:         pb.GetCorrupt(),
+	}
+}
+
+// ToPB converts d to gdfspb.DirCount
+func (d *DirCount) ToPB() *gdfspb.DirCount {
+	return &gdfspb.DirCount{
+		Path:          d.Path,
+		Dirs:          d.Dirs,
+		Files:         d.Files,
+		Bytes:         d.Bytes,
+		NsQuota:       d.NsQuota,
+		SpaceQuota:    d.SpaceQuota,
+		SpaceConsumed: d.SpaceConsumed,
+	}
+}
+
+// FromPB sets d to pb
+func (d *DirCount) FromPB(pb *gdfspb.DirCount) {
+	*d = DirCount{
+		Path:          pb.GetPath(),
+		Dirs:          pb.GetDirs(),
+		Files:         pb.GetFiles(),
+		Bytes:         pb.GetBytes(),
+		NsQuota:       pb.GetNsQuota(),
+		SpaceQuota:    pb.GetSpaceQuota(),
+		SpaceConsumed: pb.GetSpaceConsumed(),
+	}
+}
+
+// ToPB converts s to gdfspb.SubmitJobReply
+func (s *SubmitJobReply) ToPB() *gdfspb.SubmitJobReply {
+	return &gdfspb.SubmitJobReply{
+		JobId: s.JobID,
+	}
+}
+
+// FromPB sets s to pb
+func (s *SubmitJobReply) FromPB(pb *gdfspb.SubmitJobReply) {
+	*s = SubmitJobReply{
+		JobID: pb.GetJobId(),
+	}
+}
+
+// ToPB converts j to gdfspb.JobArgs
+func (j *JobArgs) ToPB() *gdfspb.JobArgs {
+	return &gdfspb.JobArgs{
+		JobId: j.JobID,
+		Token: j.Token,
+	}
+}
+
+// FromPB sets j to pb
+func (j *JobArgs) FromPB(pb *gdfspb.JobArgs) {
+	*j = JobArgs{
+		JobID: pb.GetJobId(),
+		Token: pb.GetToken(),
+	}
+}
+
+// ToPB converts j to gdfspb.JobStatusReply
+func (j *JobStatus) ToPB() *gdfspb.JobStatusReply {
+	return &gdfspb.JobStatusReply{
+		JobId:       j.JobID,
+		Job:         j.Job,
+		Path:        j.Path,
+		State:       j.State,
+		Tasks:       j.Tasks,
+		ReduceTasks: j.ReduceTasks,
+		Start:       j.Start,
+		End:         j.End,
+		Err:         j.Err,
+		Result:      j.Result,
+		JobResult:   j.JobResult,
+		Files:       j.Files,
+	}
+}
+
+// FromPB sets j to pb
+func (j *JobStatus) FromPB(pb *gdfspb.JobStatusReply) {
+	*j = JobStatus{
+		JobID:       pb.GetJobId(),
+		Job:         pb.GetJob(),
+		Path:        pb.GetPath(),
+		State:       pb.GetState(),
+		Tasks:       pb.GetTasks(),
+		ReduceTasks: pb.GetReduceTasks(),
+		Start:       pb.GetStart(),
+		End:         pb.GetEnd(),
+		Err:         pb.GetErr(),
+		Result:      pb.GetResult(),
+		JobResult:   pb.GetJobResult(),
+		Files:       pb.GetFiles(),
+	}
+}
+
+// ToPB converts a to gdfspb.AdminArgs
+func (a *AdminArgs) ToPB() *gdfspb.AdminArgs {
+	return &gdfspb.AdminArgs{
+		Token: a.Token,
+	}
+}
+
+// FromPB sets a to pb
+func (a *AdminArgs) FromPB(pb *gdfspb.AdminArgs) {
+	*a = AdminArgs{
+		Token: pb.GetToken(),
+	}
+}
+
+// ToPB converts c to gdfspb.ClusterStatusReply
+func (c *ClusterStatusReply) ToPB() *gdfspb.ClusterStatusReply {
+	pb := &gdfspb.ClusterStatusReply{
+		Capacity:  c.Capacity,
+		Used:      c.Used,
+		Remaining: c.Remaining,
+	}
+	for i := range c.Nodes {
+		pb.Nodes = append(pb.Nodes, c.Nodes[i].ToPB())
+	}
+	return pb
+}
+
+// FromPB sets c to pb
+func (c *ClusterStatusReply) FromPB(pb *gdfspb.ClusterStatusReply) {
+	*c = ClusterStatusReply{
+		Capacity:  pb.GetCapacity(),
+		Used:      pb.GetUsed(),
+		Remaining: pb.GetRemaining(),
+	}
+	if vs := pb.GetNodes(); len(vs) > 0 {
+		c.Nodes = make([]NodeStatus, len(vs))
+		for i, v := range vs {
+			c.Nodes[i].FromPB(v)
+		}
+	}
+}
+
+// ToPB converts n to gdfspb.NodeStatus
+func (n *NodeStatus) ToPB() *gdfspb.NodeStatus {
+	return &gdfspb.NodeStatus{
+		Addr:          n.Addr,
+		HostName:      n.HostName,
+		StorageId:     n.StorageID,
+		State:         n.State,
+		Capacity:      n.Capacity,
+		Used:          n.Used,
+		Remaining:     n.Remaining,
+		FracInUse:     n.FracInUse,
+		LastHeartBeat: n.LastHeartBeat,
+		DeadSince:     n.DeadSince,
+	}
+}
+
+// FromPB sets n to pb
+func (n *NodeStatus) FromPB(pb *gdfspb.NodeStatus) {
+	*n = NodeStatus{
+		Addr:          pb.GetAddr(),
+		HostName:      pb.GetHostName(),
+		StorageID:     pb.GetStorageId(),
+		State:         pb.GetState(),
+		Capacity:      pb.GetCapacity(),
+		Used:          pb.GetUsed(),
+		Remaining:     pb.GetRemaining(),
+		FracInUse:     pb.GetFracInUse(),
+		LastHeartBeat: pb.GetLastHeartBeat(),
+		DeadSince:     pb.GetDeadSince(),
+	}
+}
+
+// ToPB converts r to gdfspb.ReportNodesReply
+func (r *ReportNodesReply) ToPB() *gdfspb.ReportNodesReply {
+	pb := &gdfspb.ReportNodesReply{}
+	for i := range r.Nodes {
+		pb.Nodes = append(pb.Nodes, r.Nodes[i].ToPB())
+	}
+	return pb
+}
+
+// FromPB sets r to pb
+func (r *ReportNodesReply) FromPB(pb *gdfspb.ReportNodesReply) {
+	*r = ReportNodesReply{}
+	if vs := pb.GetNodes(); len(vs) > 0 {
+		r.Nodes = make([]NodeStatus, len(vs))
+		for i, v := range vs {
+			r.Nodes[i].FromPB(v)
+		}
+	}
+}
+
+// ToPB converts n to gdfspb.NotifyArgs
+func (n *NotifyArgs) ToPB() *gdfspb.NotifyArgs {
+	return &gdfspb.NotifyArgs{
+		Addrs: n.Addrs,
+		Token: n.Token,
+	}
+}
+
+// FromPB sets n to pb
+func (n *NotifyArgs) FromPB(pb *gdfspb.NotifyArgs) {
+	*n = NotifyArgs{
+		Addrs: pb.GetAddrs(),
+		Token: pb.GetToken(),
+	}
+}
+
+// ToPB converts n to gdfspb.NotifyReply
+func (n *NotifyReply) ToPB() *gdfspb.NotifyReply {
+	return &gdfspb.NotifyReply{
+		Status: n.Status,
+	}
+}
+
+// FromPB sets n to pb
+func (n *NotifyReply) FromPB(pb *gdfspb.NotifyReply) {
+	*n = NotifyReply{
+		Status: pb.GetStatus(),
+	}
+}
+
+// ToPB converts h to gdfspb.HandshakeArgs
+func (h *HandshakeArgs) ToPB() *gdfspb.HandshakeArgs {
+	return &gdfspb.HandshakeArgs{
+		NamespaceId: int64(h.NamespaceID),
+		Addr:        h.Addr,
+		HostName:    h.HostName,
+		Token:       h.Token,
+		Version:     h.Version,
+	}
+}
+
+// FromPB sets h to pb
+func (h *HandshakeArgs) FromPB(pb *gdfspb.HandshakeArgs) {
+	*h = HandshakeArgs{
+		NamespaceID: int(pb.GetNamespaceId()),
+		Addr:        pb.GetAddr(),
+		HostName:    pb.GetHostName(),
+		Token:       pb.GetToken(),
+		Version:     pb.GetVersion(),
+	}
+}
+
+// ToPB converts h to gdfspb.HandshakeReply
+func (h *HandshakeReply) ToPB() *gdfspb.HandshakeReply {
+	return &gdfspb.HandshakeReply{
+		NamespaceId: int64(h.NamespaceID),
+		Version:     h.Version,
+	}
+}
+
+// FromPB sets h to pb
+func (h *HandshakeReply) FromPB(pb *gdfspb.HandshakeReply) {
+	*h = HandshakeReply{
+		NamespaceID: int(pb.GetNamespaceId()),
+		Version:     pb.GetVersion(),
+	}
+}
+
+// ToPB converts r to gdfspb.RegisterArgs
+func (r *RegisterArgs) ToPB() *gdfspb.RegisterArgs {
+	return &gdfspb.RegisterArgs{
+		HostName:  r.HostName,
+		Addr:      r.Addr,
+		StorageId: r.StorageID,
+		Token:     r.Token,
+	}
+}
+
+// FromPB sets r to pb
+func (r *RegisterArgs) FromPB(pb *gdfspb.RegisterArgs) {
+	*r = RegisterArgs{
+		HostName:  pb.GetHostName(),
+		Addr:      pb.GetAddr(),
+		StorageID: pb.GetStorageId(),
+		Token:     pb.GetToken(),
+	}
+}
+
+// ToPB converts r to gdfspb.RegisterReply
+func (r *RegisterReply) ToPB() *gdfspb.RegisterReply {
+	return &gdfspb.RegisterReply{
+		StorageId: r.StorageID,
+	}
+}
+
+// FromPB sets r to pb
+func (r *RegisterReply) FromPB(pb *gdfspb.RegisterReply) {
+	*r = RegisterReply{
+		StorageID: pb.GetStorageId(),
+	}
+}
+
+// ToPB converts h to gdfspb.HeartBeatArgs
+func (h *HeartBeatArgs) ToPB() *gdfspb.HeartBeatArgs {
+	return &gdfspb.HeartBeatArgs{
+		HostName:      h.HostName,
+		Addr:          h.Addr,
+		TotalCapacity: h.TotalCapacity,
+		FracInUse:     h.FracInUse,
+		NumDataTrans:  int64(h.NumDataTrans),
+		Token:         h.Token,
+	}
+}
+
+// FromPB sets h to pb
+func (h *HeartBeatArgs) FromPB(pb *gdfspb.HeartBeatArgs) {
+	*h = HeartBeatArgs{
+		HostName:      pb.GetHostName(),
+		Addr:          pb.GetAddr(),
+		TotalCapacity: pb.GetTotalCapacity(),
+		FracInUse:     pb.GetFracInUse(),
+		NumDataTrans:  int(pb.GetNumDataTrans()),
+		Token:         pb.GetToken(),
+	}
+}
+
+// ToPB converts h to gdfspb.HeartBeatReply
+func (h *HeartBeatReply) ToPB() *gdfspb.HeartBeatReply {
+	return &gdfspb.HeartBeatReply{
+		RepBlkToNodes: h.RepBlkToNodes,
+		RmBlk:         h.RmBlk,
+		ReRegister:    h.ReRegister,
+		Shutdown:      h.Shutdown,
+		ReqBlkReport:  h.ReqBlkReport,
+		Format:        h.Format,
+		FormatId:      int64(h.FormatID),
+	}
+}
+
+// FromPB sets h to pb
+func (h *HeartBeatReply) FromPB(pb *gdfspb.HeartBeatReply) {
+	*h = HeartBeatReply{
+		RepBlkToNodes: pb.GetRepBlkToNodes(),
+		RmBlk:         pb.GetRmBlk(),
+		ReRegister:    pb.GetReRegister(),
+		Shutdown:      pb.GetShutdown(),
+		ReqBlkReport:  pb.GetReqBlkReport(),
+		Format:        pb.GetFormat(),
+		FormatID:      int(pb.GetFormatId()),
+	}
+}
+
+// ToPB converts r to gdfspb.ReportBlockArgs
+func (r *ReportBlockArgs) ToPB() *gdfspb.ReportBlockArgs {
+	pb := &gdfspb.ReportBlockArgs{
+		HostName: r.HostName,
+		Addr:     r.Addr,
+		Token:    r.Token,
+	}
+	if len(r.IDToMetaData) > 0 {
+		pb.IdToMetaData = make(map[string]*gdfspb.MetaData, len(r.IDToMetaData))
+		for k, v := range r.IDToMetaData {
+			pb.IdToMetaData[k] = v.ToPB()
+		}
+	}
+	return pb
+}
+
+// FromPB sets r to pb
+func (r *ReportBlockArgs) FromPB(pb *gdfspb.ReportBlockArgs) {
+	*r = ReportBlockArgs{
+		HostName: pb.GetHostName(),
+		Addr:     pb.GetAddr(),
+		Token:    pb.GetToken(),
+	}
+	if vs := pb.GetIdToMetaData(); len(vs) > 0 {
+		r.IDToMetaData = make(map[string]utils.MetaData, len(vs))
+		for k, v := range vs {
+			var x utils.MetaData
+			x.FromPB(v)
+			r.IDToMetaData[k] = x
+		}
+	}
+}
+
+// ToPB converts r to gdfspb.ReportBlockReply
+func (r *ReportBlockReply) ToPB() *gdfspb.ReportBlockReply {
+	return &gdfspb.ReportBlockReply{
+		Status: r.Status,
+	}
+}
+
+// FromPB sets r to pb
+func (r *ReportBlockReply) FromPB(pb *gdfspb.ReportBlockReply) {
+	*r = ReportBlockReply{
+		Status: pb.GetStatus(),
+	}
+}
+
+// ToPB converts r to gdfspb.ReportCorruptBlockArgs
+func (r *ReportCorruptBlockArgs) ToPB() *gdfspb.ReportCorruptBlockArgs {
+	return &gdfspb.ReportCorruptBlockArgs{
+		Addr:   r.Addr,
+		BlkIds: r.BlkIDs,
+		Token:  r.Token,
+	}
+}
+
+// FromPB sets r to pb
+func (r *ReportCorruptBlockArgs) FromPB(pb *gdfspb.ReportCorruptBlockArgs) {
+	*r = ReportCorruptBlockArgs{
+		Addr:   pb.GetAddr(),
+		BlkIDs: pb.GetBlkIds(),
+		Token:  pb.GetToken(),
+	}
+}
+
+// ToPB converts r to gdfspb.ReportCorruptBlockReply
+func (r *ReportCorruptBlockReply) ToPB() *gdfspb.ReportCorruptBlockReply {
+	return &gdfspb.ReportCorruptBlockReply{
+		Status: r.Status,
+	}
+}
+
+// FromPB sets r to pb
+func (r *ReportCorruptBlockReply) FromPB(pb *gdfspb.ReportCorruptBlockReply) {
+	*r = ReportCorruptBlockReply{
+		Status: pb.GetStatus(),
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/logger"
+)
+
+// NodeInfo describes a live datanode to a PlacementPolicy, as told by its
+// last heartbeat
+type NodeInfo struct {
+	Addr         string
+	HostName     string
+	Capacity     uint64  // in bytes, 0 before the first heartbeat
+	FracInUse    float64 // fraction in use
+	NumDataTrans int     // number of block transfers in progress
+}
+
+// PlacementPolicy chooses the datanodes holding the replicas of a block,
+// see config.BlockPlacement. Rack or zone aware policies are added with
+// RegisterPlacementPolicy.
+type PlacementPolicy interface {
+	// Choose returns the addresses of numReplicas distinct datanodes of
+	// liveNodes, leaving out the addresses in exclude
+	Choose(numReplicas int, liveNodes []NodeInfo, exclude []string) ([]string, error)
+}
+
+// built-in policies of config.BlockPlacement
+const (
+	// FirstN places replicas on the first datanodes, by address
+	FirstN = "first-n"
+	// CapacityAware places replicas on distinct hosts, favoring emptier
+	// and less busy datanodes
+	CapacityAware = "capacity"
+)
+
+var (
+	policyMu sync.Mutex
+	policies = map[string]PlacementPolicy{
+		FirstN:        firstN{},
+		CapacityAware: capacityAware{},
+	}
+)
+
+// RegisterPlacementPolicy makes policy available to config.BlockPlacement
+// under name, it panics if name is taken
+func RegisterPlacementPolicy(name string, policy PlacementPolicy) {
+	policyMu.Lock()
+	defer policyMu.Unlock()
+	if _, ok := policies[name]; ok {
+		panic("namenode: placement policy " + name + " registered twice")
+	}
+	policies[name] = policy
+}
+
+// placementPolicy returns the policy registered under name,
+// CapacityAware if there is none
+func placementPolicy(name string) PlacementPolicy {
+	policyMu.Lock()
+	defer policyMu.Unlock()
+	policy, ok := policies[name]
+	if !ok {
+		logger.Errorf("unknown placement policy %q, use %q\n", name, CapacityAware)
+		return policies[CapacityAware]
+	}
+	return policy
+}
+
+// candidates returns the nodes of liveNodes not in exclude, it fails if
+// they are fewer than numReplicas
+func candidates(numReplicas int, liveNodes []NodeInfo, exclude []string) ([]NodeInfo, error) {
+	res := make([]NodeInfo, 0, len(liveNodes))
+	for _, node := range liveNodes {
+		if !contains(exclude, node.Addr) {
+			res = append(res, node)
+		}
+	}
+	if len(res) < numReplicas {
+		return nil, fmt.Errorf("Not enough live datanodes for replication factor %v: %v",
+			numReplicas, len(res))
+	}
+	return res, nil
+}
+
+// firstN is the FirstN policy
+type firstN struct{}
+
+func (firstN) Choose(numReplicas int, liveNodes []NodeInfo, exclude []string) ([]string, error) {
+	nodes, err := candidates(numReplicas, liveNodes, exclude)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Addr < nodes[j].Addr })
+	res := make([]string, 0, numReplicas)
+	for _, node := range nodes[:numReplicas] {
+		res = append(res, node.Addr)
+	}
+	return res, nil
+}
+
+// capacityAware is the CapacityAware policy. Emptier datanodes are more
+// likely to be chosen: each one gets a random key rand^(1/weight) with
+// weight being its free fraction, see freeWeight, and the largest keys
+// win. This also spreads consecutive blocks over the cluster. Datanodes
+// on a host already holding a replica, excluded ones included, are only
+// chosen when no other is left.
+type capacityAware struct{}
+
+func (capacityAware) Choose(numReplicas int, liveNodes []NodeInfo, exclude []string) ([]string, error) {
+	nodes, err := candidates(numReplicas, liveNodes, exclude)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]float64)
+	for _, node := range nodes {
+		keys[node.Addr] = math.Pow(rand.Float64(), 1/freeWeight(node))
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return keys[nodes[i].Addr] > keys[nodes[j].Addr]
+	})
+	res := make([]string, 0, numReplicas)
+	hosts := make(map[string]bool)
+	for _, node := range liveNodes {
+		if contains(exclude, node.Addr) {
+			hosts[node.HostName] = true
+		}
+	}
+	var sameHost []string
+	for _, node := range nodes {
+		if len(res) == numReplicas {
+			break
+		}
+		if hosts[node.HostName] {
+			sameHost = append(sameHost, node.Addr)
+			continue
+		}
+		hosts[node.HostName] = true
+		res = append(res, node.Addr)
+	}
+	return append(res, sameHost[:numReplicas-len(res)]...), nil
+}
+
+// freeWeight is the placement weight of a datanode, i.e. its free
+// fraction, a datanode without heartbeat stats counts as half full. The
+// weight is divided by one plus the number of transfers in progress, so
+// that busy datanodes get fewer new blocks.
+func freeWeight(node NodeInfo) float64 {
+	w := 0.5
+	if node.Capacity > 0 {
+		w = 1 - node.FracInUse
+	}
+	if node.NumDataTrans > 0 {
+		w /= float64(1 + node.NumDataTrans)
+	}
+	// keep nearly full datanodes selectable as the last resort
+	return math.Max(w, 0.01)
+}
+
+// liveNodes describes the live datanodes not being decommissioned, sorted
+// by address. The caller should hold n.mu.
+func (n *NameNode) liveNodes() []NodeInfo {
+	res := make([]NodeInfo, 0, len(n.Addr2SID))
+	for addr := range n.Addr2SID {
+		if !n.inService(addr) {
+			continue
+		}
+		stat := n.NodeStats[addr]
+		res = append(res, NodeInfo{Addr: addr, HostName: stat.HostName,
+			Capacity: stat.TotalCapacity, FracInUse: stat.FracInUse,
+			NumDataTrans: stat.NumDataTrans})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Addr < res[j].Addr })
+	return res
+}
+
+// choosePlacement picks numReplicas distinct live datanodes for a new
+// block of blkSize bytes with the placement policy of n, decommissioning
+// ones excluded. Datanodes fuller than config.MaxFracInUse or without room
+// for the block are left out, unless that leaves too few of them.
+// The caller should hold n.mu.
+func (n *NameNode) choosePlacement(numReplicas int, blkSize int64) ([]string, error) {
+	live := n.liveNodes()
+	roomy := make([]NodeInfo, 0, len(live))
+	for _, node := range live {
+		if n.hasRoom(node.Addr, blkSize) {
+			roomy = append(roomy, node)
+		}
+	}
+	if len(roomy) < numReplicas {
+		if len(live) >= numReplicas {
+			logger.Warnf("only %v datanodes have room for a block, use all live ones\n",
+				len(roomy))
+		}
+		roomy = live
+	}
+	return n.placement.Choose(numReplicas, roomy, nil)
+}
+
+// hasRoom tells whether the datanode at addr is below config.MaxFracInUse
+// and has blkSize free bytes, a datanode without heartbeat stats is assumed
+// to have room. The caller should hold n.mu.
+func (n *NameNode) hasRoom(addr string, blkSize int64) bool {
+	stat, ok := n.NodeStats[addr]
+	if !ok || stat.TotalCapacity == 0 {
+		return true
+	}
+	free := float64(stat.TotalCapacity) * (1 - stat.FracInUse)
+	return stat.FracInUse <= config.MaxFracInUse && free >= float64(blkSize)
+}
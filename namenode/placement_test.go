@@ -0,0 +1,182 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+func TestChoosePlacementSpreads(t *testing.T) {
+	c := newFakeCluster(t, 6)
+	const blks, rep = 600, 3
+	counts := make(map[string]int)
+	c.n.mu.Lock()
+	defer c.n.mu.Unlock()
+	for i := 0; i < blks; i++ {
+		addrs, err := c.n.choosePlacement(rep, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(addrs) != rep {
+			t.Fatalf("placement %v has %v replicas, want %v", addrs, len(addrs), rep)
+		}
+		seen := make(map[string]bool)
+		for _, addr := range addrs {
+			if seen[addr] {
+				t.Fatalf("placement %v holds %v twice", addrs, addr)
+			}
+			seen[addr] = true
+			counts[addr]++
+		}
+	}
+	// each datanode gets half of the blocks on average
+	for _, addr := range c.addrs {
+		if got := counts[addr]; got < blks/3 || got > 2*blks/3 {
+			t.Errorf("%v holds %v of %v blocks, want about %v", addr, got, blks, blks/2)
+		}
+	}
+}
+
+func TestChoosePlacementAvoidsBusy(t *testing.T) {
+	c := newFakeCluster(t, 2)
+	busy, idle := c.addrs[0], c.addrs[1]
+	stat := c.stats[busy]
+	stat.NumDataTrans = 9
+	c.stats[busy] = stat
+	c.heartbeat(busy)
+	const blks = 1000
+	counts := make(map[string]int)
+	c.n.mu.Lock()
+	defer c.n.mu.Unlock()
+	for i := 0; i < blks; i++ {
+		addrs, err := c.n.choosePlacement(1, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[addrs[0]]++
+	}
+	// the busy datanode weighs a tenth of the idle one
+	if counts[busy] > blks/4 || counts[idle] < blks/2 {
+		t.Errorf("busy datanode got %v blocks and idle one %v", counts[busy],
+			counts[idle])
+	}
+}
+
+// testNodes describes a datanode per host in hosts, of 1 GB and frac in
+// use each
+func testNodes(frac float64, hosts ...string) []NodeInfo {
+	var res []NodeInfo
+	for i, host := range hosts {
+		res = append(res, NodeInfo{Addr: fmt.Sprintf("127.0.0.1:%v", 11170+i),
+			HostName: host, Capacity: 1 << 30, FracInUse: frac})
+	}
+	return res
+}
+
+func TestFirstN(t *testing.T) {
+	nodes := testNodes(0, "a", "b", "c", "d")
+	// the order of the live datanodes doesn't matter
+	nodes[0], nodes[3] = nodes[3], nodes[0]
+	policy := placementPolicy(FirstN)
+	for i := 0; i < 10; i++ {
+		addrs, err := policy.Choose(2, nodes, []string{"127.0.0.1:11171"})
+		want := []string{"127.0.0.1:11170", "127.0.0.1:11172"}
+		if err != nil || !reflect.DeepEqual(addrs, want) {
+			t.Fatalf("first-n chose %v, %v, want %v", addrs, err, want)
+		}
+	}
+	if _, err := policy.Choose(4, nodes, []string{"127.0.0.1:11171"}); err == nil {
+		t.Errorf("first-n chose 4 of 3 datanodes")
+	}
+}
+
+func TestCapacityAware(t *testing.T) {
+	policy := placementPolicy(CapacityAware)
+	// two datanodes share host a, replicas go to distinct hosts first
+	nodes := testNodes(0, "a", "a", "b")
+	for i := 0; i < 100; i++ {
+		addrs, err := policy.Choose(2, nodes, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !contains(addrs, nodes[2].Addr) {
+			t.Fatalf("chose %v, both on host a", addrs)
+		}
+		// the host of an excluded datanode holds a replica already
+		addrs, err = policy.Choose(1, nodes, []string{nodes[2].Addr})
+		if err != nil || len(addrs) != 1 || addrs[0] == nodes[2].Addr {
+			t.Fatalf("chose %v, %v excluding %v", addrs, err, nodes[2].Addr)
+		}
+		addrs, err = policy.Choose(1, nodes, []string{nodes[0].Addr})
+		if err != nil || !reflect.DeepEqual(addrs, []string{nodes[2].Addr}) {
+			t.Fatalf("chose %v, %v with a replica on host a", addrs, err)
+		}
+	}
+	// with a single host left, its datanodes hold every replica
+	addrs, err := policy.Choose(3, nodes, nil)
+	if err != nil || len(addrs) != 3 {
+		t.Errorf("chose %v, %v for 3 replicas on 2 hosts", addrs, err)
+	}
+	if _, err := policy.Choose(3, nodes, []string{nodes[1].Addr}); err == nil {
+		t.Errorf("chose 3 of 2 datanodes")
+	}
+
+	// emptier datanodes get more blocks
+	nodes = append(testNodes(0.9, "full"), testNodes(0.1, "x", "empty")[1])
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		addrs, err := policy.Choose(1, nodes, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[addrs[0]]++
+	}
+	if full, empty := counts[nodes[0].Addr], counts[nodes[1].Addr]; full > empty/4 {
+		t.Errorf("full datanode got %v blocks and empty one %v", full, empty)
+	}
+}
+
+// lastN places replicas on the last datanodes by address
+type lastN struct{}
+
+func (lastN) Choose(numReplicas int, liveNodes []NodeInfo, exclude []string) ([]string, error) {
+	addrs, err := firstN{}.Choose(len(liveNodes), liveNodes, exclude)
+	if err != nil || len(addrs) < numReplicas {
+		return nil, errors.New("Not enough datanodes")
+	}
+	return addrs[len(addrs)-numReplicas:], nil
+}
+
+// registerLastN registers lastN once, however many times tests run
+var registerLastN sync.Once
+
+func TestRegisterPlacementPolicy(t *testing.T) {
+	registerLastN.Do(func() { RegisterPlacementPolicy("last-n", lastN{}) })
+	defer func(p string) { config.BlockPlacement = p }(config.BlockPlacement)
+	config.BlockPlacement = "last-n"
+	c := newFakeCluster(t, 4)
+	c.n.placement = placementPolicy(config.BlockPlacement)
+	reply := c.write("/", "f", 10, 0, 2)
+	want := []string{c.addrs[2], c.addrs[3]}
+	if got := reply.BlkToDataNodes[reply.BlkList[0]]; !reflect.DeepEqual(got, want) {
+		t.Errorf("last-n placed the block on %v, want %v", got, want)
+	}
+}
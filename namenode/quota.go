@@ -0,0 +1,92 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/logger"
+)
+
+// ErrQuota is returned for a creation exceeding the quota of a directory.
+// RPCs only carry the message, which starts with ErrQuota.Error().
+var ErrQuota = errors.New("Quota exceeded")
+
+func (n *NameNode) runSetQuota(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runSetQuota %v %v\n", args.DPath, args.Quota)
+	/** the name quota of a directory bounds the number of files and
+	 * directories in its subtree, itself included, the space quota the
+	 * bytes of the files in its subtree times their replication factor,
+	 * as du counts them.
+	 * A quota of 0 removes it. Quotas are checked when a file or
+	 * directory is created or grows, not when one is moved. Lowering a
+	 * quota below the current usage only blocks further growth.
+	 * */
+	if args.Quota < 0 {
+		return errors.New("Invalid quota")
+	}
+	op := opSetNsQuota
+	if args.CommandType == config.SetSpaceQuota {
+		op = opSetSpaceQuota
+	}
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	node := n.root.lookup(args.DPath)
+	if node == nil {
+		return ErrNotFound
+	}
+	if !node.IsDir {
+		return errors.New("Not a directory")
+	}
+	return n.logAndApply(&journalEntry{Op: op, Path: cleanPath(args.DPath), Quota: args.Quota})
+}
+
+// checkQuota tells whether adding names files or directories and space
+// bytes of replicas at dfs path p keeps every directory above p within
+// its quotas. The caller should hold n.nsMu.
+func (n *NameNode) checkQuota(p string, names, space int64) error {
+	cur, dir := n.root, "/"
+	parents := splitPath(path.Dir(cleanPath(p)))
+	for i := 0; cur != nil && cur.IsDir; i++ {
+		if cur.NsQuota > 0 && names > 0 {
+			if used := countNames(cur); used+names > cur.NsQuota {
+				return fmt.Errorf("%v: %v has a name quota of %v, %v used",
+					ErrQuota, dir, cur.NsQuota, used)
+			}
+		}
+		if cur.SpaceQuota > 0 && space > 0 {
+			if used := n.diskUsage(dir, cur).Replicated; used+space > cur.SpaceQuota {
+				return fmt.Errorf("%v: %v has a space quota of %v bytes, %v used",
+					ErrQuota, dir, cur.SpaceQuota, used)
+			}
+		}
+		if i == len(parents) {
+			break
+		}
+		cur, dir = cur.Children[parents[i]], path.Join(dir, parents[i])
+	}
+	return nil
+}
+
+// countNames returns the number of files and directories in the subtree
+// of node, itself included
+func countNames(node *inode) int64 {
+	var res int64
+	node.walk("/", func(string, *inode) { res++ })
+	return res
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+// wantQuotaErr fails the test unless running args on n exceeds a quota
+func wantQuotaErr(t *testing.T, n *NameNode, args *CommandArgs) {
+	t.Helper()
+	err := n.RunCommand(args, &CommandReply{})
+	if err == nil || !strings.HasPrefix(err.Error(), ErrQuota.Error()) {
+		t.Errorf("command %v on %v %v: %v, want a quota error", args.CommandType,
+			args.DPath, args.FileName, err)
+	}
+}
+
+func TestNameQuota(t *testing.T) {
+	c := newFakeCluster(t, 3)
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Mkdir, DPath: "/q"})
+	runCommand(t, c.n, &CommandArgs{CommandType: config.SetQuota, DPath: "/q", Quota: 4})
+	// /q itself, b and a.txt fit, the 4th name goes in b
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Mkdir, DPath: "/q/b"})
+	c.write("/q", "a.txt", 10, 0, 1)
+	wantQuotaErr(t, c.n, &CommandArgs{CommandType: config.MkdirP, DPath: "/q/b/x/y"})
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Touch, DPaths: []string{"/q/b/c"}})
+	wantQuotaErr(t, c.n, &CommandArgs{CommandType: config.CopyFromLocal, DPath: "/q/b",
+		FileName: "d.txt", FileSize: 10})
+	wantQuotaErr(t, c.n, &CommandArgs{CommandType: config.Mkdir, DPath: "/q/e"})
+	touch := runCommand(t, c.n, &CommandArgs{CommandType: config.Touch,
+		DPaths: []string{"/q/f"}})
+	if !strings.HasPrefix(touch.Errors["/q/f"], ErrQuota.Error()) {
+		t.Errorf("touch past the name quota: %q", touch.Errors["/q/f"])
+	}
+	// the quota is kept in the edit log
+	wantQuotaErr(t, NewNameNode(), &CommandArgs{CommandType: config.Mkdir, DPath: "/q/e"})
+	count := runCommand(t, c.n, &CommandArgs{CommandType: config.Count, DPath: "/q"}).Counts
+	if len(count) != 1 || count[0].Dirs != 2 || count[0].Files != 2 || count[0].NsQuota != 4 {
+		t.Errorf("count -q /q = %+v, want 2 dirs, 2 files and a quota of 4", count)
+	}
+	// 0 removes the quota
+	runCommand(t, c.n, &CommandArgs{CommandType: config.SetQuota, DPath: "/q"})
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Mkdir, DPath: "/q/e"})
+}
+
+func TestSpaceQuota(t *testing.T) {
+	c := newFakeCluster(t, 3)
+	runCommand(t, c.n, &CommandArgs{CommandType: config.MkdirP, DPath: "/s/sub"})
+	runCommand(t, c.n, &CommandArgs{CommandType: config.SetSpaceQuota, DPath: "/s",
+		Quota: 3000})
+	// replicas count against the quota
+	c.write("/s/sub", "a.bin", 1000, 0, 2)
+	wantQuotaErr(t, c.n, &CommandArgs{CommandType: config.CopyFromLocal, DPath: "/s/sub",
+		FileName: "b.bin", FileSize: 600, Replication: 2})
+	c.write("/s", "b.bin", 600, 0, 1)
+	wantQuotaErr(t, c.n, &CommandArgs{CommandType: config.AppendToFile,
+		DPath: "/s/b.bin", FileSize: 401})
+	wantQuotaErr(t, c.n, &CommandArgs{CommandType: config.Cp,
+		DPaths: []string{"/s/b.bin", "/s/sub/c.bin"}})
+	app := runCommand(t, c.n, &CommandArgs{CommandType: config.AppendToFile,
+		DPath: "/s/b.bin", FileSize: 400})
+	for blk, addrs := range app.BlkToDataNodes {
+		for _, addr := range addrs {
+			c.store(addr, blk, 400)
+		}
+	}
+	// outside the directory there is no limit
+	c.write("/", "big.bin", 5000, 0, 3)
+	count := runCommand(t, c.n, &CommandArgs{CommandType: config.Count, DPath: "/s"}).Counts[0]
+	if count.SpaceQuota != 3000 || count.SpaceConsumed != 3000 || count.Bytes != 2000 {
+		t.Errorf("count -q /s = %+v, want 3000 of 3000 bytes used by 2000 bytes", count)
+	}
+	if err := c.n.RunCommand(&CommandArgs{CommandType: config.SetSpaceQuota,
+		DPath: "/big.bin", Quota: 1}, &CommandReply{}); err == nil {
+		t.Errorf("setSpaceQuota on a file succeeded")
+	}
+}
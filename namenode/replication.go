@@ -0,0 +1,135 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/utils"
+)
+
+// scheduleReplication finds under-replicated blocks held by the datanode
+// at addr, and picks a target datanode lacking each of them with the
+// placement policy. The result
+// maps block id to target address and is sent back in the heartbeat reply,
+// so the datanode at addr acts as the replication source.
+// The caller should hold n.mu.
+func (n *NameNode) scheduleReplication(addr string) map[string]string {
+	res := make(map[string]string)
+	sid, ok := n.Addr2SID[addr]
+	if !ok {
+		return res
+	}
+	now := utils.GetCurrentTimeInMs()
+	live := n.liveNodes()
+	for blk, nodes := range n.BlkToDatanodes {
+		// replicas on decommissioning datanodes don't count
+		if n.inServiceReplicas(nodes) >= n.replicationOf(blk) || !contains(nodes, sid) {
+			continue
+		}
+		// a replication scheduled recently may not be reported yet
+		if t, ok := n.PendingRep[blk]; ok &&
+			now-t < int64(config.RepPendingInSec)*1000 {
+			continue
+		}
+		holders := make([]string, 0, len(nodes))
+		for _, s := range nodes {
+			holders = append(holders, n.SID2Addr[s])
+		}
+		targets, err := n.placement.Choose(1, live, holders)
+		if err != nil {
+			continue // every live datanode already holds it
+		}
+		target := targets[0]
+		logger.Infof("block %v has %v replicas, replicate from %v to %v\n",
+			blk, len(nodes), addr, target)
+		n.PendingRep[blk] = now
+		res[blk] = target
+	}
+	return res
+}
+
+// scheduleRemoval finds over-replicated blocks held by the datanode at
+// addr. Replicas beyond the replication factor in BlkToDatanodes are
+// removed, so only datanodes at those positions are told to delete the
+// block. For a block moved by the balancer, the source replica is removed
+// instead. The replica is dropped from BlkToDatanodes right away.
+// The caller should hold n.mu.
+func (n *NameNode) scheduleRemoval(addr string) []string {
+	res := make([]string, 0)
+	sid, ok := n.Addr2SID[addr]
+	if !ok {
+		return res
+	}
+	for blk, nodes := range n.BlkToDatanodes {
+		rep := n.replicationOf(blk)
+		if len(nodes) <= rep {
+			continue
+		}
+		if mv, ok := n.Moves[blk]; ok {
+			// the source goes only after the target holds the block
+			if mv.Src != sid || !contains(nodes, mv.Dst) {
+				continue
+			}
+			delete(n.Moves, blk)
+		} else if !contains(nodes[rep:], sid) {
+			continue
+		} else if n.inService(addr) && n.inServiceReplicas(nodes) <= rep {
+			continue // the extra replica is on a decommissioning datanode
+		}
+		logger.Infof("block %v has %v replicas, remove it from %v\n",
+			blk, len(nodes), addr)
+		n.BlkToDatanodes[blk] = remove(nodes, sid)
+		res = append(res, blk)
+	}
+	return res
+}
+
+// replicationOf returns the target replication factor of a block,
+// the caller should hold n.mu
+func (n *NameNode) replicationOf(blk string) int {
+	if rep, ok := n.BlkRep[blk]; ok && rep > 0 {
+		return rep
+	}
+	return config.ReplicationFactor
+}
+
+// setBlkRep records the replication factor of file p for each of its
+// blocks, it is called after p is written or its replication changes
+func (n *NameNode) setBlkRep(p string) {
+	node := n.root.lookup(p)
+	if node == nil || node.IsDir {
+		return
+	}
+	n.mu.Lock()
+	for _, blk := range node.BlkList {
+		n.BlkRep[blk] = node.replication()
+	}
+	n.mu.Unlock()
+}
+
+// indexBlkRep rebuilds BlkRep from the namespace tree
+func (n *NameNode) indexBlkRep() {
+	n.nsMu.Lock()
+	defer n.nsMu.Unlock()
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.BlkRep = make(map[string]int)
+	n.root.walk("/", func(p string, node *inode) {
+		for _, blk := range node.BlkList {
+			n.BlkRep[blk] = node.replication()
+		}
+	})
+}
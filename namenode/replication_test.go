@@ -0,0 +1,118 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+func TestReplicateAfterNodeDies(t *testing.T) {
+	c := newFakeCluster(t, config.ReplicationFactor+1)
+	reply := c.write("/", "a.txt", 100, 0, 0)
+	blk := reply.BlkList[0]
+	holders := c.holders(blk)
+	if len(holders) != config.ReplicationFactor {
+		t.Fatalf("%v is on %v, want %v datanodes", blk, holders,
+			config.ReplicationFactor)
+	}
+	dead, survivors := holders[0], holders[1:]
+	survivor := survivors[0]
+	last := c.n.LastHeartBeat[dead]
+	for _, addr := range c.addrs {
+		if addr != dead {
+			c.n.LastHeartBeat[addr] = last + 1<<20
+		}
+	}
+	c.n.checkDeadNodes(last + 1<<20)
+	if got := c.holders(blk); len(got) != len(survivors) {
+		t.Fatalf("%v is on %v after %v died, want %v", blk, got, dead, survivors)
+	}
+	hb := c.heartbeat(survivor)
+	target, ok := hb.RepBlkToNodes[blk]
+	if !ok || target == dead || contains(survivors, target) {
+		t.Fatalf("heartbeat of %v replicates %v, want %v to another datanode",
+			survivor, hb.RepBlkToNodes, blk)
+	}
+	if got := c.holders(blk); len(got) != len(holders) || !contains(got, target) {
+		t.Errorf("%v is on %v after replication, want %v and %v", blk, got,
+			survivors, target)
+	}
+	// the block has its replicas again, nothing more is scheduled
+	if hb := c.heartbeat(target); len(hb.RepBlkToNodes) != 0 {
+		t.Errorf("replications %v scheduled for a healthy block", hb.RepBlkToNodes)
+	}
+}
+
+func TestSetRep(t *testing.T) {
+	defer func(sec int) { config.RepPendingInSec = sec }(config.RepPendingInSec)
+	config.RepPendingInSec = 0 // replicas are reported right away here
+	c := newFakeCluster(t, 4)
+	// copyFromLocal -rep places that many replicas and records the factor
+	reply := c.write("/", "a.txt", 100, 0, 2)
+	blk := reply.BlkList[0]
+	if got := c.holders(blk); len(got) != 2 {
+		t.Fatalf("%v written with 2 replicas is on %v", blk, got)
+	}
+	repOf := func(n *NameNode) int {
+		stat := runCommand(t, n, &CommandArgs{CommandType: config.Stat,
+			DPaths: []string{"/a.txt"}})
+		return stat.Stats[0].Replication
+	}
+	if got := repOf(c.n); got != 2 {
+		t.Errorf("stat reports replication %v, want 2", got)
+	}
+	// heartbeats bring the replicas to the new factor, up then down
+	for _, rep := range []int{4, 1} {
+		runCommand(t, c.n, &CommandArgs{CommandType: config.SetRep, DPath: "/a.txt",
+			Replication: rep})
+		for round := 0; round < rep+1; round++ {
+			for _, addr := range c.addrs {
+				c.heartbeat(addr)
+			}
+		}
+		if got := c.holders(blk); len(got) != rep {
+			t.Errorf("%v is on %v after setrep %v", blk, got, rep)
+		}
+		used := 0
+		for _, addr := range c.addrs {
+			if _, ok := c.blks[addr][blk]; ok {
+				used++
+			}
+		}
+		if used != rep {
+			t.Errorf("%v datanodes hold %v after setrep %v", used, blk, rep)
+		}
+	}
+	// the factor is journaled
+	restarted := NewNameNode()
+	if got := repOf(restarted); got != 1 {
+		t.Errorf("replication is %v after restart, want 1", got)
+	}
+	if got := restarted.replicationOf(blk); got != 1 {
+		t.Errorf("replication of %v is %v after restart, want 1", blk, got)
+	}
+	// a factor the live datanodes cannot satisfy is refused
+	for _, rep := range []int{5, 0, -1} {
+		args := CommandArgs{CommandType: config.SetRep, DPath: "/a.txt", Replication: rep}
+		if err := c.n.RunCommand(&args, &CommandReply{}); err == nil {
+			t.Errorf("setrep %v on 4 datanodes succeeded", rep)
+		}
+	}
+	if got := repOf(c.n); got != 1 {
+		t.Errorf("replication is %v after refused setreps, want 1", got)
+	}
+}
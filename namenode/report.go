@@ -0,0 +1,142 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"sort"
+)
+
+// states of a datanode
+const (
+	NodeLive            = "live"
+	NodeDecommissioning = "decommissioning"
+	NodeDecommissioned  = "decommissioned"
+	NodeDead            = "dead"
+)
+
+// AdminArgs stores the arguments of the cluster admin RPCs
+type AdminArgs struct {
+	Token string // see config.AuthToken
+}
+
+// NodeStatus stores the storage of a datanode in its last heartbeat
+type NodeStatus struct {
+	Addr          string
+	HostName      string
+	StorageID     string
+	State         string  // see NodeLive
+	Capacity      uint64  // in bytes
+	Used          uint64  // in bytes
+	Remaining     uint64  // in bytes
+	FracInUse     float64 // fraction in use
+	LastHeartBeat int64   // time of the last heartbeat in ms
+	DeadSince     int64   // time in ms a dead datanode was declared dead
+}
+
+// ClusterStatusReply stores the storage of the live datanodes, one
+// NodeStatus per datanode sorted by address, and their sums
+type ClusterStatusReply struct {
+	Capacity  uint64 // in bytes
+	Used      uint64 // in bytes
+	Remaining uint64 // in bytes
+	Nodes     []NodeStatus
+}
+
+// ClusterStatus reports the storage of the cluster as told by the last
+// heartbeat of each live datanode
+func (n *NameNode) ClusterStatus(args *AdminArgs, reply *ClusterStatusReply) error {
+	if err := CheckToken("ClusterStatus", args.Token); err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	reply.Nodes = make([]NodeStatus, 0, len(n.Addr2SID))
+	for addr := range n.Addr2SID {
+		node := n.nodeStatus(addr)
+		reply.Capacity += node.Capacity
+		reply.Used += node.Used
+		reply.Remaining += node.Remaining
+		reply.Nodes = append(reply.Nodes, node)
+	}
+	sort.Slice(reply.Nodes, func(i, j int) bool {
+		return reply.Nodes[i].Addr < reply.Nodes[j].Addr
+	})
+	return nil
+}
+
+// ReportNodesReply stores the status of every datanode namenode knows of,
+// ordered by state as listed above then by address
+type ReportNodesReply struct {
+	Nodes []NodeStatus
+}
+
+// ReportNodes reports the state and storage of the datanodes. Namenode
+// forgets the storage of dead datanodes, only their address and time of
+// death are reported.
+func (n *NameNode) ReportNodes(args *AdminArgs, reply *ReportNodesReply) error {
+	if err := CheckToken("ReportNodes", args.Token); err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	reply.Nodes = n.reportNodes()
+	return nil
+}
+
+// reportNodes returns the status of every datanode ordered as
+// ReportNodes does, the caller holds mu
+func (n *NameNode) reportNodes() []NodeStatus {
+	nodes := make([]NodeStatus, 0, len(n.Addr2SID)+len(n.DeadNodes))
+	for addr := range n.Addr2SID {
+		nodes = append(nodes, n.nodeStatus(addr))
+	}
+	for addr, since := range n.DeadNodes {
+		// a retired datanode is expected to be gone
+		state := NodeDead
+		if n.Decommission[addr] == NodeDecommissioned {
+			state = NodeDecommissioned
+		}
+		nodes = append(nodes, NodeStatus{Addr: addr, State: state,
+			DeadSince: since})
+	}
+	rank := map[string]int{NodeLive: 0, NodeDecommissioning: 1, NodeDecommissioned: 2,
+		NodeDead: 3}
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if a.State != b.State {
+			return rank[a.State] < rank[b.State]
+		}
+		return a.Addr < b.Addr
+	})
+	return nodes
+}
+
+// nodeStatus returns the status of the live datanode at addr, a datanode
+// which hasn't sent a heartbeat yet has no storage. The caller holds mu.
+func (n *NameNode) nodeStatus(addr string) NodeStatus {
+	stat := n.NodeStats[addr]
+	node := NodeStatus{Addr: addr, HostName: stat.HostName, StorageID: n.Addr2SID[addr],
+		State: NodeLive, Capacity: stat.TotalCapacity, FracInUse: stat.FracInUse,
+		LastHeartBeat: n.LastHeartBeat[addr]}
+	node.Used = uint64(float64(stat.TotalCapacity) * stat.FracInUse)
+	if node.Used > node.Capacity {
+		node.Used = node.Capacity
+	}
+	node.Remaining = node.Capacity - node.Used
+	if state, ok := n.Decommission[addr]; ok {
+		node.State = state
+	}
+	return node
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+func TestClusterStatus(t *testing.T) {
+	n := newTestNameNode(t)
+	a, b, dead := "127.0.0.1:11171", "127.0.0.1:11170", "127.0.0.1:11172"
+	sidA := addDataNode(t, n, a, 1000, 0.25)
+	sidB := addDataNode(t, n, b, 3000, 0.5)
+	addDataNode(t, n, dead, 5000, 0.1)
+	// the latest heartbeat counts
+	hb := HeartBeatArgs{HostName: "host-a", Addr: a, TotalCapacity: 2000, FracInUse: 0.75}
+	if err := n.HeartBeat(&hb, &HeartBeatReply{}); err != nil {
+		t.Fatal(err)
+	}
+	now := n.LastHeartBeat[a] + int64(config.DeadNodeInSec)*1000
+	n.LastHeartBeat[a], n.LastHeartBeat[b] = now, now
+	n.checkDeadNodes(now + 1)
+	reply := ClusterStatusReply{}
+	if err := n.ClusterStatus(&AdminArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Capacity != 5000 || reply.Used != 3000 || reply.Remaining != 2000 {
+		t.Errorf("cluster has %v bytes, %v used, %v remaining, want 5000, 3000, 2000",
+			reply.Capacity, reply.Used, reply.Remaining)
+	}
+	want := []NodeStatus{
+		{Addr: b, HostName: b, StorageID: sidB, State: NodeLive, Capacity: 3000,
+			Used: 1500, Remaining: 1500, FracInUse: 0.5, LastHeartBeat: now},
+		{Addr: a, HostName: "host-a", StorageID: sidA, State: NodeLive, Capacity: 2000,
+			Used: 1500, Remaining: 500, FracInUse: 0.75, LastHeartBeat: now},
+	}
+	if len(reply.Nodes) != len(want) {
+		t.Fatalf("cluster status lists %+v, want %+v", reply.Nodes, want)
+	}
+	for i := range want {
+		if reply.Nodes[i] != want[i] {
+			t.Errorf("datanode %v is %+v, want %+v", i, reply.Nodes[i], want[i])
+		}
+	}
+}
+
+func TestReportNodes(t *testing.T) {
+	n := newTestNameNode(t)
+	addrs := []string{"127.0.0.1:11172", "127.0.0.1:11170", "127.0.0.1:11173",
+		"127.0.0.1:11171"}
+	sids := make(map[string]string)
+	for i, addr := range addrs {
+		sids[addr] = addDataNode(t, n, addr, uint64(1000*(i+1)), 0.5)
+	}
+	// 11173 and 11170 go silent
+	now := n.LastHeartBeat[addrs[0]] + int64(config.DeadNodeInSec)*1000
+	n.LastHeartBeat[addrs[0]], n.LastHeartBeat[addrs[3]] = now, now
+	n.checkDeadNodes(now + 1)
+	reply := ReportNodesReply{}
+	if err := n.ReportNodes(&AdminArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		addr, state string
+		capacity    uint64
+	}{
+		{"127.0.0.1:11171", NodeLive, 4000},
+		{"127.0.0.1:11172", NodeLive, 1000},
+		{"127.0.0.1:11170", NodeDead, 0},
+		{"127.0.0.1:11173", NodeDead, 0},
+	}
+	if len(reply.Nodes) != len(want) {
+		t.Fatalf("report lists %+v, want %v datanodes", reply.Nodes, len(want))
+	}
+	for i, w := range want {
+		node := reply.Nodes[i]
+		if node.Addr != w.addr || node.State != w.state || node.Capacity != w.capacity {
+			t.Errorf("row %v is %+v, want %v %v with %v bytes", i, node, w.addr,
+				w.state, w.capacity)
+		}
+		if w.state == NodeLive && (node.StorageID != sids[w.addr] ||
+			node.LastHeartBeat != now || node.Used != w.capacity/2) {
+			t.Errorf("live datanode %v is %+v, want storage id %v, heartbeat at %v",
+				w.addr, node, sids[w.addr], now)
+		}
+		if w.state == NodeDead && node.DeadSince != now+1 {
+			t.Errorf("dead datanode %v died at %v, want %v", w.addr, node.DeadSince, now+1)
+		}
+	}
+}
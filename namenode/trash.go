@@ -0,0 +1,146 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/logger"
+	"github.com/WineChord/gdfs/utils"
+)
+
+/** With config.TrashInSec set, rm moves files into the trash of the user
+ * instead of deleting them. Each rm command makes a checkpoint directory
+ * config.TrashDir/<user>/<time of rm in ms>, where removed files keep their
+ * path, e.g. /a/b.txt removed at 1600000000000 by alice becomes
+ * /.Trash/alice/1600000000000/a/b.txt. Blocks stay untouched until
+ * purgeTrashPeriodically deletes checkpoints older than TrashInSec, or the
+ * user runs expunge.
+ * */
+
+// defaultUser owns the trash of commands not naming a user
+const defaultUser = "default"
+
+// trashUser returns the user owning the trash of args
+func trashUser(args *CommandArgs) string {
+	// a user name is a single path element
+	user := strings.ReplaceAll(args.User, "/", "_")
+	if user == "" || user == "." || user == ".." {
+		return defaultUser
+	}
+	return user
+}
+
+// inTrash tells whether dfs path p is inside the trash
+func inTrash(p string) bool {
+	p, dir := cleanPath(p), cleanPath(config.TrashDir)
+	return p == dir || strings.HasPrefix(p, dir+"/")
+}
+
+// moveToTrash moves file p into checkpoint stamp of the trash of user and
+// returns its new path, the caller should hold n.nsMu
+func (n *NameNode) moveToTrash(p, user string, stamp int64) (string, error) {
+	if _, err := n.lookupFile(p); err != nil {
+		return "", err
+	}
+	target := path.Join(config.TrashDir, user, strconv.FormatInt(stamp, 10), cleanPath(p))
+	if n.root.lookup(path.Dir(target)) == nil {
+		err := n.logAndApply(&journalEntry{Op: opMkdirP, Path: cleanPath(path.Dir(target))})
+		if err != nil {
+			return "", err
+		}
+	}
+	return target, n.moveEntry(p, target)
+}
+
+func (n *NameNode) runExpunge(args *CommandArgs, reply *CommandReply) error {
+	logger.Debugf("inside runExpunge\n")
+	num := n.purgeTrash(trashUser(args), utils.GetCurrentTimeInMs())
+	reply.Result = fmt.Sprintf("expunged %v trash checkpoints", num)
+	return nil
+}
+
+// purgeTrash deletes the trash checkpoints of user, of every user if it
+// is empty, made before time before in ms. It returns the number of
+// checkpoints deleted.
+func (n *NameNode) purgeTrash(user string, before int64) int {
+	n.nsMu.Lock()
+	var expired []string
+	trash := n.root.lookup(config.TrashDir)
+	if trash != nil && trash.IsDir {
+		for name, dir := range trash.Children {
+			if (user != "" && name != user) || !dir.IsDir {
+				continue
+			}
+			for stamp := range dir.Children {
+				t, err := strconv.ParseInt(stamp, 10, 64)
+				if err == nil && t <= before {
+					expired = append(expired, path.Join(config.TrashDir, name, stamp))
+				}
+			}
+		}
+	}
+	n.nsMu.Unlock()
+	num := 0
+	for _, p := range expired {
+		if err := n.removeTree(p); err != nil {
+			logger.Errorf("error when purging trash %v: %v\n", p, err)
+			continue
+		}
+		num++
+	}
+	return num
+}
+
+// purgeTrashPeriodically deletes trash checkpoints older than
+// config.TrashInSec every config.TrashCheckInSec
+func (n *NameNode) purgeTrashPeriodically() {
+	for n.sleep(config.TrashCheckInSec) {
+		if config.TrashInSec > 0 {
+			n.purgeTrash("", utils.GetCurrentTimeInMs()-int64(config.TrashInSec)*1000)
+		}
+	}
+}
+
+// removeTree deletes dfs directory p with everything below it, and the
+// blocks of the files removed
+func (n *NameNode) removeTree(p string) error {
+	n.nsMu.Lock()
+	node := n.root.lookup(p)
+	if node == nil {
+		n.nsMu.Unlock()
+		return ErrNotFound
+	}
+	if !node.IsDir {
+		n.nsMu.Unlock()
+		return errors.New("Not a directory")
+	}
+	var blkList []string
+	node.walk(p, func(_ string, node *inode) {
+		blkList = append(blkList, node.BlkList...)
+	})
+	err := n.logAndApply(&journalEntry{Op: opDeleteDir, Path: cleanPath(p)})
+	n.nsMu.Unlock()
+	if err != nil {
+		return err
+	}
+	n.dropBlks(blkList)
+	return nil
+}
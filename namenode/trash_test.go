@@ -0,0 +1,86 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+func TestTrash(t *testing.T) {
+	defer func(trash int) { config.TrashInSec = trash }(config.TrashInSec)
+	config.TrashInSec = 3600
+	c := newFakeCluster(t, 3)
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Mkdir, DPath: "/a"})
+	blk := c.write("/a", "b.txt", 100, 0, 0).BlkList[0]
+	gone := c.write("/a", "gone.txt", 100, 0, 0).BlkList[0]
+	bobs := c.write("/", "bob.txt", 100, 0, 0).BlkList[0]
+	rm := runCommand(t, c.n, &CommandArgs{CommandType: config.Rm,
+		DPaths: []string{"/a/b.txt"}, User: "alice"})
+	trashed := rm.Trashed["/a/b.txt"]
+	prefix := path.Join(config.TrashDir, "alice") + "/"
+	if !strings.HasPrefix(trashed, prefix) || !strings.HasSuffix(trashed, "/a/b.txt") {
+		t.Fatalf("rm moved /a/b.txt to %q, want it in %v<stamp>/a/b.txt", trashed, prefix)
+	}
+	stamp, err := strconv.ParseInt(strings.Split(trashed[len(prefix):], "/")[0], 10, 64)
+	if err != nil {
+		t.Fatalf("trash checkpoint of %v: %v", trashed, err)
+	}
+	if _, err := c.n.getFile("/a/b.txt"); err == nil {
+		t.Errorf("/a/b.txt is still there after rm")
+	}
+	if file, err := c.n.getFile(trashed); err != nil || file.BlkList[0] != blk {
+		t.Errorf("%v in trash: %v", trashed, err)
+	}
+	if len(c.holders(blk)) == 0 {
+		t.Errorf("blocks of a trashed file are dropped")
+	}
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Rm,
+		DPaths: []string{"/a/gone.txt"}, User: "alice", SkipTrash: true})
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Rm,
+		DPaths: []string{"/bob.txt"}, User: "bob"})
+	if len(c.holders(gone)) != 0 {
+		t.Errorf("rm -skipTrash kept the blocks of /a/gone.txt")
+	}
+	// checkpoints are purged once expired
+	if num := c.n.purgeTrash("", stamp-1); num != 0 {
+		t.Errorf("purged %v checkpoints made after the deadline", num)
+	}
+	if num := c.n.purgeTrash("alice", stamp); num != 1 {
+		t.Errorf("purged %v checkpoints of alice, want 1", num)
+	}
+	if _, err := c.n.getFile(trashed); err == nil || len(c.holders(blk)) != 0 {
+		t.Errorf("%v or its blocks are still there after purge", trashed)
+	}
+	if len(c.holders(bobs)) == 0 {
+		t.Errorf("purging the trash of alice dropped the blocks of bob")
+	}
+	ex := runCommand(t, c.n, &CommandArgs{CommandType: config.Expunge, User: "bob"})
+	if len(c.holders(bobs)) != 0 {
+		t.Errorf("expunge kept the blocks of bob: %v", ex.Result)
+	}
+	// without the trash rm deletes right away
+	config.TrashInSec = 0
+	blk = c.write("/a", "b.txt", 100, 0, 0).BlkList[0]
+	rm = runCommand(t, c.n, &CommandArgs{CommandType: config.Rm,
+		DPaths: []string{"/a/b.txt"}, User: "alice"})
+	if len(rm.Trashed) != 0 || len(c.holders(blk)) != 0 {
+		t.Errorf("rm with the trash disabled moved %v", rm.Trashed)
+	}
+}
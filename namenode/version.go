@@ -0,0 +1,86 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/logger"
+)
+
+// policies of config.VersionCheck
+const (
+	// VersionStrict rejects datanodes of another major or minor version
+	VersionStrict = "strict"
+	// VersionMajor rejects datanodes of another major version
+	VersionMajor = "major"
+	// VersionOff accepts every datanode
+	VersionOff = "off"
+)
+
+// parseVersion returns the major and minor numbers of a version
+// major.minor[.patch], a leading v is allowed
+func parseVersion(version string) (int, int, error) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("Invalid version %q", version)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid version %q", version)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid version %q", version)
+	}
+	return major, minor, nil
+}
+
+// checkVersion tells whether a datanode running version may join under
+// config.VersionCheck, mismatches let in are logged. A datanode sending
+// no version predates version checks, it only joins with a lenient policy.
+func checkVersion(addr, version string) error {
+	strict := config.VersionCheck == VersionStrict
+	if version == "" {
+		if strict {
+			return fmt.Errorf("Datanode sends no version, namenode runs %v", config.Version)
+		}
+		logger.Warnf("datanode %v sends no version, namenode runs %v\n", addr, config.Version)
+		return nil
+	}
+	major, minor, err := parseVersion(version)
+	if err != nil {
+		return err
+	}
+	nnMajor, nnMinor, err := parseVersion(config.Version)
+	if err != nil {
+		return err
+	}
+	if major == nnMajor && minor == nnMinor {
+		return nil
+	}
+	if config.VersionCheck != VersionOff && (major != nnMajor || strict) {
+		logger.Warnf("datanode %v runs version %v, namenode runs %v, refuse to join\n",
+			addr, version, config.Version)
+		return fmt.Errorf("Incompatible version %v, namenode runs %v", version,
+			config.Version)
+	}
+	logger.Warnf("datanode %v runs version %v, namenode runs %v\n", addr, version,
+		config.Version)
+	return nil
+}
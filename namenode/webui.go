@@ -0,0 +1,245 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/WineChord/gdfs/logger"
+)
+
+/** The web UI is a read-only view of the namenode served at
+ * config.NameNodeWebPort: / lists the datanodes, /browse?path=<dir> the
+ * children of a directory and /file?path=<file> the blocks of a file with
+ * their replicas. Pages are rendered from the namenode state on each
+ * request.
+ * */
+
+var webTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"bytes": formatBytes,
+	"base":  path.Base,
+	"time": func(ms int64) string {
+		if ms == 0 {
+			return "-"
+		}
+		return time.Unix(0, ms*int64(time.Millisecond)).Format("2006-01-02 15:04:05")
+	},
+	"percent": func(frac float64) string { return fmt.Sprintf("%.1f%%", frac*100) },
+}).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>gdfs {{.}}</title>
+<style>body{font-family:sans-serif}table{border-collapse:collapse}
+td,th{border:1px solid #ccc;padding:2px 8px;text-align:left}</style>
+</head><body><p><a href="/">Datanodes</a> | <a href="/browse?path=/">Browse</a></p>
+{{end}}
+{{define "nodes"}}{{template "header" "datanodes"}}
+<h1>Datanodes</h1>
+<p>Capacity {{bytes .Capacity}}, used {{bytes .Used}}, remaining {{bytes .Remaining}}</p>
+<table><tr><th>Address</th><th>Host</th><th>State</th><th>Capacity</th><th>Used</th>
+<th>Remaining</th><th>Use</th><th>Blocks</th><th>Last heartbeat</th></tr>
+{{range .Nodes}}<tr><td>{{.Addr}}</td><td>{{.HostName}}</td><td>{{.State}}</td>
+{{if .DeadSince}}<td colspan="5"></td><td>dead since {{time .DeadSince}}</td>
+{{else}}<td>{{bytes .Capacity}}</td><td>{{bytes .Used}}</td><td>{{bytes .Remaining}}</td>
+<td>{{percent .FracInUse}}</td><td>{{.Blks}}</td><td>{{time .LastHeartBeat}}</td>{{end}}</tr>
+{{end}}</table></body></html>
+{{end}}
+{{define "browse"}}{{template "header" .Path}}
+<h1>{{.Path}}</h1>
+{{if .Parent}}<p><a href="/browse?path={{.Parent}}">..</a></p>{{end}}
+<table><tr><th>Name</th><th>Size</th><th>Replication</th><th>Blocks</th><th>Modified</th></tr>
+{{range .Entries}}<tr>{{if .IsDir}}<td><a href="/browse?path={{.Path}}">{{base .Path}}/</a></td>
+<td></td><td></td><td></td>{{else}}<td><a href="/file?path={{.Path}}">{{base .Path}}</a></td>
+<td>{{bytes .Size}}</td><td>{{.Replication}}</td><td>{{.NumBlks}}</td>{{end}}
+<td>{{time .ModTime}}</td></tr>
+{{end}}</table></body></html>
+{{end}}
+{{define "file"}}{{template "header" .Stat.Path}}
+<h1>{{.Stat.Path}}</h1>
+<p>Size {{bytes .Stat.Size}}, block size {{bytes .Stat.BlkSize}}, replication
+{{.Stat.Replication}}, modified {{time .Stat.ModTime}}</p>
+<table><tr><th>Block</th><th>Length</th><th>Generation stamp</th><th>Replicas</th></tr>
+{{range .Blks}}<tr><td>{{.ID}}</td><td>{{bytes .Length}}</td><td>{{.GenStamp}}</td>
+<td>{{range .Replicas}}{{.Addr}}{{if .Corrupt}} (corrupt){{end}}<br>{{else}}missing{{end}}</td></tr>
+{{end}}</table></body></html>
+{{end}}
+`))
+
+// webNode is a datanode row of the web UI
+type webNode struct {
+	NodeStatus
+	Blks int // number of replicas it holds
+}
+
+// webBlk is a block row of the file page of the web UI
+type webBlk struct {
+	ID       string
+	Length   int64
+	GenStamp int64
+	Replicas []webReplica
+}
+
+type webReplica struct {
+	Addr    string
+	Corrupt bool // reported corrupt by a block scanner, see CorruptBlks
+}
+
+// webUI returns the handler of the web UI
+func (n *NameNode) webUI() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		n.serveNodes(w)
+	})
+	mux.HandleFunc("/browse", n.serveBrowse)
+	mux.HandleFunc("/file", n.serveFile)
+	return mux
+}
+
+// render writes template name executed on data
+func render(w http.ResponseWriter, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := webTemplates.ExecuteTemplate(w, name, data); err != nil {
+		logger.Errorf("error when rendering %v: %v\n", name, err)
+	}
+}
+
+func (n *NameNode) serveNodes(w http.ResponseWriter) {
+	n.mu.Lock()
+	blks := make(map[string]int)
+	for _, nodes := range n.BlkToDatanodes {
+		for _, sid := range nodes {
+			blks[n.SID2Addr[sid]]++
+		}
+	}
+	var page struct {
+		Capacity, Used, Remaining uint64
+		Nodes                     []webNode
+	}
+	for _, node := range n.reportNodes() {
+		if node.DeadSince == 0 {
+			page.Capacity += node.Capacity
+			page.Used += node.Used
+			page.Remaining += node.Remaining
+		}
+		page.Nodes = append(page.Nodes, webNode{NodeStatus: node, Blks: blks[node.Addr]})
+	}
+	n.mu.Unlock()
+	render(w, "nodes", page)
+}
+
+func (n *NameNode) serveBrowse(w http.ResponseWriter, r *http.Request) {
+	p := r.URL.Query().Get("path")
+	if p != "" {
+		if err := checkPath(p); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	p = cleanPath(p)
+	n.nsMu.Lock()
+	node := n.root.lookup(p)
+	if node == nil || !node.IsDir {
+		n.nsMu.Unlock()
+		http.Error(w, "No such directory", http.StatusNotFound)
+		return
+	}
+	var page struct {
+		Path, Parent string
+		Entries      []FileStat
+	}
+	page.Path = p
+	if p != "/" {
+		page.Parent = path.Dir(p)
+	}
+	for name, child := range node.Children {
+		page.Entries = append(page.Entries, n.statNode(path.Join(p, name), child))
+	}
+	n.nsMu.Unlock()
+	sort.Slice(page.Entries, func(i, j int) bool {
+		return page.Entries[i].Path < page.Entries[j].Path
+	})
+	render(w, "browse", page)
+}
+
+func (n *NameNode) serveFile(w http.ResponseWriter, r *http.Request) {
+	p := r.URL.Query().Get("path")
+	if p != "" {
+		if err := checkPath(p); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	p = cleanPath(p)
+	n.nsMu.Lock()
+	node := n.root.lookup(p)
+	if node == nil || node.IsDir {
+		n.nsMu.Unlock()
+		http.Error(w, "No such file", http.StatusNotFound)
+		return
+	}
+	var page struct {
+		Stat FileStat
+		Blks []webBlk
+	}
+	page.Stat = n.statNode(p, node)
+	n.mu.Lock()
+	for _, blk := range node.BlkList {
+		meta := n.BlkMeta[blk]
+		row := webBlk{ID: blk, Length: meta.Length, GenStamp: meta.GenStamp}
+		for _, sid := range n.BlkToDatanodes[blk] {
+			row.Replicas = append(row.Replicas, webReplica{Addr: n.SID2Addr[sid]})
+		}
+		// corrupt replicas are no longer in BlkToDatanodes
+		for _, sid := range n.CorruptBlks[blk] {
+			if addr, ok := n.SID2Addr[sid]; ok {
+				row.Replicas = append(row.Replicas, webReplica{Addr: addr, Corrupt: true})
+			}
+		}
+		page.Blks = append(page.Blks, row)
+	}
+	n.mu.Unlock()
+	n.nsMu.Unlock()
+	render(w, "file", page)
+}
+
+// formatBytes writes a size, an int64 or uint64, in the largest binary
+// unit it reaches
+func formatBytes(b interface{}) string {
+	var size float64
+	switch b := b.(type) {
+	case int64:
+		size = float64(b)
+	case uint64:
+		size = float64(b)
+	}
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+	i := 0
+	for size >= 1024 && i < len(units)-1 {
+		size /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%v B", size)
+	}
+	return fmt.Sprintf("%.2f %v", size, units[i])
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namenode
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+func TestWebUI(t *testing.T) {
+	c := newFakeCluster(t, 3)
+	runCommand(t, c.n, &CommandArgs{CommandType: config.Mkdir, DPath: "/d"})
+	file := c.write("/d", "a.bin", 30, 10, 2)
+	c.write("/", "b.bin", 2048, 0, 1)
+	corrupt := file.BlkList[1]
+	bad := c.holders(corrupt)[0]
+	err := c.n.ReportCorruptBlock(&ReportCorruptBlockArgs{Addr: bad,
+		BlkIDs: []string{corrupt}}, &ReportCorruptBlockReply{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(url string, code int, want ...string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		c.n.webUI().ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+		body := w.Body.String()
+		if w.Code != code {
+			t.Errorf("GET %v: status %v, want %v\n%s", url, w.Code, code, body)
+		}
+		for _, s := range want {
+			if !strings.Contains(body, s) {
+				t.Errorf("GET %v misses %q:\n%s", url, s, body)
+			}
+		}
+	}
+	// every replica is on a datanode of 1 GB
+	get("/", http.StatusOK, "<td>"+c.addrs[0]+"</td>", "<td>live</td>",
+		"<td>1.00 GB</td>", "Capacity 3.00 GB")
+	// paths are escaped in links
+	get("/browse?path=/", http.StatusOK, `<a href="/browse?path=%2fd">d/</a>`,
+		`<a href="/file?path=%2fb.bin">b.bin</a>`, "<td>2.00 KB</td>")
+	get("/browse?path=/d", http.StatusOK, `<a href="/browse?path=%2f">..</a>`,
+		`<a href="/file?path=%2fd%2fa.bin">a.bin</a>`, "<td>30 B</td>", "<td>2</td><td>3</td>")
+	want := []string{"<h1>/d/a.bin</h1>", bad + " (corrupt)"}
+	for _, blk := range file.BlkList {
+		want = append(want, blk)
+		for _, addr := range c.holders(blk) {
+			want = append(want, addr)
+		}
+	}
+	get("/file?path=/d/a.bin", http.StatusOK, want...)
+	get("/browse?path=/d/a.bin", http.StatusNotFound)
+	get("/file?path=/d", http.StatusNotFound)
+	get("/nope", http.StatusNotFound)
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"net"
+	"strings"
+)
+
+// JoinHostPort combines host and port into an address, IPv6 literals are
+// bracketed, e.g. [::1]:11170. The host may be bracketed already, IP
+// literals are written in their canonical form so that the same node
+// always has the same address.
+func JoinHostPort(host, port string) string {
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if ip := net.ParseIP(host); ip != nil {
+		host = ip.String()
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// SplitHostPort splits an address into host and port, the brackets of an
+// IPv6 host are removed
+func SplitHostPort(addr string) (host, port string, err error) {
+	return net.SplitHostPort(addr)
+}
+
+// NormalizeAddr returns addr as JoinHostPort writes it, e.g.
+// [0:0::1]:11170 becomes [::1]:11170
+func NormalizeAddr(addr string) (string, error) {
+	host, port, err := SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	return JoinHostPort(host, port), nil
+}
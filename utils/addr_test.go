@@ -0,0 +1,68 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestJoinHostPort(t *testing.T) {
+	for _, tc := range []struct {
+		host, want string
+	}{
+		{"192.168.0.101", "192.168.0.101:11170"},
+		{"thumm01", "thumm01:11170"},
+		{"::1", "[::1]:11170"},
+		{"[::1]", "[::1]:11170"},
+		{"2001:DB8:0:0::2", "[2001:db8::2]:11170"},
+	} {
+		addr := JoinHostPort(tc.host, "11170")
+		if addr != tc.want {
+			t.Errorf("JoinHostPort(%q) = %q, want %q", tc.host, addr, tc.want)
+		}
+		host, port, err := SplitHostPort(addr)
+		if err != nil || JoinHostPort(host, port) != addr {
+			t.Errorf("SplitHostPort(%q) = %q, %q, %v", addr, host, port, err)
+		}
+	}
+	if addr, err := NormalizeAddr("[0:0::1]:11170"); err != nil || addr != "[::1]:11170" {
+		t.Errorf("NormalizeAddr([0:0::1]:11170) = %q, %v", addr, err)
+	}
+	if _, err := NormalizeAddr("::1:11170"); err == nil {
+		t.Errorf("NormalizeAddr of an unbracketed IPv6 address succeeded")
+	}
+}
+
+func TestDialIPv6(t *testing.T) {
+	l, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("no IPv6 loopback: %v", err)
+	}
+	serveSleeper(t, l)
+	_, port, err := SplitHostPort(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc, err := Dial(JoinHostPort("::1", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+	if err := sleep(cc, "1ms", time.Second); err != nil {
+		t.Errorf("call over IPv6 = %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/crc32"
+)
+
+// checksum algorithms of config.ChecksumType
+const (
+	CRC32  = "crc32"  // IEEE polynomial, the type of blocks without one
+	CRC32C = "crc32c" // Castagnoli polynomial
+	SHA256 = "sha256"
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// NewHash returns a hash computing checksums of type typ, nil if typ is
+// unknown. An empty typ is CRC32.
+func NewHash(typ string) hash.Hash {
+	switch typ {
+	case CRC32, "":
+		return crc32.NewIEEE()
+	case CRC32C:
+		return crc32.New(castagnoli)
+	case SHA256:
+		return sha256.New()
+	}
+	return nil
+}
+
+// CheckChecksumType fails if typ is not a checksum type
+func CheckChecksumType(typ string) error {
+	if NewHash(typ) == nil {
+		return fmt.Errorf("Unknown checksum type %q", typ)
+	}
+	return nil
+}
+
+// ChecksumOf returns the checksum of type typ of data, nil if typ is
+// unknown
+func ChecksumOf(typ string, data []byte) Sum {
+	h := NewHash(typ)
+	if h == nil {
+		return nil
+	}
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// FileDigest is the digest of a whole file, the hex MD5 over the
+// checksums of its blocks in order
+func FileDigest(sums []Sum) string {
+	h := md5.New()
+	for _, sum := range sums {
+		h.Write(sum)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Sum is the checksum of block data, of the type it goes with. A crc is
+// 4 big endian bytes.
+type Sum []byte
+
+// Equal tells whether s and o are the same checksum
+func (s Sum) Equal(o Sum) bool {
+	return len(s) > 0 && bytes.Equal(s, o)
+}
+
+func (s Sum) String() string {
+	return hex.EncodeToString(s)
+}
+
+// MarshalJSON writes s in hex
+func (s Sum) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON reads s in hex, or a crc32 written as a number by
+// versions keeping checksums in uint32
+func (s *Sum) UnmarshalJSON(data []byte) error {
+	var crc uint32
+	if err := json.Unmarshal(data, &crc); err == nil {
+		*s = make(Sum, 4)
+		binary.BigEndian.PutUint32(*s, crc)
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	res, err := hex.DecodeString(str)
+	if err != nil {
+		return err
+	}
+	*s = nil
+	if len(res) > 0 {
+		*s = res
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"hash/crc32"
+	"testing"
+)
+
+func TestChecksumOf(t *testing.T) {
+	data := []byte("some data")
+	for typ, size := range map[string]int{"": 4, CRC32: 4, CRC32C: 4, SHA256: 32} {
+		sum := ChecksumOf(typ, data)
+		if len(sum) != size || !sum.Equal(ChecksumOf(typ, data)) {
+			t.Errorf("checksum %v of %q is %v, want %v bytes", typ, data, sum, size)
+		}
+		if sum.Equal(ChecksumOf(typ, []byte("other data"))) {
+			t.Errorf("checksum %v is the same for different data", typ)
+		}
+	}
+	if !ChecksumOf("", data).Equal(ChecksumOf(CRC32, data)) ||
+		ChecksumOf(CRC32, data).Equal(ChecksumOf(CRC32C, data)) {
+		t.Errorf("checksum types are mixed up")
+	}
+	if ChecksumOf("md5", data) != nil || CheckChecksumType("md5") == nil {
+		t.Errorf("md5 is taken for a checksum type")
+	}
+	if Sum(nil).Equal(nil) {
+		t.Errorf("empty checksums are equal")
+	}
+}
+
+func TestSumJSON(t *testing.T) {
+	sum := ChecksumOf(SHA256, []byte("some data"))
+	bytes, err := json.Marshal(sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Sum
+	if err := json.Unmarshal(bytes, &got); err != nil || !got.Equal(sum) {
+		t.Errorf("%s reads back as %v, %v, want %v", bytes, got, err, sum)
+	}
+	// crc32 checksums used to be written as numbers
+	crc := crc32.ChecksumIEEE([]byte("some data"))
+	bytes, _ = json.Marshal(crc)
+	if err := json.Unmarshal(bytes, &got); err != nil ||
+		!got.Equal(ChecksumOf(CRC32, []byte("some data"))) {
+		t.Errorf("legacy checksum %s reads as %v, %v", bytes, got, err)
+	}
+	if err := json.Unmarshal([]byte(`"not hex"`), &got); err == nil {
+		t.Errorf("reading a checksum out of %q succeeded", "not hex")
+	}
+}
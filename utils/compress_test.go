@@ -0,0 +1,39 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGzip(t *testing.T) {
+	for _, data := range [][]byte{nil, []byte("x"), bytes.Repeat([]byte("1.5\n2.5\n"), 1000)} {
+		zipped, err := Gzip(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Gunzip(zipped)
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("%v bytes read back as %v bytes, %v", len(data), len(got), err)
+		}
+		if len(data) > 1000 && len(zipped) > len(data)/10 {
+			t.Errorf("%v bytes of text compressed to %v", len(data), len(zipped))
+		}
+	}
+	if _, err := Gunzip([]byte("not gzip")); err == nil {
+		t.Errorf("gunzip of garbage succeeded")
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import "regexp"
+
+// GrepRegexp compiles the pattern of grep, matching regardless of case
+// with ignoreCase
+func GrepRegexp(pattern string, ignoreCase bool) (*regexp.Regexp, error) {
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// Grep returns the lines of data matching re, in order. data starts and
+// ends with a newline as the part between the edges of a block does, see
+// SplitEdges.
+func Grep(re *regexp.Regexp, data []byte) []string {
+	var lines []string
+	for _, line := range Lines(data) {
+		if re.MatchString(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGrep(t *testing.T) {
+	re, err := GrepRegexp("^ab", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for data, want := range map[string][]string{
+		"":                   nil,
+		"\n":                 nil,
+		"\nAbc\n":            {"Abc"},
+		"\nabc\nxab\n\nab\n": {"abc", "ab"},
+	} {
+		if got := Grep(re, []byte(data)); !reflect.DeepEqual(got, want) {
+			t.Errorf("grep of %q = %q, want %q", data, got, want)
+		}
+	}
+	empty, _ := GrepRegexp("^$", false)
+	if got := Grep(empty, []byte("\nx\n\n")); !reflect.DeepEqual(got, []string{""}) {
+		t.Errorf("grep of an empty line = %q", got)
+	}
+	if _, err := GrepRegexp("(", false); err == nil {
+		t.Errorf("invalid pattern compiled")
+	}
+}
@@ -0,0 +1,359 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"github.com/WineChord/gdfs/gdfspb"
+)
+
+/** Each type an RPC carries converts to its message of gdfspb with ToPB
+ * and back with FromPB, field by field. A field is named as its message
+ * field in camel case, see gdfs.proto, ints are int64 and time.Duration
+ * is in nanoseconds. The packages owning the types convert them, gdfspb
+ * stays free of gdfs imports.
+ * */
+
+// StringsToPB converts a map of string lists, e.g. the datanodes of each
+// block, to the values of a map<string, Strings> field
+func StringsToPB(m map[string][]string) map[string]*gdfspb.Strings {
+	if len(m) == 0 {
+		return nil
+	}
+	pb := make(map[string]*gdfspb.Strings, len(m))
+	for k, v := range m {
+		pb[k] = &gdfspb.Strings{Values: v}
+	}
+	return pb
+}
+
+// StringsFromPB is the inverse of StringsToPB
+func StringsFromPB(pb map[string]*gdfspb.Strings) map[string][]string {
+	if len(pb) == 0 {
+		return nil
+	}
+	m := make(map[string][]string, len(pb))
+	for k, v := range pb {
+		m[k] = v.GetValues()
+	}
+	return m
+}
+
+// ToPB converts m to gdfspb.MetaData
+func (m *MetaData) ToPB() *gdfspb.MetaData {
+	return &gdfspb.MetaData{
+		Checksum:     m.Checksum,
+		ChecksumType: m.ChecksumType,
+		Timestamp:    m.Timestamp,
+		Length:       m.Length,
+		GenStamp:     m.GenStamp,
+		Compressed:   m.Compressed,
+	}
+}
+
+// FromPB sets m to pb
+func (m *MetaData) FromPB(pb *gdfspb.MetaData) {
+	*m = MetaData{
+		Checksum:     pb.GetChecksum(),
+		ChecksumType: pb.GetChecksumType(),
+		Timestamp:    pb.GetTimestamp(),
+		Length:       pb.GetLength(),
+		GenStamp:     pb.GetGenStamp(),
+		Compressed:   pb.GetCompressed(),
+	}
+}
+
+// ToPB converts e to gdfspb.Edges
+func (e *Edges) ToPB() *gdfspb.Edges {
+	return &gdfspb.Edges{
+		Head:  e.Head,
+		Tail:  e.Tail,
+		Whole: e.Whole,
+	}
+}
+
+// FromPB sets e to pb
+func (e *Edges) FromPB(pb *gdfspb.Edges) {
+	*e = Edges{
+		Head:  pb.GetHead(),
+		Tail:  pb.GetTail(),
+		Whole: pb.GetWhole(),
+	}
+}
+
+// ToPB converts c to gdfspb.CalMVArgs
+func (c *CalMVArgs) ToPB() *gdfspb.CalMVArgs {
+	return &gdfspb.CalMVArgs{
+		BlkId: c.BlkID,
+		Token: c.Token,
+	}
+}
+
+// FromPB sets c to pb
+func (c *CalMVArgs) FromPB(pb *gdfspb.CalMVArgs) {
+	*c = CalMVArgs{
+		BlkID: pb.GetBlkId(),
+		Token: pb.GetToken(),
+	}
+}
+
+// ToPB converts c to gdfspb.CalMVReply
+func (c *CalMVReply) ToPB() *gdfspb.CalMVReply {
+	return &gdfspb.CalMVReply{
+		Cnt:    c.Cnt,
+		Mean:   c.Mean,
+		MeanSq: c.MeanSQ,
+		Edges:  c.Edges.ToPB(),
+	}
+}
+
+// FromPB sets c to pb
+func (c *CalMVReply) FromPB(pb *gdfspb.CalMVReply) {
+	*c = CalMVReply{
+		Cnt:    pb.GetCnt(),
+		Mean:   pb.GetMean(),
+		MeanSQ: pb.GetMeanSq(),
+	}
+	c.Edges.FromPB(pb.GetEdges())
+}
+
+// ToPB converts w to gdfspb.WordCountArgs
+func (w *WordCountArgs) ToPB() *gdfspb.WordCountArgs {
+	return &gdfspb.WordCountArgs{
+		BlkId: w.BlkID,
+		Token: w.Token,
+	}
+}
+
+// FromPB sets w to pb
+func (w *WordCountArgs) FromPB(pb *gdfspb.WordCountArgs) {
+	*w = WordCountArgs{
+		BlkID: pb.GetBlkId(),
+		Token: pb.GetToken(),
+	}
+}
+
+// ToPB converts w to gdfspb.WordCountReply
+func (w *WordCountReply) ToPB() *gdfspb.WordCountReply {
+	pb := &gdfspb.WordCountReply{
+		Edges: w.Edges.ToPB(),
+	}
+	if len(w.Counts) > 0 {
+		pb.Counts = make(map[string]int64, len(w.Counts))
+		for k, v := range w.Counts {
+			pb.Counts[k] = int64(v)
+		}
+	}
+	return pb
+}
+
+// FromPB sets w to pb
+func (w *WordCountReply) FromPB(pb *gdfspb.WordCountReply) {
+	*w = WordCountReply{}
+	if vs := pb.GetCounts(); len(vs) > 0 {
+		w.Counts = make(map[string]int, len(vs))
+		for k, v := range vs {
+			w.Counts[k] = int(v)
+		}
+	}
+	w.Edges.FromPB(pb.GetEdges())
+}
+
+// ToPB converts g to gdfspb.GrepArgs
+func (g *GrepArgs) ToPB() *gdfspb.GrepArgs {
+	return &gdfspb.GrepArgs{
+		BlkId:      g.BlkID,
+		Pattern:    g.Pattern,
+		IgnoreCase: g.IgnoreCase,
+		CountOnly:  g.CountOnly,
+		Token:      g.Token,
+	}
+}
+
+// FromPB sets g to pb
+func (g *GrepArgs) FromPB(pb *gdfspb.GrepArgs) {
+	*g = GrepArgs{
+		BlkID:      pb.GetBlkId(),
+		Pattern:    pb.GetPattern(),
+		IgnoreCase: pb.GetIgnoreCase(),
+		CountOnly:  pb.GetCountOnly(),
+		Token:      pb.GetToken(),
+	}
+}
+
+// ToPB converts g to gdfspb.GrepReply
+func (g *GrepReply) ToPB() *gdfspb.GrepReply {
+	return &gdfspb.GrepReply{
+		Lines: g.Lines,
+		Count: int64(g.Count),
+		Edges: g.Edges.ToPB(),
+	}
+}
+
+// FromPB sets g to pb
+func (g *GrepReply) FromPB(pb *gdfspb.GrepReply) {
+	*g = GrepReply{
+		Lines: pb.GetLines(),
+		Count: int(pb.GetCount()),
+	}
+	g.Edges.FromPB(pb.GetEdges())
+}
+
+// ToPB converts t to gdfspb.TopNArgs
+func (t *TopNArgs) ToPB() *gdfspb.TopNArgs {
+	return &gdfspb.TopNArgs{
+		BlkId:   t.BlkID,
+		K:       int64(t.K),
+		Lexical: t.Lexical,
+		Token:   t.Token,
+	}
+}
+
+// FromPB sets t to pb
+func (t *TopNArgs) FromPB(pb *gdfspb.TopNArgs) {
+	*t = TopNArgs{
+		BlkID:   pb.GetBlkId(),
+		K:       int(pb.GetK()),
+		Lexical: pb.GetLexical(),
+		Token:   pb.GetToken(),
+	}
+}
+
+// ToPB converts t to gdfspb.TopNReply
+func (t *TopNReply) ToPB() *gdfspb.TopNReply {
+	return &gdfspb.TopNReply{
+		Top:   t.Top,
+		Edges: t.Edges.ToPB(),
+	}
+}
+
+// FromPB sets t to pb
+func (t *TopNReply) FromPB(pb *gdfspb.TopNReply) {
+	*t = TopNReply{
+		Top: pb.GetTop(),
+	}
+	t.Edges.FromPB(pb.GetEdges())
+}
+
+// ToPB converts b to gdfspb.BlkData
+func (b *BlkData) ToPB() *gdfspb.BlkData {
+	return &gdfspb.BlkData{
+		BlkId:        b.BlkID,
+		Data:         b.Data,
+		Checksum:     b.Checksum,
+		Length:       int64(b.Length),
+		GenStamp:     b.GenStamp,
+		ChecksumType: b.ChecksumType,
+		Targets:      b.Targets,
+		Compressed:   b.Compressed,
+		Token:        b.Token,
+	}
+}
+
+// FromPB sets b to pb
+func (b *BlkData) FromPB(pb *gdfspb.BlkData) {
+	*b = BlkData{
+		BlkID:        pb.GetBlkId(),
+		Data:         pb.GetData(),
+		Checksum:     pb.GetChecksum(),
+		Length:       int(pb.GetLength()),
+		GenStamp:     pb.GetGenStamp(),
+		ChecksumType: pb.GetChecksumType(),
+		Targets:      pb.GetTargets(),
+		Compressed:   pb.GetCompressed(),
+		Token:        pb.GetToken(),
+	}
+}
+
+// ToPB converts b to gdfspb.BlkChunk
+func (b *BlkChunk) ToPB() *gdfspb.BlkChunk {
+	return &gdfspb.BlkChunk{
+		BlkId:        b.BlkID,
+		Offset:       b.Offset,
+		Data:         b.Data,
+		Last:         b.Last,
+		Checksum:     b.Checksum,
+		GenStamp:     b.GenStamp,
+		Targets:      b.Targets,
+		ChecksumType: b.ChecksumType,
+		Compressed:   b.Compressed,
+		Token:        b.Token,
+	}
+}
+
+// FromPB sets b to pb
+func (b *BlkChunk) FromPB(pb *gdfspb.BlkChunk) {
+	*b = BlkChunk{
+		BlkID:        pb.GetBlkId(),
+		Offset:       pb.GetOffset(),
+		Data:         pb.GetData(),
+		Last:         pb.GetLast(),
+		Checksum:     pb.GetChecksum(),
+		GenStamp:     pb.GetGenStamp(),
+		Targets:      pb.GetTargets(),
+		ChecksumType: pb.GetChecksumType(),
+		Compressed:   pb.GetCompressed(),
+		Token:        pb.GetToken(),
+	}
+}
+
+// ToPB converts t to gdfspb.TruncateBlkArgs
+func (t *TruncateBlkArgs) ToPB() *gdfspb.TruncateBlkArgs {
+	return &gdfspb.TruncateBlkArgs{
+		BlkId:    t.BlkID,
+		Length:   t.Length,
+		GenStamp: t.GenStamp,
+		Token:    t.Token,
+	}
+}
+
+// FromPB sets t to pb
+func (t *TruncateBlkArgs) FromPB(pb *gdfspb.TruncateBlkArgs) {
+	*t = TruncateBlkArgs{
+		BlkID:    pb.GetBlkId(),
+		Length:   pb.GetLength(),
+		GenStamp: pb.GetGenStamp(),
+		Token:    pb.GetToken(),
+	}
+}
+
+// ToPB converts d to gdfspb.DeleteBlkArgs
+func (d *DeleteBlkArgs) ToPB() *gdfspb.DeleteBlkArgs {
+	return &gdfspb.DeleteBlkArgs{
+		BlkId: d.BlkID,
+		Token: d.Token,
+	}
+}
+
+// FromPB sets d to pb
+func (d *DeleteBlkArgs) FromPB(pb *gdfspb.DeleteBlkArgs) {
+	*d = DeleteBlkArgs{
+		BlkID: pb.GetBlkId(),
+		Token: pb.GetToken(),
+	}
+}
+
+// ToPB converts d to gdfspb.DeleteBlkReply
+func (d *DeleteBlkReply) ToPB() *gdfspb.DeleteBlkReply {
+	return &gdfspb.DeleteBlkReply{
+		Status: d.Status,
+	}
+}
+
+// FromPB sets d to pb
+func (d *DeleteBlkReply) FromPB(pb *gdfspb.DeleteBlkReply) {
+	*d = DeleteBlkReply{
+		Status: pb.GetStatus(),
+	}
+}
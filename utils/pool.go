@@ -0,0 +1,155 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+	"github.com/WineChord/gdfs/gdfspb"
+	"google.golang.org/grpc"
+)
+
+// Pool keeps a connection per gRPC server address: the first call to an
+// address dials it, later calls share the connection, since gRPC
+// multiplexes calls. Connections unused for IdleTimeout are closed on the
+// next call. A connection whose server turned unavailable is replaced, so
+// that the next call dials again instead of waiting for gRPC to reconnect.
+// Pool is safe for concurrent use.
+type Pool struct {
+	IdleTimeout time.Duration
+	mu          sync.Mutex
+	conns       map[string]*poolConn
+}
+
+// poolConn is a connection of a Pool
+type poolConn struct {
+	addr     string
+	cc       *grpc.ClientConn
+	refs     int       // calls in progress
+	lastUsed time.Time // end of the last call
+	// dropped connections are closed once their calls end
+	dropped bool
+}
+
+// NewPool returns a pool closing connections idle for config.ConnIdleInSec
+func NewPool() *Pool {
+	return &Pool{IdleTimeout: time.Duration(config.ConnIdleInSec) * time.Second,
+		conns: make(map[string]*poolConn)}
+}
+
+// CallDataNode calls f with the DataNode service at addr and returns its
+// error, the context of f gives up after timeout or once cancel is closed,
+// see Context
+func (p *Pool) CallDataNode(addr string, timeout time.Duration, cancel <-chan struct{},
+	f func(ctx context.Context, dn gdfspb.DataNodeClient) error) error {
+	cc, release, err := p.Get(addr)
+	if err != nil {
+		return err
+	}
+	ctx, stop := Context(timeout, cancel)
+	err = f(ctx, gdfspb.NewDataNodeClient(cc))
+	stop()
+	release(err)
+	return err
+}
+
+// Get returns the connection to addr for calls outlasting a function, e.g.
+// a stream, release must be called with their error once they end
+func (p *Pool) Get(addr string) (cc *grpc.ClientConn, release func(error), err error) {
+	pc, err := p.get(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	var once sync.Once
+	return pc.cc, func(err error) { once.Do(func() { p.put(pc, err) }) }, nil
+}
+
+// get returns the connection to addr
+func (p *Pool) get(addr string) (*poolConn, error) {
+	p.mu.Lock()
+	p.closeIdle()
+	pc := p.conns[addr]
+	if pc != nil {
+		pc.refs++
+		p.mu.Unlock()
+		return pc, nil
+	}
+	p.mu.Unlock()
+	// dialing may take RPCTimeout, calls to other addresses go on
+	cc, err := Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pc = p.conns[addr]; pc != nil {
+		// another call dialed addr meanwhile
+		cc.Close()
+		pc.refs++
+		return pc, nil
+	}
+	pc = &poolConn{addr: addr, cc: cc, refs: 1}
+	p.conns[addr] = pc
+	return pc, nil
+}
+
+// put ends a call on pc which returned err
+func (p *Pool) put(pc *poolConn, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pc.refs--
+	pc.lastUsed = time.Now()
+	if errors.Is(err, ErrUnavailable) {
+		p.drop(pc)
+	}
+	if pc.dropped && pc.refs == 0 {
+		pc.cc.Close()
+	}
+}
+
+// drop takes pc out of the pool, p.mu is held
+func (p *Pool) drop(pc *poolConn) {
+	pc.dropped = true
+	if p.conns[pc.addr] == pc {
+		delete(p.conns, pc.addr)
+	}
+}
+
+// closeIdle closes the connections unused for IdleTimeout, p.mu is held
+func (p *Pool) closeIdle() {
+	for _, pc := range p.conns {
+		if pc.refs == 0 && time.Since(pc.lastUsed) > p.IdleTimeout {
+			p.drop(pc)
+			pc.cc.Close()
+		}
+	}
+}
+
+// Close closes every connection, those in use once their calls end
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, pc := range p.conns {
+		p.drop(pc)
+		if pc.refs == 0 {
+			pc.cc.Close()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WineChord/gdfs/gdfspb"
+)
+
+// countingListener records the connections it accepts
+type countingListener struct {
+	net.Listener
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.mu.Lock()
+		l.conns = append(l.conns, conn)
+		l.mu.Unlock()
+	}
+	return conn, err
+}
+
+func (l *countingListener) accepted() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.conns)
+}
+
+// closeAll closes the connections accepted as a restarted server would
+func (l *countingListener) closeAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, conn := range l.conns {
+		conn.Close()
+	}
+}
+
+func TestPool(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := &countingListener{Listener: inner}
+	serveSleeper(t, l)
+	addr := l.Addr().String()
+	p := NewPool()
+	defer p.Close()
+	call := func(d string) error {
+		return p.CallDataNode(addr, 100*time.Millisecond, nil,
+			func(ctx context.Context, dn gdfspb.DataNodeClient) error {
+				_, err := dn.DeleteBlk(ctx, &gdfspb.DeleteBlkArgs{BlkId: d})
+				return err
+			})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		if err := call("0s"); err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := call("0s"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	// a timed out call leaves the connection to the others
+	if err := call("1h"); !errors.Is(err, ErrTimeout) {
+		t.Errorf("hung call = %v, want a timeout", err)
+	}
+	if n := l.accepted(); n != 1 {
+		t.Errorf("21 calls opened %v connections, want 1", n)
+	}
+
+	// a connection closed by the server is dialed again
+	l.closeAll()
+	time.Sleep(50 * time.Millisecond)
+	if err := call("0s"); err != nil {
+		t.Errorf("call after the server closed the connection: %v", err)
+	}
+	if n := l.accepted(); n != 2 {
+		t.Errorf("%v connections after the server closed one, want 2", n)
+	}
+
+	// an idle one is closed
+	p.mu.Lock()
+	p.IdleTimeout = 10 * time.Millisecond
+	p.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	if err := call("0s"); err != nil || l.accepted() != 3 {
+		t.Errorf("call after idling = %v with %v connections, want 3", err, l.accepted())
+	}
+	p.mu.Lock()
+	p.IdleTimeout = time.Hour
+	p.mu.Unlock()
+
+	// and the one of a server gone is replaced once it is back
+	l.Close()
+	l.closeAll()
+	time.Sleep(50 * time.Millisecond)
+	if err := call("0s"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("call to a server gone = %v, want unavailable", err)
+	}
+	inner, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("cannot listen at %v again: %v", addr, err)
+	}
+	l = &countingListener{Listener: inner}
+	serveSleeper(t, l)
+	if err := call("0s"); err != nil || l.accepted() != 1 {
+		t.Errorf("call once the server is back = %v with %v connections, want 1",
+			err, l.accepted())
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+/** Files are cut into blocks at a fixed size, regardless of their content,
+ * so a record (a line, a word) may straddle block boundaries. The record
+ * boundary rule of map tasks is the following:
+ * 	1. the map task of a block only processes the records lying entirely
+ * 	   between the first and the last separator of the block
+ * 	2. the bytes before the first separator and after the last one are
+ * 	   sent back as the Edges of the block
+ * 	3. the reducer walks the Edges in block order and glues the tail of
+ * 	   each block to the head of the next one, which gives back the
+ * 	   records straddling boundaries, see JoinEdges
+ * The first record of a file and the last one are edges as well, they are
+ * completed by the start and the end of the file.
+ * */
+
+// Edges are the partial records at both ends of a block. Whole is set
+// when the block has no separator at all, Head is then the whole block
+// and continues the record started in the previous blocks.
+type Edges struct {
+	Head  string
+	Tail  string
+	Whole bool
+}
+
+// SplitEdges splits data into its edges and the part in between, which
+// starts and ends with a separator. isSep tells whether a rune separates
+// records, a rune cut by a block boundary is never a separator.
+func SplitEdges(data []byte, isSep func(rune) bool) (Edges, []byte) {
+	first := bytes.IndexFunc(data, isSep)
+	if first < 0 {
+		return Edges{Head: string(data), Whole: true}, nil
+	}
+	last := bytes.LastIndexFunc(data, isSep)
+	_, size := utf8.DecodeRune(data[last:])
+	edges := Edges{Head: string(data[:first]), Tail: string(data[last+size:])}
+	return edges, data[first : last+size]
+}
+
+// JoinEdges glues the edges of consecutive blocks of a file, given in
+// block order, and calls emit with each non-empty record they make up
+func JoinEdges(edges []Edges, emit func(record string)) {
+	pending := "" // the part of a record seen so far
+	for _, e := range edges {
+		if e.Whole {
+			pending += e.Head
+			continue
+		}
+		if record := pending + e.Head; record != "" {
+			emit(record)
+		}
+		pending = e.Tail
+	}
+	if pending != "" {
+		emit(pending)
+	}
+}
+
+// IsNewline separates lines
+func IsNewline(r rune) bool {
+	return r == '\n'
+}
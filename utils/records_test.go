@@ -0,0 +1,55 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// TestRecordsAcrossBlocks cuts a file into blocks of every size and checks
+// that the inner lines of the blocks and the lines joined from their edges
+// are the lines of the file
+func TestRecordsAcrossBlocks(t *testing.T) {
+	file := "12\n345\n\n6\n7890123\n4"
+	want := []string{"12", "345", "4", "6", "7890123"} // sorted
+	for size := 1; size <= len(file)+1; size++ {
+		var got []string
+		var edges []Edges
+		for off := 0; off < len(file); off += size {
+			end := off + size
+			if end > len(file) {
+				end = len(file)
+			}
+			e, inner := SplitEdges([]byte(file[off:end]), IsNewline)
+			s := bufio.NewScanner(bytes.NewReader(inner))
+			for s.Scan() {
+				if s.Text() != "" {
+					got = append(got, s.Text())
+				}
+			}
+			edges = append(edges, e)
+		}
+		JoinEdges(edges, func(line string) { got = append(got, line) })
+		// inner lines come first, order doesn't matter to map reduce
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("blocks of %v bytes give lines %q, want %q", size, got, want)
+		}
+	}
+}
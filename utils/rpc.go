@@ -0,0 +1,204 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// ErrTimeout is wrapped by errors of calls the server doesn't reply to in
+// time
+var ErrTimeout = errors.New("RPC timed out")
+
+// ErrCanceled is wrapped by errors of calls canceled by the caller
+var ErrCanceled = errors.New("RPC canceled")
+
+// ErrUnavailable is wrapped by errors of calls which couldn't reach the
+// server, e.g. it is down or the connection broke
+var ErrUnavailable = errors.New("RPC server unavailable")
+
+// ServerError is an error returned by the handler of a call, its message
+// is the one of the server
+type ServerError string
+
+func (e ServerError) Error() string {
+	return string(e)
+}
+
+// RPCTimeout is the default timeout of Dial and of calls
+func RPCTimeout() time.Duration {
+	return time.Duration(config.RPCTimeoutInSec) * time.Second
+}
+
+// NewServer returns a gRPC server taking messages of any size, as blocks
+// are sent whole
+func NewServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{grpc.MaxRecvMsgSize(math.MaxInt32),
+		grpc.MaxSendMsgSize(math.MaxInt32)}, opts...)
+	return grpc.NewServer(opts...)
+}
+
+// Dial connects to the gRPC server at addr giving up after RPCTimeout, the
+// connection is secured by TLS if config.TLS is set, see Listen. Calls on
+// it fail with a ServerError holding the error of their handler, or with
+// an error wrapping ErrTimeout, ErrCanceled or ErrUnavailable.
+func Dial(addr string) (*grpc.ClientConn, error) {
+	timeout := RPCTimeout()
+	/** connect now so that an unreachable server or a failed
+	 * handshake fail here, gRPC would only connect on the first call.
+	 * The first connection is dialed here for its error to be returned
+	 * as is, once the server closes it gRPC dials again.
+	 * */
+	conn, err := dial(addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	var mu sync.Mutex
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) {
+		mu.Lock()
+		c := conn
+		conn = nil
+		mu.Unlock()
+		if c != nil {
+			return c, nil
+		}
+		return dial(addr, timeout)
+	}
+	cc, err := grpc.NewClient("passthrough:///"+addr,
+		grpc.WithContextDialer(dialer),
+		// TLS, if any, is done by dial
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(math.MaxInt32),
+			grpc.MaxCallSendMsgSize(math.MaxInt32)),
+		grpc.WithChainUnaryInterceptor(unaryErrors),
+		grpc.WithChainStreamInterceptor(streamErrors))
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cc.Connect()
+	for state := cc.GetState(); state != connectivity.Ready; state = cc.GetState() {
+		if state == connectivity.TransientFailure || !cc.WaitForStateChange(ctx, state) {
+			cc.Close()
+			return nil, fmt.Errorf("dial %v: %w", addr, ErrUnavailable)
+		}
+	}
+	return cc, nil
+}
+
+// Context returns the context of a call giving up after timeout, or once
+// cancel is closed if it isn't nil
+func Context(timeout time.Duration, cancel <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, stop := context.WithTimeout(context.Background(), timeout)
+	if cancel != nil {
+		go func() {
+			select {
+			case <-cancel:
+				stop()
+			case <-ctx.Done():
+			}
+		}()
+	}
+	return ctx, stop
+}
+
+// StreamContext returns the context of a stream along with bound, which
+// gives a call on the stream, e.g. Send, timeout to return: the stream is
+// canceled once a call takes longer and fails with ErrTimeout. Time spent
+// between calls is not bounded.
+func StreamContext(timeout time.Duration) (ctx context.Context,
+	bound func(call func() error) error, cancel context.CancelFunc) {
+	ctx, cancelCause := context.WithCancelCause(context.Background())
+	timer := time.AfterFunc(timeout, func() { cancelCause(ErrTimeout) })
+	timer.Stop()
+	bound = func(call func() error) error {
+		timer.Reset(timeout)
+		defer timer.Stop()
+		return call()
+	}
+	return ctx, bound, func() {
+		timer.Stop()
+		cancelCause(context.Canceled)
+	}
+}
+
+// callError converts the status err of a call to method, e.g.
+// "/gdfs.NameNode/RunCommand", made with ctx, see Dial
+func callError(ctx context.Context, method string, err error) error {
+	s, ok := status.FromError(err)
+	if err == nil || !ok {
+		return err
+	}
+	method = strings.Replace(strings.TrimPrefix(method, "/gdfs."), "/", ".", 1)
+	switch s.Code() {
+	case codes.Unknown: // a plain error of the handler
+		return ServerError(s.Message())
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%v: %w", method, ErrTimeout)
+	case codes.Canceled:
+		if errors.Is(context.Cause(ctx), ErrTimeout) { // see StreamContext
+			return fmt.Errorf("%v: %w", method, ErrTimeout)
+		}
+		return fmt.Errorf("%v: %w", method, ErrCanceled)
+	case codes.Unavailable:
+		return fmt.Errorf("%v: %w: %v", method, ErrUnavailable, s.Message())
+	}
+	return err
+}
+
+func unaryErrors(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return callError(ctx, method, invoker(ctx, method, req, reply, cc, opts...))
+}
+
+func streamErrors(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	s, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, callError(ctx, method, err)
+	}
+	return &errorStream{ClientStream: s, ctx: ctx, method: method}, nil
+}
+
+// errorStream is a client stream converting the errors of its calls
+type errorStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	method string
+}
+
+func (s *errorStream) SendMsg(m interface{}) error {
+	return callError(s.ctx, s.method, s.ClientStream.SendMsg(m))
+}
+
+func (s *errorStream) RecvMsg(m interface{}) error {
+	return callError(s.ctx, s.method, s.ClientStream.RecvMsg(m))
+}
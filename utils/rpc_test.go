@@ -0,0 +1,119 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/WineChord/gdfs/gdfspb"
+	"google.golang.org/grpc"
+)
+
+// sleeper serves DeleteBlk only, sleeping for the duration its block id
+// holds, e.g. "10ms", and failing on ids which aren't durations
+type sleeper struct {
+	gdfspb.UnimplementedDataNodeServer
+}
+
+func (sleeper) DeleteBlk(ctx context.Context, args *gdfspb.DeleteBlkArgs) (*gdfspb.DeleteBlkReply, error) {
+	d, err := time.ParseDuration(args.BlkId)
+	if err != nil {
+		return nil, errors.New("Not a duration: " + args.BlkId)
+	}
+	time.Sleep(d)
+	return &gdfspb.DeleteBlkReply{Status: true}, nil
+}
+
+// serveSleeper serves sleeper on l until the test ends
+func serveSleeper(t *testing.T, l net.Listener) {
+	serv := NewServer()
+	gdfspb.RegisterDataNodeServer(serv, sleeper{})
+	go serv.Serve(l)
+	t.Cleanup(serv.Stop)
+}
+
+// sleep calls sleeper for d, giving up after timeout
+func sleep(cc grpc.ClientConnInterface, d string, timeout time.Duration) error {
+	ctx, cancel := Context(timeout, nil)
+	defer cancel()
+	_, err := gdfspb.NewDataNodeClient(cc).DeleteBlk(ctx, &gdfspb.DeleteBlkArgs{BlkId: d})
+	return err
+}
+
+func TestCallErrors(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	serveSleeper(t, l)
+	cc, err := Dial(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+	if err := sleep(cc, "1ms", time.Second); err != nil {
+		t.Errorf("a quick call = %v", err)
+	}
+	// the error of the handler comes as is
+	err = sleep(cc, "forever", time.Second)
+	if _, ok := err.(ServerError); !ok || err.Error() != "Not a duration: forever" {
+		t.Errorf("a failing call = %#v, want the error of the handler", err)
+	}
+	start := time.Now()
+	err = sleep(cc, "1h", 50*time.Millisecond)
+	if !errors.Is(err, ErrTimeout) || time.Since(start) > time.Second {
+		t.Errorf("a hung call = %v after %v, want a timeout", err, time.Since(start))
+	}
+	cancel := make(chan struct{})
+	ctx, stop := Context(time.Hour, cancel)
+	defer stop()
+	time.AfterFunc(10*time.Millisecond, func() { close(cancel) })
+	_, err = gdfspb.NewDataNodeClient(cc).DeleteBlk(ctx, &gdfspb.DeleteBlkArgs{BlkId: "1h"})
+	if !errors.Is(err, ErrCanceled) {
+		t.Errorf("a canceled call = %v, want canceled", err)
+	}
+}
+
+func TestDialUnavailable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	if cc, err := Dial(addr); err == nil {
+		cc.Close()
+		t.Errorf("dialing a closed port succeeded")
+	}
+
+	// so is a server which doesn't speak gRPC
+	l, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go http.Serve(l, http.NotFoundHandler())
+	if cc, err := Dial(l.Addr().String()); !errors.Is(err, ErrUnavailable) {
+		if err == nil {
+			cc.Close()
+		}
+		t.Errorf("dialing a plain HTTP server = %v, want unavailable", err)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// Throttler limits the bytes sent per second with a token bucket holding
+// at most a second of tokens, empty at first. A nil Throttler doesn't
+// limit anything. Throttler is safe for concurrent use, senders sharing it
+// share its rate.
+type Throttler struct {
+	rate   float64 // bytes per second
+	mu     sync.Mutex
+	tokens float64 // negative while bytes wait for their turn
+	last   time.Time
+}
+
+// NewThrottler returns a throttler of bytesPerSec, nil if it isn't positive
+func NewThrottler(bytesPerSec int64) *Throttler {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	return &Throttler{rate: float64(bytesPerSec), last: time.Now()}
+}
+
+// reserve takes n tokens and returns how long to wait for them
+func (t *Throttler) reserve(n int) time.Duration {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	t.tokens += now.Sub(t.last).Seconds() * t.rate
+	if t.tokens > t.rate {
+		t.tokens = t.rate
+	}
+	t.last = now
+	t.tokens -= float64(n)
+	if t.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-t.tokens / t.rate * float64(time.Second))
+}
+
+// Throttle waits until n bytes may be sent under every throttler of ts
+func Throttle(n int, ts ...*Throttler) {
+	var wait time.Duration
+	for _, t := range ts {
+		if d := t.reserve(n); d > wait {
+			wait = d
+		}
+	}
+	time.Sleep(wait)
+}
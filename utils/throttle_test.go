@@ -0,0 +1,48 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottle(t *testing.T) {
+	// 50 kB at 100 kB/s take half a second, however they are split
+	start := time.Now()
+	th := NewThrottler(100 << 10)
+	for i := 0; i < 10; i++ {
+		Throttle(5<<10, th, nil)
+	}
+	if d := time.Since(start); d < 400*time.Millisecond || d > 1500*time.Millisecond {
+		t.Errorf("50 kB at 100 kB/s took %v, want about 500ms", d)
+	}
+
+	// the slowest throttler sets the pace
+	start = time.Now()
+	Throttle(10<<10, NewThrottler(1<<20), NewThrottler(50<<10))
+	if d := time.Since(start); d < 150*time.Millisecond || d > time.Second {
+		t.Errorf("10 kB at 50 kB/s took %v, want about 200ms", d)
+	}
+
+	if NewThrottler(0) != nil || NewThrottler(-1) != nil {
+		t.Errorf("NewThrottler of a non-positive rate should not limit")
+	}
+	start = time.Now()
+	Throttle(1<<30, nil)
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Errorf("nil throttler waited %v", d)
+	}
+}
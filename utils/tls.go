@@ -0,0 +1,83 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+var (
+	tlsMu     sync.Mutex
+	tlsCAFile string // file roots were loaded from
+	tlsRoots  *x509.CertPool
+)
+
+// Listen listens for RPCs on addr, over TLS if config.TLS is set
+func Listen(addr string) (net.Listener, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil || !config.TLS {
+		return l, err
+	}
+	cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	conf := &tls.Config{Certificates: []tls.Certificate{cert},
+		MinVersion: tls.VersionTLS12}
+	return tls.NewListener(l, conf), nil
+}
+
+// dial connects to addr, over TLS if config.TLS is set. The server
+// certificate must be signed by a CA of config.TLSCAFile and name the
+// host of addr.
+func dial(addr string, timeout time.Duration) (net.Conn, error) {
+	if !config.TLS {
+		return net.DialTimeout("tcp", addr, timeout)
+	}
+	roots, err := rootCAs()
+	if err != nil {
+		return nil, err
+	}
+	conf := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	return tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", addr, conf)
+}
+
+// rootCAs loads the certificates of config.TLSCAFile, once per file
+func rootCAs() (*x509.CertPool, error) {
+	tlsMu.Lock()
+	defer tlsMu.Unlock()
+	if tlsRoots != nil && tlsCAFile == config.TLSCAFile {
+		return tlsRoots, nil
+	}
+	pem, err := ioutil.ReadFile(config.TLSCAFile)
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(pem) {
+		return nil, errors.New("No certificate in " + config.TLSCAFile)
+	}
+	tlsCAFile, tlsRoots = config.TLSCAFile, roots
+	return roots, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/WineChord/gdfs/config"
+)
+
+// writeCert writes a certificate for 127.0.0.1 signed by parent (self
+// signed if nil) and its key as PEM files, and returns them for signing
+func writeCert(t *testing.T, certFile, keyFile string, isCA bool,
+	parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "gdfs test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	if err == nil {
+		err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestTLSCall(t *testing.T) {
+	dir := t.TempDir()
+	file := func(name string) string { return filepath.Join(dir, name) }
+	ca, caKey := writeCert(t, file("ca.crt"), file("ca.key"), true, nil, nil)
+	writeCert(t, file("node.crt"), file("node.key"), false, ca, caKey)
+	writeCert(t, file("other.crt"), file("other.key"), true, nil, nil)
+	defer func(on bool, cert, key, caFile string) {
+		config.TLS, config.TLSCertFile, config.TLSKeyFile, config.TLSCAFile = on, cert, key, caFile
+	}(config.TLS, config.TLSCertFile, config.TLSKeyFile, config.TLSCAFile)
+	config.TLS = true
+	config.TLSCertFile, config.TLSKeyFile = file("node.crt"), file("node.key")
+	config.TLSCAFile = file("ca.crt")
+
+	l, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	serveSleeper(t, l)
+	addr := l.Addr().String()
+	cc, err := Dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+	if err := sleep(cc, "1ms", time.Second); err != nil {
+		t.Errorf("call over TLS = %v", err)
+	}
+
+	// a caller trusting another CA rejects the server
+	config.TLSCAFile = file("other.crt")
+	if cc, err := Dial(addr); err == nil {
+		cc.Close()
+		t.Errorf("dialing a server signed by an untrusted CA succeeded")
+	}
+	// a plaintext caller cannot talk to a TLS server
+	config.TLS = false
+	if cc, err := Dial(addr); err == nil {
+		cc.Close()
+		t.Errorf("dialing a TLS server in plaintext succeeded")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lines returns the lines of data, which starts and ends with a newline
+// as the part between the edges of a block does, see SplitEdges
+func Lines(data []byte) []string {
+	if len(data) < 2 {
+		return nil
+	}
+	var lines []string
+	for _, line := range bytes.Split(data[1:len(data)-1], []byte("\n")) {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// TopN returns the k greatest records, greatest first. Records are
+// trimmed and compared as integers, blank and non-numeric ones are
+// skipped as calMeanVal does. With lexical, they are compared as strings
+// and only blank ones are skipped. Equal records are all kept, so the k
+// greatest of [3 3 1] are [3 3] for k = 2, and fewer than k records are
+// returned if there are not enough of them.
+func TopN(records []string, k int, lexical bool) []string {
+	type rec struct {
+		s string
+		n int64
+	}
+	var recs []rec
+	for _, s := range records {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		r := rec{s: s}
+		if !lexical {
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				continue
+			}
+			r.n = n
+		}
+		recs = append(recs, r)
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		if !lexical && recs[i].n != recs[j].n {
+			return recs[i].n > recs[j].n
+		}
+		return recs[i].s > recs[j].s // "07" and "7" in a fixed order
+	})
+	if len(recs) > k {
+		recs = recs[:k]
+	}
+	var top []string
+	for _, r := range recs {
+		top = append(top, r.s)
+	}
+	return top
+}
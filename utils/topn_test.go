@@ -0,0 +1,42 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopN(t *testing.T) {
+	records := []string{"3", " 10 ", "", "x", "-2", "10", "7", "03"}
+	for _, c := range []struct {
+		k       int
+		lexical bool
+		want    []string
+	}{
+		{2, false, []string{"10", "10"}}, // ties are all kept
+		{4, false, []string{"10", "10", "7", "3"}},
+		{10, false, []string{"10", "10", "7", "3", "03", "-2"}},
+		{3, true, []string{"x", "7", "3"}},
+		{0, false, nil},
+	} {
+		if got := TopN(records, c.k, c.lexical); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("top %v (lexical %v) = %q, want %q", c.k, c.lexical, got, c.want)
+		}
+	}
+	if got := Lines([]byte("\na\n\nb\n")); !reflect.DeepEqual(got, []string{"a", "", "b"}) {
+		t.Errorf("lines = %q", got)
+	}
+}
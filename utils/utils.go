@@ -15,35 +15,246 @@
 package utils
 
 import (
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // CalMVArgs is argument for calculating mean and avriance
 type CalMVArgs struct {
 	BlkID string
+	Token string // see config.AuthToken
 }
 
-// CalMVReply is result for each subtask
+// CalMVReply is result for each subtask, over the lines lying entirely
+// in the block, see Edges for the lines straddling block boundaries
 type CalMVReply struct {
 	Cnt    int64
 	Mean   float64
 	MeanSQ float64 // (\sum x^2)/n
+	Edges
+}
+
+// WordCountArgs is argument for counting words of a block
+type WordCountArgs struct {
+	BlkID string
+	Token string // see config.AuthToken
+}
+
+// WordCountReply is the word count of a block, over the words lying
+// entirely in the block, see Edges for the words straddling block
+// boundaries
+type WordCountReply struct {
+	Counts map[string]int
+	Edges
+}
+
+// GrepArgs asks for the lines of a block matching Pattern, see Grep
+type GrepArgs struct {
+	BlkID      string
+	Pattern    string
+	IgnoreCase bool
+	CountOnly  bool   // leave Lines out of the reply
+	Token      string // see config.AuthToken
+}
+
+// GrepReply holds the lines lying entirely in a block which match, in
+// order, and their number, along with the partial lines at its ends
+type GrepReply struct {
+	Lines []string
+	Count int
+	Edges
+}
+
+// TopNArgs asks for the K greatest records of a block, see TopN
+type TopNArgs struct {
+	BlkID   string
+	K       int
+	Lexical bool
+	Token   string // see config.AuthToken
+}
+
+// TopNReply holds the K greatest records lying entirely in a block,
+// greatest first, along with the partial lines at its ends
+type TopNReply struct {
+	Top []string
+	Edges
 }
 
 // MetaData stores checksum and timestamp of a file
 type MetaData struct {
-	Checksum  uint32 // crc checksum
-	Timestamp int64  // timestamp in millisecond
-	Length    int64  // block length
+	Checksum     Sum    // checksum of the data
+	ChecksumType string // algorithm of Checksum, see config.ChecksumType
+	Timestamp    int64  // timestamp in millisecond
+	Length       int64  // block length
+	GenStamp     int64  // generation stamp of the last write of the block
+	// Compressed blocks are stored gzip compressed, see
+	// config.CompressAtRest. Checksum and Length are always of the
+	// uncompressed data.
+	Compressed bool
 }
 
 // BlkData is used by client to send block data to datanodes
 type BlkData struct {
 	BlkID    string // of format filename-index-timestamp-random
 	Data     []byte // data in bytes
-	Checksum uint32 // checksum of data
+	Checksum Sum    // checksum of data
 	Length   int
+	GenStamp int64 // generation stamp handed out by namenode for the write
+	// ChecksumType is the algorithm of Checksum, see config.ChecksumType
+	ChecksumType string
+	// Targets are the datanodes the receiver forwards the block to, in
+	// pipeline order
+	Targets []string
+	// Compressed tells that Data is gzip compressed, see
+	// config.CompressBlks. Checksum and Length are of the uncompressed
+	// data.
+	Compressed bool
+	// Token authenticates a sent block, see config.AuthToken
+	Token string
+}
+
+// BlkChunk is a piece of a block streamed by client to datanodes, chunks
+// of a block are sent in order, Offset is the position of Data in the block.
+// The last chunk carries the checksum of the whole block.
+type BlkChunk struct {
+	BlkID    string
+	Offset   int64
+	Data     []byte
+	Last     bool
+	Checksum Sum      // checksum of the whole block, set on the last chunk
+	GenStamp int64    // see BlkData
+	Targets  []string // see BlkData
+	// ChecksumType is the algorithm of Checksum, the same for every chunk
+	ChecksumType string
+	// Compressed tells that Data is gzip compressed on its own, Offset and
+	// Checksum are of the uncompressed block
+	Compressed bool
+	Token      string // see config.AuthToken
+}
+
+// DeleteBlkArgs is used by namenode to ask a datanode to delete a block
+type DeleteBlkArgs struct {
+	BlkID string
+	Token string // see config.AuthToken
+}
+
+// DeleteBlkReply contains status of block deletion
+type DeleteBlkReply struct {
+	Status bool
+}
+
+// TruncateBlkArgs is used by namenode to ask a datanode to cut block
+// BlkID to its first Length bytes, the replica gets generation stamp
+// GenStamp
+type TruncateBlkArgs struct {
+	BlkID    string
+	Length   int64
+	GenStamp int64
+	Token    string // see config.AuthToken
+}
+
+// BlockID is the structured form of a block name, which is of format
+// filename-index-timestamp-random. The file name may contain dashes, so
+// the other fields are taken from the right.
+type BlockID struct {
+	FileName  string
+	Index     int
+	Timestamp int64 // in ms
+	Random    string
+}
+
+// String formats a block id with an 8 digit index
+func (b BlockID) String() string {
+	return fmt.Sprintf("%v-%08d-%v-%v", b.FileName, b.Index, b.Timestamp, b.Random)
+}
+
+// Parse fills b from a block name produced by String
+func (b *BlockID) Parse(s string) error {
+	parts := strings.Split(s, "-")
+	if len(parts) < 4 {
+		return fmt.Errorf("Malformed block id %q", s)
+	}
+	n := len(parts)
+	index, err := strconv.Atoi(parts[n-3])
+	if err != nil {
+		return fmt.Errorf("Malformed block index in %q", s)
+	}
+	timestamp, err := strconv.ParseInt(parts[n-2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("Malformed block timestamp in %q", s)
+	}
+	name := strings.Join(parts[:n-3], "-")
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\x00") {
+		return fmt.Errorf("Invalid file name in block id %q", s)
+	}
+	b.FileName = name
+	b.Index = index
+	b.Timestamp = timestamp
+	b.Random = parts[n-1]
+	return nil
+}
+
+// NewUUID returns a random (version 4) UUID as 32 hex digits, without
+// dashes so that it can be embedded in a block id
+func NewUUID() string {
+	var b [16]byte
+	_, err := cryptorand.Read(b[:])
+	if err != nil {
+		// crypto/rand never fails on supported platforms
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+	return hex.EncodeToString(b[:])
+}
+
+// TmpSuffix marks files being written by WriteFileAtomic
+const TmpSuffix = ".tmp"
+
+// WriteFileAtomic writes data to a temp file in the same directory as
+// path, syncs it and renames it into place, so readers of path only ever
+// see complete content. A crash leaves at most a stale temp file.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp := path + TmpSuffix
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// RemoveTmpFiles removes temp files left in dir by an interrupted
+// WriteFileAtomic or partial upload, i.e. names ending with a suffix
+func RemoveTmpFiles(dir string, suffixes ...string) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		return
+	}
+	for _, m := range matches {
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(m, suffix) {
+				os.Remove(m)
+				break
+			}
+		}
+	}
 }
 
 // Exists checks whether a path exist
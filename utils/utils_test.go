@@ -0,0 +1,81 @@
+// Copyright 2020 Qizhou Guo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestBlockIDRoundTrip(t *testing.T) {
+	for _, name := range []string{
+		"a.txt",
+		"my-data.txt",
+		"-leading",
+		"trailing-",
+		"--many---dashes--",
+		"2020-01-02-00000001-3-4",
+		"..%2f..%2fx",
+		"%2e%2e",
+	} {
+		want := BlockID{FileName: name, Index: 12, Timestamp: 1600000000000,
+			Random: "8674665223082153551"}
+		got := BlockID{}
+		if err := got.Parse(want.String()); err != nil {
+			t.Errorf("parsing %q: %v", want.String(), err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%q parses to %+v, want %+v", want.String(), got, want)
+		}
+	}
+}
+
+func TestBlockIDParseMalformed(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"a.txt",
+		"a.txt-1-2",
+		"a.txt-x-2-r",
+		"a.txt-1-y-r",
+		"my-data.txt-00000001-1600000000000",
+		"-00000001-1600000000000-r",
+		"..-00000001-1600000000000-r",
+		".-00000001-1600000000000-r",
+		"../../x-00000001-1600000000000-r",
+		"a/b-00000001-1600000000000-r",
+		"a\x00-00000001-1600000000000-r",
+	} {
+		id := BlockID{}
+		if err := id.Parse(s); err == nil {
+			t.Errorf("%q parses to %+v, want an error", s, id)
+		}
+	}
+}
+
+func TestNewUUID(t *testing.T) {
+	valid := regexp.MustCompile(`^[0-9a-f]{12}4[0-9a-f]{3}[89ab][0-9a-f]{15}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 10000; i++ {
+		id := NewUUID()
+		if !valid.MatchString(id) {
+			t.Fatalf("%q is not a version 4 UUID without dashes", id)
+		}
+		if seen[id] {
+			t.Fatalf("%q generated twice", id)
+		}
+		seen[id] = true
+	}
+}